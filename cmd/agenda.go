@@ -0,0 +1,603 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/x/term"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/agenda"
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/tui/components"
+)
+
+// AgendaCmd represents the agenda command
+var AgendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Gerencia os eventos e compromissos da agenda",
+	Long: `O comando 'agenda' gerencia os eventos e compromissos do usuário.
+Os eventos ficam salvos no banco de dados local e podem ser adicionados, listados, editados e removidos.
+Use 'agenda ver-dia' para ver os compromissos de hoje.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var agendaAdicionarEventoCmd = &cobra.Command{
+	Use:   "adicionar-evento",
+	Short: "Adiciona um novo evento à agenda",
+	Long: `Adiciona um novo evento à agenda.
+Exemplo: vickgenda agenda adicionar-evento --titulo "Reunião de pais" --inicio "2024-07-01 19:00" --fim "2024-07-01 20:30" --local "Auditório"
+
+Eventos recorrentes usam uma regra RRULE (RFC 5545) com FREQ, INTERVAL, BYDAY, BYMONTHDAY, UNTIL e COUNT:
+  vickgenda agenda adicionar-evento --titulo "Reunião de departamento" --inicio "2024-08-05 10:00" --fim "2024-08-05 11:00" \
+    --recorrencia "FREQ=WEEKLY;BYDAY=MO;UNTIL=20241216" --excecoes "2024-09-02 10:00"
+
+Com --projeto, o evento é adicionado ao projeto informado (nome ou ID).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		titulo, _ := cmd.Flags().GetString("titulo")
+		inicio, _ := cmd.Flags().GetString("inicio")
+		fim, _ := cmd.Flags().GetString("fim")
+		descricao, _ := cmd.Flags().GetString("descricao")
+		local, _ := cmd.Flags().GetString("local")
+		recorrencia, _ := cmd.Flags().GetString("recorrencia")
+		excecoes, _ := cmd.Flags().GetString("excecoes")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+
+		if strings.TrimSpace(titulo) == "" || inicio == "" || fim == "" {
+			return fmt.Errorf("erro: os campos --titulo, --inicio e --fim são obrigatórios")
+		}
+		if projetoRef != "" {
+			if _, err := projeto.BuscarProjeto(projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+
+		rejeitarConflitos, _ := cmd.Flags().GetBool("rejeitar-conflitos")
+		conflitos, err := agenda.VerificarConflitos(inicio, fim, recorrencia, excecoes)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(conflitos) > 0 && rejeitarConflitos {
+			return fmt.Errorf("erro: %w", &agenda.ErroConflito{Conflitos: conflitos})
+		}
+
+		evento, err := agenda.AdicionarEventoRecorrente(titulo, inicio, fim, descricao, local, recorrencia, excecoes)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if projetoRef != "" {
+			if _, err := projeto.AtribuirEvento(evento.ID, projetoRef); err != nil {
+				return fmt.Errorf("erro: evento '%s' adicionado, mas não foi colocado no projeto '%s': %w", evento.ID, projetoRef, err)
+			}
+		}
+		cmd.Printf("Evento '%s' adicionado com sucesso.\n", evento.ID)
+		imprimirConflitos(cmd, conflitos)
+		return nil
+	},
+}
+
+var agendaListarEventosCmd = &cobra.Command{
+	Use:   "listar-eventos",
+	Short: "Lista eventos futuros ou de um período",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		periodo, _ := cmd.Flags().GetString("periodo")
+		dataInicio, _ := cmd.Flags().GetString("data-inicio")
+		dataFim, _ := cmd.Flags().GetString("data-fim")
+		ordenarPor, _ := cmd.Flags().GetString("ordenar-por")
+		ordem, _ := cmd.Flags().GetString("ordem")
+
+		eventos, err := agenda.ListarEventos(periodo, dataInicio, dataFim, ordenarPor, ordem)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(eventos) == 0 {
+			cmd.Println("Nenhum evento encontrado para o período especificado.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Título", "Início", "Fim", "Local", "Descrição"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, e := range eventos {
+			table.Append([]string{
+				e.ID,
+				e.Title,
+				e.StartTime.Local().Format("02/01/2006 15:04"),
+				e.EndTime.Local().Format("02/01/2006 15:04"),
+				e.Location,
+				e.Description,
+			})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var agendaVerDiaCmd = &cobra.Command{
+	Use:   "ver-dia [data]",
+	Short: "Mostra os eventos de um dia (padrão: hoje)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dia := time.Now().Format("2006-01-02")
+		if len(args) == 1 {
+			dia = args[0]
+		}
+
+		eventos, err := agenda.VerDia(dia)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if d, err := datas.Data(dia); err == nil { // Exibe expressões como "amanhã" pela data correspondente.
+			dia = d.Format("2006-01-02")
+		}
+		if len(eventos) == 0 {
+			cmd.Printf("Nenhum evento agendado para %s.\n", dia)
+			return nil
+		}
+
+		cmd.Printf("Eventos para %s:\n", dia)
+		for _, e := range eventos {
+			linha := fmt.Sprintf("%s - %s: %s", e.StartTime.Local().Format("15:04"), e.EndTime.Local().Format("15:04"), e.Title)
+			if e.Location != "" {
+				linha += fmt.Sprintf(" (%s)", e.Location)
+			}
+			cmd.Printf("  %s\n", linha)
+		}
+		return nil
+	},
+}
+
+var agendaEditarEventoCmd = &cobra.Command{
+	Use:   "editar-evento <ID do evento>",
+	Short: "Edita um evento existente",
+	Long: `Edita um evento existente. Em eventos recorrentes, a edição vale para a série inteira,
+a menos que --ocorrencia seja informada:
+  --ocorrencia "YYYY-MM-DD HH:MM" --escopo ocorrencia   altera somente esta ocorrência
+  --ocorrencia "YYYY-MM-DD HH:MM" --escopo seguintes    altera esta e as ocorrências seguintes
+Use --recorrencia para definir ou alterar a regra RRULE da série ("nenhuma" remove a recorrência).
+--projeto move o evento (ou a série inteira) para um projeto; --sem-projeto o retira do projeto atual.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		titulo, _ := cmd.Flags().GetString("titulo")
+		inicio, _ := cmd.Flags().GetString("inicio")
+		fim, _ := cmd.Flags().GetString("fim")
+		descricao, _ := cmd.Flags().GetString("descricao")
+		local, _ := cmd.Flags().GetString("local")
+		recorrencia, _ := cmd.Flags().GetString("recorrencia")
+		ocorrencia, _ := cmd.Flags().GetString("ocorrencia")
+		escopo, _ := cmd.Flags().GetString("escopo")
+		rejeitarConflitos, _ := cmd.Flags().GetBool("rejeitar-conflitos")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+		semProjeto, _ := cmd.Flags().GetBool("sem-projeto")
+
+		if ocorrencia != "" && recorrencia != "" {
+			return fmt.Errorf("erro: --recorrencia altera a série inteira e não pode ser usada com --ocorrencia")
+		}
+		mudarProjeto := projetoRef != "" || semProjeto
+		if projetoRef != "" && semProjeto {
+			return fmt.Errorf("erro: use --projeto ou --sem-projeto, não ambos")
+		}
+		if ocorrencia != "" && mudarProjeto {
+			return fmt.Errorf("erro: o projeto vale para a série inteira e não pode ser alterado com --ocorrencia")
+		}
+
+		// Só mudanças de horário ou de recorrência podem criar conflitos.
+		var conflitos []agenda.Conflito
+		if inicio != "" || fim != "" || recorrencia != "" {
+			var err error
+			conflitos, err = agenda.VerificarConflitosEdicao(id, inicio, fim, recorrencia, ocorrencia, escopo)
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			if len(conflitos) > 0 && rejeitarConflitos {
+				return fmt.Errorf("erro: %w", &agenda.ErroConflito{Conflitos: conflitos})
+			}
+		}
+
+		if ocorrencia != "" {
+			var evento models.Event
+			var err error
+			switch escopo {
+			case "ocorrencia":
+				evento, err = agenda.EditarOcorrencia(id, ocorrencia, titulo, inicio, fim, descricao, local)
+			case "seguintes":
+				evento, err = agenda.EditarOcorrenciasSeguintes(id, ocorrencia, titulo, inicio, fim, descricao, local)
+			default:
+				return fmt.Errorf("erro: escopo '%s' inválido. Use ocorrencia ou seguintes", escopo)
+			}
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cmd.Printf("Ocorrência de %s atualizada com sucesso (evento '%s').\n", ocorrencia, evento.ID)
+			imprimirConflitos(cmd, conflitos)
+			return nil
+		}
+
+		if mudarProjeto {
+			if _, err := projeto.AtribuirEvento(id, projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		if recorrencia != "" {
+			if _, err := agenda.EditarRecorrencia(id, recorrencia); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		if (mudarProjeto || recorrencia != "") && titulo == "" && inicio == "" && fim == "" && descricao == "" && local == "" {
+			cmd.Printf("Evento '%s' atualizado com sucesso.\n", id)
+			imprimirConflitos(cmd, conflitos)
+			return nil
+		}
+
+		evento, err := agenda.EditarEvento(id, titulo, inicio, fim, descricao, local)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Evento '%s' atualizado com sucesso.\n", evento.ID)
+		imprimirConflitos(cmd, conflitos)
+		return nil
+	},
+}
+
+// maxConflitosExibidos limita a lista de conflitos impressa após adicionar ou editar um evento.
+const maxConflitosExibidos = 10
+
+// imprimirConflitos avisa sobre os eventos já agendados que se sobrepõem ao evento gravado.
+func imprimirConflitos(cmd *cobra.Command, conflitos []agenda.Conflito) {
+	if len(conflitos) == 0 {
+		return
+	}
+	cmd.Printf("Aviso: o evento conflita com %d evento(s) já agendado(s):\n", len(conflitos))
+	for i, c := range conflitos {
+		if i == maxConflitosExibidos {
+			cmd.Printf("  ... e mais %d conflito(s).\n", len(conflitos)-maxConflitosExibidos)
+			break
+		}
+		cmd.Printf("  %s: %s\n", c.Inicio.Format("2006-01-02 15:04"), c)
+	}
+}
+
+var agendaRemoverEventoCmd = &cobra.Command{
+	Use:   "remover-evento <ID do evento>",
+	Short: "Remove um evento da agenda",
+	Long: `Remove um evento da agenda. Para um evento recorrente, remove a série inteira,
+ou apenas uma ocorrência quando --ocorrencia "YYYY-MM-DD HH:MM" é informada.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+		ocorrencia, _ := cmd.Flags().GetString("ocorrencia")
+
+		evento, err := agenda.GetEventoByID(id)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		if !force {
+			mensagem := fmt.Sprintf("Tem certeza que deseja remover o evento '%s' (ID: %s)?", evento.Title, evento.ID)
+			if ocorrencia != "" {
+				mensagem = fmt.Sprintf("Tem certeza que deseja remover a ocorrência de %s do evento '%s' (ID: %s)?", ocorrencia, evento.Title, evento.ID)
+			} else if evento.RecurrenceRule != "" {
+				mensagem = fmt.Sprintf("Tem certeza que deseja remover todas as ocorrências do evento recorrente '%s' (ID: %s)?", evento.Title, evento.ID)
+			}
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: mensagem,
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
+
+		if ocorrencia != "" {
+			if err := agenda.RemoverOcorrencia(id, ocorrencia); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cmd.Printf("Ocorrência de %s do evento '%s' removida com sucesso.\n", ocorrencia, id)
+			return nil
+		}
+		if err := agenda.RemoverEvento(id); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Evento '%s' removido com sucesso.\n", id)
+		return nil
+	},
+}
+
+var agendaExportarCmd = &cobra.Command{
+	Use:   "exportar",
+	Short: "Exporta os eventos da agenda para um arquivo iCalendar (.ics)",
+	Long: `Exporta todos os eventos da agenda, incluindo séries recorrentes, para um arquivo iCalendar (.ics)
+que pode ser importado no Google Agenda, Outlook ou outro aplicativo de calendário.
+Exemplo: vickgenda agenda exportar --ics agenda.ics`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caminho, _ := cmd.Flags().GetString("ics")
+		if caminho == "" {
+			return fmt.Errorf("erro: o caminho do arquivo deve ser informado com --ics")
+		}
+
+		arquivo, err := os.Create(caminho)
+		if err != nil {
+			return fmt.Errorf("erro ao criar o arquivo '%s': %w", caminho, err)
+		}
+		total, err := agenda.ExportarICS(arquivo)
+		if errFechar := arquivo.Close(); err == nil {
+			err = errFechar
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("%d evento(s) exportado(s) para '%s'.\n", total, caminho)
+		return nil
+	},
+}
+
+var agendaImportarCmd = &cobra.Command{
+	Use:   "importar <arquivo.ics>",
+	Short: "Importa eventos de um arquivo iCalendar (.ics)",
+	Long: `Importa os eventos (VEVENT) de um arquivo iCalendar (.ics) para a agenda.
+Eventos são identificados pelo UID: reimportar um calendário atualizado altera os eventos já importados em vez de duplicá-los.
+Eventos com recursos não suportados (ex: RRULE com BYSETPOS) são ignorados e listados ao final.
+Exemplo: vickgenda agenda importar calendario-escolar.ics`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arquivo, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("erro ao abrir o arquivo '%s': %w", args[0], err)
+		}
+		defer arquivo.Close()
+
+		resultado, err := agenda.ImportarICS(arquivo)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Importação concluída: %d criado(s), %d atualizado(s), %d ignorado(s).\n",
+			resultado.Criados, resultado.Atualizados, resultado.Ignorados)
+		for _, aviso := range resultado.Avisos {
+			cmd.Printf("  Aviso: %s\n", aviso)
+		}
+		return nil
+	},
+}
+
+var agendaLivreCmd = &cobra.Command{
+	Use:   "livre",
+	Short: "Mostra os horários livres de um período",
+	Long: `Mostra os intervalos sem eventos nem aulas em cada dia de um período, dentro de uma janela diária.
+Útil para encontrar horários para aulas de reposição ou reuniões com os pais.
+Exemplo: vickgenda agenda livre --de 2024-08-01 --ate 2024-08-07 --duracao 50m --entre 07:00-18:00`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		de, _ := cmd.Flags().GetString("de")
+		ate, _ := cmd.Flags().GetString("ate")
+		duracao, _ := cmd.Flags().GetString("duracao")
+		entre, _ := cmd.Flags().GetString("entre")
+		duracaoAula, _ := cmd.Flags().GetString("duracao-aula")
+
+		if de == "" {
+			de = time.Now().Format("2006-01-02")
+		}
+		if ate == "" {
+			inicio, err := datas.Data(de)
+			if err != nil {
+				return fmt.Errorf("erro: formato de data inválido para --de: %w", err)
+			}
+			ate = inicio.AddDate(0, 0, 6).Format("2006-01-02")
+		}
+
+		livres, err := agenda.BuscarHorariosLivres(de, ate, duracao, entre, duracaoAula)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(livres) == 0 {
+			cmd.Printf("Nenhum horário livre de pelo menos %s entre %s e %s.\n", duracao, de, ate)
+			return nil
+		}
+
+		cmd.Printf("Horários livres de pelo menos %s (%s), de %s a %s:\n", duracao, entre, de, ate)
+		diaAtual := ""
+		var intervalos []string
+		imprimirDia := func() {
+			if diaAtual != "" {
+				cmd.Printf("  %s: %s\n", diaAtual, strings.Join(intervalos, ", "))
+			}
+		}
+		for _, l := range livres {
+			dia := l.Inicio.Format("2006-01-02") + " (" + diasDaSemana[l.Inicio.Weekday()] + ")"
+			if dia != diaAtual {
+				imprimirDia()
+				diaAtual, intervalos = dia, nil
+			}
+			intervalos = append(intervalos, l.Inicio.Format("15:04")+"-"+l.Fim.Format("15:04"))
+		}
+		imprimirDia()
+		return nil
+	},
+}
+
+var agendaSemanaCmd = &cobra.Command{
+	Use:   "semana [data]",
+	Short: "Mostra a semana em uma grade de horários",
+	Long: `Mostra a semana (segunda a domingo) que contém a data informada, ou a semana atual, em uma grade
+com as horas nas linhas e os dias nas colunas. Eventos, aulas e prazos de tarefas aparecem com marcadores
+e cores distintos; compromissos sobrepostos ficam lado a lado.
+Fora de um terminal (ex: redirecionando para um arquivo), a grade usa apenas caracteres ASCII.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataStr := ""
+		if len(args) > 0 {
+			dataStr = args[0]
+		}
+		inicio, err := agenda.InicioDaSemana(dataStr)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		opcoes, itens, err := dadosDaVisao(cmd, inicio, inicio.AddDate(0, 0, 7))
+		if err != nil {
+			return err
+		}
+		cmd.Println(components.RenderSemana(inicio, itens, opcoes))
+		return nil
+	},
+}
+
+var agendaMesCmd = &cobra.Command{
+	Use:   "mes [YYYY-MM]",
+	Short: "Mostra o mês em uma grade de calendário",
+	Long: `Mostra o mês informado, ou o mês atual, em uma grade de semanas com os compromissos de cada dia.
+Eventos, aulas e prazos de tarefas aparecem com marcadores e cores distintos.
+Fora de um terminal (ex: redirecionando para um arquivo), a grade usa apenas caracteres ASCII.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mesStr := ""
+		if len(args) > 0 {
+			mesStr = args[0]
+		}
+		inicio, err := agenda.InicioDoMes(mesStr)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		// A grade mostra semanas completas, então inclui os dias vizinhos ao mês.
+		opcoes, itens, err := dadosDaVisao(cmd, inicio.AddDate(0, 0, -7), inicio.AddDate(0, 1, 7))
+		if err != nil {
+			return err
+		}
+		cmd.Println(components.RenderMes(inicio, itens, opcoes))
+		return nil
+	},
+}
+
+// dadosDaVisao lê as flags comuns de 'agenda semana' e 'agenda mes' e busca os compromissos de [inicio, fim).
+func dadosDaVisao(cmd *cobra.Command, inicio, fim time.Time) (components.OpcoesCalendario, []components.ItemCalendario, error) {
+	duracaoAulaStr, _ := cmd.Flags().GetString("duracao-aula")
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	largura, _ := cmd.Flags().GetInt("largura")
+
+	duracaoAula, err := time.ParseDuration(duracaoAulaStr)
+	if err != nil || duracaoAula <= 0 {
+		return components.OpcoesCalendario{}, nil, fmt.Errorf("erro: duração de aula '%s' inválida. Use, por exemplo, 50m", duracaoAulaStr)
+	}
+	opcoes := components.OpcoesCalendario{
+		ASCII:      ascii || !saidaEhTerminal(cmd),
+		LarguraDia: largura,
+		Hoje:       time.Now(),
+	}
+	if cmd.Flags().Lookup("hora-inicio") != nil {
+		opcoes.HoraInicio, _ = cmd.Flags().GetInt("hora-inicio")
+		opcoes.HoraFim, _ = cmd.Flags().GetInt("hora-fim")
+		if opcoes.HoraInicio < 0 || opcoes.HoraFim > 24 || opcoes.HoraFim <= opcoes.HoraInicio {
+			return components.OpcoesCalendario{}, nil, fmt.Errorf("erro: faixa de horas %d-%d inválida", opcoes.HoraInicio, opcoes.HoraFim)
+		}
+	}
+
+	itensAgenda, err := agenda.ItensDoPeriodo(inicio, fim, duracaoAula)
+	if err != nil {
+		return components.OpcoesCalendario{}, nil, fmt.Errorf("erro: %w", err)
+	}
+	tipos := map[string]components.TipoItemCalendario{
+		agenda.TipoEvento:    components.ItemEvento,
+		agenda.TipoAula:      components.ItemAula,
+		agenda.TipoTarefa:    components.ItemTarefa,
+		agenda.TipoNaoLetivo: components.ItemNaoLetivo,
+	}
+	var itens []components.ItemCalendario
+	for _, item := range itensAgenda {
+		itens = append(itens, components.ItemCalendario{Tipo: tipos[item.Tipo], Titulo: item.Titulo, Inicio: item.Inicio, Fim: item.Fim})
+	}
+	return opcoes, itens, nil
+}
+
+// saidaEhTerminal informa se o comando escreve diretamente em um terminal, onde cores e bordas Unicode são seguras.
+func saidaEhTerminal(cmd *cobra.Command) bool {
+	saida, ok := cmd.OutOrStdout().(*os.File)
+	return ok && term.IsTerminal(saida.Fd())
+}
+
+// diasDaSemana abrevia os dias da semana em português, na ordem de time.Weekday.
+var diasDaSemana = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+func init() {
+	// rootCmd.AddCommand(AgendaCmd) // This will be done in cmd/cli/cli.go
+
+	agendaAdicionarEventoCmd.Flags().String("titulo", "", "Título do evento (obrigatório)")
+	agendaAdicionarEventoCmd.Flags().String("inicio", "", "Data e hora de início (ex: \"2024-08-15 14:00\", \"amanhã 14h\"; obrigatório)")
+	agendaAdicionarEventoCmd.Flags().String("fim", "", "Data e hora de término (ex: \"2024-08-15 15:00\", \"amanhã 15h\"; obrigatório)")
+	agendaAdicionarEventoCmd.Flags().String("descricao", "", "Descrição detalhada do evento")
+	agendaAdicionarEventoCmd.Flags().String("local", "", "Local do evento")
+	agendaAdicionarEventoCmd.Flags().String("recorrencia", "", "Regra de recorrência RRULE (ex: \"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10\")")
+	agendaAdicionarEventoCmd.Flags().String("excecoes", "", "Ocorrências a excluir da série, separadas por vírgula (YYYY-MM-DD HH:MM)")
+	agendaAdicionarEventoCmd.Flags().String("projeto", "", "Nome ou ID do projeto do evento")
+
+	agendaAdicionarEventoCmd.Flags().Bool("rejeitar-conflitos", false, "Não adiciona o evento se ele se sobrepuser a outro já agendado")
+
+	agendaListarEventosCmd.Flags().String("periodo", "proximos", "Período: dia, semana, mes ou proximos")
+	agendaListarEventosCmd.Flags().String("data-inicio", "", "Data de início de um período customizado ("+datas.Exemplos+"). Requer --data-fim")
+	agendaListarEventosCmd.Flags().String("data-fim", "", "Data de fim de um período customizado ("+datas.Exemplos+"). Requer --data-inicio")
+	agendaListarEventosCmd.Flags().String("ordenar-por", "inicio", "Campo de ordenação: inicio, fim, titulo, local")
+	agendaListarEventosCmd.Flags().String("ordem", "asc", "Ordem de classificação: asc ou desc")
+
+	agendaEditarEventoCmd.Flags().String("titulo", "", "Novo título")
+	agendaEditarEventoCmd.Flags().String("inicio", "", "Nova data e hora de início (ex: \"2024-08-15 14:00\", \"sexta 10h\")")
+	agendaEditarEventoCmd.Flags().String("fim", "", "Nova data e hora de término (ex: \"2024-08-15 15:00\", \"sexta 11h\")")
+	agendaEditarEventoCmd.Flags().String("descricao", "", "Nova descrição")
+	agendaEditarEventoCmd.Flags().String("local", "", "Novo local")
+	agendaEditarEventoCmd.Flags().String("recorrencia", "", "Nova regra RRULE da série (\"nenhuma\" remove a recorrência)")
+	agendaEditarEventoCmd.Flags().String("ocorrencia", "", "Início da ocorrência a editar em um evento recorrente (YYYY-MM-DD HH:MM)")
+	agendaEditarEventoCmd.Flags().String("escopo", "ocorrencia", "Com --ocorrencia: 'ocorrencia' (somente esta) ou 'seguintes' (esta e as seguintes)")
+	agendaEditarEventoCmd.Flags().String("projeto", "", "Nome ou ID do novo projeto do evento (vale para a série inteira)")
+	agendaEditarEventoCmd.Flags().Bool("sem-projeto", false, "Retira o evento (ou a série) do projeto")
+
+	agendaEditarEventoCmd.Flags().Bool("rejeitar-conflitos", false, "Não salva a edição se o evento passar a se sobrepor a outro já agendado")
+
+	agendaRemoverEventoCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
+	agendaRemoverEventoCmd.Flags().String("ocorrencia", "", "Remove apenas a ocorrência com este início em um evento recorrente (YYYY-MM-DD HH:MM)")
+
+	agendaLivreCmd.Flags().String("de", "", "Primeiro dia da busca ("+datas.Exemplos+"). Padrão: hoje")
+	agendaLivreCmd.Flags().String("ate", "", "Último dia da busca ("+datas.Exemplos+"). Padrão: 6 dias após --de")
+	agendaLivreCmd.Flags().String("duracao", "50m", "Duração mínima dos horários livres (ex: 50m, 1h30m)")
+	agendaLivreCmd.Flags().String("entre", "07:00-18:00", "Janela diária pesquisada (HH:MM-HH:MM)")
+	agendaLivreCmd.Flags().String("duracao-aula", "50m", "Duração considerada para cada aula registrada")
+
+	for _, visao := range []*cobra.Command{agendaSemanaCmd, agendaMesCmd} {
+		visao.Flags().String("duracao-aula", "50m", "Duração considerada para cada aula registrada")
+		visao.Flags().Bool("ascii", false, "Força a saída sem cores e com bordas ASCII")
+		visao.Flags().Int("largura", 18, "Largura de cada coluna de dia")
+	}
+	agendaSemanaCmd.Flags().Int("hora-inicio", 7, "Primeira hora exibida na grade")
+	agendaSemanaCmd.Flags().Int("hora-fim", 19, "Hora em que a grade termina")
+
+	agendaExportarCmd.Flags().String("ics", "", "Caminho do arquivo .ics a ser gerado (obrigatório)")
+
+	AgendaCmd.AddCommand(agendaAdicionarEventoCmd)
+	AgendaCmd.AddCommand(agendaListarEventosCmd)
+	AgendaCmd.AddCommand(agendaVerDiaCmd)
+	AgendaCmd.AddCommand(agendaEditarEventoCmd)
+	AgendaCmd.AddCommand(agendaRemoverEventoCmd)
+	AgendaCmd.AddCommand(agendaLivreCmd)
+	AgendaCmd.AddCommand(agendaSemanaCmd)
+	AgendaCmd.AddCommand(agendaMesCmd)
+	AgendaCmd.AddCommand(agendaExportarCmd)
+	AgendaCmd.AddCommand(agendaImportarCmd)
+}
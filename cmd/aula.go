@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// AulaCmd represents the aula command
+var AulaCmd = &cobra.Command{
+	Use:   "aula",
+	Short: "Consulta as aulas e as tarefas vinculadas a elas",
+	Long: `O comando 'aula' consulta as aulas gravadas, como as geradas por 'horario gerar'.
+'aula ver' mostra a aula com as tarefas abertas vinculadas a ela ou à sua turma (veja 'tarefa vincular').`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var aulaListarCmd = &cobra.Command{
+	Use:     "listar",
+	Aliases: []string{"ls"},
+	Short:   "Lista as aulas, com filtros opcionais",
+	Long: `Lista as aulas em ordem de data.
+Exemplo: vickgenda aula listar --turma 7B --periodo hoje:"fim do mês"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		disciplina, _ := cmd.Flags().GetString("disciplina")
+		turma, _ := cmd.Flags().GetString("turma")
+		periodo, _ := cmd.Flags().GetString("periodo")
+		mes, _ := cmd.Flags().GetString("mes")
+		ano, _ := cmd.Flags().GetString("ano")
+
+		aulas, err := store.NewSQLiteAulaStore(db.GetDB()).ListLessons(disciplina, turma, periodo, mes, ano)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(aulas) == 0 {
+			cmd.Println("Nenhuma aula encontrada com os filtros especificados.")
+			return nil
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Data", "Disciplina", "Tópico", "Turma"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, a := range aulas {
+			table.Append([]string{a.ID, a.Date.Local().Format("02-01-2006 15:04"), a.Subject, a.Topic, a.ClassID})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var aulaVerCmd = &cobra.Command{
+	Use:   "ver <id_aula>",
+	Short: "Mostra uma aula com as suas tarefas abertas",
+	Long: `Mostra os detalhes de uma aula, as tarefas abertas vinculadas a ela (aula:<id_aula>)
+e as tarefas abertas vinculadas à sua turma (turma:<turma>).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aula, err := store.NewSQLiteAulaStore(db.GetDB()).GetLessonByID(args[0])
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("erro: aula com ID '%s' não encontrada", args[0])
+			}
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		fmt.Printf("ID: %s\n", aula.ID)
+		fmt.Printf("Disciplina: %s\n", aula.Subject)
+		fmt.Printf("Tópico: %s\n", aula.Topic)
+		fmt.Printf("Data: %s\n", aula.Date.Local().Format("02-01-2006 15:04"))
+		fmt.Printf("Turma: %s\n", aula.ClassID)
+		if aula.Plan != "" {
+			fmt.Printf("Plano de Aula:\n-------------\n%s\n", aula.Plan)
+		}
+		if aula.Observations != "" {
+			fmt.Printf("Observações:\n-----------\n%s\n", aula.Observations)
+		}
+
+		if err := imprimirTarefasVinculadas("Tarefas abertas da aula:", models.TaskLinkLesson, aula.ID); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if aula.ClassID != "" {
+			titulo := fmt.Sprintf("Tarefas abertas da turma %s:", aula.ClassID)
+			if err := imprimirTarefasVinculadas(titulo, models.TaskLinkClass, aula.ClassID); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		return nil
+	},
+}
+
+// imprimirTarefasVinculadas mostra, abaixo do título, a tabela das tarefas abertas vinculadas à entidade.
+func imprimirTarefasVinculadas(titulo, tipo, entidadeID string) error {
+	tarefas, err := tarefa.TarefasVinculadas(tipo, entidadeID, true)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n" + titulo)
+	if len(tarefas) == 0 {
+		fmt.Println("  Nenhuma tarefa aberta.")
+		return nil
+	}
+	bloqueios, err := tarefa.CarregarBloqueios()
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Descrição", "Prazo", "Prioridade", "Status"})
+	table.SetBorder(true)
+	table.SetAutoWrapText(false)
+	for _, t := range tarefas {
+		prazo := "-"
+		if !t.DueDate.IsZero() {
+			prazo = t.DueDate.Format("02/01/2006")
+		}
+		table.Append([]string{t.ID, t.Description, prazo, strconv.Itoa(t.Priority), bloqueios.StatusEfetivo(t)})
+	}
+	table.Render()
+	return nil
+}
+
+func init() {
+	// rootCmd.AddCommand(AulaCmd) // This will be done in cmd/cli/cli.go
+
+	aulaListarCmd.Flags().String("disciplina", "", "Filtra pela disciplina")
+	aulaListarCmd.Flags().String("turma", "", "Filtra pela turma")
+	aulaListarCmd.Flags().String("periodo", "", "Filtra pelo período início:fim (ex: hoje:sexta, 01-08-2024:31-08-2024)")
+	aulaListarCmd.Flags().String("mes", "", "Filtra pelo mês (mm-aaaa)")
+	aulaListarCmd.Flags().String("ano", "", "Filtra pelo ano (aaaa)")
+
+	AulaCmd.AddCommand(aulaListarCmd)
+	AulaCmd.AddCommand(aulaVerCmd)
+}
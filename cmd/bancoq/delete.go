@@ -20,8 +20,9 @@ import (
 var bancoqDeleteCmd = &cobra.Command{
 	Use:   "delete <ID_DA_QUESTAO>",
 	Short: "Remove uma questão do banco de dados",
-	Long: `Remove permanentemente uma questão específica do banco de dados, utilizando o seu ID.
+	Long: `Remove uma questão específica do banco de dados, utilizando o seu ID.
 Por padrão, solicita confirmação antes de excluir. Use a flag --force para pular a confirmação.
+Uma remoção feita por engano pode ser revertida com 'vickgenda desfazer'.
 Exemplo:
   vickgenda bancoq delete 123e4567-e89b-12d3-a456-426614174000
   vickgenda bancoq delete 123e4567-e89b-12d3-a456-426614174000 --force`,
@@ -76,9 +77,9 @@ func runDeleteQuestion(cmd *cobra.Command, args []string) {
 		}
 
 		confirmPrompt := &survey.Confirm{
-			Message: fmt.Sprintf("Tem certeza que deseja remover permanentemente a questão %s?", questionPreviewMsg),
+			Message: fmt.Sprintf("Tem certeza que deseja remover a questão %s?", questionPreviewMsg),
 			Default: false,
-			Help:    "Esta ação é irreversível.",
+			Help:    "A remoção pode ser revertida com 'vickgenda desfazer'.",
 		}
 		// Reatribuir err para o erro do survey.AskOne
 		err = survey.AskOne(confirmPrompt, &confirmed)
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/datas"
+)
+
+// CalendarioCmd represents the calendario command
+var CalendarioCmd = &cobra.Command{
+	Use:   "calendario",
+	Short: "Gerencia o calendário escolar: feriados, recessos e dias sem aula",
+	Long: `O comando 'calendario' registra os dias não letivos do ano letivo: feriados, recessos e dias de planejamento.
+Esses dias não recebem aulas em 'horario gerar', não aparecem como livres em 'agenda livre',
+são exibidos em 'agenda semana' e 'agenda mes' e adiam para o próximo dia letivo as tarefas geradas por 'rotina'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var calendarioAdicionarCmd = &cobra.Command{
+	Use:   "adicionar <nome>",
+	Short: "Adiciona um dia ou período não letivo",
+	Long: `Adiciona um dia ou período não letivo ao calendário escolar.
+Exemplos:
+  vickgenda calendario adicionar "Tiradentes" --data 2024-04-21
+  vickgenda calendario adicionar "Recesso de julho" --data 2024-07-15 --fim 2024-07-26 --tipo recesso`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, _ := cmd.Flags().GetString("data")
+		fim, _ := cmd.Flags().GetString("fim")
+		tipo, _ := cmd.Flags().GetString("tipo")
+
+		dia, err := calendario.AdicionarDiaNaoLetivo(args[0], data, fim, tipo)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Dia não letivo '%s' adicionado: %s (%s, %s).\n", dia.ID, dia.Name, periodoNaoLetivo(dia.StartDate, dia.EndDate), dia.Kind)
+		return nil
+	},
+}
+
+var calendarioListarCmd = &cobra.Command{
+	Use:   "listar",
+	Short: "Lista os dias não letivos",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ano, _ := cmd.Flags().GetInt("ano")
+
+		dias, err := calendario.ListarDiasNaoLetivos(ano)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(dias) == 0 {
+			cmd.Println("Nenhum dia não letivo cadastrado.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Ano Letivo", "Período", "Nome", "Tipo"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, d := range dias {
+			table.Append([]string{d.ID, d.AcademicYear, periodoNaoLetivo(d.StartDate, d.EndDate), d.Name, d.Kind})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var calendarioRemoverCmd = &cobra.Command{
+	Use:   "remover <ID do dia não letivo>",
+	Short: "Remove um dia não letivo do calendário",
+	Long: `Remove um dia não letivo do calendário.
+As aulas do período só são criadas na próxima execução de 'horario gerar'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+
+		if !force {
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Tem certeza que deseja remover o dia não letivo '%s'?", id),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
+
+		if err := calendario.RemoverDiaNaoLetivo(id); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Dia não letivo '%s' removido.\n", id)
+		return nil
+	},
+}
+
+var calendarioImportarCmd = &cobra.Command{
+	Use:   "importar <arquivo.csv|arquivo.ics>",
+	Short: "Importa uma lista de feriados em CSV ou iCalendar (.ics)",
+	Long: `Importa uma lista de feriados para o calendário escolar.
+Arquivos .ics têm cada VEVENT importado como feriado. Nos demais, cada linha do CSV (separado por vírgula ou
+ponto e vírgula) tem "data,nome[,tipo]" ou "inicio,fim,nome[,tipo]", com datas em YYYY-MM-DD ou DD/MM/YYYY.
+Dias já cadastrados com o mesmo nome e as mesmas datas são ignorados, então reimportar um arquivo não duplica nada.
+Exemplo: vickgenda calendario importar feriados-2024.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arquivo, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("erro ao abrir o arquivo '%s': %w", args[0], err)
+		}
+		defer arquivo.Close()
+
+		var resultado calendario.ResultadoImportacao
+		if strings.EqualFold(filepath.Ext(args[0]), ".ics") {
+			resultado, err = calendario.ImportarICS(arquivo)
+		} else {
+			resultado, err = calendario.ImportarCSV(arquivo)
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Importação concluída: %d criado(s), %d ignorado(s).\n", resultado.Criados, resultado.Ignorados)
+		for _, aviso := range resultado.Avisos {
+			cmd.Printf("  Aviso: %s\n", aviso)
+		}
+		return nil
+	},
+}
+
+// periodoNaoLetivo formata as datas de um dia não letivo como "21/04/2024" ou "15/07/2024 a 26/07/2024".
+func periodoNaoLetivo(inicio, fim time.Time) string {
+	if inicio.Format("2006-01-02") == fim.Format("2006-01-02") {
+		return inicio.Format("02/01/2006")
+	}
+	return inicio.Format("02/01/2006") + " a " + fim.Format("02/01/2006")
+}
+
+func init() {
+	// rootCmd.AddCommand(CalendarioCmd) // This will be done in cmd/cli/cli.go
+
+	calendarioAdicionarCmd.Flags().String("data", "", "Data do dia não letivo ou início do período ("+datas.Exemplos+") (obrigatório)")
+	calendarioAdicionarCmd.Flags().String("fim", "", "Último dia do período, inclusivo (padrão: o próprio --data)")
+	calendarioAdicionarCmd.Flags().String("tipo", "feriado", "Tipo: feriado, recesso ou planejamento")
+	calendarioAdicionarCmd.MarkFlagRequired("data")
+
+	calendarioListarCmd.Flags().Int("ano", 0, "Filtra pelo ano letivo (ex: 2024)")
+
+	calendarioRemoverCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
+
+	CalendarioCmd.AddCommand(calendarioAdicionarCmd)
+	CalendarioCmd.AddCommand(calendarioListarCmd)
+	CalendarioCmd.AddCommand(calendarioRemoverCmd)
+	CalendarioCmd.AddCommand(calendarioImportarCmd)
+}
@@ -213,8 +213,16 @@ func SetupRootCmd() {
 	rootCmd.AddCommand(cmd.TarefaCmd)
 	rootCmd.AddCommand(cmd.AgendaCmd)
 	rootCmd.AddCommand(cmd.RotinaCmd)
+	rootCmd.AddCommand(cmd.ProjetoCmd)
+	rootCmd.AddCommand(cmd.DaemonCmd)
+	rootCmd.AddCommand(cmd.HorarioCmd)
+	rootCmd.AddCommand(cmd.CalendarioCmd)
 	rootCmd.AddCommand(cmd.AulaCmd)
 	rootCmd.AddCommand(cmd.NotasCmd)
+	rootCmd.AddCommand(cmd.DbCmd)
+	rootCmd.AddCommand(cmd.HistoricoCmd)
+	rootCmd.AddCommand(cmd.DesfazerCmd)
+	rootCmd.AddCommand(cmd.RefazerCmd)
 	// DashboardCmd, RelembrarCmd, FocoCmd, RelatorioCmd are added via squad4.InitSquad4Commands
 
 	// Squad 5 commands (bancoq.BancoqCmd, prova.ProvaCmd) are added in init()
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/rotina"
+	"vickgenda-cli/internal/db"
+)
+
+// DaemonCmd represents the daemon command
+var DaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Mantém o agendador de rotinas rodando em primeiro plano",
+	Long: `Executa 'rotina executar-pendentes' ao iniciar e depois a cada --intervalo, até receber Ctrl+C ou SIGTERM.
+É uma alternativa ao cron ou ao timer do systemd; pode rodar junto com eles, pois duas execuções
+simultâneas nunca geram as mesmas tarefas.
+Exemplo: vickgenda daemon --intervalo 5m`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{AnotacaoHistoricoPorAcao: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		intervalo, _ := cmd.Flags().GetDuration("intervalo")
+		if intervalo < time.Second {
+			return fmt.Errorf("erro: intervalo '%s' inválido. Use, por exemplo, 1m ou 30s", intervalo)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		cmd.Printf("Agendador de rotinas iniciado; verificando a cada %s. Use Ctrl+C para encerrar.\n", intervalo)
+		ticker := time.NewTicker(intervalo)
+		defer ticker.Stop()
+		for {
+			executarRotinasDoDaemon(cmd)
+			select {
+			case <-ctx.Done():
+				cmd.Println("Agendador de rotinas encerrado.")
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// executarRotinasDoDaemon faz uma execução do agendador. Erros são exibidos, mas não encerram o daemon.
+// Cada execução é registrada como uma operação do histórico, que 'desfazer' pode reverter.
+func executarRotinasDoDaemon(cmd *cobra.Command) {
+	agora := time.Now().Format("02/01/2006 15:04:05")
+	marca, errMarca := db.JournalMark()
+	resultado, err := rotina.ExecutarPendentes()
+	if errMarca == nil {
+		if _, errOp := db.RecordOperation("daemon: rotina executar-pendentes", marca); errOp != nil {
+			cmd.PrintErrf("[%s] Aviso: a execução não foi registrada no histórico: %v\n", agora, errOp)
+		}
+	}
+	switch {
+	case errors.Is(err, rotina.ErrExecucaoEmAndamento):
+		// Outra instância (ex: o cron) está executando; tenta de novo no próximo ciclo.
+	case err != nil:
+		cmd.PrintErrf("[%s] Erro: %v\n", agora, err)
+	case len(resultado.Execucoes) > 0:
+		cmd.Printf("[%s]\n", agora)
+		imprimirExecucoesRotina(cmd, resultado)
+	}
+}
+
+func init() {
+	// rootCmd.AddCommand(DaemonCmd) // This will be done in cmd/cli/cli.go
+
+	DaemonCmd.Flags().Duration("intervalo", time.Minute, "Intervalo entre as verificações das rotinas (ex: 30s, 5m)")
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/tui/components"
+)
+
+// DbCmd agrupa os comandos de manutenção do banco de dados.
+// O main.go abre o banco sem aplicar migrações quando este comando é usado,
+// para que 'migrate down' não seja desfeito automaticamente na mesma execução.
+var DbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manutenção do banco de dados",
+	Long:  `Comandos para inspecionar e gerenciar o banco de dados local do Vickgenda.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if db.GetDB() != nil {
+			return nil
+		}
+		if err := db.OpenDB(""); err != nil {
+			return fmt.Errorf("falha ao abrir o banco de dados: %w", err)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Gerencia as migrações do esquema do banco de dados",
+	Long: `Gerencia as migrações versionadas do esquema do banco de dados.
+As migrações pendentes são aplicadas automaticamente ao iniciar o Vickgenda;
+use estes subcomandos para consultar ou ajustar a versão do esquema manualmente.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Mostra as migrações aplicadas e pendentes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		states, err := db.MigrationStatus(db.GetDB())
+		if err != nil {
+			return fmt.Errorf("erro ao consultar o estado das migrações: %w", err)
+		}
+
+		headers := []string{"Versão", "Nome", "Estado", "Aplicada em"}
+		var rows [][]string
+		current := 0
+		for _, s := range states {
+			estado := "Pendente"
+			aplicadaEm := "-"
+			if s.Applied {
+				estado = "Aplicada"
+				aplicadaEm = s.AppliedAt.Local().Format("02/01/2006 15:04")
+				current = s.Version
+			}
+			rows = append(rows, []string{strconv.Itoa(s.Version), s.Name, estado, aplicadaEm})
+		}
+		cmd.Print(components.RenderTable(headers, rows))
+		cmd.Printf("\nVersão atual do esquema: %d (mais recente disponível: %d)\n", current, db.LatestSchemaVersion())
+		return nil
+	},
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Aplica migrações pendentes",
+	Long: `Aplica as migrações pendentes em ordem crescente de versão.
+Por padrão todas as pendentes são aplicadas; use --passos para limitar a quantidade.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passos, _ := cmd.Flags().GetInt("passos")
+		if passos < 0 {
+			return fmt.Errorf("--passos não pode ser negativo")
+		}
+		applied, err := db.MigrateUp(db.GetDB(), passos)
+		for _, m := range applied {
+			cmd.Printf("Migração %d (%s) aplicada.\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao aplicar migrações: %w", err)
+		}
+		if len(applied) == 0 {
+			cmd.Println("Nenhuma migração pendente. O esquema já está atualizado.")
+		}
+		return nil
+	},
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Reverte as migrações mais recentes",
+	Long: `Reverte migrações aplicadas, começando pela mais recente.
+Por padrão apenas uma migração é revertida; use --passos para reverter mais.
+Atenção: reverter a migração inicial remove as tabelas e todos os seus dados.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passos, _ := cmd.Flags().GetInt("passos")
+		if passos < 1 {
+			return fmt.Errorf("--passos deve ser maior ou igual a 1")
+		}
+		reverted, err := db.MigrateDown(db.GetDB(), passos)
+		for _, m := range reverted {
+			cmd.Printf("Migração %d (%s) revertida.\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao reverter migrações: %w", err)
+		}
+		if len(reverted) == 0 {
+			cmd.Println("Nenhuma migração aplicada para reverter.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbMigrateUpCmd.Flags().Int("passos", 0, "Número máximo de migrações a aplicar (0 = todas)")
+	dbMigrateDownCmd.Flags().Int("passos", 1, "Número de migrações a reverter")
+
+	dbMigrateCmd.AddCommand(dbMigrateStatusCmd)
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd)
+	dbMigrateCmd.AddCommand(dbMigrateDownCmd)
+	DbCmd.AddCommand(dbMigrateCmd)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/db"
+)
+
+// rotulosTabelas nomeia, no histórico, as tabelas alteradas pelas operações.
+var rotulosTabelas = map[string]string{
+	"questions":             "questões",
+	"tasks":                 "tarefas",
+	"task_dependencies":     "dependências de tarefas",
+	"task_links":            "vínculos de tarefas",
+	"task_time_entries":     "registros de tempo",
+	"projects":              "projetos",
+	"events":                "eventos",
+	"routines":              "rotinas",
+	"routine_runs":          "execuções de rotinas",
+	"terms":                 "bimestres",
+	"students":              "alunos",
+	"lessons":               "aulas",
+	"grades":                "notas",
+	"classes":               "turmas",
+	"subjects":              "disciplinas",
+	"timetable_slots":       "horários",
+	"timetable_occurrences": "aulas do horário",
+	"non_school_days":       "dias não letivos",
+	"settings":              "configurações",
+}
+
+// AnotacaoHistoricoPorAcao marca os comandos de longa duração (o daemon e o quadro), que registram
+// uma operação no histórico para cada ação, em vez de o main.go registrar o comando inteiro como uma.
+const AnotacaoHistoricoPorAcao = "historico-por-acao"
+
+// HistoricoCmd lista as operações registradas no histórico.
+var HistoricoCmd = &cobra.Command{
+	Use:   "historico",
+	Short: "Lista as operações recentes, que podem ser desfeitas",
+	Long: `Lista as operações recentes, da mais nova para a mais antiga. Cada comando que altera os dados
+(criar, editar, concluir, remover, importar, lançar notas, gerar tarefas de rotinas etc.) é registrado
+como uma operação, com o estado anterior e o posterior de cada registro alterado.
+A coluna Alterações resume os registros afetados: + criados, ~ alterados e - removidos.
+Use 'vickgenda desfazer' para reverter a última operação e 'vickgenda refazer' para aplicá-la de novo.
+São mantidas as últimas 100 operações.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limite, _ := cmd.Flags().GetInt("limite")
+		operacoes, err := db.ListOperations(limite)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(operacoes) == 0 {
+			cmd.Println("Nenhuma operação registrada.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"#", "Data", "Comando", "Alterações", "Situação"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, op := range operacoes {
+			situacao := "Aplicada"
+			if op.Undone() {
+				situacao = "Desfeita"
+			}
+			table.Append([]string{strconv.FormatInt(op.ID, 10), op.CreatedAt.Local().Format("02/01/2006 15:04"), op.Command, resumirAlteracoes(op.Changes), situacao})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+// DesfazerCmd reverte a última operação do histórico.
+var DesfazerCmd = &cobra.Command{
+	Use:   "desfazer",
+	Short: "Desfaz a última operação",
+	Long: `Desfaz a última operação ainda aplicada do histórico, restaurando cada registro que ela criou,
+alterou ou removeu; por exemplo, uma remoção em lote feita por engano. Repita para desfazer as anteriores.
+'vickgenda refazer' aplica de novo a operação desfeita, até que um novo comando altere os dados.
+Se algum registro foi alterado depois por fora do histórico, nada é desfeito.
+'prova delete' ainda trabalha sobre uma lista de simulação, sem gravar no banco, e não entra no histórico.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		op, err := db.UndoOperation()
+		if errors.Is(err, db.ErrNothingToUndo) {
+			cmd.Println("Nenhuma operação para desfazer.")
+			return nil
+		}
+		if errors.Is(err, db.ErrJournalConflict) {
+			return fmt.Errorf("erro: a operação não pode ser desfeita, pois os dados foram alterados depois dela (%w)", err)
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Operação #%d desfeita: %s (%s).\n", op.ID, op.Command, op.CreatedAt.Local().Format("02/01/2006 15:04"))
+		cmd.Printf("Alterações revertidas: %s\n", resumirAlteracoes(op.Changes))
+		return nil
+	},
+}
+
+// RefazerCmd aplica de novo a última operação desfeita.
+var RefazerCmd = &cobra.Command{
+	Use:   "refazer",
+	Short: "Refaz a última operação desfeita",
+	Long: `Aplica de novo a última operação desfeita com 'vickgenda desfazer'.
+Depois que um novo comando altera os dados, as operações desfeitas não podem mais ser refeitas.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		op, err := db.RedoOperation()
+		if errors.Is(err, db.ErrNothingToRedo) {
+			cmd.Println("Nenhuma operação para refazer.")
+			return nil
+		}
+		if errors.Is(err, db.ErrJournalConflict) {
+			return fmt.Errorf("erro: a operação não pode ser refeita, pois os dados foram alterados depois dela (%w)", err)
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Operação #%d refeita: %s.\n", op.ID, op.Command)
+		cmd.Printf("Alterações aplicadas: %s\n", resumirAlteracoes(op.Changes))
+		return nil
+	},
+}
+
+// resumirAlteracoes conta os registros criados (+), alterados (~) e removidos (-) por tabela,
+// na ordem em que as tabelas aparecem (ex: "tarefas -1, vínculos de tarefas -2").
+func resumirAlteracoes(alteracoes []db.JournalChange) string {
+	var tabelas []string
+	contagem := map[string]map[string]int{}
+	for _, a := range alteracoes {
+		if contagem[a.Table] == nil {
+			contagem[a.Table] = map[string]int{}
+			tabelas = append(tabelas, a.Table)
+		}
+		contagem[a.Table][a.Kind()]++
+	}
+
+	var partes []string
+	for _, tabela := range tabelas {
+		rotulo, ok := rotulosTabelas[tabela]
+		if !ok {
+			rotulo = tabela
+		}
+		var numeros []string
+		for _, tipo := range []struct{ nome, sinal string }{{"insert", "+"}, {"update", "~"}, {"delete", "-"}} {
+			if n := contagem[tabela][tipo.nome]; n > 0 {
+				numeros = append(numeros, tipo.sinal+strconv.Itoa(n))
+			}
+		}
+		partes = append(partes, rotulo+" "+strings.Join(numeros, " "))
+	}
+	return strings.Join(partes, ", ")
+}
+
+func init() {
+	// rootCmd.AddCommand(HistoricoCmd, DesfazerCmd, RefazerCmd) // This will be done in cmd/cli/cli.go
+
+	HistoricoCmd.Flags().Int("limite", 20, "Quantas operações mostrar (0 mostra todas as mantidas)")
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/horario"
+)
+
+// HorarioCmd represents the horario command
+var HorarioCmd = &cobra.Command{
+	Use:   "horario",
+	Short: "Gerencia a grade horária semanal e gera as aulas do bimestre",
+	Long: `O comando 'horario' gerencia a grade horária de um bimestre: os horários fixos de cada semana,
+como "seg 08:00-08:50 7B Matemática".
+Use 'horario gerar --bimestre <ID>' para criar as aulas e os eventos da agenda de todos os dias letivos do bimestre.
+Depois de alterar a grade, gere novamente: somente as aulas futuras são atualizadas.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var horarioAdicionarCmd = &cobra.Command{
+	Use:   "adicionar",
+	Short: "Adiciona um horário à grade do bimestre",
+	Long: `Adiciona um horário semanal à grade do bimestre.
+Exemplo: vickgenda horario adicionar --bimestre <ID> --turma 7B --disciplina Matemática --dia seg --inicio 08:00 --fim 08:50`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bimestre, _ := cmd.Flags().GetString("bimestre")
+		turma, _ := cmd.Flags().GetString("turma")
+		disciplina, _ := cmd.Flags().GetString("disciplina")
+		dia, _ := cmd.Flags().GetString("dia")
+		inicio, _ := cmd.Flags().GetString("inicio")
+		fim, _ := cmd.Flags().GetString("fim")
+		local, _ := cmd.Flags().GetString("local")
+
+		slot, err := horario.AdicionarHorario(bimestre, turma, disciplina, dia, inicio, fim, local)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Horário '%s' adicionado: %s %s-%s %s %s.\n", slot.ID, diasDaSemana[slot.Weekday], slot.StartTime, slot.EndTime, slot.ClassID, slot.Subject)
+		cmd.Println("Use 'horario gerar' para criar as aulas e os eventos do bimestre.")
+		return nil
+	},
+}
+
+var horarioListarCmd = &cobra.Command{
+	Use:   "listar",
+	Short: "Lista a grade horária do bimestre",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bimestre, _ := cmd.Flags().GetString("bimestre")
+		turma, _ := cmd.Flags().GetString("turma")
+
+		slots, err := horario.ListarHorarios(bimestre, turma)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(slots) == 0 {
+			cmd.Println("Nenhum horário cadastrado na grade.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Dia", "Início", "Fim", "Turma", "Disciplina", "Local"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, s := range slots {
+			table.Append([]string{s.ID, diasDaSemana[s.Weekday], s.StartTime, s.EndTime, s.ClassID, s.Subject, s.Location})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var horarioEditarCmd = &cobra.Command{
+	Use:   "editar <ID do horário>",
+	Short: "Edita um horário da grade",
+	Long: `Edita um horário da grade. Somente os campos informados são alterados.
+As aulas já geradas mudam apenas na próxima execução de 'horario gerar'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		turma, _ := cmd.Flags().GetString("turma")
+		disciplina, _ := cmd.Flags().GetString("disciplina")
+		dia, _ := cmd.Flags().GetString("dia")
+		inicio, _ := cmd.Flags().GetString("inicio")
+		fim, _ := cmd.Flags().GetString("fim")
+		local, _ := cmd.Flags().GetString("local")
+
+		slot, err := horario.EditarHorario(args[0], turma, disciplina, dia, inicio, fim, local)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Horário '%s' atualizado: %s %s-%s %s %s.\n", slot.ID, diasDaSemana[slot.Weekday], slot.StartTime, slot.EndTime, slot.ClassID, slot.Subject)
+		return nil
+	},
+}
+
+var horarioRemoverCmd = &cobra.Command{
+	Use:   "remover <ID do horário>",
+	Short: "Remove um horário da grade e as aulas futuras geradas por ele",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+
+		if !force {
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Tem certeza que deseja remover o horário '%s' e as aulas futuras geradas por ele?", id),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
+
+		removidas, err := horario.RemoverHorario(id)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Horário '%s' removido. %d aula(s) futura(s) removida(s); as aulas passadas foram mantidas.\n", id, removidas)
+		return nil
+	},
+}
+
+var horarioGerarCmd = &cobra.Command{
+	Use:   "gerar",
+	Short: "Gera as aulas e os eventos da agenda a partir da grade do bimestre",
+	Long: `Gera uma aula e um evento na agenda para cada horário da grade em cada dia letivo do bimestre.
+Dias letivos que já passaram e ainda não têm aula também são preenchidos.
+Pode ser executado novamente após alterar a grade: as aulas futuras são criadas, atualizadas ou removidas
+para refletir a grade atual, e as aulas que já aconteceram não são alteradas.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bimestre, _ := cmd.Flags().GetString("bimestre")
+
+		resultado, err := horario.GerarAulas(bimestre)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Aulas geradas: %d criada(s), %d atualizada(s), %d removida(s), %d sem alteração, %d passada(s) preservada(s).\n",
+			resultado.Criadas, resultado.Atualizadas, resultado.Removidas, resultado.Mantidas, resultado.Passadas)
+		return nil
+	},
+}
+
+func init() {
+	// rootCmd.AddCommand(HorarioCmd) // This will be done in cmd/cli/cli.go
+
+	horarioAdicionarCmd.Flags().String("bimestre", "", "ID do bimestre (obrigatório)")
+	horarioAdicionarCmd.Flags().String("turma", "", "Turma (obrigatório)")
+	horarioAdicionarCmd.Flags().String("disciplina", "", "Disciplina (obrigatório)")
+	horarioAdicionarCmd.Flags().String("dia", "", "Dia da semana: seg, ter, qua, qui, sex, sáb ou dom (obrigatório)")
+	horarioAdicionarCmd.Flags().String("inicio", "", "Horário de início (HH:MM) (obrigatório)")
+	horarioAdicionarCmd.Flags().String("fim", "", "Horário de término (HH:MM) (obrigatório)")
+	horarioAdicionarCmd.Flags().String("local", "", "Sala ou local da aula")
+	horarioAdicionarCmd.MarkFlagRequired("bimestre")
+	horarioAdicionarCmd.MarkFlagRequired("turma")
+	horarioAdicionarCmd.MarkFlagRequired("disciplina")
+	horarioAdicionarCmd.MarkFlagRequired("dia")
+	horarioAdicionarCmd.MarkFlagRequired("inicio")
+	horarioAdicionarCmd.MarkFlagRequired("fim")
+
+	horarioListarCmd.Flags().String("bimestre", "", "ID do bimestre (obrigatório)")
+	horarioListarCmd.Flags().String("turma", "", "Filtra pela turma")
+	horarioListarCmd.MarkFlagRequired("bimestre")
+
+	horarioEditarCmd.Flags().String("turma", "", "Nova turma")
+	horarioEditarCmd.Flags().String("disciplina", "", "Nova disciplina")
+	horarioEditarCmd.Flags().String("dia", "", "Novo dia da semana")
+	horarioEditarCmd.Flags().String("inicio", "", "Novo horário de início (HH:MM)")
+	horarioEditarCmd.Flags().String("fim", "", "Novo horário de término (HH:MM)")
+	horarioEditarCmd.Flags().String("local", "", "Nova sala ou local")
+
+	horarioRemoverCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
+
+	horarioGerarCmd.Flags().String("bimestre", "", "ID do bimestre (obrigatório)")
+	horarioGerarCmd.MarkFlagRequired("bimestre")
+
+	HorarioCmd.AddCommand(horarioAdicionarCmd)
+	HorarioCmd.AddCommand(horarioListarCmd)
+	HorarioCmd.AddCommand(horarioEditarCmd)
+	HorarioCmd.AddCommand(horarioRemoverCmd)
+	HorarioCmd.AddCommand(horarioGerarCmd)
+}
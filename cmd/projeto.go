@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/agenda"
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/datas"
+)
+
+// ProjetoCmd represents the projeto command
+var ProjetoCmd = &cobra.Command{
+	Use:   "projeto",
+	Short: "Gerencia projetos que agrupam tarefas, eventos e rotinas",
+	Long: `O comando 'projeto' gerencia projetos (ou contextos), como "Feira de Ciências" ou "Escola A",
+que agrupam tarefas, eventos e modelos de rotina. Cada item pertence a no máximo um projeto,
+definido com --projeto em 'tarefa criar', 'agenda adicionar-evento' e 'rotina criar-modelo' (ou nos comandos de edição).
+O progresso de um projeto é calculado a partir das suas tarefas, incluindo as subtarefas.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var projetoCriarCmd = &cobra.Command{
+	Use:   "criar <nome>",
+	Short: "Cria um novo projeto",
+	Long: `Cria um novo projeto ativo. O nome não pode repetir o de outro projeto (sem diferenciar maiúsculas).
+Exemplo: vickgenda projeto criar "Feira de Ciências" --prazo 20/10 --descricao "Feira anual do 7º ano"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prazo, _ := cmd.Flags().GetString("prazo")
+		descricao, _ := cmd.Flags().GetString("descricao")
+
+		p, err := projeto.CriarProjeto(args[0], prazo, descricao)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Projeto '%s' criado com sucesso (ID %s).\n", p.Name, p.ID)
+		return nil
+	},
+}
+
+var projetoListarCmd = &cobra.Command{
+	Use:   "listar",
+	Short: "Lista os projetos com o seu progresso",
+	Long: `Lista os projetos em ordem alfabética. A coluna Progresso mostra quantas tarefas do projeto
+estão concluídas (ex: 3/10 (30%)); prazos vencidos de projetos ativos são marcados com "!".`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, _ := cmd.Flags().GetString("status")
+
+		progressos, err := projeto.CarregarProgressos(status)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(progressos) == 0 {
+			cmd.Println("Nenhum projeto encontrado.")
+			return nil
+		}
+
+		agora := time.Now()
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Nome", "Prazo", "Status", "Progresso", "Atrasadas", "Tempo"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, p := range progressos {
+			table.Append([]string{p.Projeto.ID, p.Projeto.Name, prazoProjeto(p, agora), p.Projeto.Status,
+				p.String(), strconv.Itoa(p.Atrasadas), tarefa.FormatarDuracao(p.Tempo)})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+// prazoProjeto formata o prazo de um projeto para as listagens, com "!" se estiver vencido.
+func prazoProjeto(p projeto.Progresso, agora time.Time) string {
+	if p.Projeto.DueDate.IsZero() {
+		return "-"
+	}
+	prazo := p.Projeto.DueDate.Format("02/01/2006")
+	if p.PrazoVencido(agora) {
+		prazo += " !"
+	}
+	return prazo
+}
+
+var projetoVerCmd = &cobra.Command{
+	Use:   "ver <nome ou ID do projeto>",
+	Short: "Mostra um projeto com as suas tarefas, próximos eventos e rotinas",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		progresso, err := projeto.CarregarProgresso(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		p := progresso.Projeto
+
+		fmt.Printf("Projeto: %s (%s)\n", p.Name, p.ID)
+		fmt.Printf("Status: %s\n", p.Status)
+		fmt.Printf("Prazo: %s\n", prazoProjeto(progresso, time.Now()))
+		if p.Description != "" {
+			fmt.Printf("Descrição: %s\n", p.Description)
+		}
+		fmt.Printf("Progresso: %s; %d em andamento, %d atrasada(s)\n", progresso, progresso.EmAndamento, progresso.Atrasadas)
+		fmt.Printf("Tempo registrado: %s\n", tarefa.FormatarDuracao(progresso.Tempo))
+
+		tarefas, err := tarefa.ListarTarefasFiltro(tarefa.FiltroTarefas{Projeto: p.ID, OrdenarPor: "prazo", Ordem: "asc"})
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		fmt.Println("\nTarefas:")
+		if len(tarefas) == 0 {
+			fmt.Println("  Nenhuma tarefa no projeto.")
+		} else {
+			bloqueios, err := tarefa.CarregarBloqueios()
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"ID", "Descrição", "Prazo", "Prioridade", "Status"})
+			table.SetBorder(true)
+			table.SetAutoWrapText(false)
+			for _, t := range tarefas {
+				prazo := "-"
+				if !t.DueDate.IsZero() {
+					prazo = t.DueDate.Format("02/01/2006")
+				}
+				table.Append([]string{t.ID, t.Description, prazo, strconv.Itoa(t.Priority), bloqueios.StatusEfetivo(t)})
+			}
+			table.Render()
+		}
+
+		eventos, err := agenda.ListarEventos("proximos", "", "", "inicio", "asc")
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		fmt.Println("\nPróximos eventos:")
+		nenhum := true
+		for _, e := range eventos {
+			if e.ProjectID != p.ID {
+				continue
+			}
+			nenhum = false
+			fmt.Printf("  - %s %s-%s: %s\n", e.StartTime.Local().Format("02/01/2006"), e.StartTime.Local().Format("15:04"),
+				e.EndTime.Local().Format("15:04"), e.Title)
+		}
+		if nenhum {
+			fmt.Println("  Nenhum evento futuro no projeto.")
+		}
+
+		rotinas, err := projeto.RotinasDoProjeto(p.ID)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(rotinas) > 0 {
+			fmt.Println("\nRotinas:")
+			for _, r := range rotinas {
+				fmt.Printf("  - %s (%s, %s)\n", r.Name, r.ID, r.Frequency)
+			}
+		}
+		return nil
+	},
+}
+
+var projetoEditarCmd = &cobra.Command{
+	Use:   "editar <nome ou ID do projeto>",
+	Short: "Edita um projeto existente",
+	Long: `Edita um projeto existente. Pelo menos uma alteração deve ser informada.
+--status aceita Ativo, Concluído ou Arquivado; projetos concluídos e arquivados continuam com as suas tarefas.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nome, _ := cmd.Flags().GetString("nome")
+		prazo, _ := cmd.Flags().GetString("prazo")
+		descricao, _ := cmd.Flags().GetString("descricao")
+		status, _ := cmd.Flags().GetString("status")
+
+		p, err := projeto.EditarProjeto(args[0], nome, prazo, descricao, status)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Projeto '%s' atualizado com sucesso.\n", p.Name)
+		return nil
+	},
+}
+
+var projetoRemoverCmd = &cobra.Command{
+	Use:   "remover <nome ou ID do projeto>",
+	Short: "Remove um projeto (as tarefas, eventos e rotinas ficam sem projeto)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		p, err := projeto.BuscarProjeto(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		if !force {
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Tem certeza que deseja remover o projeto '%s'? Os itens do projeto são mantidos, sem projeto.", p.Name),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
+
+		if err := projeto.RemoverProjeto(p.ID); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Projeto '%s' removido com sucesso.\n", p.Name)
+		return nil
+	},
+}
+
+func init() {
+	// rootCmd.AddCommand(ProjetoCmd) // This will be done in cmd/cli/cli.go
+
+	projetoCriarCmd.Flags().String("prazo", "", "Prazo do projeto ("+datas.Exemplos+")")
+	projetoCriarCmd.Flags().String("descricao", "", "Descrição do projeto")
+
+	projetoListarCmd.Flags().String("status", "", "Filtra pelo status (Ativo, Concluído ou Arquivado)")
+
+	projetoEditarCmd.Flags().String("nome", "", "Novo nome")
+	projetoEditarCmd.Flags().String("prazo", "", "Novo prazo ("+datas.Exemplos+")")
+	projetoEditarCmd.Flags().String("descricao", "", "Nova descrição")
+	projetoEditarCmd.Flags().String("status", "", "Novo status (Ativo, Concluído ou Arquivado)")
+
+	projetoRemoverCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
+
+	ProjetoCmd.AddCommand(projetoCriarCmd)
+	ProjetoCmd.AddCommand(projetoListarCmd)
+	ProjetoCmd.AddCommand(projetoVerCmd)
+	ProjetoCmd.AddCommand(projetoEditarCmd)
+	ProjetoCmd.AddCommand(projetoRemoverCmd)
+}
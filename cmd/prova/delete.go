@@ -25,8 +25,9 @@ var sampleGeneratedProvasForDelete = []models.Test{
 var deleteCmd = &cobra.Command{
 	Use:   "delete <id_prova>",
 	Short: "Remove uma prova existente",
-	Long:  `Exclui permanentemente uma prova do sistema com base no ID fornecido. Por padrão, solicita confirmação antes de excluir.`,
-	Args:  cobra.ExactArgs(1), // Espera exatamente um argumento: o ID da prova.
+	Long: `Exclui permanentemente uma prova do sistema com base no ID fornecido. Por padrão, solicita confirmação antes de excluir.
+As provas ainda não são gravadas no banco, então a remoção não entra no histórico e não pode ser revertida com 'vickgenda desfazer'.`,
+	Args: cobra.ExactArgs(1), // Espera exatamente um argumento: o ID da prova.
 	Run: func(cmd *cobra.Command, args []string) {
 		provaID := args[0] // Already validated by cobra.ExactArgs(1)
 		force, _ := cmd.Flags().GetBool("force")
@@ -56,7 +57,7 @@ var deleteCmd = &cobra.Command{
 			proceedToDelete = true
 		} else {
 			fmt.Printf("\nTem certeza que deseja remover a prova '%s' (ID: %s)?\n", provaTitle, provaID)
-			fmt.Print("Esta ação não pode ser desfeita. Digite 'sim' para confirmar: ")
+			fmt.Print("Esta ação não pode ser desfeita (nem com 'vickgenda desfazer'). Digite 'sim' para confirmar: ")
 
 			reader := bufio.NewReader(os.Stdin)
 			input, _ := reader.ReadString('\n')
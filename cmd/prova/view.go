@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"vickgenda-cli/internal/commands/tarefa"
 	"vickgenda-cli/internal/models"
 )
 
@@ -166,6 +167,23 @@ var viewCmd = &cobra.Command{
 			}
 		}
 		fmt.Println("\n------------------------------------")
+
+		// 5. Tarefas abertas vinculadas à prova (ex: tarefa vincular <ID> prova:<id_prova>)
+		tarefasAbertas, err := tarefa.TarefasVinculadas(models.TaskLinkTest, prova.ID, true)
+		if err != nil {
+			fmt.Printf("AVISO: Não foi possível carregar as tarefas da prova: %v\n", err)
+		} else if len(tarefasAbertas) == 0 {
+			fmt.Println("\nTarefas abertas: nenhuma.")
+		} else {
+			fmt.Printf("\n--- Tarefas abertas (%d) ---\n", len(tarefasAbertas))
+			for _, t := range tarefasAbertas {
+				prazo := "sem prazo"
+				if !t.DueDate.IsZero() {
+					prazo = "prazo " + t.DueDate.Format("02/01/2006")
+				}
+				fmt.Printf("  - [%s] %s (%s, %s)\n", t.ID, t.Description, prazo, t.Status)
+			}
+		}
 		fmt.Println("\nComando 'prova view' concluído com lógica de simulação.")
 	},
 }
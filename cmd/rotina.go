@@ -5,23 +5,422 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/commands/rotina"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/models"
 )
 
 // RotinaCmd represents the rotina command
 var RotinaCmd = &cobra.Command{
 	Use:   "rotina",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Gerencia modelos de rotina que geram tarefas",
+	Long: `O comando 'rotina' gerencia modelos de rotina, que geram tarefas de forma recorrente ou sob demanda.
+Os modelos ficam salvos no banco de dados local e podem ser criados, listados, editados e removidos.
+Use 'rotina gerar-tarefas <ID>' para gerar manualmente as tarefas de um modelo.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var rotinaCriarModeloCmd = &cobra.Command{
+	Use:   "criar-modelo",
+	Short: "Cria um novo modelo de rotina",
+	Long: `Cria um novo modelo de rotina.
+Exemplo: vickgenda rotina criar-modelo --nome "Planejamento semanal" --frequencia "semanal:seg" --desc-tarefa "Planejar aulas da semana {data}"
+Para conferir as datas de uma frequência antes de criar o modelo, use 'rotina proximas --frequencia <frequência>'.
+A descrição e as tags são templates do text/template, com variáveis como {{.Data}}, {{.DiaDaSemana}}, {{.Semana}},
+{{.Bimestre.Nome}}, {{.Turmas}} e {{.Disciplinas}} e funções como somarDias e dataBR; veja docs/specifications/squad2/rotina_spec.md.
+Com --tarefa e --evento (repetíveis), o modelo gera um pacote: tarefas adicionais e eventos com prazos e inícios
+relativos à data base, criados junto com a tarefa principal. Exemplo:
+  vickgenda rotina criar-modelo --nome "Fechamento do bimestre" --frequencia manual --desc-tarefa "Lançar notas do {{.Bimestre.Nome}}" \
+    --tarefa "Imprimir boletins|+3u|1" --evento "Conselho de classe|+7d 14:00|2h|Sala dos professores"
+Com --projeto, o modelo pertence ao projeto (nome ou ID), assim como as tarefas e eventos que ele gerar.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nome, _ := cmd.Flags().GetString("nome")
+		frequencia, _ := cmd.Flags().GetString("frequencia")
+		descTarefa, _ := cmd.Flags().GetString("desc-tarefa")
+		prioridade, _ := cmd.Flags().GetInt("prioridade-tarefa")
+		tags, _ := cmd.Flags().GetString("tags-tarefa")
+		proximaExecucao, _ := cmd.Flags().GetString("proxima-execucao")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+
+		// O pacote e o projeto são validados antes de criar o modelo, para não deixar um modelo pela metade.
+		tarefasPacote, eventosPacote, err := lerPacoteRotina(cmd)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if projetoRef != "" {
+			if _, err := projeto.BuscarProjeto(projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		modelo, err := rotina.CriarModeloRotina(nome, frequencia, descTarefa, prioridade, tags, proximaExecucao)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(tarefasPacote) > 0 || len(eventosPacote) > 0 {
+			if modelo, err = rotina.DefinirPacoteModelo(modelo.ID, tarefasPacote, eventosPacote); err != nil {
+				return fmt.Errorf("erro: modelo '%s' criado, mas o pacote não foi salvo: %w", modelo.ID, err)
+			}
+		}
+		if projetoRef != "" {
+			if modelo, err = projeto.AtribuirRotina(modelo.ID, projetoRef); err != nil {
+				return fmt.Errorf("erro: modelo '%s' criado, mas não foi colocado no projeto '%s': %w", modelo.ID, projetoRef, err)
+			}
+		}
+		cmd.Printf("Modelo de rotina '%s' criado com sucesso.\n", modelo.ID)
+		if len(modelo.TaskTemplates) > 0 || len(modelo.EventTemplates) > 0 {
+			cmd.Printf("Pacote: tarefa principal, %d tarefa(s) adicional(is) e %d evento(s).\n", len(modelo.TaskTemplates), len(modelo.EventTemplates))
+		}
+		if !modelo.NextRunTime.IsZero() {
+			cmd.Printf("Primeira execução: %s %s\n", diasDaSemana[modelo.NextRunTime.Weekday()], modelo.NextRunTime.Format("02/01/2006 15:04"))
+		}
+		return nil
+	},
+}
+
+var rotinaListarModelosCmd = &cobra.Command{
+	Use:   "listar-modelos",
+	Short: "Lista os modelos de rotina existentes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ordenarPor, _ := cmd.Flags().GetString("ordenar-por")
+		ordem, _ := cmd.Flags().GetString("ordem")
+
+		modelos, err := rotina.ListarModelosRotina(ordenarPor, ordem)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(modelos) == 0 {
+			cmd.Println("Nenhum modelo de rotina encontrado.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Nome", "Frequência", "Descrição Tarefa Padrão", "Próxima Execução"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, m := range modelos {
+			proxima := "-"
+			if !m.NextRunTime.IsZero() {
+				proxima = m.NextRunTime.Format("02/01/2006 15:04")
+			}
+			table.Append([]string{m.ID, m.Name, m.Frequency, m.TaskDescription, proxima})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var rotinaGerarTarefasCmd = &cobra.Command{
+	Use:   "gerar-tarefas <ID do modelo>",
+	Short: "Gera manualmente as tarefas de um modelo de rotina",
+	Long: `Gera as tarefas de um modelo de rotina para a data base (padrão: hoje) e registra a execução no histórico.
+Se o modelo tiver um pacote, as tarefas adicionais e os eventos são criados junto, em uma única transação.
+Se o modelo já gerou as tarefas dessa data (manualmente ou pelo agendador), nada é feito, a menos que --forcar seja usado.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataBase, _ := cmd.Flags().GetString("data-base")
+		forcar, _ := cmd.Flags().GetBool("forcar")
+
+		pacote, err := rotina.GerarPacoteFromModelo(args[0], dataBase, forcar)
+		if errors.Is(err, rotina.ErrOcorrenciaJaExecutada) {
+			cmd.Printf("Nada a fazer: %v. Use --forcar para gerar de novo.\n", err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Tarefas geradas com sucesso a partir do modelo '%s'.\n", args[0])
+		for _, t := range pacote.Tarefas {
+			linha := fmt.Sprintf("  - %s: %s", t.ID, t.Description)
+			if !t.DueDate.IsZero() {
+				linha += fmt.Sprintf(" (prazo: %s)", t.DueDate.Format("02/01/2006"))
+			}
+			cmd.Println(linha)
+		}
+		for _, e := range pacote.Eventos {
+			cmd.Printf("  - Evento %s: %s (%s a %s)\n", e.ID, e.Title, e.StartTime.In(time.Local).Format("02/01/2006 15:04"), e.EndTime.In(time.Local).Format("15:04"))
+		}
+		return nil
+	},
+}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+var rotinaExecutarPendentesCmd = &cobra.Command{
+	Use:   "executar-pendentes",
+	Short: "Gera as tarefas das rotinas cuja próxima execução já passou",
+	Long: `Gera as tarefas de todos os modelos de rotina cuja próxima execução já passou e avança a próxima execução
+de acordo com a frequência. Execuções perdidas (ex: computador desligado) são recuperadas, uma a uma.
+Feito para ser chamado periodicamente pelo cron ou por um timer do systemd, ex: */15 * * * * vickgenda rotina executar-pendentes
+Duas execuções simultâneas nunca geram as mesmas tarefas: a segunda termina sem fazer nada.
+Para manter o agendador rodando sem cron, use 'vickgenda daemon'.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("rotina called, logic to be implemented.")
+		resultado, err := rotina.ExecutarPendentes()
+		if errors.Is(err, rotina.ErrExecucaoEmAndamento) {
+			cmd.Println("Outra execução de rotinas já está em andamento; nada a fazer.")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		imprimirExecucoesRotina(cmd, resultado)
+		return nil
+	},
+}
+
+var rotinaProximasCmd = &cobra.Command{
+	Use:   "proximas [ID do modelo]",
+	Short: "Mostra as próximas execuções de um modelo ou de uma frequência",
+	Long: `Mostra as próximas execuções agendadas de um modelo de rotina, a partir da sua próxima execução.
+Sem ID, mostra as execuções de --frequencia a partir de --inicio, para conferir a frequência antes de criar o modelo.
+Execuções em dias não letivos são indicadas com o dia letivo em que as tarefas serão geradas.
+Exemplos:
+  vickgenda rotina proximas <ID> --n 5
+  vickgenda rotina proximas --frequencia "mensal:2a-terca" --inicio "2025-02-01 08:00"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, _ := cmd.Flags().GetInt("n")
+		frequencia, _ := cmd.Flags().GetString("frequencia")
+		inicioStr, _ := cmd.Flags().GetString("inicio")
+
+		var execucoes []time.Time
+		var err error
+		switch {
+		case len(args) == 1 && (frequencia != "" || inicioStr != ""):
+			return errors.New("erro: informe o ID do modelo ou --frequencia, não ambos")
+		case len(args) == 1:
+			execucoes, err = rotina.ProximasExecucoesModelo(args[0], n)
+		case frequencia == "":
+			return errors.New("erro: informe o ID do modelo ou --frequencia")
+		default:
+			var inicio time.Time // Zero: a partir da meia-noite de hoje.
+			if inicioStr != "" {
+				r, err := datas.DataHora(inicioStr)
+				if err != nil {
+					return fmt.Errorf("erro: data de início inválida: %w", err)
+				}
+				inicio = r.Momento
+			}
+			execucoes, err = rotina.ProximasExecucoes(frequencia, inicio, n)
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		for i, e := range execucoes {
+			linha := fmt.Sprintf("%3d. %s %s", i+1, diasDaSemana[e.Weekday()], e.Format("02/01/2006 15:04"))
+			// As tarefas de uma execução em dia não letivo são geradas para o próximo dia letivo.
+			if diaLetivo, err := calendario.ProximoDiaLetivo(e); err == nil && diaLetivo.Format("2006-01-02") != e.Format("2006-01-02") {
+				linha += fmt.Sprintf("  (dia não letivo: tarefas para %s %s)", diasDaSemana[diaLetivo.Weekday()], diaLetivo.Format("02/01/2006"))
+			}
+			cmd.Println(linha)
+		}
+		return nil
+	},
+}
+
+// imprimirExecucoesRotina mostra as ocorrências executadas por rotina.ExecutarPendentes, uma por linha.
+func imprimirExecucoesRotina(cmd *cobra.Command, resultado rotina.ResultadoExecucao) {
+	if len(resultado.Execucoes) == 0 {
+		cmd.Println("Nenhuma rotina pendente.")
+		return
+	}
+	gerada := 0
+	for _, e := range resultado.Execucoes {
+		if e.Erro != nil {
+			cmd.Printf("  Erro em '%s' (%s): %v\n", e.Modelo.Name, e.Ocorrencia.Format("02/01/2006 15:04"), e.Erro)
+			continue
+		}
+		if e.JaExecutada {
+			cmd.Printf("  %s (%s): tarefas já geradas anteriormente; nada a fazer.\n", e.Modelo.Name, e.Ocorrencia.Format("02/01/2006 15:04"))
+			continue
+		}
+		gerada += len(e.Tarefas)
+		for _, t := range e.Tarefas {
+			cmd.Printf("  %s (%s): %s\n", e.Modelo.Name, e.Ocorrencia.Format("02/01/2006 15:04"), t.Description)
+		}
+		for _, ev := range e.Eventos {
+			cmd.Printf("  %s (%s): evento %s em %s\n", e.Modelo.Name, e.Ocorrencia.Format("02/01/2006 15:04"), ev.Title, ev.StartTime.In(time.Local).Format("02/01/2006 15:04"))
+		}
+	}
+	cmd.Printf("%d tarefa(s) gerada(s) em %d execução(ões).\n", gerada, len(resultado.Execucoes))
+	for _, m := range resultado.Atrasados {
+		cmd.Printf("  Aviso: '%s' ainda tem execuções atrasadas; elas serão recuperadas na próxima execução.\n", m.Name)
+	}
+}
+
+var rotinaHistoricoCmd = &cobra.Command{
+	Use:   "historico <ID do modelo>",
+	Short: "Mostra as execuções de um modelo de rotina e as tarefas geradas",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		execucoes, err := rotina.HistoricoExecucoes(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(execucoes) == 0 {
+			cmd.Println("Nenhuma execução registrada para este modelo.")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Ocorrência", "Executada em", "Tarefas Geradas", "Eventos Gerados", "Forçada"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, e := range execucoes {
+			ocorrencia := e.OccurrenceDate
+			if d, err := time.Parse("2006-01-02", e.OccurrenceDate); err == nil {
+				ocorrencia = d.Format("02/01/2006")
+			}
+			forcada := "Não"
+			if e.Forced {
+				forcada = "Sim"
+			}
+			table.Append([]string{ocorrencia, e.CreatedAt.In(time.Local).Format("02/01/2006 15:04"), strings.Join(e.TaskIDs, ", "), strings.Join(e.EventIDs, ", "), forcada})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var rotinaEditarModeloCmd = &cobra.Command{
+	Use:   "editar-modelo <ID do modelo>",
+	Short: "Edita um modelo de rotina existente",
+	Long: `Edita um modelo de rotina existente.
+--tarefa e --evento substituem, respectivamente, as tarefas adicionais e os eventos do pacote;
+--limpar-pacote remove o pacote, e o modelo volta a gerar apenas a tarefa principal.
+--projeto move o modelo para um projeto e --sem-projeto o retira do projeto atual; as tarefas e eventos
+já gerados continuam onde estão.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nome, _ := cmd.Flags().GetString("nome")
+		frequencia, _ := cmd.Flags().GetString("frequencia")
+		descTarefa, _ := cmd.Flags().GetString("desc-tarefa")
+		prioridade, _ := cmd.Flags().GetInt("prioridade-tarefa")
+		tags, _ := cmd.Flags().GetString("tags-tarefa")
+		proximaExecucao, _ := cmd.Flags().GetString("proxima-execucao")
+		limparPacote, _ := cmd.Flags().GetBool("limpar-pacote")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+		semProjeto, _ := cmd.Flags().GetBool("sem-projeto")
+
+		alterarPacote := limparPacote || cmd.Flags().Changed("tarefa") || cmd.Flags().Changed("evento")
+		if limparPacote && (cmd.Flags().Changed("tarefa") || cmd.Flags().Changed("evento")) {
+			return errors.New("erro: use --limpar-pacote ou --tarefa/--evento, não ambos")
+		}
+		if projetoRef != "" && semProjeto {
+			return errors.New("erro: use --projeto ou --sem-projeto, não ambos")
+		}
+		mudarProjeto := projetoRef != "" || semProjeto
+		if projetoRef != "" {
+			if _, err := projeto.BuscarProjeto(projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		tarefasPacote, eventosPacote, err := lerPacoteRotina(cmd)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		modelo, err := rotina.GetModeloRotinaByID(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if (!alterarPacote && !mudarProjeto) || nome != "" || frequencia != "" || descTarefa != "" || prioridade > 0 || tags != "" || proximaExecucao != "" {
+			modelo, err = rotina.EditarModeloRotina(args[0], nome, frequencia, descTarefa, prioridade, tags, proximaExecucao)
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		if alterarPacote {
+			if !cmd.Flags().Changed("tarefa") && !limparPacote {
+				tarefasPacote = modelo.TaskTemplates
+			}
+			if !cmd.Flags().Changed("evento") && !limparPacote {
+				eventosPacote = modelo.EventTemplates
+			}
+			if modelo, err = rotina.DefinirPacoteModelo(modelo.ID, tarefasPacote, eventosPacote); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		if mudarProjeto {
+			if modelo, err = projeto.AtribuirRotina(modelo.ID, projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		cmd.Printf("Modelo de rotina '%s' atualizado com sucesso.\n", modelo.ID)
+		return nil
+	},
+}
+
+// lerPacoteRotina interpreta as flags --tarefa e --evento de criar-modelo e editar-modelo.
+func lerPacoteRotina(cmd *cobra.Command) ([]models.RoutineTaskTemplate, []models.RoutineEventTemplate, error) {
+	specsTarefas, _ := cmd.Flags().GetStringArray("tarefa")
+	specsEventos, _ := cmd.Flags().GetStringArray("evento")
+	var tarefas []models.RoutineTaskTemplate
+	for _, spec := range specsTarefas {
+		item, err := rotina.ParseModeloTarefa(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		tarefas = append(tarefas, item)
+	}
+	var eventos []models.RoutineEventTemplate
+	for _, spec := range specsEventos {
+		item, err := rotina.ParseModeloEvento(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		eventos = append(eventos, item)
+	}
+	return tarefas, eventos, nil
+}
+
+var rotinaRemoverModeloCmd = &cobra.Command{
+	Use:   "remover-modelo <ID do modelo>",
+	Short: "Remove um modelo de rotina (as tarefas já geradas são mantidas)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+
+		modelo, err := rotina.GetModeloRotinaByID(id)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		if !force {
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Tem certeza que deseja remover o modelo de rotina '%s' (ID: %s)?", modelo.Name, modelo.ID),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
+
+		if err := rotina.RemoverModeloRotina(id); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Modelo de rotina '%s' removido com sucesso.\n", id)
 		return nil
 	},
 }
@@ -29,13 +428,49 @@ to quickly create a Cobra application.`,
 func init() {
 	// rootCmd.AddCommand(RotinaCmd) // This will be done in cmd/cli/cli.go
 
-	// Here you will define your flags and configuration settings.
+	rotinaCriarModeloCmd.Flags().String("nome", "", "Nome descritivo do modelo de rotina (obrigatório)")
+	rotinaCriarModeloCmd.Flags().String("frequencia", "", "Recorrência (obrigatório): 'diaria', 'semanal:seg,qua', 'quinzenal', 'a-cada:3d', 'mensal:15', 'mensal:ultimo-dia-util', 'mensal:2a-terca', 'cron:0 7 * * seg-sex' ou 'manual'")
+	rotinaCriarModeloCmd.Flags().String("desc-tarefa", "", "Template da descrição das tarefas geradas, ex: \"Corrigir provas até {{somarDiasUteis .Dia 3 | dataBR}}\" (obrigatório)")
+	rotinaCriarModeloCmd.Flags().Int("prioridade-tarefa", 2, "Prioridade das tarefas geradas (1-Alta, 2-Média, 3-Baixa)")
+	rotinaCriarModeloCmd.Flags().String("tags-tarefa", "", "Tags das tarefas geradas, separadas por vírgula; cada tag também é um template")
+	rotinaCriarModeloCmd.Flags().String("proxima-execucao", "", "Data/hora a partir da qual a rotina é executada (ex: \"2025-02-03 07:00\", \"segunda 7h\"); a primeira execução segue a frequência")
+	rotinaCriarModeloCmd.Flags().StringArray("tarefa", nil, "Tarefa adicional do pacote, \"descrição|prazo|prioridade|tags\" (ex: \"Imprimir boletins|+3u|1\"); repetível")
+	rotinaCriarModeloCmd.Flags().StringArray("evento", nil, "Evento do pacote, \"título|início|duração|local\" (ex: \"Conselho de classe|+7d 14:00|2h\"); repetível")
+	rotinaCriarModeloCmd.Flags().String("projeto", "", "Nome ou ID do projeto do modelo e das tarefas e eventos gerados")
+	rotinaCriarModeloCmd.MarkFlagRequired("nome")
+	rotinaCriarModeloCmd.MarkFlagRequired("frequencia")
+	rotinaCriarModeloCmd.MarkFlagRequired("desc-tarefa")
+
+	rotinaListarModelosCmd.Flags().String("ordenar-por", "nome", "Campo de ordenação: nome, frequencia, proxima_execucao")
+	rotinaListarModelosCmd.Flags().String("ordem", "asc", "Ordem de classificação: asc ou desc")
+
+	rotinaGerarTarefasCmd.Flags().String("data-base", "", "Data base para a geração das tarefas ("+datas.Exemplos+"). Padrão: hoje")
+	rotinaGerarTarefasCmd.Flags().Bool("forcar", false, "Gera as tarefas mesmo que a data base já tenha sido executada")
+
+	rotinaEditarModeloCmd.Flags().String("nome", "", "Novo nome do modelo")
+	rotinaEditarModeloCmd.Flags().String("frequencia", "", "Nova frequência")
+	rotinaEditarModeloCmd.Flags().String("desc-tarefa", "", "Novo modelo de descrição das tarefas")
+	rotinaEditarModeloCmd.Flags().Int("prioridade-tarefa", 0, "Nova prioridade das tarefas geradas")
+	rotinaEditarModeloCmd.Flags().String("tags-tarefa", "", "Novas tags das tarefas geradas (substituem as atuais)")
+	rotinaEditarModeloCmd.Flags().String("proxima-execucao", "", "Nova data/hora da próxima execução (ex: \"2025-02-03 07:00\", \"segunda 7h\")")
+	rotinaEditarModeloCmd.Flags().StringArray("tarefa", nil, "Tarefa adicional do pacote, \"descrição|prazo|prioridade|tags\"; repetível, substitui as atuais")
+	rotinaEditarModeloCmd.Flags().StringArray("evento", nil, "Evento do pacote, \"título|início|duração|local\"; repetível, substitui os atuais")
+	rotinaEditarModeloCmd.Flags().Bool("limpar-pacote", false, "Remove as tarefas adicionais e os eventos do pacote")
+	rotinaEditarModeloCmd.Flags().String("projeto", "", "Nome ou ID do novo projeto do modelo")
+	rotinaEditarModeloCmd.Flags().Bool("sem-projeto", false, "Retira o modelo do projeto")
+
+	rotinaProximasCmd.Flags().Int("n", 10, "Quantidade de execuções a mostrar")
+	rotinaProximasCmd.Flags().String("frequencia", "", "Frequência a simular, sem ID (ex: 'mensal:ultima-sexta')")
+	rotinaProximasCmd.Flags().String("inicio", "", "Início da simulação com --frequencia (ex: \"2025-02-01 08:00\", \"amanhã 8h\"); as execuções mantêm o seu horário. Padrão: hoje, 00:00")
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// rotinaCmd.PersistentFlags().String("foo", "", "A help for foo")
+	rotinaRemoverModeloCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// rotinaCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	RotinaCmd.AddCommand(rotinaCriarModeloCmd)
+	RotinaCmd.AddCommand(rotinaListarModelosCmd)
+	RotinaCmd.AddCommand(rotinaGerarTarefasCmd)
+	RotinaCmd.AddCommand(rotinaExecutarPendentesCmd)
+	RotinaCmd.AddCommand(rotinaProximasCmd)
+	RotinaCmd.AddCommand(rotinaHistoricoCmd)
+	RotinaCmd.AddCommand(rotinaEditarModeloCmd)
+	RotinaCmd.AddCommand(rotinaRemoverModeloCmd)
 }
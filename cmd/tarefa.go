@@ -5,23 +5,950 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/tui"
 )
 
 // TarefaCmd represents the tarefa command
 var TarefaCmd = &cobra.Command{
 	Use:   "tarefa",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Gerencia as tarefas do usuário",
+	Long: `O comando 'tarefa' gerencia as tarefas do usuário: criar, listar, editar, concluir e remover.
+Uma tarefa pode ter subtarefas (itens de checklist), criadas com 'tarefa criar --pai <ID>';
+o progresso da tarefa pai é calculado a partir das subtarefas.
+Uma tarefa também pode depender de outras ('tarefa dependencia'); enquanto algum pré-requisito
+estiver aberto, ela aparece como "Bloqueada".
+Uma tarefa pode pertencer a um projeto ('projeto'), informado com --projeto, e ser vinculada
+a aulas, provas, turmas, alunos e eventos ('tarefa vincular').`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var tarefaCriarCmd = &cobra.Command{
+	Use:   "criar",
+	Short: "Cria uma nova tarefa",
+	Long: `Cria uma nova tarefa.
+Exemplo: vickgenda tarefa criar --descricao "Preparar feira de ciências" --prazo 2024-10-20 --prioridade 1
+Com --pai, a tarefa é criada como subtarefa: vickgenda tarefa criar --pai <ID> --descricao "Reservar o pátio"
+Com --depende-de, a tarefa fica bloqueada até os pré-requisitos serem concluídos:
+  vickgenda tarefa criar --descricao "Lançar notas 7B" --depende-de <ID de "Corrigir provas 7B">
+Com --projeto, a tarefa é criada no projeto (nome ou ID); uma subtarefa sem --projeto fica no projeto da tarefa pai.
+Com --vincular, a tarefa é vinculada a aulas, provas, turmas, alunos ou eventos (veja 'tarefa vincular'):
+  vickgenda tarefa criar --descricao "Corrigir provas" --vincular prova:prova123,turma:7B`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		descricao, _ := cmd.Flags().GetString("descricao")
+		prazo, _ := cmd.Flags().GetString("prazo")
+		prioridade, _ := cmd.Flags().GetInt("prioridade")
+		tags, _ := cmd.Flags().GetString("tags")
+		pai, _ := cmd.Flags().GetString("pai")
+		dependeDe, _ := cmd.Flags().GetStringSlice("depende-de")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+		vincular, _ := cmd.Flags().GetStringSlice("vincular")
+
+		// Os pré-requisitos, o projeto e os vínculos são conferidos antes, para não criar a tarefa sem eles.
+		for _, id := range dependeDe {
+			if _, err := tarefa.GetTarefaByID(id); err != nil {
+				return fmt.Errorf("erro: pré-requisito inválido: %w", err)
+			}
+		}
+		if projetoRef != "" {
+			if _, err := projeto.BuscarProjeto(projetoRef); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		for _, ref := range vincular {
+			tipo, entidadeID, err := tarefa.ParseVinculo(ref)
+			if err == nil {
+				_, err = tarefa.DescreverEntidade(tipo, entidadeID)
+			}
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+		}
+		var nova models.Task
+		var err error
+		if pai != "" {
+			nova, err = tarefa.CriarSubtarefa(pai, descricao, prazo, prioridade, tags)
+		} else {
+			nova, err = tarefa.CriarTarefa(descricao, prazo, prioridade, tags)
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if projetoRef != "" {
+			if _, err := projeto.AtribuirTarefa(nova.ID, projetoRef); err != nil {
+				return fmt.Errorf("erro: tarefa '%s' criada, mas não foi colocada no projeto '%s': %w", nova.ID, projetoRef, err)
+			}
+		}
+		for _, id := range dependeDe {
+			if err := tarefa.AdicionarDependencia(nova.ID, id); err != nil {
+				return fmt.Errorf("erro: tarefa '%s' criada, mas a dependência de '%s' não foi salva: %w", nova.ID, id, err)
+			}
+		}
+		for _, ref := range vincular {
+			if _, err := tarefa.VincularTarefa(nova.ID, ref); err != nil {
+				return fmt.Errorf("erro: tarefa '%s' criada, mas o vínculo '%s' não foi salvo: %w", nova.ID, ref, err)
+			}
+		}
+		cmd.Printf("Tarefa '%s' criada com sucesso.\n", nova.ID)
+		return nil
+	},
+}
+
+var tarefaListarCmd = &cobra.Command{
+	Use:   "listar [consulta]",
+	Short: "Lista as tarefas, com filtros opcionais",
+	Long: `Lista as tarefas que correspondem aos filtros.
+Os filtros também podem ser escritos como uma consulta, no lugar de --status, --prioridade, --prazo-ate, --tag e --projeto:
+  vickgenda tarefa listar 'status:pendente prio:1 tag:provas prazo<sexta -tag:pessoal'
+Campos: status, prio, tag, projeto, pai, prazo, criada, desc (palavras soltas também procuram na descrição)
+e os vínculos aula, prova, turma, aluno e evento ("prova:prova123").
+":" testa igualdade; prio, prazo e criada aceitam <, <=, > e >=. Vírgulas separam alternativas ("status:pendente,andamento"),
+"*" testa se o campo está preenchido ("prazo:*") e "-" nega o termo ("-tag:pessoal").
+A coluna Progresso mostra quantas subtarefas diretas de cada tarefa pai estão concluídas (ex: 3/10).
+Tarefas com pré-requisitos abertos aparecem com o status "Bloqueada" (também aceito em --status e em "status:bloqueada");
+"status:pendente" e "status:andamento" não as incluem.
+Com --prontas, só aparecem as tarefas não concluídas cujos pré-requisitos já foram concluídos.
+Com --projeto, só aparecem as tarefas do projeto (nome ou ID).
+Com --arvore, as subtarefas aparecem indentadas abaixo da tarefa pai.
+Com --ordenar urgencia, as tarefas mais urgentes aparecem primeiro, com a coluna Urgência (veja 'tarefa urgencia').`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, _ := cmd.Flags().GetString("status")
+		prioridade, _ := cmd.Flags().GetInt("prioridade")
+		prazoAte, _ := cmd.Flags().GetString("prazo-ate")
+		tag, _ := cmd.Flags().GetString("tag")
+		ordenarPor, _ := cmd.Flags().GetString("ordenar-por")
+		ordem, _ := cmd.Flags().GetString("ordem")
+		arvore, _ := cmd.Flags().GetBool("arvore")
+		prontas, _ := cmd.Flags().GetBool("prontas")
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+		porUrgencia := strings.EqualFold(ordenarPor, "urgencia")
+		if porUrgencia && !cmd.Flags().Changed("ordem") {
+			ordem = "desc"
+		}
+
+		var tarefas []models.Task
+		var err error
+		if len(args) == 1 {
+			for _, filtro := range []string{"status", "prioridade", "prazo-ate", "tag", "projeto"} {
+				if cmd.Flags().Changed(filtro) {
+					return fmt.Errorf("erro: --%s não pode ser combinado com uma consulta; escreva o filtro na consulta (veja 'tarefa listar --help')", filtro)
+				}
+			}
+			tarefas, err = tarefa.BuscarTarefas(args[0], ordenarPor, ordem)
+		} else {
+			tarefas, err = tarefa.ListarTarefasFiltro(tarefa.FiltroTarefas{
+				Status: status, Prioridade: prioridade, PrazoAte: prazoAte, Tag: tag, Projeto: projetoRef,
+				OrdenarPor: ordenarPor, Ordem: ordem,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		bloqueios, err := tarefa.CarregarBloqueios()
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if prontas {
+			tarefas = tarefa.FiltrarProntas(tarefas, bloqueios)
+		}
+		if len(tarefas) == 0 {
+			cmd.Println("Nenhuma tarefa encontrada.")
+			return nil
+		}
+		// O progresso considera todas as subtarefas, mesmo as excluídas pelos filtros.
+		todas, err := tarefa.ListarTarefas("", 0, "", "", "", "")
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		progresso := tarefa.CalcularProgresso(todas)
+
+		cabecalho := []string{"ID", "Descrição", "Prazo", "Prioridade", "Status", "Tags", "Progresso"}
+		linha := func(t models.Task, descricao string) []string {
+			return linhaTarefa(t, descricao, progresso, bloqueios)
+		}
+		if porUrgencia {
+			contexto, err := tarefa.CarregarContextoUrgencia()
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cabecalho = append(cabecalho, "Urgência")
+			linha = func(t models.Task, descricao string) []string {
+				return append(linhaTarefa(t, descricao, progresso, bloqueios), contexto.Calcular(t).String())
+			}
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader(cabecalho)
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		if arvore {
+			for _, raiz := range tarefa.MontarArvore(tarefas) {
+				appendArvoreTarefas(table, raiz, "", "", linha)
+			}
+		} else {
+			for _, t := range tarefas {
+				table.Append(linha(t, t.Description))
+			}
+		}
+		table.Render()
+		return nil
+	},
+}
+
+// appendArvoreTarefas adiciona um nó e suas subtarefas à tabela, desenhando a hierarquia na coluna Descrição.
+// prefixo é desenhado antes da descrição do nó; recuo é o desenho herdado pelas linhas das subtarefas.
+// linha formata cada tarefa com a descrição já desenhada.
+func appendArvoreTarefas(table *tablewriter.Table, no *tarefa.NoTarefa, prefixo, recuo string, linha func(t models.Task, descricao string) []string) {
+	table.Append(linha(no.Tarefa, prefixo+no.Tarefa.Description))
+	for i, filho := range no.Subtarefas {
+		if i == len(no.Subtarefas)-1 {
+			appendArvoreTarefas(table, filho, recuo+"└─ ", recuo+"   ", linha)
+		} else {
+			appendArvoreTarefas(table, filho, recuo+"├─ ", recuo+"│  ", linha)
+		}
+	}
+}
+
+// linhaTarefa formata uma tarefa como linha da tabela de 'tarefa listar', com o status efetivo (incluindo "Bloqueada").
+func linhaTarefa(t models.Task, descricao string, progresso map[string]tarefa.Progresso, bloqueios tarefa.Bloqueios) []string {
+	prazo := "-"
+	if !t.DueDate.IsZero() {
+		prazo = t.DueDate.Format("02/01/2006")
+	}
+	andamento := "-"
+	if p, ok := progresso[t.ID]; ok {
+		andamento = p.String()
+	}
+	return []string{t.ID, descricao, prazo, strconv.Itoa(t.Priority), bloqueios.StatusEfetivo(t), strings.Join(t.Tags, ", "), andamento}
+}
+
+var tarefaVerCmd = &cobra.Command{
+	Use:   "ver <ID da tarefa>",
+	Short: "Mostra os detalhes de uma tarefa, com as entidades vinculadas",
+	Long: `Mostra os detalhes de uma tarefa: status, prazo, projeto, tarefa pai, subtarefas, pré-requisitos,
+tempo registrado e as aulas, provas, turmas, alunos e eventos vinculados a ela.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := tarefa.GetTarefaByID(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		bloqueios, err := tarefa.CarregarBloqueios()
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		fmt.Printf("Tarefa: %s (%s)\n", t.Description, t.ID)
+		fmt.Printf("Status: %s\n", bloqueios.StatusEfetivo(t))
+		fmt.Printf("Prioridade: %d\n", t.Priority)
+		if !t.DueDate.IsZero() {
+			fmt.Printf("Prazo: %s\n", t.DueDate.Format("02/01/2006"))
+		}
+		if len(t.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(t.Tags, ", "))
+		}
+		if t.ProjectID != "" {
+			if p, err := projeto.BuscarProjeto(t.ProjectID); err == nil {
+				fmt.Printf("Projeto: %s\n", p.Name)
+			}
+		}
+		if t.ParentID != "" {
+			if pai, err := tarefa.GetTarefaByID(t.ParentID); err == nil {
+				fmt.Printf("Tarefa pai: %s (%s)\n", pai.Description, pai.ID)
+			}
+		}
+		if progresso, err := tarefa.ProgressoTarefa(t.ID); err == nil && progresso.Total > 0 {
+			fmt.Printf("Subtarefas: %s concluídas\n", progresso)
+		}
+		preRequisitos, err := tarefa.ListarPreRequisitos(t.ID)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		for _, p := range preRequisitos {
+			fmt.Printf("Pré-requisito: %s (%s) [%s]\n", p.Description, p.ID, bloqueios.StatusEfetivo(p))
+		}
+		if tempo, err := tarefa.TempoTarefa(t.ID); err == nil && tempo > 0 {
+			fmt.Printf("Tempo registrado: %s\n", tarefa.FormatarDuracao(tempo))
+		}
+		if t.Status != models.TaskStatusCompleted {
+			if contexto, err := tarefa.CarregarContextoUrgencia(); err == nil {
+				u := contexto.Calcular(t)
+				fmt.Printf("Urgência: %s (%s)\n", u, u.Detalhes())
+			}
+		}
+
+		vinculos, err := tarefa.ListarVinculos(t.ID)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		fmt.Println("\nVínculos:")
+		if len(vinculos) == 0 {
+			fmt.Println("  Nenhum vínculo. Use 'tarefa vincular' para vincular a tarefa a uma aula, prova, turma, aluno ou evento.")
+		}
+		for _, v := range vinculos {
+			descricao, err := tarefa.DescreverEntidade(v.EntityType, v.EntityID)
+			if err != nil {
+				descricao = "Entidade não encontrada"
+			}
+			fmt.Printf("  - %s (%s:%s)\n", descricao, v.EntityType, v.EntityID)
+		}
+		return nil
+	},
+}
+
+var tarefaVincularCmd = &cobra.Command{
+	Use:   "vincular <ID da tarefa> <tipo:id>...",
+	Short: "Vincula uma tarefa a aulas, provas, turmas, alunos ou eventos",
+	Long: `Vincula uma tarefa a uma ou mais entidades, informadas como tipo:id, com tipo aula, prova, turma, aluno ou evento.
+Aulas, alunos e eventos precisam existir; provas e turmas são aceitas pelo ID informado (ex: turma:7B).
+As tarefas abertas aparecem em 'aula ver' e 'prova view', e os vínculos, em 'tarefa ver'. Exemplo:
+  vickgenda tarefa vincular <ID> prova:prova123 turma:7B`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, ref := range args[1:] {
+			v, err := tarefa.VincularTarefa(args[0], ref)
+			if err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cmd.Printf("Tarefa '%s' vinculada a %s:%s.\n", args[0], v.EntityType, v.EntityID)
+		}
+		return nil
+	},
+}
+
+var tarefaDesvincularCmd = &cobra.Command{
+	Use:   "desvincular <ID da tarefa> <tipo:id>...",
+	Short: "Remove vínculos de uma tarefa",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, ref := range args[1:] {
+			if err := tarefa.DesvincularTarefa(args[0], ref); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cmd.Printf("Vínculo da tarefa '%s' com %s removido.\n", args[0], ref)
+		}
+		return nil
+	},
+}
+
+var tarefaEditarCmd = &cobra.Command{
+	Use:   "editar <ID da tarefa>",
+	Short: "Edita uma tarefa existente, ou em lote as tarefas de uma consulta",
+	Long: `Edita uma tarefa existente. Pelo menos uma alteração deve ser informada.
+--pai transforma a tarefa em subtarefa de outra; --sem-pai a devolve ao primeiro nível.
+--projeto move a tarefa para um projeto (nome ou ID); --sem-projeto a retira do projeto atual.
+--adicionar-tag e --remover-tag alteram as tags sem substituir as demais (--tags substitui todas).
+Com --onde, no lugar do ID, edita todas as tarefas que correspondem à consulta (a mesma de 'tarefa listar'),
+depois de mostrar quais são e pedir confirmação; --simular apenas mostra as tarefas. Em lote, --descricao,
+--pai e --sem-pai não são aceitos. Exemplo:
+  vickgenda tarefa editar --onde 'tag:provas status:pendente' --adicionar-tag urgente --prioridade 1`,
+	Args: argsIDOuConsulta,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var e edicaoTarefa
+		e.descricao, _ = cmd.Flags().GetString("descricao")
+		e.prazo, _ = cmd.Flags().GetString("prazo")
+		e.prioridade, _ = cmd.Flags().GetInt("prioridade")
+		e.status, _ = cmd.Flags().GetString("status")
+		e.tags, _ = cmd.Flags().GetString("tags")
+		e.adicionarTags, _ = cmd.Flags().GetStringSlice("adicionar-tag")
+		e.removerTags, _ = cmd.Flags().GetStringSlice("remover-tag")
+		e.pai, _ = cmd.Flags().GetString("pai")
+		e.semPai, _ = cmd.Flags().GetBool("sem-pai")
+		e.projeto, _ = cmd.Flags().GetString("projeto")
+		e.semProjeto, _ = cmd.Flags().GetBool("sem-projeto")
+
+		if e.pai != "" && e.semPai {
+			return errors.New("erro: use --pai ou --sem-pai, não ambos")
+		}
+		if e.projeto != "" && e.semProjeto {
+			return errors.New("erro: use --projeto ou --sem-projeto, não ambos")
+		}
+		if e.tags != "" && (len(e.adicionarTags) > 0 || len(e.removerTags) > 0) {
+			return errors.New("erro: use --tags ou --adicionar-tag/--remover-tag, não ambos")
+		}
+
+		if len(args) == 1 {
+			if err := e.aplicar(args[0]); err != nil {
+				return fmt.Errorf("erro: %w", err)
+			}
+			cmd.Printf("Tarefa '%s' atualizada com sucesso.\n", args[0])
+			return nil
+		}
+
+		if e.descricao != "" || e.pai != "" || e.semPai {
+			return errors.New("erro: --descricao, --pai e --sem-pai não podem ser usados com --onde")
+		}
+		if e.vazia() {
+			return errors.New("erro: nenhuma alteração especificada")
+		}
+		tarefas, err := selecionarEmLote(cmd, "editadas", nil)
+		if err != nil || tarefas == nil {
+			return err
+		}
+		return aplicarEmLote(cmd, tarefas, "atualizada(s)", func(t models.Task) error {
+			return e.aplicar(t.ID)
+		})
+	},
+}
+
+// edicaoTarefa reúne as alterações pedidas em 'tarefa editar', aplicadas a uma tarefa ou a cada tarefa de uma consulta.
+type edicaoTarefa struct {
+	descricao, prazo, status, tags string
+	prioridade                     int
+	adicionarTags, removerTags     []string
+	pai, projeto                   string
+	semPai, semProjeto             bool
+}
+
+// vazia informa se nenhuma alteração foi pedida.
+func (e edicaoTarefa) vazia() bool {
+	return e.descricao == "" && e.prazo == "" && e.prioridade <= 0 && e.status == "" && e.tags == "" &&
+		len(e.adicionarTags) == 0 && len(e.removerTags) == 0 && e.pai == "" && !e.semPai && e.projeto == "" && !e.semProjeto
+}
+
+// aplicar edita a tarefa id.
+func (e edicaoTarefa) aplicar(id string) error {
+	// A mudança de tarefa pai vem primeiro: é a que pode ser recusada (ciclo ou pai inexistente).
+	moverTarefa := e.pai != "" || e.semPai
+	if moverTarefa {
+		if _, err := tarefa.MoverTarefa(id, e.pai); err != nil {
+			return err
+		}
+	}
+	mudarProjeto := e.projeto != "" || e.semProjeto
+	if mudarProjeto {
+		if _, err := projeto.AtribuirTarefa(id, e.projeto); err != nil {
+			return err
+		}
+	}
+	ajustarTags := len(e.adicionarTags) > 0 || len(e.removerTags) > 0
+	if (!moverTarefa && !mudarProjeto && !ajustarTags) || e.descricao != "" || e.prazo != "" || e.prioridade > 0 || e.status != "" || e.tags != "" {
+		if _, err := tarefa.EditarTarefa(id, e.descricao, e.prazo, e.prioridade, e.status, e.tags); err != nil {
+			return err
+		}
+	}
+	if ajustarTags {
+		if _, err := tarefa.AjustarTags(id, e.adicionarTags, e.removerTags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// argsIDOuConsulta valida os argumentos dos comandos que recebem o ID de uma tarefa ou, com --onde, uma consulta.
+func argsIDOuConsulta(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("onde") {
+		if len(args) > 0 {
+			return errors.New("informe o ID da tarefa ou --onde, não ambos")
+		}
+		return nil
+	}
+	if simular, _ := cmd.Flags().GetBool("simular"); simular {
+		return errors.New("--simular só pode ser usado com --onde")
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// selecionarEmLote lista as tarefas da consulta de --onde que passam pelo filtro (nil aceita todas), mostra-as
+// e pede confirmação para que sejam acao (ex: "removidas"), a menos que --force seja usado.
+// Retorna nil, sem erro, quando não há tarefas, com --simular ou quando o usuário não confirma.
+func selecionarEmLote(cmd *cobra.Command, acao string, filtro func(models.Task) bool) ([]models.Task, error) {
+	onde, _ := cmd.Flags().GetString("onde")
+	simular, _ := cmd.Flags().GetBool("simular")
+	force, _ := cmd.Flags().GetBool("force")
+
+	consulta, err := tarefa.ParseConsulta(onde)
+	if err != nil {
+		return nil, fmt.Errorf("erro: %w", err)
+	}
+	if consulta.Vazia() {
+		return nil, errors.New("erro: a consulta de --onde está vazia; para selecionar todas as tarefas, use por exemplo --onde 'status:pendente,andamento,concluida,bloqueada'")
+	}
+	todas, err := consulta.Listar("prazo", "asc")
+	if err != nil {
+		return nil, fmt.Errorf("erro: %w", err)
+	}
+	var tarefas []models.Task
+	for _, t := range todas {
+		if filtro == nil || filtro(t) {
+			tarefas = append(tarefas, t)
+		}
+	}
+	if len(tarefas) == 0 {
+		cmd.Println("Nenhuma tarefa corresponde à consulta.")
+		return nil, nil
+	}
+
+	bloqueios, err := tarefa.CarregarBloqueios()
+	if err != nil {
+		return nil, fmt.Errorf("erro: %w", err)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Descrição", "Prazo", "Prioridade", "Status", "Tags"})
+	table.SetBorder(true)
+	table.SetAutoWrapText(false)
+	for _, t := range tarefas {
+		linha := linhaTarefa(t, t.Description, nil, bloqueios)
+		table.Append(linha[:len(linha)-1])
+	}
+	table.Render()
+
+	if simular {
+		cmd.Printf("Simulação: %d tarefa(s) seriam %s. Nenhuma alteração foi feita.\n", len(tarefas), acao)
+		return nil, nil
+	}
+	if !force {
+		confirmado := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("%d tarefa(s) serão %s. Deseja continuar?", len(tarefas), acao),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &confirmado); err != nil {
+			return nil, fmt.Errorf("erro ao obter confirmação: %w", err)
+		}
+		if !confirmado {
+			cmd.Println("Operação cancelada.")
+			return nil, nil
+		}
+	}
+	return tarefas, nil
+}
+
+// aplicarEmLote executa operacao em cada tarefa, continuando após os erros, que são listados.
+// feito descreve o resultado no resumo (ex: "removida(s)"). Retorna um erro se alguma tarefa falhar.
+func aplicarEmLote(cmd *cobra.Command, tarefas []models.Task, feito string, operacao func(models.Task) error) error {
+	falhas := 0
+	for _, t := range tarefas {
+		if err := operacao(t); err != nil {
+			falhas++
+			cmd.Printf("Erro na tarefa '%s' (%s): %v\n", t.ID, t.Description, err)
+		}
+	}
+	cmd.Printf("%d tarefa(s) %s.\n", len(tarefas)-falhas, feito)
+	if falhas > 0 {
+		return fmt.Errorf("erro: %d tarefa(s) não foram alteradas", falhas)
+	}
+	return nil
+}
+
+var tarefaConcluirCmd = &cobra.Command{
+	Use:   "concluir <ID da tarefa>",
+	Short: "Marca uma tarefa, ou as tarefas de uma consulta, como concluída",
+	Long: `Marca uma tarefa como concluída.
+Se a tarefa tiver subtarefas ainda abertas, ela é concluída mesmo assim e as subtarefas pendentes são listadas como aviso.
+As tarefas que dependiam desta e não têm mais pré-requisitos abertos são listadas como desbloqueadas.
+Com --onde, no lugar do ID, conclui as tarefas ainda abertas que correspondem à consulta (a mesma de 'tarefa listar'),
+depois de mostrar quais são e pedir confirmação; --simular apenas mostra as tarefas. Exemplo:
+  vickgenda tarefa concluir --onde 'tag:provas prazo<hoje' --simular`,
+	Args: argsIDOuConsulta,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			tarefas, err := selecionarEmLote(cmd, "concluídas", func(t models.Task) bool {
+				return t.Status != models.TaskStatusCompleted
+			})
+			if err != nil || tarefas == nil {
+				return err
+			}
+			selecionadas := make(map[string]bool, len(tarefas))
+			for _, t := range tarefas {
+				selecionadas[t.ID] = true
+			}
+			return aplicarEmLote(cmd, tarefas, "concluída(s)", func(t models.Task) error {
+				conclusao, err := tarefa.ConcluirTarefaComAvisos(t.ID)
+				if errors.Is(err, tarefa.ErrTarefaJaConcluida) {
+					return nil
+				}
+				for _, d := range conclusao.Desbloqueadas {
+					if !selecionadas[d.ID] {
+						cmd.Printf("Desbloqueada: %s (%s)\n", d.Description, d.ID)
+					}
+				}
+				return err
+			})
+		}
+
+		id := args[0]
+		conclusao, err := tarefa.ConcluirTarefaComAvisos(id)
+		if errors.Is(err, tarefa.ErrTarefaJaConcluida) {
+			cmd.Printf("Info: Tarefa '%s' já está concluída.\n", id)
+			return nil
+		}
+		if err != nil && conclusao.Tarefa.ID == "" {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Tarefa '%s' marcada como concluída.\n", id)
+		if err != nil {
+			return fmt.Errorf("erro ao verificar as subtarefas: %w", err)
+		}
+		if n := len(conclusao.SubtarefasAbertas); n > 0 {
+			cmd.Printf("Aviso: %d subtarefa(s) ainda em aberto:\n", n)
+			for _, s := range conclusao.SubtarefasAbertas {
+				cmd.Printf("  - %s (%s) [%s]\n", s.Description, s.ID, s.Status)
+			}
+		}
+		for _, d := range conclusao.Desbloqueadas {
+			cmd.Printf("Desbloqueada: %s (%s)\n", d.Description, d.ID)
+		}
+		if conclusao.CronometroParado {
+			if total, err := tarefa.TempoTarefa(id); err == nil {
+				cmd.Printf("Cronômetro parado. Tempo total registrado: %s\n", tarefa.FormatarDuracao(total))
+			}
+		}
+		return nil
+	},
+}
+
+var tarefaRemoverCmd = &cobra.Command{
+	Use:   "remover <ID da tarefa>",
+	Short: "Remove uma tarefa (as subtarefas passam para a tarefa pai da removida)",
+	Long: `Remove uma tarefa. As subtarefas não são removidas: passam para a tarefa pai da removida.
+Com --onde, no lugar do ID, remove todas as tarefas que correspondem à consulta (a mesma de 'tarefa listar'),
+depois de mostrar quais são e pedir confirmação; --simular apenas mostra as tarefas. Exemplo:
+  vickgenda tarefa remover --onde 'status:concluida criada<-3m' --simular`,
+	Args: argsIDOuConsulta,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		if len(args) == 0 {
+			tarefas, err := selecionarEmLote(cmd, "removidas", nil)
+			if err != nil || tarefas == nil {
+				return err
+			}
+			return aplicarEmLote(cmd, tarefas, "removida(s)", func(t models.Task) error {
+				return tarefa.RemoverTarefa(t.ID)
+			})
+		}
+
+		id := args[0]
+
+		if _, err := tarefa.GetTarefaByID(id); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		if !force {
+			confirmado := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Tem certeza que deseja remover a tarefa '%s'?", id),
+				Default: false,
+			}
+			if err := survey.AskOne(prompt, &confirmado); err != nil {
+				return fmt.Errorf("erro ao obter confirmação: %w", err)
+			}
+			if !confirmado {
+				cmd.Println("Remoção cancelada.")
+				return nil
+			}
+		}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+		if err := tarefa.RemoverTarefa(id); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Tarefa '%s' removida com sucesso.\n", id)
+		return nil
+	},
+}
+
+var tarefaDependenciaCmd = &cobra.Command{
+	Use:   "dependencia",
+	Short: "Gerencia os pré-requisitos das tarefas",
+	Long: `Gerencia as dependências entre tarefas. Uma tarefa com pré-requisitos abertos aparece como "Bloqueada"
+até que todos eles sejam concluídos. Dependências que formariam um ciclo são recusadas.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var tarefaDependenciaAdicionarCmd = &cobra.Command{
+	Use:   "adicionar <ID da tarefa> <ID do pré-requisito>",
+	Short: "Faz uma tarefa depender de outra",
+	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("tarefa called, logic to be implemented.")
+		if err := tarefa.AdicionarDependencia(args[0], args[1]); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Tarefa '%s' agora depende de '%s'.\n", args[0], args[1])
+		return nil
+	},
+}
+
+var tarefaDependenciaRemoverCmd = &cobra.Command{
+	Use:   "remover <ID da tarefa> <ID do pré-requisito>",
+	Short: "Remove a dependência de uma tarefa em outra",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := tarefa.RemoverDependencia(args[0], args[1]); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Dependência de '%s' em '%s' removida.\n", args[0], args[1])
+		return nil
+	},
+}
+
+var tarefaDependenciaListarCmd = &cobra.Command{
+	Use:   "listar <ID da tarefa>",
+	Short: "Mostra os pré-requisitos de uma tarefa e as tarefas que dependem dela",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preRequisitos, err := tarefa.ListarPreRequisitos(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		dependentes, err := tarefa.ListarDependentes(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(preRequisitos) == 0 && len(dependentes) == 0 {
+			cmd.Println("A tarefa não tem dependências.")
+			return nil
+		}
+		bloqueios, err := tarefa.CarregarBloqueios()
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Relação", "ID", "Descrição", "Status"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, t := range preRequisitos {
+			table.Append([]string{"Pré-requisito", t.ID, t.Description, bloqueios.StatusEfetivo(t)})
+		}
+		for _, t := range dependentes {
+			table.Append([]string{"Dependente", t.ID, t.Description, bloqueios.StatusEfetivo(t)})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var tarefaIniciarCmd = &cobra.Command{
+	Use:   "iniciar <ID da tarefa>",
+	Short: "Liga o cronômetro de uma tarefa",
+	Long: `Liga o cronômetro de uma tarefa para registrar o tempo gasto nela. Só um cronômetro roda por vez:
+se outra tarefa estiver sendo cronometrada, ela é pausada. Uma tarefa pendente passa a "Em Andamento".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inicio, err := tarefa.IniciarTarefa(args[0])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if p := inicio.Pausado; p != nil {
+			cmd.Printf("Cronômetro de '%s' pausado após %s.\n", p.Tarefa.Description, tarefa.FormatarDuracao(p.Registro.Duration(p.Registro.EndTime)))
+		}
+		cmd.Printf("Cronômetro iniciado às %s para '%s'.\n", inicio.Registro.StartTime.Local().Format("15:04"), inicio.Tarefa.Description)
+		return nil
+	},
+}
+
+var tarefaPausarCmd = &cobra.Command{
+	Use:   "pausar",
+	Short: "Para o cronômetro da tarefa em andamento",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parado, err := tarefa.PausarTarefa()
+		if errors.Is(err, tarefa.ErrNenhumCronometro) {
+			cmd.Println("Nenhum cronômetro está rodando.")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Cronômetro de '%s' pausado: %s registrados.\n", parado.Tarefa.Description, tarefa.FormatarDuracao(parado.Registro.Duration(parado.Registro.EndTime)))
+		if total, err := tarefa.TempoTarefa(parado.Registro.TaskID); err == nil {
+			cmd.Printf("Tempo total na tarefa: %s\n", tarefa.FormatarDuracao(total))
+		}
+		return nil
+	},
+}
+
+var tarefaRegistrarCmd = &cobra.Command{
+	Use:   "registrar <ID da tarefa> <duração>",
+	Short: "Registra manualmente o tempo gasto em uma tarefa",
+	Long: `Registra manualmente um tempo de trabalho em uma tarefa, sem usar o cronômetro.
+A duração aceita, por exemplo, 45m, 1h30m, 1h30 ou 2h. Sem --data, o intervalo termina agora.
+Exemplo: vickgenda tarefa registrar <ID> 45m --data "ontem 14h"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, _ := cmd.Flags().GetString("data")
+		registro, err := tarefa.RegistrarTempo(args[0], args[1], data)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("%s registrados na tarefa '%s' (%s - %s).\n", tarefa.FormatarDuracao(registro.Duration(registro.EndTime)), args[0],
+			registro.StartTime.Local().Format("02/01/2006 15:04"), registro.EndTime.Local().Format("15:04"))
+		if total, err := tarefa.TempoTarefa(args[0]); err == nil {
+			cmd.Printf("Tempo total na tarefa: %s\n", tarefa.FormatarDuracao(total))
+		}
+		return nil
+	},
+}
+
+var tarefaQuadroCmd = &cobra.Command{
+	Use:   "quadro",
+	Short: "Abre o quadro kanban das tarefas",
+	Long: `Abre um quadro interativo com uma coluna por status: Pendente, Em Andamento e Concluída.
+Os cartões são ordenados pelo prazo, que fica amarelo quando está próximo, laranja no dia e vermelho quando atrasado.
+Teclas: ←/→ escolhe a coluna, ↑/↓ o cartão, < e > movem o cartão para a coluna ao lado;
+e edita a descrição, d o prazo, t as tags e 1, 2 ou 3 definem a prioridade;
+/ filtra por uma tag, f alterna o filtro de prioridade e esc limpa os filtros; q sai.
+As alterações são gravadas na hora, como em 'tarefa editar' e 'tarefa concluir', e cada uma pode ser revertida com 'vickgenda desfazer'.
+Exemplo: vickgenda tarefa quadro --tag provas`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{AnotacaoHistoricoPorAcao: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag, _ := cmd.Flags().GetString("tag")
+		prioridade, _ := cmd.Flags().GetInt("prioridade")
+		if prioridade < 0 || prioridade > 3 {
+			return errors.New("erro: nível de prioridade inválido. Use 1, 2 ou 3")
+		}
+
+		p := tea.NewProgram(tui.NovoQuadro(tag, prioridade), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("erro ao executar o quadro: %w", err)
+		}
+		return nil
+	},
+}
+
+var tarefaProximaCmd = &cobra.Command{
+	Use:   "proxima",
+	Short: "Mostra a tarefa mais urgente entre as prontas para serem feitas",
+	Long: `Mostra a tarefa de maior urgência entre as não concluídas sem pré-requisitos abertos.
+A urgência soma prioridade, proximidade do prazo, idade, dependências e tags, com os pesos de 'tarefa urgencia pesos'.
+Com --quantidade, mostra as N tarefas mais urgentes. Exemplo: vickgenda tarefa proxima --quantidade 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		quantidade, _ := cmd.Flags().GetInt("quantidade")
+		if quantidade < 1 {
+			return errors.New("erro: --quantidade deve ser pelo menos 1")
+		}
+		proximas, err := tarefa.ProximasTarefas(quantidade)
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		if len(proximas) == 0 {
+			cmd.Println("Nenhuma tarefa pronta para ser feita.")
+			return nil
+		}
+
+		if quantidade == 1 {
+			p := proximas[0]
+			fmt.Printf("Próxima tarefa: %s (%s)\n", p.Tarefa.Description, p.Tarefa.ID)
+			if !p.Tarefa.DueDate.IsZero() {
+				fmt.Printf("Prazo: %s\n", p.Tarefa.DueDate.Format("02/01/2006"))
+			}
+			fmt.Printf("Prioridade: %d\n", p.Tarefa.Priority)
+			fmt.Printf("Urgência: %s (%s)\n", p.Urgencia, p.Urgencia.Detalhes())
+			return nil
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Descrição", "Prazo", "Prioridade", "Urgência"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, p := range proximas {
+			prazo := "-"
+			if !p.Tarefa.DueDate.IsZero() {
+				prazo = p.Tarefa.DueDate.Format("02/01/2006")
+			}
+			table.Append([]string{p.Tarefa.ID, p.Tarefa.Description, prazo, strconv.Itoa(p.Tarefa.Priority), p.Urgencia.String()})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var tarefaUrgenciaCmd = &cobra.Command{
+	Use:   "urgencia",
+	Short: "Mostra e ajusta os pesos da urgência das tarefas",
+	Long: `A urgência de uma tarefa é a soma de critérios multiplicados por pesos: prioridade, proximidade do prazo,
+idade, se a tarefa bloqueia ou está bloqueada por outras, se está em andamento e quantas tags tem.
+Pesos "tag.<nome>" somam um valor às tarefas com a tag (ex: tag.provas 3; negativos diminuem a urgência).
+A urgência ordena 'tarefa listar --ordenar urgencia', escolhe 'tarefa proxima' e o foco do dia do dashboard.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var tarefaUrgenciaPesosCmd = &cobra.Command{
+	Use:   "pesos",
+	Short: "Lista os pesos da urgência",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pesos, err := tarefa.CarregarPesos()
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		formatar := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Peso", "Valor", "Padrão", "Descrição"})
+		table.SetBorder(true)
+		table.SetAutoWrapText(false)
+		for _, c := range tarefa.CoeficientesUrgencia {
+			table.Append([]string{c.Nome, formatar(pesos[c.Nome]), formatar(c.Padrao), c.Descricao})
+		}
+		var tags []string
+		for nome := range pesos {
+			if strings.HasPrefix(nome, "tag.") {
+				tags = append(tags, nome)
+			}
+		}
+		sort.Strings(tags)
+		for _, nome := range tags {
+			table.Append([]string{nome, formatar(pesos[nome]), "0", "se a tarefa tem a tag " + strings.TrimPrefix(nome, "tag.")})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var tarefaUrgenciaDefinirCmd = &cobra.Command{
+	Use:   "definir <peso> <valor>",
+	Short: "Altera um peso da urgência",
+	Long: `Altera um dos pesos listados em 'tarefa urgencia pesos', ou o peso de uma tag com "tag.<nome>".
+Exemplos:
+  vickgenda tarefa urgencia definir prazo 15
+  vickgenda tarefa urgencia definir tag.pessoal -- -2`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		valor, err := tarefa.DefinirPeso(args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Peso '%s' definido como %s.\n", strings.ToLower(args[0]), strconv.FormatFloat(valor, 'f', -1, 64))
+		return nil
+	},
+}
+
+var tarefaUrgenciaRestaurarCmd = &cobra.Command{
+	Use:   "restaurar <peso>",
+	Short: "Volta um peso da urgência ao valor padrão",
+	Long:  `Volta um peso ao valor padrão. Para um peso "tag.<nome>", remove o peso da tag.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := tarefa.RestaurarPeso(args[0]); err != nil {
+			return fmt.Errorf("erro: %w", err)
+		}
+		cmd.Printf("Peso '%s' restaurado.\n", strings.ToLower(args[0]))
 		return nil
 	},
 }
@@ -29,13 +956,81 @@ to quickly create a Cobra application.`,
 func init() {
 	// rootCmd.AddCommand(TarefaCmd) // This will be done in cmd/cli/cli.go
 
-	// Here you will define your flags and configuration settings.
+	tarefaCriarCmd.Flags().String("descricao", "", "Texto descritivo da tarefa (obrigatório)")
+	tarefaCriarCmd.Flags().String("prazo", "", "Data de vencimento ("+datas.Exemplos+")")
+	tarefaCriarCmd.Flags().Int("prioridade", 2, "Prioridade (1-Alta, 2-Média, 3-Baixa)")
+	tarefaCriarCmd.Flags().String("tags", "", "Tags separadas por vírgula")
+	tarefaCriarCmd.Flags().String("pai", "", "ID da tarefa pai; cria a tarefa como subtarefa")
+	tarefaCriarCmd.Flags().StringSlice("depende-de", nil, "IDs das tarefas pré-requisito, separados por vírgula")
+	tarefaCriarCmd.Flags().String("projeto", "", "Nome ou ID do projeto da tarefa")
+	tarefaCriarCmd.Flags().StringSlice("vincular", nil, "Vínculos tipo:id separados por vírgula (ex: prova:prova123,turma:7B)")
+	tarefaCriarCmd.MarkFlagRequired("descricao")
+
+	tarefaListarCmd.Flags().String("status", "", "Filtra pelo status (ex: Pendente, Em Andamento, Concluída)")
+	tarefaListarCmd.Flags().Int("prioridade", 0, "Filtra pela prioridade")
+	tarefaListarCmd.Flags().String("prazo-ate", "", "Lista tarefas com prazo até a data ("+datas.Exemplos+")")
+	tarefaListarCmd.Flags().String("tag", "", "Filtra por uma tag")
+	tarefaListarCmd.Flags().String("ordenar-por", "CreatedAt", "Campo de ordenação: prazo, prioridade, descricao, status, CreatedAt, urgencia (--ordenar também é aceito)")
+	tarefaListarCmd.Flags().String("ordem", "asc", "Ordem de classificação: asc ou desc")
+	tarefaListarCmd.Flags().Bool("arvore", false, "Mostra as subtarefas indentadas abaixo da tarefa pai")
+	tarefaListarCmd.Flags().Bool("prontas", false, "Mostra apenas as tarefas não concluídas com todos os pré-requisitos concluídos")
+	tarefaListarCmd.Flags().String("projeto", "", "Filtra pelo projeto (nome ou ID)")
+	tarefaListarCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, nome string) pflag.NormalizedName {
+		if nome == "ordenar" {
+			nome = "ordenar-por"
+		}
+		return pflag.NormalizedName(nome)
+	})
+
+	tarefaEditarCmd.Flags().String("descricao", "", "Nova descrição")
+	tarefaEditarCmd.Flags().String("prazo", "", "Novo prazo ("+datas.Exemplos+"); \" \" remove o prazo")
+	tarefaEditarCmd.Flags().Int("prioridade", 0, "Nova prioridade")
+	tarefaEditarCmd.Flags().String("status", "", "Novo status")
+	tarefaEditarCmd.Flags().String("tags", "", "Novas tags (substituem as atuais)")
+	tarefaEditarCmd.Flags().String("pai", "", "ID da nova tarefa pai")
+	tarefaEditarCmd.Flags().Bool("sem-pai", false, "Devolve a tarefa ao primeiro nível")
+	tarefaEditarCmd.Flags().String("projeto", "", "Nome ou ID do novo projeto da tarefa")
+	tarefaEditarCmd.Flags().Bool("sem-projeto", false, "Retira a tarefa do projeto")
+	tarefaEditarCmd.Flags().StringSlice("adicionar-tag", nil, "Tags a adicionar, separadas por vírgula")
+	tarefaEditarCmd.Flags().StringSlice("remover-tag", nil, "Tags a remover, separadas por vírgula")
+
+	// Operações em lote: editar, concluir e remover aceitam uma consulta no lugar do ID.
+	for _, c := range []*cobra.Command{tarefaEditarCmd, tarefaConcluirCmd, tarefaRemoverCmd} {
+		c.Flags().String("onde", "", "Consulta que seleciona as tarefas, no lugar do ID (veja 'tarefa listar --help')")
+		c.Flags().Bool("simular", false, "Com --onde, apenas mostra as tarefas selecionadas, sem alterá-las")
+	}
+	tarefaEditarCmd.Flags().Bool("force", false, "Com --onde, edita sem pedir confirmação")
+	tarefaConcluirCmd.Flags().Bool("force", false, "Com --onde, conclui sem pedir confirmação")
+	tarefaRemoverCmd.Flags().Bool("force", false, "Remove sem pedir confirmação")
+
+	tarefaQuadroCmd.Flags().String("tag", "", "Mostra apenas as tarefas com a tag")
+	tarefaQuadroCmd.Flags().Int("prioridade", 0, "Mostra apenas as tarefas com a prioridade (1, 2 ou 3)")
+
+	tarefaProximaCmd.Flags().Int("quantidade", 1, "Quantas tarefas mostrar, da mais urgente para a menos urgente")
+
+	tarefaRegistrarCmd.Flags().String("data", "", "Quando o trabalho foi feito (ex: \"ontem 14h\", \"15/08\"); sem horário, a partir da meia-noite")
+
+	TarefaCmd.AddCommand(tarefaCriarCmd)
+	TarefaCmd.AddCommand(tarefaListarCmd)
+	TarefaCmd.AddCommand(tarefaQuadroCmd)
+	TarefaCmd.AddCommand(tarefaProximaCmd)
+	TarefaCmd.AddCommand(tarefaVerCmd)
+	TarefaCmd.AddCommand(tarefaEditarCmd)
+	TarefaCmd.AddCommand(tarefaConcluirCmd)
+	TarefaCmd.AddCommand(tarefaRemoverCmd)
+	TarefaCmd.AddCommand(tarefaIniciarCmd)
+	TarefaCmd.AddCommand(tarefaPausarCmd)
+	TarefaCmd.AddCommand(tarefaRegistrarCmd)
+	TarefaCmd.AddCommand(tarefaVincularCmd)
+	TarefaCmd.AddCommand(tarefaDesvincularCmd)
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// tarefaCmd.PersistentFlags().String("foo", "", "A help for foo")
+	tarefaDependenciaCmd.AddCommand(tarefaDependenciaAdicionarCmd)
+	tarefaDependenciaCmd.AddCommand(tarefaDependenciaRemoverCmd)
+	tarefaDependenciaCmd.AddCommand(tarefaDependenciaListarCmd)
+	TarefaCmd.AddCommand(tarefaDependenciaCmd)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// tarefaCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	tarefaUrgenciaCmd.AddCommand(tarefaUrgenciaPesosCmd)
+	tarefaUrgenciaCmd.AddCommand(tarefaUrgenciaDefinirCmd)
+	tarefaUrgenciaCmd.AddCommand(tarefaUrgenciaRestaurarCmd)
+	TarefaCmd.AddCommand(tarefaUrgenciaCmd)
 }
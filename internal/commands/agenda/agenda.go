@@ -1,41 +1,612 @@
-/*
-Copyright © 2025 NAME HERE <EMAIL ADDRESS>
-
-*/
-package cmd
+package agenda
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/spf13/cobra"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
 )
 
-// AgendaCmd represents the agenda command
-var AgendaCmd = &cobra.Command{
-	Use:   "agenda",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+// Os eventos são persistidos na tabela "events" do banco SQLite através das funções
+// db.CreateEvent, db.GetEvent, db.ListEvents, db.UpdateEvent e db.DeleteEvent.
+// O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
+
+const (
+	dateTimeLayout = "2006-01-02 15:04"
+	dateLayout     = "2006-01-02"
+)
+
+// erroEventoNaoEncontrado padroniza a mensagem de erro para IDs inexistentes.
+func erroEventoNaoEncontrado(id string) error {
+	return fmt.Errorf("evento com ID '%s' não encontrado", id)
+}
+
+// parseDataHora interpreta uma expressão de data com horário do pacote datas ("YYYY-MM-DD HH:MM", "amanhã 14h",
+// "15/08 9h30") no fuso local. O horário é obrigatório.
+// campo identifica o valor na mensagem de erro (ex: "início", "término").
+func parseDataHora(valor, campo string) (time.Time, error) {
+	r, err := datas.DataHora(valor)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("formato de data/hora inválido para %s: %w", campo, err)
+	}
+	if !r.TemHorario {
+		return time.Time{}, fmt.Errorf("formato de data/hora inválido para %s: informe também o horário (ex: \"%s 14h\")", campo, strings.TrimSpace(valor))
+	}
+	return r.Momento, nil
+}
+
+// inicioDoDia retorna a meia-noite (fuso local) do dia de t.
+func inicioDoDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// sortColumnEvento traduz o campo de ordenação aceito pela CLI para a coluna correspondente da tabela events.
+func sortColumnEvento(sortBy string) string {
+	switch strings.ToLower(sortBy) {
+	case "titulo":
+		return "title"
+	case "fim":
+		return "end_time"
+	case "local":
+		return "location"
+	default: // "inicio" ou qualquer outro
+		return "start_time"
+	}
+}
+
+// AdicionarEvento cria um novo evento na agenda.
+// titulo, inicioStr e fimStr são obrigatórios; as datas são expressões com horário ("YYYY-MM-DD HH:MM", "amanhã 14h").
+// A hora de término deve ser posterior à hora de início.
+// Retorna o evento criado ou um erro de validação.
+func AdicionarEvento(titulo string, inicioStr string, fimStr string, descricao string, local string) (models.Event, error) {
+	return AdicionarEventoRecorrente(titulo, inicioStr, fimStr, descricao, local, "", "")
+}
+
+// AdicionarEventoRecorrente cria um evento que se repete conforme uma regra RRULE do RFC 5545
+// (ex: "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20241220"). inicioStr e fimStr descrevem a primeira ocorrência.
+// excecoesStr é uma lista opcional, separada por vírgulas, de inícios de ocorrências a excluir
+// ("YYYY-MM-DD HH:MM", equivalente a EXDATE). Com regraStr vazia, cria um evento único.
+func AdicionarEventoRecorrente(titulo, inicioStr, fimStr, descricao, local, regraStr, excecoesStr string) (models.Event, error) {
+	if strings.TrimSpace(titulo) == "" {
+		return models.Event{}, errors.New("o título do evento é obrigatório")
+	}
+	inicio, err := parseDataHora(inicioStr, "início")
+	if err != nil {
+		return models.Event{}, err
+	}
+	fim, err := parseDataHora(fimStr, "término")
+	if err != nil {
+		return models.Event{}, err
+	}
+	if !fim.After(inicio) {
+		return models.Event{}, errors.New("a hora de término deve ser posterior à hora de início")
+	}
+
+	now := time.Now()
+	evento := models.Event{
+		Title:       titulo,
+		Description: descricao,
+		StartTime:   inicio,
+		EndTime:     fim,
+		Location:    local,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if strings.TrimSpace(regraStr) != "" {
+		regra, err := parseRegraRecorrencia(regraStr)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("regra de recorrência inválida: %w", err)
+		}
+		evento.RecurrenceRule = regra.String()
+	}
+	if strings.TrimSpace(excecoesStr) != "" {
+		if evento.RecurrenceRule == "" {
+			return models.Event{}, errors.New("exceções só podem ser usadas em eventos recorrentes")
+		}
+		for _, item := range strings.Split(excecoesStr, ",") {
+			excecao, err := parseDataHora(item, "exceção")
+			if err != nil {
+				return models.Event{}, err
+			}
+			evento.ExceptionDates = append(evento.ExceptionDates, excecao)
+		}
+	}
+
+	id, err := db.CreateEvent(evento)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("erro ao salvar o evento: %w", err)
+	}
+	evento.ID = id
+	return evento, nil
+}
+
+// intervaloDoPeriodo calcula o intervalo [inicio, fim) correspondente a um período da CLI.
+// Um fim zero indica intervalo aberto (todos os eventos a partir de inicio).
+// Se dataInicioStr ou dataFimStr forem informadas, o período é tratado como "custom";
+// nesse caso ambas são obrigatórias e o dia final é incluído por completo.
+func intervaloDoPeriodo(periodo, dataInicioStr, dataFimStr string, now time.Time) (time.Time, time.Time, error) {
+	periodo = strings.ToLower(strings.TrimSpace(periodo))
+	if dataInicioStr != "" || dataFimStr != "" {
+		periodo = "custom"
+	}
+	hoje := inicioDoDia(now)
+
+	switch periodo {
+	case "", "proximos":
+		return now, time.Time{}, nil
+	case "dia":
+		return hoje, hoje.AddDate(0, 0, 1), nil
+	case "semana":
+		return hoje, hoje.AddDate(0, 0, 7), nil
+	case "mes":
+		return hoje, hoje.AddDate(0, 0, 30), nil
+	case "custom":
+		if dataInicioStr == "" || dataFimStr == "" {
+			return time.Time{}, time.Time{}, errors.New("para período customizado, forneça --data-inicio e --data-fim")
+		}
+		inicio, err := datas.Data(dataInicioStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("formato de data inválido para --data-inicio: %w", err)
+		}
+		fim, err := datas.Data(dataFimStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("formato de data inválido para --data-fim: %w", err)
+		}
+		if fim.Before(inicio) {
+			return time.Time{}, time.Time{}, errors.New("a data de fim deve ser igual ou posterior à data de início")
+		}
+		return inicio, fim.AddDate(0, 0, 1), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("período '%s' inválido", periodo)
+	}
+}
+
+// ListarEventos retorna os eventos de um período, ordenados conforme solicitado.
+// periodo: "dia" (hoje), "semana" (próximos 7 dias), "mes" (próximos 30 dias),
+// "proximos" (eventos futuros ou em andamento; padrão) ou "custom".
+// dataInicioStr e dataFimStr (expressões do pacote datas, ex: "2024-08-01" ou "fim do mês") definem o período customizado e são obrigatórias juntas.
+// sortBy: "inicio" (padrão), "fim", "titulo" ou "local". sortOrder: "asc" (padrão) ou "desc".
+// Um evento é incluído quando qualquer parte dele ocorre dentro do período.
+// Eventos recorrentes aparecem uma vez por ocorrência no período; em "proximos", que não tem fim,
+// cada série contribui apenas com a sua próxima ocorrência.
+func ListarEventos(periodo string, dataInicioStr string, dataFimStr string, sortBy string, sortOrder string) ([]models.Event, error) {
+	inicio, fim, err := intervaloDoPeriodo(periodo, dataInicioStr, dataFimStr, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return listarNoIntervalo(inicio, fim, sortColumnEvento(sortBy), sortOrder, 0)
+}
+
+// listarNoIntervalo busca os eventos que se sobrepõem a [inicio, fim), expandindo as séries recorrentes.
+// Um fim zero deixa o intervalo aberto; nesse caso cada série contribui com até limite ocorrências
+// (ou apenas uma, se limite <= 0). O limite total é aplicado após a expansão e a ordenação.
+func listarNoIntervalo(inicio, fim time.Time, sortColumn, sortOrder string, limite int) ([]models.Event, error) {
+	filters := map[string]interface{}{"range_start": inicio}
+	if !fim.IsZero() {
+		filters["range_end"] = fim
+	}
+	registros, _, err := db.ListEvents(filters, "start_time", "asc", 0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+
+	maxPorSerie := 1
+	if limite > 0 {
+		maxPorSerie = limite
+	}
+	eventos := []models.Event{}
+	for _, registro := range registros {
+		ocorrencias, err := expandirEvento(registro, inicio, fim, maxPorSerie)
+		if err != nil {
+			return nil, err
+		}
+		eventos = append(eventos, ocorrencias...)
+	}
+
+	ordenarEventos(eventos, sortColumn, sortOrder)
+	if limite > 0 && len(eventos) > limite {
+		eventos = eventos[:limite]
+	}
+	return eventos, nil
+}
+
+// expandirEvento devolve as ocorrências de evento que se sobrepõem a [inicio, fim).
+// Eventos sem recorrência são devolvidos como estão. Cada ocorrência mantém o ID do evento mestre
+// e tem StartTime/EndTime deslocados; ocorrências listadas em ExceptionDates são omitidas.
+// Com fim zero, no máximo maxPorSerie ocorrências são devolvidas.
+func expandirEvento(evento models.Event, inicio, fim time.Time, maxPorSerie int) ([]models.Event, error) {
+	if evento.RecurrenceRule == "" {
+		return []models.Event{evento}, nil
+	}
+	regra, err := parseRegraRecorrencia(evento.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("regra de recorrência inválida no evento '%s': %w", evento.ID, err)
+	}
+
+	duracao := evento.EndTime.Sub(evento.StartTime)
+	var ocorrencias []models.Event
+	regra.ocorrencias(evento.StartTime, func(t time.Time) bool {
+		if !fim.IsZero() && !t.Before(fim) {
+			return false
+		}
+		if t.Add(duracao).After(inicio) && !ehExcecao(evento, t) {
+			ocorrencia := evento
+			ocorrencia.StartTime = t
+			ocorrencia.EndTime = t.Add(duracao)
+			ocorrencias = append(ocorrencias, ocorrencia)
+		}
+		return !fim.IsZero() || len(ocorrencias) < maxPorSerie
+	})
+	return ocorrencias, nil
+}
+
+// ehExcecao informa se a ocorrência iniciada em t foi excluída da série (EXDATE).
+func ehExcecao(evento models.Event, t time.Time) bool {
+	for _, excecao := range evento.ExceptionDates {
+		if excecao.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ordenarEventos ordena as ocorrências já expandidas pela coluna de sortColumnEvento.
+// Empates são resolvidos pelo horário de início.
+func ordenarEventos(eventos []models.Event, sortColumn, sortOrder string) {
+	desc := strings.ToLower(sortOrder) == "desc"
+	chave := func(e models.Event) string {
+		switch sortColumn {
+		case "title":
+			return strings.ToLower(e.Title)
+		case "location":
+			return strings.ToLower(e.Location)
+		case "end_time":
+			return e.EndTime.UTC().Format(time.RFC3339)
+		default:
+			return e.StartTime.UTC().Format(time.RFC3339)
+		}
+	}
+	sort.SliceStable(eventos, func(i, j int) bool {
+		a, b := chave(eventos[i]), chave(eventos[j])
+		if a == b {
+			return eventos[i].StartTime.Before(eventos[j].StartTime)
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("agenda called, logic to be implemented.")
-		return nil
-	},
+// VerDia retorna os eventos que ocorrem (total ou parcialmente) no dia informado, em ordem de início.
+// diaStr é uma expressão do pacote datas (ex: "2024-08-15", "amanhã", "sexta"); se vazio, usa o dia atual.
+func VerDia(diaStr string) ([]models.Event, error) {
+	dia := inicioDoDia(time.Now())
+	if strings.TrimSpace(diaStr) != "" {
+		var err error
+		dia, err = datas.Data(diaStr)
+		if err != nil {
+			return nil, fmt.Errorf("formato de data inválido: %w", err)
+		}
+	}
+	return listarNoIntervalo(dia, dia.AddDate(0, 0, 1), "start_time", "asc", 0)
 }
 
-func init() {
-	// rootCmd.AddCommand(AgendaCmd) // This will be done in cmd/cli/cli.go
+// EditarEvento atualiza os campos de um evento existente.
+// Campos vazios não são alterados; ao menos um deve ser informado.
+// As datas são expressões com horário ("YYYY-MM-DD HH:MM", "sexta 10h") e o término continua obrigatoriamente posterior ao início.
+// Para um evento recorrente, a alteração vale para toda a série.
+// Retorna o evento atualizado ou um erro se o evento não for encontrado ou a validação falhar.
+func EditarEvento(id string, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal string) (models.Event, error) {
+	evento, err := GetEventoByID(id)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if err := aplicarAlteracoes(&evento, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal); err != nil {
+		return models.Event{}, err
+	}
+	if err := salvarEvento(evento); err != nil {
+		return models.Event{}, err
+	}
+	return evento, nil
+}
 
-	// Here you will define your flags and configuration settings.
+// aplicarAlteracoes aplica em evento os campos não vazios de uma edição e valida o resultado.
+func aplicarAlteracoes(evento *models.Event, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal string) error {
+	updated := false
+	if novoTitulo != "" {
+		evento.Title = novoTitulo
+		updated = true
+	}
+	if novoInicioStr != "" {
+		inicio, err := parseDataHora(novoInicioStr, "início")
+		if err != nil {
+			return err
+		}
+		evento.StartTime = inicio
+		updated = true
+	}
+	if novoFimStr != "" {
+		fim, err := parseDataHora(novoFimStr, "término")
+		if err != nil {
+			return err
+		}
+		evento.EndTime = fim
+		updated = true
+	}
+	if novaDesc != "" {
+		evento.Description = novaDesc
+		updated = true
+	}
+	if novoLocal != "" {
+		evento.Location = novoLocal
+		updated = true
+	}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// agendaCmd.PersistentFlags().String("foo", "", "A help for foo")
+	if !updated {
+		return errors.New("nenhuma alteração especificada")
+	}
+	if !evento.EndTime.After(evento.StartTime) {
+		return errors.New("a hora de término deve ser posterior à hora de início")
+	}
+	evento.UpdatedAt = time.Now()
+	return nil
+}
+
+// salvarEvento persiste as alterações de um evento existente.
+func salvarEvento(evento models.Event) error {
+	if err := db.UpdateEvent(evento); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroEventoNaoEncontrado(evento.ID)
+		}
+		return fmt.Errorf("erro ao salvar o evento: %w", err)
+	}
+	return nil
+}
+
+// EditarRecorrencia define uma nova regra RRULE para o evento, transformando-o em série
+// ou alterando a série existente. regraStr igual a "nenhuma" remove a recorrência e as exceções.
+func EditarRecorrencia(id string, regraStr string) (models.Event, error) {
+	evento, err := GetEventoByID(id)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if evento.SeriesID != "" {
+		return models.Event{}, errors.New("uma ocorrência editada isoladamente não pode ter recorrência própria")
+	}
+
+	if strings.EqualFold(strings.TrimSpace(regraStr), "nenhuma") {
+		evento.RecurrenceRule = ""
+		evento.ExceptionDates = nil
+	} else {
+		regra, err := parseRegraRecorrencia(regraStr)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("regra de recorrência inválida: %w", err)
+		}
+		evento.RecurrenceRule = regra.String()
+	}
+
+	evento.UpdatedAt = time.Now()
+	if err := salvarEvento(evento); err != nil {
+		return models.Event{}, err
+	}
+	return evento, nil
+}
+
+// buscarOcorrencia carrega o mestre de uma série e valida que ocorrenciaStr ("YYYY-MM-DD HH:MM")
+// é o início de uma de suas ocorrências não excluídas.
+func buscarOcorrencia(id, ocorrenciaStr string) (models.Event, regraRecorrencia, time.Time, error) {
+	mestre, err := GetEventoByID(id)
+	if err != nil {
+		return models.Event{}, regraRecorrencia{}, time.Time{}, err
+	}
+	if mestre.RecurrenceRule == "" {
+		return models.Event{}, regraRecorrencia{}, time.Time{}, fmt.Errorf("o evento '%s' não é recorrente", id)
+	}
+	regra, err := parseRegraRecorrencia(mestre.RecurrenceRule)
+	if err != nil {
+		return models.Event{}, regraRecorrencia{}, time.Time{}, fmt.Errorf("regra de recorrência inválida no evento '%s': %w", id, err)
+	}
+	ocorrencia, err := parseDataHora(ocorrenciaStr, "ocorrência")
+	if err != nil {
+		return models.Event{}, regraRecorrencia{}, time.Time{}, err
+	}
+
+	encontrada := false
+	regra.ocorrencias(mestre.StartTime, func(t time.Time) bool {
+		if t.Equal(ocorrencia) {
+			encontrada = true
+		}
+		return t.Before(ocorrencia)
+	})
+	if !encontrada || ehExcecao(mestre, ocorrencia) {
+		return models.Event{}, regraRecorrencia{}, time.Time{}, fmt.Errorf("o evento '%s' não tem ocorrência em %s", id, ocorrencia.Format(dateTimeLayout))
+	}
+	return mestre, regra, ocorrencia, nil
+}
+
+// EditarOcorrencia altera apenas uma ocorrência de um evento recorrente ("somente esta").
+// A ocorrência, identificada pelo seu início ("YYYY-MM-DD HH:MM"), é excluída da série e
+// substituída por um evento avulso vinculado ao mestre (SeriesID/OriginalStartTime).
+// Retorna o evento avulso criado.
+func EditarOcorrencia(id, ocorrenciaStr string, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal string) (models.Event, error) {
+	mestre, _, ocorrencia, err := buscarOcorrencia(id, ocorrenciaStr)
+	if err != nil {
+		return models.Event{}, err
+	}
+
+	avulso := mestre
+	avulso.ID = ""
+	avulso.RecurrenceRule = ""
+	avulso.ExceptionDates = nil
+	avulso.SeriesID = mestre.ID
+	avulso.ICalUID = ""
+	avulso.OriginalStartTime = ocorrencia
+	avulso.StartTime = ocorrencia
+	avulso.EndTime = ocorrencia.Add(mestre.EndTime.Sub(mestre.StartTime))
+	if err := aplicarAlteracoes(&avulso, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal); err != nil {
+		return models.Event{}, err
+	}
+	avulso.CreatedAt = avulso.UpdatedAt
+
+	avulso.ID, err = db.CreateEvent(avulso)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("erro ao salvar a ocorrência: %w", err)
+	}
+
+	mestre.ExceptionDates = append(mestre.ExceptionDates, ocorrencia)
+	mestre.UpdatedAt = time.Now()
+	if err := salvarEvento(mestre); err != nil {
+		db.DeleteEvent(avulso.ID)
+		return models.Event{}, err
+	}
+	return avulso, nil
+}
+
+// EditarOcorrenciasSeguintes altera uma ocorrência e todas as seguintes ("esta e as seguintes").
+// A série original passa a terminar antes da ocorrência (ajustando UNTIL ou COUNT) e uma nova série,
+// com as alterações aplicadas, começa na ocorrência. Exceções e ocorrências avulsas posteriores
+// são transferidas para a nova série. Se a ocorrência for a primeira, a série inteira é editada.
+// Retorna o mestre da nova série (ou o mestre original, no caso da primeira ocorrência).
+func EditarOcorrenciasSeguintes(id, ocorrenciaStr string, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal string) (models.Event, error) {
+	mestre, regra, ocorrencia, err := buscarOcorrencia(id, ocorrenciaStr)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if ocorrencia.Equal(mestre.StartTime) {
+		return EditarEvento(id, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal)
+	}
+
+	// COUNT inclui as ocorrências excluídas por EXDATE, então a contagem usa a regra pura.
+	anteriores := 0
+	regra.ocorrencias(mestre.StartTime, func(t time.Time) bool {
+		if !t.Before(ocorrencia) {
+			return false
+		}
+		anteriores++
+		return true
+	})
+
+	regraAnterior, regraNova := regra, regra
+	if regra.Count > 0 {
+		regraAnterior.Count = anteriores
+		regraNova.Count = regra.Count - anteriores
+	} else {
+		regraAnterior.Until = ocorrencia.Add(-time.Second)
+	}
+
+	novaSerie := mestre
+	novaSerie.ID = ""
+	novaSerie.ICalUID = "" // O UID pertence à série original.
+	novaSerie.RecurrenceRule = regraNova.String()
+	novaSerie.StartTime = ocorrencia
+	novaSerie.EndTime = ocorrencia.Add(mestre.EndTime.Sub(mestre.StartTime))
+	novaSerie.ExceptionDates = nil
+	var excecoesAnteriores []time.Time
+	for _, excecao := range mestre.ExceptionDates {
+		if excecao.Before(ocorrencia) {
+			excecoesAnteriores = append(excecoesAnteriores, excecao)
+		} else {
+			novaSerie.ExceptionDates = append(novaSerie.ExceptionDates, excecao)
+		}
+	}
+	mestre.ExceptionDates = excecoesAnteriores
+	if err := aplicarAlteracoes(&novaSerie, novoTitulo, novoInicioStr, novoFimStr, novaDesc, novoLocal); err != nil {
+		return models.Event{}, err
+	}
+	novaSerie.CreatedAt = novaSerie.UpdatedAt
+
+	novaSerie.ID, err = db.CreateEvent(novaSerie)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("erro ao salvar a nova série: %w", err)
+	}
+
+	mestre.RecurrenceRule = regraAnterior.String()
+	mestre.UpdatedAt = time.Now()
+	if err := salvarEvento(mestre); err != nil {
+		db.DeleteEvent(novaSerie.ID)
+		return models.Event{}, err
+	}
+
+	avulsos, _, err := db.ListEvents(map[string]interface{}{"series_id": mestre.ID}, "", "", 0, 1)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("erro ao buscar ocorrências avulsas: %w", err)
+	}
+	for _, avulso := range avulsos {
+		if avulso.OriginalStartTime.Before(ocorrencia) {
+			continue
+		}
+		avulso.SeriesID = novaSerie.ID
+		if err := salvarEvento(avulso); err != nil {
+			return models.Event{}, err
+		}
+	}
+	return novaSerie, nil
+}
+
+// RemoverOcorrencia exclui uma única ocorrência de um evento recorrente, adicionando-a às exceções (EXDATE).
+// ocorrenciaStr é o início da ocorrência no formato "YYYY-MM-DD HH:MM".
+func RemoverOcorrencia(id, ocorrenciaStr string) error {
+	mestre, _, ocorrencia, err := buscarOcorrencia(id, ocorrenciaStr)
+	if err != nil {
+		return err
+	}
+	mestre.ExceptionDates = append(mestre.ExceptionDates, ocorrencia)
+	mestre.UpdatedAt = time.Now()
+	return salvarEvento(mestre)
+}
+
+// RemoverEvento exclui um evento pelo seu ID.
+// Para um evento recorrente, remove a série inteira, incluindo as ocorrências editadas isoladamente.
+// Retorna um erro se o evento não for encontrado.
+func RemoverEvento(id string) error {
+	if err := db.DeleteEvent(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroEventoNaoEncontrado(id)
+		}
+		return fmt.Errorf("erro ao remover o evento: %w", err)
+	}
+	return nil
+}
+
+// GetEventoByID busca e retorna um evento pelo seu ID.
+func GetEventoByID(id string) (models.Event, error) {
+	evento, err := db.GetEvent(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Event{}, erroEventoNaoEncontrado(id)
+		}
+		return models.Event{}, fmt.Errorf("erro ao buscar o evento: %w", err)
+	}
+	return evento, nil
+}
+
+// ListarProximosXEventos retorna os próximos count eventos futuros ou em andamento, em ordem de início.
+// Ocorrências de eventos recorrentes contam individualmente.
+// Se count <= 0, retorna todos os eventos futuros ou em andamento, com a próxima ocorrência de cada série.
+func ListarProximosXEventos(count int) ([]models.Event, error) {
+	if count < 0 {
+		count = 0
+	}
+	return listarNoIntervalo(time.Now(), time.Time{}, "start_time", "asc", count)
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// agendaCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+// LimparEventosStore remove todos os eventos do banco de dados.
+// Esta função é primariamente destinada a ser usada em testes para garantir um estado limpo.
+func LimparEventosStore() {
+	conn := db.GetDB()
+	if conn == nil {
+		return
+	}
+	if _, err := conn.Exec("DELETE FROM events"); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao limpar eventos: %v\n", err)
+	}
 }
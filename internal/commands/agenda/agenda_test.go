@@ -1,12 +1,23 @@
-package cmd
+package agenda
 
 import (
+	"os"
 	"strings"
 	"testing"
 	"time"
+
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes de agenda.
+func TestMain(m *testing.M) {
+	db.InitTestDB("agenda")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
 // Helper to check if a slice of events contains an event with a specific ID
 func containsEvent(events []models.Event, id string) bool {
 	for _, event := range events {
@@ -55,15 +66,24 @@ func TestAdicionarEvento(t *testing.T) {
 		}
 	})
 
+	// "01/07/2024 10:00" é uma data brasileira válida desde que as datas usam o pacote datas;
+	// os casos inválidos abaixo usam uma hora impossível e um texto que não é data.
+	t.Run("Formato brasileiro aceito", func(t *testing.T) {
+		evento, err := AdicionarEvento("Título", "01/07/2024 10:00", "01/07/2024 11:00", "", "")
+		if err != nil || evento.StartTime.Format(dateTimeLayout) != "2024-07-01 10:00" || evento.EndTime.Format(dateTimeLayout) != "2024-07-01 11:00" {
+			t.Errorf("Esperado evento de 01/07/2024 10:00 a 11:00, obtido %+v (%v)", evento, err)
+		}
+	})
+
 	t.Run("Formato de início inválido", func(t *testing.T) {
-		_, err := AdicionarEvento("Título", "01/07/2024 10:00", "2024-07-01 11:00", "", "")
+		_, err := AdicionarEvento("Título", "31/07/2024 25:00", "2024-07-01 11:00", "", "")
 		if err == nil || !strings.Contains(err.Error(), "formato de data/hora inválido para início") {
 			t.Errorf("Esperado erro para formato de início inválido, obtido: %v", err)
 		}
 	})
 
 	t.Run("Formato de fim inválido", func(t *testing.T) {
-		_, err := AdicionarEvento("Título", "2024-07-01 10:00", "01/07/2024 11:00", "", "")
+		_, err := AdicionarEvento("Título", "2024-07-01 10:00", "quando der", "", "")
 		if err == nil || !strings.Contains(err.Error(), "formato de data/hora inválido para término") {
 			t.Errorf("Esperado erro para formato de fim inválido, obtido: %v", err)
 		}
@@ -84,12 +104,16 @@ func TestAdicionarEvento(t *testing.T) {
 func TestListarEventos(t *testing.T) {
 	LimparEventosStore()
 	now := time.Now()
-	e1, _ := AdicionarEvento("Evento Futuro 1", now.Add(2*time.Hour).Format(dateTimeLayout), now.Add(3*time.Hour).Format(dateTimeLayout), "", "")
+	// e1 fica sempre no dia seguinte: em now+2h ele cairia hoje sempre que o teste rodasse antes das 22h
+	// e entraria no período customizado (que inclui o dia de hoje inteiro), contrariando o esperado abaixo.
+	amanha := time.Date(now.Year(), now.Month(), now.Day()+1, 10, 0, 0, 0, now.Location())
+	e1, _ := AdicionarEvento("Evento Futuro 1", amanha.Format(dateTimeLayout), amanha.Add(1*time.Hour).Format(dateTimeLayout), "", "")
 	e2, _ := AdicionarEvento("Evento Futuro 2", now.Add(24*time.Hour).Format(dateTimeLayout), now.Add(25*time.Hour).Format(dateTimeLayout), "", "")
 	e3, _ := AdicionarEvento("Evento Passado", now.Add(-2*time.Hour).Format(dateTimeLayout), now.Add(-1*time.Hour).Format(dateTimeLayout), "", "")
-    // Evento que abrange hoje
-    todayStart := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, now.Location())
-    todayEnd := todayStart.Add(1 * time.Hour)
+    // Evento de hoje em andamento: aparece tanto no dia quanto entre os próximos, em qualquer hora do teste.
+    // Um horário fixo (ex: 09:00-10:00) deixaria de ser "próximo" quando o teste rodasse depois das 10h.
+    todayStart := now.Add(-30 * time.Minute)
+    todayEnd := now.Add(30 * time.Minute)
     eToday, _ := AdicionarEvento("Evento de Hoje", todayStart.Format(dateTimeLayout), todayEnd.Format(dateTimeLayout), "", "")
 
 
@@ -251,3 +275,117 @@ func TestRemoverEvento(t *testing.T) {
 		}
 	})
 }
+
+func TestEventosRecorrentes(t *testing.T) {
+	LimparEventosStore()
+	// Reunião de departamento às segundas e quartas, 4 ocorrências a partir de 01/07/2024 (segunda).
+	serie, err := AdicionarEventoRecorrente("Reunião de departamento", "2024-07-01 10:00", "2024-07-01 11:00", "", "Sala 3",
+		"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", "2024-07-03 10:00")
+	if err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+
+	t.Run("Expansão por dia respeita EXDATE", func(t *testing.T) {
+		eventos, err := VerDia("2024-07-08")
+		if err != nil {
+			t.Fatalf("VerDia falhou: %v", err)
+		}
+		if len(eventos) != 1 || eventos[0].ID != serie.ID || eventos[0].StartTime.Format(dateTimeLayout) != "2024-07-08 10:00" {
+			t.Errorf("Esperada a ocorrência de 08/07, obtido %+v", eventos)
+		}
+		excluida, _ := VerDia("2024-07-03")
+		if len(excluida) != 0 {
+			t.Errorf("Ocorrência de 03/07 está em EXDATE e não deveria aparecer: %+v", excluida)
+		}
+	})
+
+	t.Run("Período customizado lista cada ocorrência", func(t *testing.T) {
+		eventos, err := ListarEventos("custom", "2024-07-01", "2024-07-31", "inicio", "asc")
+		if err != nil {
+			t.Fatalf("ListarEventos falhou: %v", err)
+		}
+		var inicios []string
+		for _, e := range eventos {
+			inicios = append(inicios, e.StartTime.Format(dateTimeLayout))
+		}
+		if strings.Join(inicios, ",") != "2024-07-01 10:00,2024-07-08 10:00,2024-07-10 10:00" {
+			t.Errorf("Ocorrências inesperadas: %v", inicios)
+		}
+	})
+
+	t.Run("Editar somente esta ocorrência", func(t *testing.T) {
+		avulso, err := EditarOcorrencia(serie.ID, "2024-07-08 10:00", "", "2024-07-08 14:00", "2024-07-08 15:00", "", "Auditório")
+		if err != nil {
+			t.Fatalf("EditarOcorrencia falhou: %v", err)
+		}
+		if avulso.SeriesID != serie.ID || avulso.OriginalStartTime.Format(dateTimeLayout) != "2024-07-08 10:00" {
+			t.Errorf("Ocorrência avulsa sem vínculo com a série: %+v", avulso)
+		}
+		eventos, _ := VerDia("2024-07-08")
+		if len(eventos) != 1 || eventos[0].ID != avulso.ID || eventos[0].Location != "Auditório" {
+			t.Errorf("Esperada apenas a ocorrência editada em 08/07, obtido %+v", eventos)
+		}
+		if _, err := EditarOcorrencia(serie.ID, "2024-07-08 10:00", "Outro", "", "", "", ""); err == nil || !strings.Contains(err.Error(), "não tem ocorrência") {
+			t.Errorf("Esperado erro ao editar ocorrência já substituída, obtido: %v", err)
+		}
+	})
+
+	t.Run("Editar esta e as seguintes", func(t *testing.T) {
+		novaSerie, err := EditarOcorrenciasSeguintes(serie.ID, "2024-07-08 10:00", "", "", "", "", "")
+		if err == nil || !strings.Contains(err.Error(), "não tem ocorrência") {
+			t.Fatalf("Esperado erro para ocorrência já substituída, obtido: %v (%+v)", err, novaSerie)
+		}
+
+		novaSerie, err = EditarOcorrenciasSeguintes(serie.ID, "2024-07-10 10:00", "Reunião (novo horário)", "2024-07-10 16:00", "2024-07-10 17:00", "", "")
+		if err != nil {
+			t.Fatalf("EditarOcorrenciasSeguintes falhou: %v", err)
+		}
+		if novaSerie.RecurrenceRule != "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=1" {
+			t.Errorf("COUNT restante incorreto na nova série: %s", novaSerie.RecurrenceRule)
+		}
+		original, _ := GetEventoByID(serie.ID)
+		if original.RecurrenceRule != "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3" {
+			t.Errorf("Série original deveria terminar antes da ocorrência editada: %s", original.RecurrenceRule)
+		}
+		eventos, _ := VerDia("2024-07-10")
+		if len(eventos) != 1 || eventos[0].ID != novaSerie.ID || eventos[0].StartTime.Format(dateTimeLayout) != "2024-07-10 16:00" {
+			t.Errorf("Esperada apenas a nova série em 10/07, obtido %+v", eventos)
+		}
+	})
+
+	t.Run("Remover ocorrência e série", func(t *testing.T) {
+		if err := RemoverOcorrencia(serie.ID, "2024-07-01 10:00"); err != nil {
+			t.Fatalf("RemoverOcorrencia falhou: %v", err)
+		}
+		if eventos, _ := VerDia("2024-07-01"); len(eventos) != 0 {
+			t.Errorf("Ocorrência removida ainda aparece: %+v", eventos)
+		}
+		if err := RemoverEvento(serie.ID); err != nil {
+			t.Fatalf("RemoverEvento falhou: %v", err)
+		}
+		if eventos, _ := VerDia("2024-07-08"); len(eventos) != 0 {
+			t.Errorf("Ocorrência avulsa deveria ser removida com a série: %+v", eventos)
+		}
+	})
+}
+
+func TestListarProximosXEventosComRecorrencia(t *testing.T) {
+	LimparEventosStore()
+	amanha := time.Now().AddDate(0, 0, 1)
+	inicio := time.Date(amanha.Year(), amanha.Month(), amanha.Day(), 8, 0, 0, 0, time.Local)
+	serie, _ := AdicionarEventoRecorrente("Plantão diário", inicio.Format(dateTimeLayout), inicio.Add(time.Hour).Format(dateTimeLayout), "", "", "FREQ=DAILY", "")
+	unico, _ := AdicionarEvento("Conselho de classe", inicio.Add(26*time.Hour).Format(dateTimeLayout), inicio.Add(27*time.Hour).Format(dateTimeLayout), "", "")
+
+	eventos, err := ListarProximosXEventos(3)
+	if err != nil {
+		t.Fatalf("ListarProximosXEventos falhou: %v", err)
+	}
+	if len(eventos) != 3 || eventos[0].ID != serie.ID || eventos[1].ID != serie.ID || eventos[2].ID != unico.ID {
+		t.Errorf("Esperadas duas ocorrências do plantão seguidas do conselho, obtido %+v", eventos)
+	}
+
+	proximos, _ := ListarEventos("proximos", "", "", "", "")
+	if len(proximos) != 2 {
+		t.Errorf("Em 'proximos' cada série deveria aparecer uma vez, obtido %d eventos", len(proximos))
+	}
+}
@@ -0,0 +1,376 @@
+package agenda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// Detecção de conflitos entre eventos e busca de horários livres.
+// A sobreposição usa models.IntervalsOverlap: eventos que apenas se encostam
+// (um termina quando o outro começa) não conflitam.
+
+const (
+	// horizonteConflitos limita a verificação de séries recorrentes sem fim ao primeiro ano de ocorrências.
+	horizonteConflitos = 365 * 24 * time.Hour
+	// maxConflitosNaMensagem limita quantos conflitos são descritos em ErroConflito.Error.
+	maxConflitosNaMensagem = 3
+)
+
+// Conflito registra a sobreposição entre uma ocorrência do evento verificado e um evento já agendado.
+type Conflito struct {
+	Inicio    time.Time    // Início da ocorrência verificada.
+	Fim       time.Time    // Término da ocorrência verificada.
+	Existente models.Event // Ocorrência do evento já agendado que se sobrepõe a ela.
+}
+
+// String descreve o conflito para mensagens da CLI.
+func (c Conflito) String() string {
+	return fmt.Sprintf("'%s' em %s - %s", c.Existente.Title, c.Existente.StartTime.Format(dateTimeLayout), c.Existente.EndTime.Format("15:04"))
+}
+
+// ErroConflito é o erro usado para rejeitar um evento que se sobrepõe a eventos já agendados.
+type ErroConflito struct {
+	Conflitos []Conflito
+}
+
+func (e *ErroConflito) Error() string {
+	var descricoes []string
+	for i, c := range e.Conflitos {
+		if i == maxConflitosNaMensagem {
+			descricoes = append(descricoes, fmt.Sprintf("e mais %d", len(e.Conflitos)-maxConflitosNaMensagem))
+			break
+		}
+		descricoes = append(descricoes, c.String())
+	}
+	return fmt.Sprintf("o evento conflita com %d evento(s) já agendado(s): %s", len(e.Conflitos), strings.Join(descricoes, "; "))
+}
+
+// VerificarConflitos informa quais eventos já agendados se sobrepõem a um novo evento,
+// com os mesmos parâmetros de AdicionarEventoRecorrente (regraStr e excecoesStr podem ser vazias).
+// Para séries sem fim, apenas as ocorrências do primeiro ano são verificadas.
+// Nada é gravado: cabe a quem chama avisar o usuário ou rejeitar o evento (ver ErroConflito).
+func VerificarConflitos(inicioStr, fimStr, regraStr, excecoesStr string) ([]Conflito, error) {
+	inicio, err := parseDataHora(inicioStr, "início")
+	if err != nil {
+		return nil, err
+	}
+	fim, err := parseDataHora(fimStr, "término")
+	if err != nil {
+		return nil, err
+	}
+	if !fim.After(inicio) {
+		return nil, errors.New("a hora de término deve ser posterior à hora de início")
+	}
+
+	candidato := models.Event{StartTime: inicio, EndTime: fim}
+	if strings.TrimSpace(regraStr) != "" {
+		regra, err := parseRegraRecorrencia(regraStr)
+		if err != nil {
+			return nil, fmt.Errorf("regra de recorrência inválida: %w", err)
+		}
+		candidato.RecurrenceRule = regra.String()
+		if strings.TrimSpace(excecoesStr) != "" {
+			for _, item := range strings.Split(excecoesStr, ",") {
+				excecao, err := parseDataHora(item, "exceção")
+				if err != nil {
+					return nil, err
+				}
+				candidato.ExceptionDates = append(candidato.ExceptionDates, excecao)
+			}
+		}
+	}
+
+	ocorrencias, err := expandirEvento(candidato, inicio, inicio.Add(horizonteConflitos), 0)
+	if err != nil {
+		return nil, err
+	}
+	return conflitosDasOcorrencias(ocorrencias, func(models.Event) bool { return false })
+}
+
+// VerificarConflitosEdicao informa quais eventos se sobreporiam ao evento id depois de uma edição de horário,
+// com os mesmos parâmetros de EditarEvento, EditarRecorrencia, EditarOcorrencia e EditarOcorrenciasSeguintes.
+// Sem ocorrenciaStr, verifica o evento (ou a série) inteiro; com ela, escopo "ocorrencia" (padrão) verifica
+// apenas a ocorrência editada e "seguintes", ela e as próximas. Ocorrências da própria série não contam como conflito.
+func VerificarConflitosEdicao(id, novoInicioStr, novoFimStr, novaRegraStr, ocorrenciaStr, escopo string) ([]Conflito, error) {
+	mesmaSerie := func(e models.Event) bool { return e.ID == id || e.SeriesID == id }
+
+	if strings.TrimSpace(ocorrenciaStr) == "" {
+		evento, err := GetEventoByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := aplicarHorarios(&evento, novoInicioStr, novoFimStr); err != nil {
+			return nil, err
+		}
+		if regraStr := strings.TrimSpace(novaRegraStr); strings.EqualFold(regraStr, "nenhuma") {
+			evento.RecurrenceRule = ""
+		} else if regraStr != "" {
+			regra, err := parseRegraRecorrencia(regraStr)
+			if err != nil {
+				return nil, fmt.Errorf("regra de recorrência inválida: %w", err)
+			}
+			evento.RecurrenceRule = regra.String()
+		}
+		ocorrencias, err := expandirEvento(evento, evento.StartTime, evento.StartTime.Add(horizonteConflitos), 0)
+		if err != nil {
+			return nil, err
+		}
+		return conflitosDasOcorrencias(ocorrencias, mesmaSerie)
+	}
+
+	mestre, _, ocorrencia, err := buscarOcorrencia(id, ocorrenciaStr)
+	if err != nil {
+		return nil, err
+	}
+	editada := mestre
+	editada.RecurrenceRule = ""
+	editada.StartTime = ocorrencia
+	editada.EndTime = ocorrencia.Add(mestre.EndTime.Sub(mestre.StartTime))
+	if err := aplicarHorarios(&editada, novoInicioStr, novoFimStr); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(escopo)) {
+	case "", "ocorrencia":
+		return conflitosDasOcorrencias([]models.Event{editada}, mesmaSerie)
+	case "seguintes":
+		// A nova série repete as ocorrências restantes deslocadas pela mesma diferença de horário.
+		deslocamento := editada.StartTime.Sub(ocorrencia)
+		duracao := editada.EndTime.Sub(editada.StartTime)
+		restantes, err := expandirEvento(mestre, ocorrencia, ocorrencia.Add(horizonteConflitos), 0)
+		if err != nil {
+			return nil, err
+		}
+		var ocorrencias []models.Event
+		for _, o := range restantes {
+			if o.StartTime.Before(ocorrencia) {
+				continue
+			}
+			o.StartTime = o.StartTime.Add(deslocamento)
+			o.EndTime = o.StartTime.Add(duracao)
+			ocorrencias = append(ocorrencias, o)
+		}
+		return conflitosDasOcorrencias(ocorrencias, mesmaSerie)
+	default:
+		return nil, fmt.Errorf("escopo '%s' inválido. Use 'ocorrencia' ou 'seguintes'", escopo)
+	}
+}
+
+// aplicarHorarios aplica novos início/término (se informados) e valida o intervalo resultante.
+func aplicarHorarios(evento *models.Event, novoInicioStr, novoFimStr string) error {
+	if novoInicioStr != "" {
+		inicio, err := parseDataHora(novoInicioStr, "início")
+		if err != nil {
+			return err
+		}
+		evento.StartTime = inicio
+	}
+	if novoFimStr != "" {
+		fim, err := parseDataHora(novoFimStr, "término")
+		if err != nil {
+			return err
+		}
+		evento.EndTime = fim
+	}
+	if !evento.EndTime.After(evento.StartTime) {
+		return errors.New("a hora de término deve ser posterior à hora de início")
+	}
+	return nil
+}
+
+// conflitosDasOcorrencias compara as ocorrências candidatas com os eventos já agendados no mesmo período.
+// Eventos para os quais ignorar retorna true não são considerados.
+func conflitosDasOcorrencias(ocorrencias []models.Event, ignorar func(models.Event) bool) ([]Conflito, error) {
+	if len(ocorrencias) == 0 {
+		return nil, nil
+	}
+	inicio, fim := ocorrencias[0].StartTime, ocorrencias[0].EndTime
+	for _, o := range ocorrencias[1:] {
+		if o.StartTime.Before(inicio) {
+			inicio = o.StartTime
+		}
+		if o.EndTime.After(fim) {
+			fim = o.EndTime
+		}
+	}
+
+	existentes, err := listarNoIntervalo(inicio, fim, "start_time", "asc", 0)
+	if err != nil {
+		return nil, err
+	}
+	var conflitos []Conflito
+	for _, o := range ocorrencias {
+		for _, e := range existentes {
+			if ignorar(e) {
+				continue
+			}
+			if models.IntervalsOverlap(o.StartTime, o.EndTime, e.StartTime, e.EndTime) {
+				conflitos = append(conflitos, Conflito{Inicio: o.StartTime, Fim: o.EndTime, Existente: e})
+			}
+		}
+	}
+	return conflitos, nil
+}
+
+// HorarioLivre é um intervalo sem eventos nem aulas dentro da janela diária pesquisada.
+type HorarioLivre struct {
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// BuscarHorariosLivres calcula os intervalos livres entre deStr e ateStr (expressões do pacote datas, ambos inclusivos),
+// considerando apenas a janela diária entreStr ("HH:MM-HH:MM", ex: "07:00-18:00").
+// Ocupam a agenda os eventos (com as séries expandidas) e as aulas registradas, que duram duracaoAulaStr.
+// Apenas intervalos com pelo menos duracaoStr (ex: "50m", "1h30m") são retornados, em ordem cronológica.
+// Dias não letivos do calendário escolar (feriados, recessos, planejamento) não têm horários livres.
+func BuscarHorariosLivres(deStr, ateStr, duracaoStr, entreStr, duracaoAulaStr string) ([]HorarioLivre, error) {
+	de, err := datas.Data(deStr)
+	if err != nil {
+		return nil, fmt.Errorf("formato de data inválido para --de: %w", err)
+	}
+	ate, err := datas.Data(ateStr)
+	if err != nil {
+		return nil, fmt.Errorf("formato de data inválido para --ate: %w", err)
+	}
+	if ate.Before(de) {
+		return nil, errors.New("a data de --ate deve ser igual ou posterior à de --de")
+	}
+	duracao, err := time.ParseDuration(strings.TrimSpace(duracaoStr))
+	if err != nil || duracao <= 0 {
+		return nil, fmt.Errorf("duração '%s' inválida. Use, por exemplo, 50m ou 1h30m", duracaoStr)
+	}
+	duracaoAula, err := time.ParseDuration(strings.TrimSpace(duracaoAulaStr))
+	if err != nil || duracaoAula <= 0 {
+		return nil, fmt.Errorf("duração de aula '%s' inválida. Use, por exemplo, 50m", duracaoAulaStr)
+	}
+	abertura, fechamento, err := parseJanelaDiaria(entreStr)
+	if err != nil {
+		return nil, err
+	}
+
+	fimDoPeriodo := ate.AddDate(0, 0, 1)
+	ocupados, err := listarNoIntervalo(de, fimDoPeriodo, "start_time", "asc", 0)
+	if err != nil {
+		return nil, err
+	}
+	aulas, err := aulasNoIntervalo(de, fimDoPeriodo, duracaoAula)
+	if err != nil {
+		return nil, err
+	}
+	ocupados = append(ocupados, aulas...)
+	sort.SliceStable(ocupados, func(i, j int) bool { return ocupados[i].StartTime.Before(ocupados[j].StartTime) })
+	naoLetivos, err := calendario.CarregarDiasNaoLetivos(de, ate)
+	if err != nil {
+		return nil, err
+	}
+
+	var livres []HorarioLivre
+	for dia := de; dia.Before(fimDoPeriodo); dia = dia.AddDate(0, 0, 1) {
+		if !naoLetivos.EhDiaLetivo(dia) {
+			continue
+		}
+		janelaInicio, janelaFim := horarioDoDia(dia, abertura), horarioDoDia(dia, fechamento)
+		cursor := janelaInicio
+		for _, o := range ocupados {
+			if !models.IntervalsOverlap(o.StartTime, o.EndTime, janelaInicio, janelaFim) {
+				continue
+			}
+			if o.StartTime.Sub(cursor) >= duracao {
+				livres = append(livres, HorarioLivre{Inicio: cursor, Fim: o.StartTime})
+			}
+			if o.EndTime.After(cursor) {
+				cursor = o.EndTime
+			}
+		}
+		if janelaFim.Sub(cursor) >= duracao {
+			livres = append(livres, HorarioLivre{Inicio: cursor, Fim: janelaFim})
+		}
+	}
+	return livres, nil
+}
+
+// parseJanelaDiaria interpreta "HH:MM-HH:MM" e devolve abertura e fechamento como deslocamentos a partir da meia-noite.
+func parseJanelaDiaria(entreStr string) (time.Duration, time.Duration, error) {
+	erroJanela := fmt.Errorf("janela '%s' inválida. Use HH:MM-HH:MM, ex: 07:00-18:00", entreStr)
+	partes := strings.Split(strings.TrimSpace(entreStr), "-")
+	if len(partes) != 2 {
+		return 0, 0, erroJanela
+	}
+	var limites [2]time.Duration
+	for i, parte := range partes {
+		t, err := time.Parse("15:04", strings.TrimSpace(parte))
+		if err != nil {
+			return 0, 0, erroJanela
+		}
+		limites[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if limites[1] <= limites[0] {
+		return 0, 0, fmt.Errorf("janela '%s' inválida: o fim deve ser posterior ao início", entreStr)
+	}
+	return limites[0], limites[1], nil
+}
+
+// horarioDoDia devolve o horário de relógio do dia correspondente a um deslocamento desde a meia-noite,
+// mesmo em dias com mudança de horário de verão.
+func horarioDoDia(dia time.Time, deslocamento time.Duration) time.Time {
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), int(deslocamento/time.Hour), int(deslocamento%time.Hour/time.Minute), 0, 0, time.Local)
+}
+
+// aulasNoIntervalo devolve as aulas registradas em [inicio, fim) como eventos de duração duracaoAula.
+// Aulas geradas pela grade horária ficam de fora: o evento gerado junto com elas já ocupa a agenda.
+func aulasNoIntervalo(inicio, fim time.Time, duracaoAula time.Duration) ([]models.Event, error) {
+	conn := db.GetDB()
+	if conn == nil {
+		return nil, errors.New("banco de dados não inicializado")
+	}
+	daGrade, _, err := ocorrenciasDaGrade(inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	// O filtro de período do AulaStore compara datas em UTC; a margem de um dia é recortada abaixo.
+	periodo := inicio.AddDate(0, 0, -1).Format("02-01-2006") + ":" + fim.AddDate(0, 0, 1).Format("02-01-2006")
+	aulas, err := store.NewSQLiteAulaStore(conn).ListLessons("", "", periodo, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar aulas: %w", err)
+	}
+
+	var eventos []models.Event
+	for _, aula := range aulas {
+		if daGrade[aula.ID] {
+			continue
+		}
+		evento := models.Event{
+			ID:        aula.ID,
+			Title:     fmt.Sprintf("Aula de %s (%s)", aula.Subject, aula.ClassID),
+			StartTime: aula.Date.In(time.Local),
+			EndTime:   aula.Date.In(time.Local).Add(duracaoAula),
+		}
+		if models.IntervalsOverlap(evento.StartTime, evento.EndTime, inicio, fim) {
+			eventos = append(eventos, evento)
+		}
+	}
+	return eventos, nil
+}
+
+// ocorrenciasDaGrade devolve os IDs das aulas e dos eventos gerados pela grade horária em torno de [inicio, fim).
+func ocorrenciasDaGrade(inicio, fim time.Time) (aulas, eventos map[string]bool, err error) {
+	ocorrencias, err := store.NewSQLiteTimetableStore(db.GetDB()).ListOccurrencesBetween(inicio.AddDate(0, 0, -1), fim.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar as aulas da grade horária: %w", err)
+	}
+	aulas, eventos = make(map[string]bool), make(map[string]bool)
+	for _, o := range ocorrencias {
+		aulas[o.LessonID] = true
+		eventos[o.EventID] = true
+	}
+	return aulas, eventos, nil
+}
@@ -0,0 +1,152 @@
+package agenda
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+func TestVerificarConflitos(t *testing.T) {
+	LimparEventosStore()
+
+	reuniao, err := AdicionarEvento("Reunião pedagógica", "2024-08-05 10:00", "2024-08-05 11:00", "", "")
+	if err != nil {
+		t.Fatalf("AdicionarEvento falhou: %v", err)
+	}
+	// Plantão às quartas, 14:00-15:00, a partir de 07/08/2024.
+	plantao, err := AdicionarEventoRecorrente("Plantão", "2024-08-07 14:00", "2024-08-07 15:00", "", "", "FREQ=WEEKLY;BYDAY=WE;COUNT=4", "")
+	if err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+
+	casos := []struct {
+		nome, inicio, fim, regra string
+		esperados                []string
+	}{
+		{"sobreposição parcial", "2024-08-05 10:30", "2024-08-05 11:30", "", []string{reuniao.ID}},
+		{"evento contido", "2024-08-05 10:15", "2024-08-05 10:45", "", []string{reuniao.ID}},
+		{"encostado no fim não conflita", "2024-08-05 11:00", "2024-08-05 12:00", "", nil},
+		{"encostado no início não conflita", "2024-08-05 09:00", "2024-08-05 10:00", "", nil},
+		{"ocorrência de série existente", "2024-08-21 14:30", "2024-08-21 15:30", "", []string{plantao.ID}},
+		{"após o fim da série", "2024-09-04 14:30", "2024-09-04 15:30", "", nil},
+		{"nova série atinge a reunião e o plantão", "2024-08-05 10:30", "2024-08-05 14:30", "FREQ=DAILY;COUNT=3", []string{reuniao.ID, plantao.ID}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			conflitos, err := VerificarConflitos(c.inicio, c.fim, c.regra, "")
+			if err != nil {
+				t.Fatalf("VerificarConflitos falhou: %v", err)
+			}
+			var ids []string
+			for _, conflito := range conflitos {
+				ids = append(ids, conflito.Existente.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(c.esperados, ",") {
+				t.Errorf("Esperados conflitos com %v, obtidos %v", c.esperados, ids)
+			}
+		})
+	}
+
+	t.Run("Edição ignora o próprio evento", func(t *testing.T) {
+		conflitos, err := VerificarConflitosEdicao(reuniao.ID, "2024-08-05 10:30", "", "", "", "")
+		if err != nil {
+			t.Fatalf("VerificarConflitosEdicao falhou: %v", err)
+		}
+		if len(conflitos) != 0 {
+			t.Errorf("Esperado nenhum conflito ao mover o evento sobre si mesmo, obtido %v", conflitos)
+		}
+	})
+
+	t.Run("Edição de uma ocorrência", func(t *testing.T) {
+		conflitos, err := VerificarConflitosEdicao(plantao.ID, "2024-08-05 10:30", "2024-08-05 11:30", "", "2024-08-07 14:00", "ocorrencia")
+		if err != nil {
+			t.Fatalf("VerificarConflitosEdicao falhou: %v", err)
+		}
+		if len(conflitos) != 1 || conflitos[0].Existente.ID != reuniao.ID {
+			t.Errorf("Esperado conflito com a reunião, obtido %v", conflitos)
+		}
+	})
+
+	t.Run("Erro de conflito descreve os eventos", func(t *testing.T) {
+		conflitos, _ := VerificarConflitos("2024-08-05 10:30", "2024-08-05 11:30", "", "")
+		var err error = &ErroConflito{Conflitos: conflitos}
+		var erroConflito *ErroConflito
+		if !errors.As(err, &erroConflito) || !strings.Contains(err.Error(), "'Reunião pedagógica' em 2024-08-05 10:00 - 11:00") {
+			t.Errorf("Mensagem de conflito inesperada: %v", err)
+		}
+	})
+}
+
+func TestBuscarHorariosLivres(t *testing.T) {
+	LimparEventosStore()
+	conn := db.GetDB()
+	if _, err := conn.Exec("DELETE FROM lessons"); err != nil {
+		t.Fatalf("Falha ao limpar aulas: %v", err)
+	}
+
+	if _, err := AdicionarEvento("Conselho de classe", "2024-08-01 09:00", "2024-08-01 10:30", "", ""); err != nil {
+		t.Fatalf("AdicionarEvento falhou: %v", err)
+	}
+	if _, err := AdicionarEvento("Atendimento aos pais", "2024-08-01 10:00", "2024-08-01 11:00", "", ""); err != nil {
+		t.Fatalf("AdicionarEvento falhou: %v", err)
+	}
+	if _, err := AdicionarEventoRecorrente("Almoço", "2024-08-01 12:00", "2024-08-01 13:00", "", "", "FREQ=DAILY", ""); err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+	aula := models.Lesson{Subject: "Matemática", Topic: "Frações", ClassID: "7A", Date: time.Date(2024, 8, 2, 7, 30, 0, 0, time.Local)}
+	if _, err := store.NewSQLiteAulaStore(conn).SaveLesson(aula); err != nil {
+		t.Fatalf("SaveLesson falhou: %v", err)
+	}
+
+	livres, err := BuscarHorariosLivres("2024-08-01", "2024-08-02", "50m", "07:00-14:00", "50m")
+	if err != nil {
+		t.Fatalf("BuscarHorariosLivres falhou: %v", err)
+	}
+	var obtidos []string
+	for _, l := range livres {
+		obtidos = append(obtidos, l.Inicio.Format(dateTimeLayout)+"-"+l.Fim.Format("15:04"))
+	}
+	esperados := []string{
+		"2024-08-01 07:00-09:00",
+		"2024-08-01 11:00-12:00",
+		"2024-08-01 13:00-14:00",
+		// A aula de 07:30-08:20 deixa apenas 30 minutos antes dela, menos que a duração pedida.
+		"2024-08-02 08:20-12:00",
+		"2024-08-02 13:00-14:00",
+	}
+	if strings.Join(obtidos, "|") != strings.Join(esperados, "|") {
+		t.Errorf("Horários livres inesperados:\nesperado %v\nobtido   %v", esperados, obtidos)
+	}
+
+	// Em um dia não letivo não há horários livres.
+	feriado, err := calendario.AdicionarDiaNaoLetivo("Feriado municipal", "2024-08-02", "", "")
+	if err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	defer calendario.RemoverDiaNaoLetivo(feriado.ID)
+	livres, err = BuscarHorariosLivres("2024-08-01", "2024-08-02", "50m", "07:00-14:00", "50m")
+	if err != nil {
+		t.Fatalf("BuscarHorariosLivres falhou: %v", err)
+	}
+	if len(livres) != 3 || livres[len(livres)-1].Inicio.Day() != 1 {
+		t.Errorf("Esperados apenas os 3 horários livres de 01/08, obtido %+v", livres)
+	}
+
+	invalidos := []struct{ de, ate, duracao, entre, trecho string }{
+		{"31/08/2024 25h", "2024-08-02", "50m", "07:00-18:00", "--de"},
+		{"2024-08-02", "2024-08-01", "50m", "07:00-18:00", "posterior"},
+		{"2024-08-01", "2024-08-02", "cinquenta", "07:00-18:00", "duração"},
+		{"2024-08-01", "2024-08-02", "50m", "18:00-07:00", "janela"},
+	}
+	for _, c := range invalidos {
+		if _, err := BuscarHorariosLivres(c.de, c.ate, c.duracao, c.entre, "50m"); err == nil || !strings.Contains(err.Error(), c.trecho) {
+			t.Errorf("BuscarHorariosLivres(%q, %q, %q, %q): esperado erro contendo %q, obtido %v", c.de, c.ate, c.duracao, c.entre, c.trecho, err)
+		}
+	}
+}
@@ -0,0 +1,690 @@
+package agenda
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Importação e exportação de eventos no formato iCalendar (RFC 5545).
+// Cada VEVENT corresponde a um models.Event: SUMMARY → Title, DESCRIPTION → Description,
+// LOCATION → Location, DTSTART/DTEND (ou DURATION) → StartTime/EndTime, RRULE/EXDATE → recorrência.
+// VEVENTs com RECURRENCE-ID são ocorrências editadas de uma série e viram eventos avulsos vinculados ao mestre.
+// A importação usa o UID como chave, então reimportar um calendário atualiza os eventos em vez de duplicá-los.
+
+const (
+	icsProdID        = "-//Vickgenda//Vickgenda CLI//PT-BR"
+	icsSufixoUID     = "@vickgenda"
+	icsLayoutUTC     = "20060102T150405Z"
+	icsLayoutLocal   = "20060102T150405"
+	icsLayoutData    = "20060102"
+	icsLimiteLinha   = 75
+	icsDuracaoPadrao = time.Hour // Para VEVENTs com data/hora sem DTEND nem DURATION.
+)
+
+// ResultadoImportacao resume o que aconteceu em uma importação de arquivo .ics.
+type ResultadoImportacao struct {
+	Criados     int
+	Atualizados int
+	Ignorados   int
+	Avisos      []string // Motivo de cada VEVENT ignorado ou importado com ressalvas.
+}
+
+func (r *ResultadoImportacao) avisar(formato string, args ...interface{}) {
+	r.Avisos = append(r.Avisos, fmt.Sprintf(formato, args...))
+}
+
+// propriedadeICS é uma linha de conteúdo já desdobrada: NOME;PARAM=valor:VALOR.
+type propriedadeICS struct {
+	Nome   string
+	Params map[string]string
+	Valor  string
+}
+
+// veventICS reúne as propriedades de um VEVENT relevantes para models.Event.
+type veventICS struct {
+	UID          string
+	Titulo       string
+	Descricao    string
+	Local        string
+	Regra        string
+	Status       string
+	Inicio       time.Time
+	Fim          time.Time
+	Excecoes     []time.Time
+	RecurrenceID time.Time
+	diaInteiro   bool // DTSTART com VALUE=DATE.
+	linha        int  // Linha do BEGIN:VEVENT, para as mensagens de aviso.
+}
+
+// --- Exportação ---
+
+// ExportarICS escreve todos os eventos da agenda em w como um VCALENDAR.
+// Séries recorrentes são exportadas com RRULE/EXDATE e as ocorrências editadas isoladamente
+// como VEVENTs com o UID da série e RECURRENCE-ID. Retorna a quantidade de VEVENTs escritos.
+func ExportarICS(w io.Writer) (int, error) {
+	eventos, _, err := db.ListEvents(nil, "start_time", "asc", 0, 1)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+
+	mestres := map[string]models.Event{}
+	substituidas := map[string][]time.Time{}
+	for _, e := range eventos {
+		if e.SeriesID == "" {
+			mestres[e.ID] = e
+		} else {
+			substituidas[e.SeriesID] = append(substituidas[e.SeriesID], e.OriginalStartTime)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	escrever := func(linha string) {
+		bw.WriteString(dobrarLinhaICS(linha))
+	}
+	escrever("BEGIN:VCALENDAR")
+	escrever("VERSION:2.0")
+	escrever("PRODID:" + icsProdID)
+	escrever("CALSCALE:GREGORIAN")
+
+	for _, e := range eventos {
+		escrever("BEGIN:VEVENT")
+		mestre, temMestre := mestres[e.SeriesID]
+		if e.SeriesID != "" && temMestre {
+			escrever("UID:" + escaparTextoICS(uidICS(mestre)))
+			escrever(formatarDataHoraICS("RECURRENCE-ID", e.OriginalStartTime))
+		} else {
+			escrever("UID:" + escaparTextoICS(uidICS(e)))
+		}
+		carimbo := e.UpdatedAt
+		if carimbo.IsZero() {
+			carimbo = time.Now()
+		}
+		escrever("DTSTAMP:" + carimbo.UTC().Format(icsLayoutUTC))
+		escrever(formatarDataHoraICS("DTSTART", e.StartTime))
+		escrever(formatarDataHoraICS("DTEND", e.EndTime))
+		escrever("SUMMARY:" + escaparTextoICS(e.Title))
+		if e.Description != "" {
+			escrever("DESCRIPTION:" + escaparTextoICS(e.Description))
+		}
+		if e.Location != "" {
+			escrever("LOCATION:" + escaparTextoICS(e.Location))
+		}
+		if e.RecurrenceRule != "" {
+			regra, err := regraICS(e.RecurrenceRule)
+			if err != nil {
+				return 0, fmt.Errorf("regra de recorrência inválida no evento '%s': %w", e.ID, err)
+			}
+			escrever("RRULE:" + regra)
+			// Ocorrências substituídas por eventos avulsos são representadas pelo RECURRENCE-ID, não por EXDATE.
+			for _, excecao := range e.ExceptionDates {
+				if !contemHorario(substituidas[e.ID], excecao) {
+					escrever(formatarDataHoraICS("EXDATE", excecao))
+				}
+			}
+		}
+		escrever("END:VEVENT")
+	}
+	escrever("END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("erro ao escrever o arquivo .ics: %w", err)
+	}
+	return len(eventos), nil
+}
+
+// uidICS devolve o UID de exportação: o UID de origem, para eventos importados, ou o ID local com sufixo.
+func uidICS(e models.Event) string {
+	if e.ICalUID != "" {
+		return e.ICalUID
+	}
+	return e.ID + icsSufixoUID
+}
+
+// formatarDataHoraICS formata uma propriedade de data/hora como horário flutuante (sem TZID nem "Z"),
+// no fuso local. Assim DTSTART, EXDATE, RECURRENCE-ID e o UNTIL da RRULE ficam no mesmo horário de relógio
+// em que o BYDAY das séries é calculado, e um evento às 22h de segunda não vira terça em UTC.
+func formatarDataHoraICS(nome string, t time.Time) string {
+	return nome + ":" + t.In(time.Local).Format(icsLayoutLocal)
+}
+
+// regraICS devolve a RRULE de exportação: a regra armazenada com UNTIL em horário flutuante,
+// como o RFC 5545 exige quando DTSTART é flutuante.
+func regraICS(regraArmazenada string) (string, error) {
+	regra, err := parseRegraRecorrencia(regraArmazenada)
+	if err != nil {
+		return "", err
+	}
+	return regra.formatar(func(t time.Time) string { return t.In(time.Local).Format(icsLayoutLocal) }), nil
+}
+
+// escaparTextoICS aplica o escape de valores TEXT (RFC 5545, seção 3.3.11).
+func escaparTextoICS(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// dobrarLinhaICS quebra linhas com mais de 75 octetos sem partir caracteres UTF-8 e termina com CRLF.
+func dobrarLinhaICS(linha string) string {
+	var b strings.Builder
+	limite := icsLimiteLinha
+	for len(linha) > limite {
+		corte := limite
+		for corte > 0 && !utf8.RuneStart(linha[corte]) {
+			corte--
+		}
+		b.WriteString(linha[:corte])
+		b.WriteString("\r\n ")
+		linha = linha[corte:]
+		limite = icsLimiteLinha - 1 // O espaço inicial da continuação conta no limite.
+	}
+	b.WriteString(linha)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func contemHorario(lista []time.Time, t time.Time) bool {
+	for _, item := range lista {
+		if item.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Importação ---
+
+// ImportarICS lê um VCALENDAR de r e grava seus VEVENTs na agenda.
+// Eventos já importados (mesmo UID) são atualizados; os demais são criados.
+// VEVENTs inválidos ou com recursos não suportados são ignorados e relatados em Avisos.
+func ImportarICS(r io.Reader) (ResultadoImportacao, error) {
+	var resultado ResultadoImportacao
+
+	vevents, err := lerVEventsICS(r, &resultado)
+	if err != nil {
+		return resultado, err
+	}
+
+	// Primeiro as séries e eventos únicos, depois as ocorrências editadas, que dependem do mestre.
+	ocorrenciasPorUID := map[string][]time.Time{}
+	for _, v := range vevents {
+		if v.RecurrenceID.IsZero() {
+			importarVEvent(v, &resultado)
+		} else {
+			ocorrenciasPorUID[v.UID] = append(ocorrenciasPorUID[v.UID], v.RecurrenceID)
+		}
+	}
+	for _, v := range vevents {
+		if !v.RecurrenceID.IsZero() {
+			importarOcorrenciaICS(v, &resultado)
+		}
+	}
+	if err := removerOcorrenciasAusentes(vevents, ocorrenciasPorUID); err != nil {
+		return resultado, err
+	}
+	return resultado, nil
+}
+
+// lerVEventsICS desdobra as linhas do arquivo e extrai os VEVENTs.
+// Componentes aninhados (ex: VALARM) são ignorados.
+func lerVEventsICS(r io.Reader, resultado *ResultadoImportacao) ([]veventICS, error) {
+	linhas, err := desdobrarLinhasICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var vevents []veventICS
+	var atual *veventICS
+	profundidade := 0
+	encontrouCalendario := false
+	for i, linha := range linhas {
+		if strings.TrimSpace(linha) == "" {
+			continue
+		}
+		prop, err := parsePropriedadeICS(linha)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d do arquivo .ics inválida: %w", i+1, err)
+		}
+
+		switch {
+		case prop.Nome == "BEGIN" && strings.EqualFold(prop.Valor, "VCALENDAR"):
+			encontrouCalendario = true
+			continue
+		case prop.Nome == "BEGIN" && strings.EqualFold(prop.Valor, "VEVENT") && atual == nil:
+			atual = &veventICS{linha: i + 1}
+			profundidade = 0
+			continue
+		case prop.Nome == "BEGIN" && atual != nil:
+			profundidade++
+			continue
+		case prop.Nome == "END" && atual != nil && profundidade > 0:
+			profundidade--
+			continue
+		case prop.Nome == "END" && strings.EqualFold(prop.Valor, "VEVENT") && atual != nil:
+			vevents = append(vevents, *atual)
+			atual = nil
+			continue
+		}
+		if atual == nil || profundidade > 0 {
+			continue
+		}
+		if err := aplicarPropriedadeICS(atual, prop); err != nil {
+			resultado.avisar("VEVENT da linha %d: %v", atual.linha, err)
+		}
+	}
+
+	if !encontrouCalendario {
+		return nil, errors.New("o arquivo não é um calendário iCalendar (BEGIN:VCALENDAR não encontrado)")
+	}
+	if atual != nil {
+		return nil, errors.New("o arquivo .ics termina dentro de um VEVENT (END:VEVENT ausente)")
+	}
+	return vevents, nil
+}
+
+// desdobrarLinhasICS junta as linhas de continuação (iniciadas por espaço ou tabulação) à linha anterior.
+func desdobrarLinhasICS(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var linhas []string
+	for scanner.Scan() {
+		linha := strings.TrimRight(scanner.Text(), "\r")
+		if len(linhas) > 0 && (strings.HasPrefix(linha, " ") || strings.HasPrefix(linha, "\t")) {
+			linhas[len(linhas)-1] += linha[1:]
+			continue
+		}
+		linhas = append(linhas, linha)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo .ics: %w", err)
+	}
+	return linhas, nil
+}
+
+// parsePropriedadeICS separa nome, parâmetros e valor de uma linha de conteúdo.
+// Dois-pontos dentro de parâmetros entre aspas não encerram o nome.
+func parsePropriedadeICS(linha string) (propriedadeICS, error) {
+	entreAspas := false
+	separador := -1
+	for i, c := range linha {
+		if c == '"' {
+			entreAspas = !entreAspas
+		} else if c == ':' && !entreAspas {
+			separador = i
+			break
+		}
+	}
+	if separador <= 0 {
+		return propriedadeICS{}, fmt.Errorf("'%s' não tem o formato NOME:VALOR", linha)
+	}
+
+	partes := strings.Split(linha[:separador], ";")
+	prop := propriedadeICS{Nome: strings.ToUpper(partes[0]), Params: map[string]string{}, Valor: linha[separador+1:]}
+	for _, param := range partes[1:] {
+		chaveValor := strings.SplitN(param, "=", 2)
+		if len(chaveValor) == 2 {
+			prop.Params[strings.ToUpper(chaveValor[0])] = strings.Trim(chaveValor[1], `"`)
+		}
+	}
+	return prop, nil
+}
+
+// aplicarPropriedadeICS copia uma propriedade do VEVENT para v.
+func aplicarPropriedadeICS(v *veventICS, prop propriedadeICS) error {
+	var err error
+	switch prop.Nome {
+	case "UID":
+		v.UID = desescaparTextoICS(prop.Valor)
+	case "SUMMARY":
+		v.Titulo = desescaparTextoICS(prop.Valor)
+	case "DESCRIPTION":
+		v.Descricao = desescaparTextoICS(prop.Valor)
+	case "LOCATION":
+		v.Local = desescaparTextoICS(prop.Valor)
+	case "STATUS":
+		v.Status = strings.ToUpper(prop.Valor)
+	case "RRULE":
+		v.Regra = prop.Valor
+	case "DTSTART":
+		v.Inicio, err = parseDataHoraICS(prop, prop.Valor)
+		v.diaInteiro = ehValorDataICS(prop, prop.Valor)
+	case "DTEND":
+		v.Fim, err = parseDataHoraICS(prop, prop.Valor)
+	case "DURATION":
+		if v.Inicio.IsZero() {
+			return errors.New("DURATION antes de DTSTART não é suportado")
+		}
+		var duracao time.Duration
+		duracao, err = parseDuracaoICS(prop.Valor)
+		if err == nil {
+			v.Fim = v.Inicio.Add(duracao)
+		}
+	case "RECURRENCE-ID":
+		v.RecurrenceID, err = parseDataHoraICS(prop, prop.Valor)
+	case "EXDATE":
+		for _, valor := range strings.Split(prop.Valor, ",") {
+			excecao, errExcecao := parseDataHoraICS(prop, valor)
+			if errExcecao != nil {
+				return errExcecao
+			}
+			v.Excecoes = append(v.Excecoes, excecao)
+		}
+	}
+	return err
+}
+
+// parseDataHoraICS interpreta DATE ou DATE-TIME (UTC com "Z", com TZID ou flutuante) e devolve o horário no fuso local.
+// Datas sem hora (VALUE=DATE) correspondem à meia-noite local.
+func parseDataHoraICS(prop propriedadeICS, valor string) (time.Time, error) {
+	valor = strings.TrimSpace(valor)
+	if ehValorDataICS(prop, valor) {
+		t, err := time.ParseInLocation(icsLayoutData, valor, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("data '%s' inválida em %s", valor, prop.Nome)
+		}
+		return t, nil
+	}
+	if strings.HasSuffix(valor, "Z") {
+		t, err := time.Parse(icsLayoutUTC, valor)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("data/hora '%s' inválida em %s", valor, prop.Nome)
+		}
+		return t.In(time.Local), nil
+	}
+
+	loc := time.Local
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		carregado, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("fuso horário '%s' desconhecido em %s", tzid, prop.Nome)
+		}
+		loc = carregado
+	}
+	t, err := time.ParseInLocation(icsLayoutLocal, valor, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data/hora '%s' inválida em %s", valor, prop.Nome)
+	}
+	return t.In(time.Local), nil
+}
+
+// ehValorDataICS indica se o valor é uma data sem hora (VALUE=DATE).
+func ehValorDataICS(prop propriedadeICS, valor string) bool {
+	return strings.EqualFold(prop.Params["VALUE"], "DATE") || len(strings.TrimSpace(valor)) == len(icsLayoutData)
+}
+
+// parseDuracaoICS interpreta durações como "PT1H30M", "P1D" ou "P2W".
+func parseDuracaoICS(valor string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(valor)), "+")
+	if strings.HasPrefix(s, "-") || !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("DURATION '%s' inválida", valor)
+	}
+
+	var total time.Duration
+	numero := ""
+	naHora := false
+	unidades := map[bool]map[rune]time.Duration{
+		false: {'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour},
+		true:  {'H': time.Hour, 'M': time.Minute, 'S': time.Second},
+	}
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			numero += string(c)
+		case c == 'T':
+			naHora = true
+		default:
+			unidade, ok := unidades[naHora][c]
+			n, err := strconv.Atoi(numero)
+			if !ok || err != nil {
+				return 0, fmt.Errorf("DURATION '%s' inválida", valor)
+			}
+			total += time.Duration(n) * unidade
+			numero = ""
+		}
+	}
+	if numero != "" || total <= 0 {
+		return 0, fmt.Errorf("DURATION '%s' inválida", valor)
+	}
+	return total, nil
+}
+
+// desescaparTextoICS desfaz o escape de valores TEXT.
+func desescaparTextoICS(s string) string {
+	var b strings.Builder
+	escapando := false
+	for _, c := range s {
+		if escapando {
+			switch c {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(c)
+			}
+			escapando = false
+			continue
+		}
+		if c == '\\' {
+			escapando = true
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// eventoDoVEvent valida um VEVENT e preenche os campos de evento correspondentes.
+func eventoDoVEvent(v veventICS, evento *models.Event) error {
+	if v.Inicio.IsZero() {
+		return errors.New("DTSTART ausente")
+	}
+	fim := v.Fim
+	if fim.IsZero() {
+		if v.diaInteiro {
+			fim = v.Inicio.AddDate(0, 0, 1)
+		} else {
+			fim = v.Inicio.Add(icsDuracaoPadrao)
+		}
+	}
+	if !fim.After(v.Inicio) {
+		return errors.New("DTEND deve ser posterior a DTSTART")
+	}
+
+	evento.Title = v.Titulo
+	if strings.TrimSpace(evento.Title) == "" {
+		evento.Title = "(sem título)"
+	}
+	evento.Description = v.Descricao
+	evento.Location = v.Local
+	evento.StartTime = v.Inicio
+	evento.EndTime = fim
+	evento.RecurrenceRule = ""
+	if v.Regra != "" {
+		regra, err := parseRegraRecorrencia(v.Regra)
+		if err != nil {
+			return fmt.Errorf("RRULE não suportada: %w", err)
+		}
+		evento.RecurrenceRule = regra.String()
+	}
+	evento.ExceptionDates = v.Excecoes
+	evento.UpdatedAt = time.Now()
+	return nil
+}
+
+// buscarEventoPorUID procura um evento já importado com o UID, ou exportado por este programa (ID + sufixo).
+func buscarEventoPorUID(uid string) (models.Event, bool, error) {
+	eventos, _, err := db.ListEvents(map[string]interface{}{"ical_uid": uid}, "", "", 1, 1)
+	if err != nil {
+		return models.Event{}, false, fmt.Errorf("erro ao buscar evento pelo UID '%s': %w", uid, err)
+	}
+	if len(eventos) > 0 {
+		return eventos[0], true, nil
+	}
+	if strings.HasSuffix(uid, icsSufixoUID) {
+		if evento, err := db.GetEvent(strings.TrimSuffix(uid, icsSufixoUID)); err == nil && evento.SeriesID == "" {
+			return evento, true, nil
+		}
+	}
+	return models.Event{}, false, nil
+}
+
+// importarVEvent cria ou atualiza o evento (ou série) de um VEVENT sem RECURRENCE-ID.
+func importarVEvent(v veventICS, resultado *ResultadoImportacao) {
+	if v.UID == "" {
+		resultado.Ignorados++
+		resultado.avisar("VEVENT da linha %d ignorado: UID ausente", v.linha)
+		return
+	}
+	if v.Status == "CANCELLED" {
+		resultado.Ignorados++
+		resultado.avisar("VEVENT '%s' ignorado: evento cancelado", v.UID)
+		return
+	}
+
+	evento, existe, err := buscarEventoPorUID(v.UID)
+	if err != nil {
+		resultado.Ignorados++
+		resultado.avisar("VEVENT '%s' ignorado: %v", v.UID, err)
+		return
+	}
+	if err := eventoDoVEvent(v, &evento); err != nil {
+		resultado.Ignorados++
+		resultado.avisar("VEVENT '%s' ignorado: %v", v.UID, err)
+		return
+	}
+	evento.ICalUID = v.UID
+
+	if existe {
+		if err := db.UpdateEvent(evento); err != nil {
+			resultado.Ignorados++
+			resultado.avisar("VEVENT '%s' não atualizado: %v", v.UID, err)
+			return
+		}
+		resultado.Atualizados++
+		return
+	}
+	evento.CreatedAt = evento.UpdatedAt
+	if _, err := db.CreateEvent(evento); err != nil {
+		resultado.Ignorados++
+		resultado.avisar("VEVENT '%s' não criado: %v", v.UID, err)
+		return
+	}
+	resultado.Criados++
+}
+
+// importarOcorrenciaICS grava um VEVENT com RECURRENCE-ID como ocorrência avulsa da série de mesmo UID.
+// A ocorrência original é adicionada às exceções do mestre; STATUS:CANCELLED apenas a exclui.
+func importarOcorrenciaICS(v veventICS, resultado *ResultadoImportacao) {
+	mestre, existe, err := buscarEventoPorUID(v.UID)
+	if err != nil || !existe || mestre.RecurrenceRule == "" {
+		resultado.Ignorados++
+		resultado.avisar("ocorrência de %s do VEVENT '%s' ignorada: série não encontrada", v.RecurrenceID.Format(dateTimeLayout), v.UID)
+		return
+	}
+
+	existente, encontrada, err := buscarOcorrenciaAvulsa(mestre.ID, v.RecurrenceID)
+	if err != nil {
+		resultado.Ignorados++
+		resultado.avisar("ocorrência do VEVENT '%s' ignorada: %v", v.UID, err)
+		return
+	}
+
+	if !contemHorario(mestre.ExceptionDates, v.RecurrenceID) {
+		mestre.ExceptionDates = append(mestre.ExceptionDates, v.RecurrenceID)
+		mestre.UpdatedAt = time.Now()
+		if err := db.UpdateEvent(mestre); err != nil {
+			resultado.Ignorados++
+			resultado.avisar("ocorrência do VEVENT '%s' ignorada: %v", v.UID, err)
+			return
+		}
+	}
+
+	if v.Status == "CANCELLED" {
+		if encontrada {
+			db.DeleteEvent(existente.ID)
+		}
+		resultado.Atualizados++
+		return
+	}
+
+	if err := eventoDoVEvent(v, &existente); err != nil {
+		resultado.Ignorados++
+		resultado.avisar("ocorrência do VEVENT '%s' ignorada: %v", v.UID, err)
+		return
+	}
+	existente.RecurrenceRule = ""
+	existente.ExceptionDates = nil
+	existente.SeriesID = mestre.ID
+	existente.OriginalStartTime = v.RecurrenceID
+
+	if encontrada {
+		if err := db.UpdateEvent(existente); err != nil {
+			resultado.Ignorados++
+			resultado.avisar("ocorrência do VEVENT '%s' não atualizada: %v", v.UID, err)
+			return
+		}
+		resultado.Atualizados++
+		return
+	}
+	existente.CreatedAt = existente.UpdatedAt
+	if _, err := db.CreateEvent(existente); err != nil {
+		resultado.Ignorados++
+		resultado.avisar("ocorrência do VEVENT '%s' não criada: %v", v.UID, err)
+		return
+	}
+	resultado.Criados++
+}
+
+// buscarOcorrenciaAvulsa procura a ocorrência avulsa da série com o início original informado.
+func buscarOcorrenciaAvulsa(serieID string, original time.Time) (models.Event, bool, error) {
+	avulsos, _, err := db.ListEvents(map[string]interface{}{"series_id": serieID}, "", "", 0, 1)
+	if err != nil {
+		return models.Event{}, false, err
+	}
+	for _, a := range avulsos {
+		if a.OriginalStartTime.Equal(original) {
+			return a, true, nil
+		}
+	}
+	return models.Event{}, false, nil
+}
+
+// removerOcorrenciasAusentes apaga as ocorrências avulsas de séries importadas que não estão mais no arquivo,
+// devolvendo a ocorrência original à série. Mantém a reimportação de um calendário atualizado fiel ao arquivo.
+func removerOcorrenciasAusentes(vevents []veventICS, ocorrenciasPorUID map[string][]time.Time) error {
+	for _, v := range vevents {
+		if !v.RecurrenceID.IsZero() || v.Regra == "" || v.UID == "" {
+			continue
+		}
+		mestre, existe, err := buscarEventoPorUID(v.UID)
+		if err != nil {
+			return err
+		}
+		if !existe {
+			continue
+		}
+		avulsos, _, err := db.ListEvents(map[string]interface{}{"series_id": mestre.ID}, "", "", 0, 1)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar ocorrências avulsas: %w", err)
+		}
+		for _, a := range avulsos {
+			if contemHorario(ocorrenciasPorUID[v.UID], a.OriginalStartTime) {
+				continue
+			}
+			if err := db.DeleteEvent(a.ID); err != nil {
+				return fmt.Errorf("erro ao remover ocorrência avulsa: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package agenda
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/db"
+)
+
+// calendarioICS monta um VCALENDAR com CRLF a partir das linhas informadas.
+func calendarioICS(linhas ...string) string {
+	todas := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Teste//PT"}, linhas...)
+	todas = append(todas, "END:VCALENDAR")
+	return strings.Join(todas, "\r\n") + "\r\n"
+}
+
+func TestImportarICS(t *testing.T) {
+	LimparEventosStore()
+
+	calendario := calendarioICS(
+		"BEGIN:VEVENT",
+		"UID:conselho@escola.example",
+		"DTSTART:20240701T100000",
+		"DTEND:20240701T113000",
+		"SUMMARY:Conselho de classe\\, 1º bimestre",
+		"DESCRIPTION:Levar as notas\\nda turma 8A",
+		"  e da turma 8B",
+		"LOCATION:Sala 3",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Alarme que não deve sobrescrever a descrição",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:plantao@escola.example",
+		"DTSTART:20240701T140000",
+		"DURATION:PT1H",
+		"SUMMARY:Plantão de dúvidas",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+		"EXDATE:20240708T140000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:plantao@escola.example",
+		"RECURRENCE-ID:20240715T140000",
+		"DTSTART:20240715T160000",
+		"DTEND:20240715T170000",
+		"SUMMARY:Plantão de dúvidas (remarcado)",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:feriado@escola.example",
+		"DTSTART;VALUE=DATE:20240709",
+		"SUMMARY:Feriado estadual",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:nao-suportado@escola.example",
+		"DTSTART:20240701T080000",
+		"DTEND:20240701T090000",
+		"SUMMARY:Regra complexa",
+		"RRULE:FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1",
+		"END:VEVENT",
+	)
+
+	resultado, err := ImportarICS(strings.NewReader(calendario))
+	if err != nil {
+		t.Fatalf("ImportarICS falhou: %v", err)
+	}
+	if resultado.Criados != 4 || resultado.Atualizados != 0 || resultado.Ignorados != 1 {
+		t.Fatalf("Resultado inesperado: %+v", resultado)
+	}
+	if len(resultado.Avisos) != 1 || !strings.Contains(resultado.Avisos[0], "nao-suportado@escola.example") {
+		t.Errorf("Esperado aviso sobre o VEVENT não suportado, obtido %v", resultado.Avisos)
+	}
+
+	eventos, err := ListarEventos("custom", "2024-07-01", "2024-07-31", "inicio", "asc")
+	if err != nil {
+		t.Fatalf("ListarEventos falhou: %v", err)
+	}
+	var obtidos []string
+	for _, e := range eventos {
+		obtidos = append(obtidos, e.StartTime.Format(dateTimeLayout)+" "+e.EndTime.Format("15:04")+" "+e.Title)
+	}
+	esperados := []string{
+		"2024-07-01 10:00 11:30 Conselho de classe, 1º bimestre",
+		"2024-07-01 14:00 15:00 Plantão de dúvidas",
+		"2024-07-09 00:00 00:00 Feriado estadual",
+		"2024-07-15 16:00 17:00 Plantão de dúvidas (remarcado)",
+		"2024-07-22 14:00 15:00 Plantão de dúvidas",
+	}
+	if strings.Join(obtidos, "|") != strings.Join(esperados, "|") {
+		t.Errorf("Eventos importados inesperados:\nesperado %v\nobtido   %v", esperados, obtidos)
+	}
+	if eventos[0].Description != "Levar as notas\nda turma 8A e da turma 8B" || eventos[0].Location != "Sala 3" {
+		t.Errorf("Descrição/local inesperados: %q / %q", eventos[0].Description, eventos[0].Location)
+	}
+
+	t.Run("Reimportação atualiza pelo UID", func(t *testing.T) {
+		atualizado := calendarioICS(
+			"BEGIN:VEVENT",
+			"UID:conselho@escola.example",
+			"DTSTART:20240702T100000",
+			"DTEND:20240702T110000",
+			"SUMMARY:Conselho de classe (nova data)",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:plantao@escola.example",
+			"DTSTART:20240701T140000",
+			"DURATION:PT1H",
+			"SUMMARY:Plantão de dúvidas",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+			"END:VEVENT",
+		)
+		resultado, err := ImportarICS(strings.NewReader(atualizado))
+		if err != nil {
+			t.Fatalf("ImportarICS falhou: %v", err)
+		}
+		if resultado.Criados != 0 || resultado.Atualizados != 2 {
+			t.Errorf("Esperado 0 criados e 2 atualizados, obtido %+v", resultado)
+		}
+
+		_, total, err := db.ListEvents(nil, "", "", 0, 1)
+		if err != nil {
+			t.Fatalf("ListEvents falhou: %v", err)
+		}
+		// Conselho, série do plantão e feriado: a ocorrência remarcada saiu do calendário e foi removida.
+		if total != 3 {
+			t.Errorf("Esperado 3 eventos após a reimportação, obtido %d", total)
+		}
+
+		eventos, _ := ListarEventos("custom", "2024-07-01", "2024-07-31", "inicio", "asc")
+		var titulos []string
+		for _, e := range eventos {
+			titulos = append(titulos, e.StartTime.Format(dateTimeLayout)+" "+e.Title)
+		}
+		esperados := []string{
+			"2024-07-01 14:00 Plantão de dúvidas",
+			"2024-07-02 10:00 Conselho de classe (nova data)",
+			"2024-07-08 14:00 Plantão de dúvidas",
+			"2024-07-09 00:00 Feriado estadual",
+			"2024-07-15 14:00 Plantão de dúvidas",
+			"2024-07-22 14:00 Plantão de dúvidas",
+		}
+		if strings.Join(titulos, "|") != strings.Join(esperados, "|") {
+			t.Errorf("Eventos após reimportação inesperados:\nesperado %v\nobtido   %v", esperados, titulos)
+		}
+	})
+
+	t.Run("Arquivo inválido", func(t *testing.T) {
+		if _, err := ImportarICS(strings.NewReader("não é um calendário")); err == nil {
+			t.Error("Esperado erro para arquivo sem VCALENDAR")
+		}
+	})
+}
+
+func TestExportarICSIdaEVolta(t *testing.T) {
+	LimparEventosStore()
+
+	descricaoLonga := "Pauta: " + strings.Repeat("avaliação, recuperação; ", 6)
+	unico, err := AdicionarEvento("Reunião de pais", "2024-07-03 19:00", "2024-07-03 20:30", descricaoLonga, "Auditório")
+	if err != nil {
+		t.Fatalf("AdicionarEvento falhou: %v", err)
+	}
+	serie, err := AdicionarEventoRecorrente("Aula de reforço", "2024-07-01 15:00", "2024-07-01 16:00", "", "Lab", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6", "2024-07-03 15:00")
+	if err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+	if _, err := EditarOcorrencia(serie.ID, "2024-07-08 15:00", "Aula de reforço (sala 2)", "", "", "", ""); err != nil {
+		t.Fatalf("EditarOcorrencia falhou: %v", err)
+	}
+
+	antes := resumoDoPeriodo(t)
+
+	var buf bytes.Buffer
+	total, err := ExportarICS(&buf)
+	if err != nil {
+		t.Fatalf("ExportarICS falhou: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Esperado 3 VEVENTs exportados, obtido %d", total)
+	}
+	conteudo := buf.String()
+	for _, trecho := range []string{"UID:" + unico.ID + "@vickgenda", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6", "RECURRENCE-ID", "EXDATE"} {
+		if !strings.Contains(conteudo, trecho) {
+			t.Errorf("Exportação não contém %q:\n%s", trecho, conteudo)
+		}
+	}
+	for _, linha := range strings.Split(conteudo, "\r\n") {
+		if len(linha) > 75 {
+			t.Errorf("Linha com mais de 75 octetos: %q", linha)
+		}
+	}
+
+	// Reimportar o próprio arquivo não duplica nada.
+	resultado, err := ImportarICS(strings.NewReader(conteudo))
+	if err != nil {
+		t.Fatalf("ImportarICS falhou: %v", err)
+	}
+	if resultado.Criados != 0 || resultado.Atualizados != 3 || resultado.Ignorados != 0 {
+		t.Errorf("Esperado 3 atualizados na reimportação, obtido %+v", resultado)
+	}
+	if depois := resumoDoPeriodo(t); depois != antes {
+		t.Errorf("Reimportação alterou a agenda:\nantes  %s\ndepois %s", antes, depois)
+	}
+
+	// Importar em uma agenda vazia reproduz os mesmos eventos.
+	LimparEventosStore()
+	if _, err := ImportarICS(strings.NewReader(conteudo)); err != nil {
+		t.Fatalf("ImportarICS falhou: %v", err)
+	}
+	if depois := resumoDoPeriodo(t); depois != antes {
+		t.Errorf("Importação em agenda vazia difere do original:\nantes  %s\ndepois %s", antes, depois)
+	}
+}
+
+func TestExportarICSSerieNoturna(t *testing.T) {
+	// Às 22h30 de segunda em UTC-3 já é terça em UTC: o BYDAY só confere com horários no fuso local.
+	fusoOriginal := time.Local
+	time.Local = time.FixedZone("UTC-3", -3*60*60)
+	defer func() { time.Local = fusoOriginal }()
+	LimparEventosStore()
+
+	serie, err := AdicionarEventoRecorrente("Reunião pedagógica", "2024-07-01 22:30", "2024-07-01 23:30", "", "",
+		"FREQ=WEEKLY;BYDAY=MO;UNTIL=20240729", "2024-07-15 22:30")
+	if err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+	antes := resumoDoPeriodo(t)
+	if antes == "" || !strings.Contains(antes, "2024-07-29 22:30") || strings.Contains(antes, "2024-07-15") {
+		t.Fatalf("Ocorrências inesperadas antes da exportação: %s", antes)
+	}
+
+	var buf bytes.Buffer
+	if _, err := ExportarICS(&buf); err != nil {
+		t.Fatalf("ExportarICS falhou: %v", err)
+	}
+	conteudo := buf.String()
+	for _, linha := range []string{"DTSTART:20240701T223000", "DTEND:20240701T233000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20240729T235959", "EXDATE:20240715T223000"} {
+		if !strings.Contains(conteudo, linha+"\r\n") {
+			t.Errorf("Exportação não contém a linha %q:\n%s", linha, conteudo)
+		}
+	}
+
+	if err := RemoverEvento(serie.ID); err != nil {
+		t.Fatalf("RemoverEvento falhou: %v", err)
+	}
+	if _, err := ImportarICS(strings.NewReader(conteudo)); err != nil {
+		t.Fatalf("ImportarICS falhou: %v", err)
+	}
+	if depois := resumoDoPeriodo(t); depois != antes {
+		t.Errorf("Importação da série noturna difere do original:\nantes  %s\ndepois %s", antes, depois)
+	}
+}
+
+// resumoDoPeriodo descreve as ocorrências de julho/2024 para comparar a agenda antes e depois de uma operação.
+func resumoDoPeriodo(t *testing.T) string {
+	t.Helper()
+	eventos, err := ListarEventos("custom", "2024-07-01", "2024-07-31", "inicio", "asc")
+	if err != nil {
+		t.Fatalf("ListarEventos falhou: %v", err)
+	}
+	var partes []string
+	for _, e := range eventos {
+		partes = append(partes, strings.Join([]string{
+			e.StartTime.Format(dateTimeLayout), e.EndTime.Format(dateTimeLayout), e.Title, e.Description, e.Location,
+		}, "/"))
+	}
+	return strings.Join(partes, "|")
+}
+
+func TestParseDuracaoICS(t *testing.T) {
+	validas := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+	}
+	for entrada, esperado := range validas {
+		if obtido, err := parseDuracaoICS(entrada); err != nil || obtido != esperado {
+			t.Errorf("parseDuracaoICS(%q) = %v, %v; esperado %v", entrada, obtido, err, esperado)
+		}
+	}
+	for _, entrada := range []string{"", "P", "1H", "-PT1H", "PT1X", "PT"} {
+		if _, err := parseDuracaoICS(entrada); err == nil {
+			t.Errorf("parseDuracaoICS(%q): esperado erro", entrada)
+		}
+	}
+}
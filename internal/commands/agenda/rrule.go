@@ -0,0 +1,367 @@
+package agenda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Regras de recorrência no formato RRULE do RFC 5545 (seção 3.3.10).
+// São suportadas as partes FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY,
+// BYMONTHDAY, UNTIL e COUNT. WKST é aceito apenas com o valor padrão MO.
+// As ocorrências são sempre calculadas sob demanda a partir do início do evento mestre (DTSTART),
+// mantendo o mesmo horário de relógio no fuso do evento.
+
+// limitePeriodosVazios evita laços infinitos em regras que nunca produzem ocorrências
+// (ex: FREQ=YEARLY;BYMONTHDAY=31 para um evento de fevereiro).
+const limitePeriodosVazios = 1000
+
+var diasRRule = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// diaDaSemanaRRule é um item de BYDAY: um dia da semana com ordinal opcional (ex: "2MO", "-1FR").
+// Ordinal zero significa "todas as ocorrências desse dia no período".
+type diaDaSemanaRRule struct {
+	Ordinal int
+	Dia     time.Weekday
+}
+
+func (d diaDaSemanaRRule) String() string {
+	for nome, dia := range diasRRule {
+		if dia == d.Dia {
+			if d.Ordinal != 0 {
+				return strconv.Itoa(d.Ordinal) + nome
+			}
+			return nome
+		}
+	}
+	return ""
+}
+
+// regraRecorrencia é a forma interpretada de uma RRULE.
+type regraRecorrencia struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY ou YEARLY
+	Interval   int    // Sempre >= 1
+	ByDay      []diaDaSemanaRRule
+	ByMonthDay []int
+	Until      time.Time // Inclusivo; zero quando ausente
+	Count      int       // Zero quando ausente
+}
+
+// parseRegraRecorrencia interpreta uma RRULE como "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// O prefixo "RRULE:" é opcional. UNTIL aceita YYYYMMDD, YYYYMMDDTHHMMSS (fuso local) ou YYYYMMDDTHHMMSSZ (UTC).
+func parseRegraRecorrencia(s string) (regraRecorrencia, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return regraRecorrencia{}, errors.New("regra de recorrência vazia")
+	}
+
+	regra := regraRecorrencia{Interval: 1}
+	for _, parte := range strings.Split(s, ";") {
+		if strings.TrimSpace(parte) == "" {
+			continue
+		}
+		chaveValor := strings.SplitN(parte, "=", 2)
+		if len(chaveValor) != 2 || chaveValor[1] == "" {
+			return regraRecorrencia{}, fmt.Errorf("parte '%s' da regra de recorrência é inválida", parte)
+		}
+		chave := strings.ToUpper(strings.TrimSpace(chaveValor[0]))
+		valor := strings.ToUpper(strings.TrimSpace(chaveValor[1]))
+
+		switch chave {
+		case "FREQ":
+			switch valor {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				regra.Freq = valor
+			default:
+				return regraRecorrencia{}, fmt.Errorf("FREQ '%s' não suportada (use DAILY, WEEKLY, MONTHLY ou YEARLY)", valor)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 {
+				return regraRecorrencia{}, fmt.Errorf("INTERVAL '%s' inválido", valor)
+			}
+			regra.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 {
+				return regraRecorrencia{}, fmt.Errorf("COUNT '%s' inválido", valor)
+			}
+			regra.Count = n
+		case "UNTIL":
+			until, err := parseUntil(valor)
+			if err != nil {
+				return regraRecorrencia{}, err
+			}
+			regra.Until = until
+		case "BYDAY":
+			for _, item := range strings.Split(valor, ",") {
+				d, err := parseDiaDaSemanaRRule(item)
+				if err != nil {
+					return regraRecorrencia{}, err
+				}
+				regra.ByDay = append(regra.ByDay, d)
+			}
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(valor, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(item))
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return regraRecorrencia{}, fmt.Errorf("BYMONTHDAY '%s' inválido", item)
+				}
+				regra.ByMonthDay = append(regra.ByMonthDay, n)
+			}
+		case "WKST":
+			if valor != "MO" {
+				return regraRecorrencia{}, errors.New("apenas WKST=MO é suportado")
+			}
+		default:
+			return regraRecorrencia{}, fmt.Errorf("parte '%s' da regra de recorrência não é suportada", chave)
+		}
+	}
+
+	if regra.Freq == "" {
+		return regraRecorrencia{}, errors.New("a regra de recorrência deve informar FREQ")
+	}
+	if regra.Count > 0 && !regra.Until.IsZero() {
+		return regraRecorrencia{}, errors.New("COUNT e UNTIL não podem ser usados juntos")
+	}
+	for _, d := range regra.ByDay {
+		if d.Ordinal != 0 && regra.Freq != "MONTHLY" && regra.Freq != "YEARLY" {
+			return regraRecorrencia{}, errors.New("BYDAY com ordinal (ex: 1MO) só é válido com FREQ=MONTHLY ou FREQ=YEARLY")
+		}
+	}
+	return regra, nil
+}
+
+func parseDiaDaSemanaRRule(item string) (diaDaSemanaRRule, error) {
+	item = strings.TrimSpace(item)
+	if len(item) < 2 {
+		return diaDaSemanaRRule{}, fmt.Errorf("BYDAY '%s' inválido", item)
+	}
+	dia, ok := diasRRule[item[len(item)-2:]]
+	if !ok {
+		return diaDaSemanaRRule{}, fmt.Errorf("BYDAY '%s' inválido", item)
+	}
+	d := diaDaSemanaRRule{Dia: dia}
+	if prefixo := item[:len(item)-2]; prefixo != "" {
+		n, err := strconv.Atoi(prefixo)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return diaDaSemanaRRule{}, fmt.Errorf("BYDAY '%s' inválido", item)
+		}
+		d.Ordinal = n
+	}
+	return d, nil
+}
+
+func parseUntil(valor string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", valor); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", valor, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", valor, time.Local); err == nil {
+		// Uma data sem hora inclui o dia inteiro.
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL '%s' inválido (use YYYYMMDD ou YYYYMMDDTHHMMSSZ)", valor)
+}
+
+// String devolve a regra no formato RRULE, com as partes em ordem canônica e UNTIL em UTC.
+func (r regraRecorrencia) String() string {
+	return r.formatar(func(t time.Time) string { return t.UTC().Format("20060102T150405Z") })
+}
+
+// formatar monta a regra no formato RRULE usando formatarUntil para o valor de UNTIL.
+func (r regraRecorrencia) formatar(formatarUntil func(time.Time) string) string {
+	partes := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		partes = append(partes, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		dias := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			dias[i] = d.String()
+		}
+		partes = append(partes, "BYDAY="+strings.Join(dias, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		dias := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			dias[i] = strconv.Itoa(d)
+		}
+		partes = append(partes, "BYMONTHDAY="+strings.Join(dias, ","))
+	}
+	if !r.Until.IsZero() {
+		partes = append(partes, "UNTIL="+formatarUntil(r.Until))
+	}
+	if r.Count > 0 {
+		partes = append(partes, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(partes, ";")
+}
+
+// ocorrencias percorre, em ordem cronológica, os inícios das ocorrências da regra a partir de inicio (DTSTART).
+// Como no RFC 5545, o próprio DTSTART é sempre a primeira ocorrência e conta para COUNT.
+// visitar é chamada para cada ocorrência; a iteração termina quando ela retorna false
+// ou quando COUNT/UNTIL são atingidos.
+func (r regraRecorrencia) ocorrencias(inicio time.Time, visitar func(time.Time) bool) {
+	emitidas := 0
+	emitir := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		emitidas++
+		if !visitar(t) {
+			return false
+		}
+		return r.Count == 0 || emitidas < r.Count
+	}
+
+	if !emitir(inicio) {
+		return
+	}
+	vazios := 0
+	for periodo := 0; vazios < limitePeriodosVazios; periodo++ {
+		candidatos := r.candidatos(inicio, periodo*r.Interval)
+		emitiuNoPeriodo := false
+		for _, c := range candidatos {
+			if !c.After(inicio) {
+				continue
+			}
+			emitiuNoPeriodo = true
+			if !emitir(c) {
+				return
+			}
+		}
+		if emitiuNoPeriodo {
+			vazios = 0
+		} else {
+			vazios++
+		}
+	}
+}
+
+// candidatos devolve, ordenados, os inícios gerados pela regra no período deslocado em passo unidades de FREQ.
+func (r regraRecorrencia) candidatos(inicio time.Time, passo int) []time.Time {
+	loc := inicio.Location()
+	h, m, sec := inicio.Clock()
+	noDia := func(ano int, mes time.Month, dia int) time.Time {
+		return time.Date(ano, mes, dia, h, m, sec, inicio.Nanosecond(), loc)
+	}
+
+	var dias []time.Time
+	switch r.Freq {
+	case "DAILY":
+		d := time.Date(inicio.Year(), inicio.Month(), inicio.Day()+passo, h, m, sec, inicio.Nanosecond(), loc)
+		if r.aceitaDiaDaSemana(d) && r.aceitaDiaDoMes(d) {
+			dias = append(dias, d)
+		}
+	case "WEEKLY":
+		// Semanas começam na segunda-feira (WKST=MO).
+		recuo := (int(inicio.Weekday()) + 6) % 7
+		segunda := time.Date(inicio.Year(), inicio.Month(), inicio.Day()-recuo+7*passo, h, m, sec, inicio.Nanosecond(), loc)
+		for i := 0; i < 7; i++ {
+			d := segunda.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && d.Weekday() != inicio.Weekday() {
+				continue
+			}
+			if r.aceitaDiaDaSemana(d) && r.aceitaDiaDoMes(d) {
+				dias = append(dias, d)
+			}
+		}
+	case "MONTHLY":
+		primeiro := time.Date(inicio.Year(), inicio.Month()+time.Month(passo), 1, 0, 0, 0, 0, loc)
+		dias = r.diasNoIntervalo(primeiro, primeiro.AddDate(0, 1, 0), inicio, noDia)
+	case "YEARLY":
+		ano := inicio.Year() + passo
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			d := noDia(ano, inicio.Month(), inicio.Day())
+			if d.Month() == inicio.Month() { // 29/02 só existe em anos bissextos
+				dias = append(dias, d)
+			}
+		} else if len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 {
+			primeiro := time.Date(ano, time.January, 1, 0, 0, 0, 0, loc)
+			dias = r.diasNoIntervalo(primeiro, primeiro.AddDate(1, 0, 0), inicio, noDia)
+		} else {
+			// BYMONTHDAY anual se aplica ao mês do DTSTART.
+			primeiro := time.Date(ano, inicio.Month(), 1, 0, 0, 0, 0, loc)
+			dias = r.diasNoIntervalo(primeiro, primeiro.AddDate(0, 1, 0), inicio, noDia)
+		}
+	}
+	sort.Slice(dias, func(i, j int) bool { return dias[i].Before(dias[j]) })
+	return dias
+}
+
+// diasNoIntervalo seleciona os dias de [primeiro, limite) que satisfazem BYDAY e BYMONTHDAY.
+// Sem nenhum dos dois, usa o dia do mês do DTSTART. Os ordinais de BYDAY contam dentro do intervalo.
+func (r regraRecorrencia) diasNoIntervalo(primeiro, limite, inicio time.Time, noDia func(int, time.Month, int) time.Time) []time.Time {
+	var dias []time.Time
+	for d := primeiro; d.Before(limite); d = d.AddDate(0, 0, 1) {
+		candidato := noDia(d.Year(), d.Month(), d.Day())
+		switch {
+		case len(r.ByDay) == 0 && len(r.ByMonthDay) == 0:
+			if d.Day() != inicio.Day() {
+				continue
+			}
+		case len(r.ByDay) > 0 && !r.aceitaDiaDaSemanaComOrdinal(d, primeiro, limite):
+			continue
+		case !r.aceitaDiaDoMes(d):
+			continue
+		}
+		dias = append(dias, candidato)
+	}
+	return dias
+}
+
+func (r regraRecorrencia) aceitaDiaDaSemana(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, b := range r.ByDay {
+		if b.Dia == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// aceitaDiaDaSemanaComOrdinal verifica BYDAY considerando ordinais contados em [primeiro, limite).
+func (r regraRecorrencia) aceitaDiaDaSemanaComOrdinal(d, primeiro, limite time.Time) bool {
+	for _, b := range r.ByDay {
+		if b.Dia != d.Weekday() {
+			continue
+		}
+		if b.Ordinal == 0 {
+			return true
+		}
+		diasDesdeInicio := int(d.Sub(primeiro).Hours()/24 + 0.5)
+		diasAteFim := int(limite.Sub(d).Hours()/24+0.5) - 1
+		if b.Ordinal > 0 && diasDesdeInicio/7+1 == b.Ordinal {
+			return true
+		}
+		if b.Ordinal < 0 && -(diasAteFim/7+1) == b.Ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+func (r regraRecorrencia) aceitaDiaDoMes(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	ultimo := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, d.Location()).Day()
+	for _, n := range r.ByMonthDay {
+		if n == d.Day() || (n < 0 && ultimo+n+1 == d.Day()) {
+			return true
+		}
+	}
+	return false
+}
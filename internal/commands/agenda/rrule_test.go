@@ -0,0 +1,89 @@
+package agenda
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// coletarOcorrencias devolve as ocorrências da regra a partir de inicio, limitadas a max.
+func coletarOcorrencias(t *testing.T, regraStr string, inicio time.Time, max int) []string {
+	t.Helper()
+	regra, err := parseRegraRecorrencia(regraStr)
+	if err != nil {
+		t.Fatalf("parseRegraRecorrencia(%q) falhou: %v", regraStr, err)
+	}
+	var datas []string
+	regra.ocorrencias(inicio, func(o time.Time) bool {
+		datas = append(datas, o.Format(dateTimeLayout))
+		return len(datas) < max
+	})
+	return datas
+}
+
+func TestRegraRecorrenciaOcorrencias(t *testing.T) {
+	// 01/07/2024 é uma segunda-feira.
+	inicio := time.Date(2024, 7, 1, 10, 0, 0, 0, time.Local)
+
+	casos := []struct {
+		nome     string
+		regra    string
+		inicio   time.Time
+		esperado []string
+	}{
+		{"diária com intervalo", "FREQ=DAILY;INTERVAL=2;COUNT=3", inicio,
+			[]string{"2024-07-01 10:00", "2024-07-03 10:00", "2024-07-05 10:00"}},
+		{"semanal com BYDAY e COUNT", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", inicio,
+			[]string{"2024-07-01 10:00", "2024-07-03 10:00", "2024-07-08 10:00", "2024-07-10 10:00"}},
+		{"quinzenal", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", inicio,
+			[]string{"2024-07-01 10:00", "2024-07-15 10:00", "2024-07-29 10:00"}},
+		{"UNTIL em data inclui o dia", "FREQ=WEEKLY;UNTIL=20240715", inicio,
+			[]string{"2024-07-01 10:00", "2024-07-08 10:00", "2024-07-15 10:00"}},
+		{"última sexta do mês", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", time.Date(2024, 7, 26, 14, 0, 0, 0, time.Local),
+			[]string{"2024-07-26 14:00", "2024-08-30 14:00", "2024-09-27 14:00"}},
+		{"dia 31 pula meses curtos", "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3", time.Date(2024, 1, 31, 8, 0, 0, 0, time.Local),
+			[]string{"2024-01-31 08:00", "2024-03-31 08:00", "2024-05-31 08:00"}},
+		{"último dia do mês", "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", time.Date(2024, 1, 31, 8, 0, 0, 0, time.Local),
+			[]string{"2024-01-31 08:00", "2024-02-29 08:00", "2024-03-31 08:00"}},
+		{"anual em 29/02", "FREQ=YEARLY;COUNT=2", time.Date(2024, 2, 29, 9, 0, 0, 0, time.Local),
+			[]string{"2024-02-29 09:00", "2028-02-29 09:00"}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			// O limite acima do esperado garante que COUNT/UNTIL encerram a série.
+			obtido := coletarOcorrencias(t, c.regra, c.inicio, len(c.esperado)+5)
+			if strings.Join(obtido, "|") != strings.Join(c.esperado, "|") {
+				t.Errorf("Esperado %v, obtido %v", c.esperado, obtido)
+			}
+		})
+	}
+}
+
+func TestParseRegraRecorrencia(t *testing.T) {
+	regra, err := parseRegraRecorrencia("RRULE:freq=weekly;interval=2;byday=MO,we;count=5")
+	if err != nil {
+		t.Fatalf("parseRegraRecorrencia falhou: %v", err)
+	}
+	if regra.String() != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5" {
+		t.Errorf("Forma canônica inesperada: %s", regra.String())
+	}
+
+	invalidas := map[string]string{
+		"":                                  "vazia",
+		"INTERVAL=2":                        "FREQ",
+		"FREQ=HOURLY":                       "não suportada",
+		"FREQ=DAILY;COUNT=0":                "COUNT",
+		"FREQ=WEEKLY;BYDAY=XX":              "BYDAY",
+		"FREQ=WEEKLY;BYDAY=1MO":             "ordinal",
+		"FREQ=MONTHLY;BYMONTHDAY=32":        "BYMONTHDAY",
+		"FREQ=DAILY;COUNT=2;UNTIL=2024":     "UNTIL",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101": "juntos",
+		"FREQ=DAILY;BYSETPOS=1":             "não é suportada",
+	}
+	for entrada, trecho := range invalidas {
+		if _, err := parseRegraRecorrencia(entrada); err == nil || !strings.Contains(err.Error(), trecho) {
+			t.Errorf("parseRegraRecorrencia(%q): esperado erro contendo %q, obtido %v", entrada, trecho, err)
+		}
+	}
+}
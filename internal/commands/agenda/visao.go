@@ -0,0 +1,126 @@
+package agenda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Dados das visões de semana e mês: eventos, aulas, prazos de tarefas e dias não letivos reunidos em uma única lista.
+
+// Tipos de ItemVisao.
+const (
+	TipoEvento = "evento"
+	TipoAula   = "aula"
+	TipoTarefa = "tarefa"
+	// TipoNaoLetivo marca um dia ou período sem aulas do calendário escolar, de meia-noite a meia-noite.
+	TipoNaoLetivo = "nao_letivo"
+)
+
+// ItemVisao é um compromisso exibido nas visões de calendário.
+// Para prazos de tarefas, Fim é igual a Inicio; um prazo à meia-noite vale para o dia inteiro.
+type ItemVisao struct {
+	Tipo   string // TipoEvento, TipoAula, TipoTarefa ou TipoNaoLetivo.
+	ID     string
+	Titulo string
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// ItensDoPeriodo reúne os compromissos de [inicio, fim) em ordem de início: ocorrências de eventos,
+// aulas registradas (com duração duracaoAula), prazos de tarefas ainda não concluídas e dias não letivos.
+// As aulas geradas pela grade horária aparecem uma única vez, pelo evento gerado, com o horário de término da grade.
+func ItensDoPeriodo(inicio, fim time.Time, duracaoAula time.Duration) ([]ItemVisao, error) {
+	var itens []ItemVisao
+
+	eventos, err := listarNoIntervalo(inicio, fim, "start_time", "asc", 0)
+	if err != nil {
+		return nil, err
+	}
+	_, daGrade, err := ocorrenciasDaGrade(inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range eventos {
+		tipo := TipoEvento
+		if daGrade[e.ID] {
+			// Eventos gerados pela grade horária representam aulas.
+			tipo = TipoAula
+		}
+		itens = append(itens, ItemVisao{Tipo: tipo, ID: e.ID, Titulo: e.Title, Inicio: e.StartTime, Fim: e.EndTime})
+	}
+
+	aulas, err := aulasNoIntervalo(inicio, fim, duracaoAula)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aulas {
+		itens = append(itens, ItemVisao{Tipo: TipoAula, ID: a.ID, Titulo: a.Title, Inicio: a.StartTime, Fim: a.EndTime})
+	}
+
+	tarefas, _, err := db.ListTasks(map[string]interface{}{"due_before": fim}, "due_date", "asc", 0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tarefas: %w", err)
+	}
+	for _, t := range tarefas {
+		// due_before mantém as tarefas sem prazo, e o limite do filtro é inclusivo.
+		if t.DueDate.IsZero() || t.DueDate.Before(inicio) || !t.DueDate.Before(fim) || t.Status == models.TaskStatusCompleted {
+			continue
+		}
+		prazo := t.DueDate.In(time.Local)
+		itens = append(itens, ItemVisao{Tipo: TipoTarefa, ID: t.ID, Titulo: t.Description, Inicio: prazo, Fim: prazo})
+	}
+
+	naoLetivos, err := calendario.CarregarDiasNaoLetivos(inicio, fim.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range naoLetivos {
+		itens = append(itens, ItemVisao{
+			Tipo:   TipoNaoLetivo,
+			ID:     d.ID,
+			Titulo: d.Name,
+			Inicio: time.Date(d.StartDate.Year(), d.StartDate.Month(), d.StartDate.Day(), 0, 0, 0, 0, time.Local),
+			Fim:    time.Date(d.EndDate.Year(), d.EndDate.Month(), d.EndDate.Day()+1, 0, 0, 0, 0, time.Local),
+		})
+	}
+
+	sort.SliceStable(itens, func(i, j int) bool { return itens[i].Inicio.Before(itens[j].Inicio) })
+	return itens, nil
+}
+
+// InicioDaSemana devolve a segunda-feira (meia-noite local) da semana que contém dataStr
+// (expressão do pacote datas, ex: "2024-08-08" ou "próxima segunda").
+// Com dataStr vazia, usa a semana atual.
+func InicioDaSemana(dataStr string) (time.Time, error) {
+	dia := inicioDoDia(time.Now())
+	if strings.TrimSpace(dataStr) != "" {
+		var err error
+		dia, err = datas.Data(dataStr)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("formato de data inválido: %w", err)
+		}
+	}
+	return dia.AddDate(0, 0, -((int(dia.Weekday()) + 6) % 7)), nil
+}
+
+// InicioDoMes devolve o primeiro dia (meia-noite local) do mês mesStr ("YYYY-MM").
+// Com mesStr vazio, usa o mês atual.
+func InicioDoMes(mesStr string) (time.Time, error) {
+	if strings.TrimSpace(mesStr) == "" {
+		hoje := time.Now()
+		return time.Date(hoje.Year(), hoje.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	}
+	mes, err := time.ParseInLocation("2006-01", strings.TrimSpace(mesStr), time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("formato de mês inválido. Use YYYY-MM")
+	}
+	return mes, nil
+}
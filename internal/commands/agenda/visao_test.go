@@ -0,0 +1,99 @@
+package agenda
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+func TestItensDoPeriodo(t *testing.T) {
+	LimparEventosStore()
+	conn := db.GetDB()
+	for _, tabela := range []string{"lessons", "tasks", "non_school_days"} {
+		if _, err := conn.Exec("DELETE FROM " + tabela); err != nil {
+			t.Fatalf("Falha ao limpar %s: %v", tabela, err)
+		}
+	}
+
+	if _, err := AdicionarEventoRecorrente("Plantão", "2024-08-05 14:00", "2024-08-05 15:00", "", "", "FREQ=WEEKLY;BYDAY=MO,WE", ""); err != nil {
+		t.Fatalf("AdicionarEventoRecorrente falhou: %v", err)
+	}
+	aula := models.Lesson{Subject: "Matemática", Topic: "Frações", ClassID: "7A", Date: time.Date(2024, 8, 6, 7, 30, 0, 0, time.Local)}
+	if _, err := store.NewSQLiteAulaStore(conn).SaveLesson(aula); err != nil {
+		t.Fatalf("SaveLesson falhou: %v", err)
+	}
+	tarefas := []models.Task{
+		{Description: "Corrigir provas", DueDate: time.Date(2024, 8, 8, 18, 0, 0, 0, time.Local), Status: models.TaskStatusPending},
+		{Description: "Lançar notas", DueDate: time.Date(2024, 8, 9, 18, 0, 0, 0, time.Local), Status: models.TaskStatusCompleted},
+		{Description: "Planejar bimestre", DueDate: time.Date(2024, 8, 12, 0, 0, 0, 0, time.Local), Status: models.TaskStatusPending},
+		{Description: "Sem prazo", Status: models.TaskStatusPending},
+	}
+	for _, tarefa := range tarefas {
+		if _, err := db.CreateTask(tarefa); err != nil {
+			t.Fatalf("CreateTask falhou: %v", err)
+		}
+	}
+
+	if _, err := calendario.AdicionarDiaNaoLetivo("Recesso", "2024-08-09", "2024-08-13", "recesso"); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	if _, err := calendario.AdicionarDiaNaoLetivo("Feriado municipal", "2024-08-15", "", ""); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+
+	inicio, err := InicioDaSemana("2024-08-07")
+	if err != nil {
+		t.Fatalf("InicioDaSemana falhou: %v", err)
+	}
+	itens, err := ItensDoPeriodo(inicio, inicio.AddDate(0, 0, 7), 50*time.Minute)
+	if err != nil {
+		t.Fatalf("ItensDoPeriodo falhou: %v", err)
+	}
+	var obtidos []string
+	for _, item := range itens {
+		obtidos = append(obtidos, item.Tipo+" "+item.Inicio.Format(dateTimeLayout)+"-"+item.Fim.Format("15:04")+" "+item.Titulo)
+	}
+	// A tarefa concluída, a sem prazo, o prazo na segunda seguinte e o feriado da outra semana ficam de fora.
+	// O recesso aparece inteiro, terminando à meia-noite do dia seguinte ao último dia.
+	esperados := []string{
+		"evento 2024-08-05 14:00-15:00 Plantão",
+		"aula 2024-08-06 07:30-08:20 Aula de Matemática (7A)",
+		"evento 2024-08-07 14:00-15:00 Plantão",
+		"tarefa 2024-08-08 18:00-18:00 Corrigir provas",
+		"nao_letivo 2024-08-09 00:00-00:00 Recesso",
+	}
+	if strings.Join(obtidos, "|") != strings.Join(esperados, "|") {
+		t.Errorf("Itens inesperados:\nesperado %v\nobtido   %v", esperados, obtidos)
+	}
+}
+
+func TestInicioDaSemanaEDoMes(t *testing.T) {
+	casos := map[string]string{
+		"2024-08-05": "2024-08-05", // segunda-feira
+		"2024-08-08": "2024-08-05",
+		"2024-08-11": "2024-08-05", // domingo fecha a semana
+		"2024-09-01": "2024-08-26",
+	}
+	for entrada, esperado := range casos {
+		obtido, err := InicioDaSemana(entrada)
+		if err != nil || obtido.Format(dateLayout) != esperado {
+			t.Errorf("InicioDaSemana(%q) = %v, %v; esperado %s", entrada, obtido, err, esperado)
+		}
+	}
+	if _, err := InicioDaSemana("08/13/2024"); err == nil {
+		t.Error("InicioDaSemana: esperado erro para data inválida")
+	}
+
+	mes, err := InicioDoMes("2024-02")
+	if err != nil || mes.Format(dateLayout) != "2024-02-01" {
+		t.Errorf("InicioDoMes(\"2024-02\") = %v, %v; esperado 2024-02-01", mes, err)
+	}
+	if _, err := InicioDoMes("2024-13"); err == nil {
+		t.Error("InicioDoMes: esperado erro para mês inválido")
+	}
+}
@@ -0,0 +1,427 @@
+package calendario
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// O calendário escolar é persistido pelo store.SchoolCalendarStore (tabela "non_school_days").
+// Somente os dias sem aula são registrados; qualquer outro dia é considerado letivo, inclusive fins de semana,
+// cuja ausência de aulas já se reflete na grade horária e nas frequências das rotinas.
+// O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
+
+const dataLayout = "2006-01-02"
+
+// calendarioStore devolve o store do calendário escolar sobre o banco inicializado.
+func calendarioStore() (store.SchoolCalendarStore, error) {
+	conn := db.GetDB()
+	if conn == nil {
+		return nil, errors.New("banco de dados não inicializado")
+	}
+	return store.NewSQLiteSchoolCalendarStore(conn), nil
+}
+
+// parseData interpreta uma expressão de data do pacote datas ("YYYY-MM-DD", "DD/MM/YYYY", "15/08", "próxima sexta")
+// no fuso local.
+func parseData(valor, campo string) (time.Time, error) {
+	t, err := datas.Data(valor)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("formato de data inválido para %s: %w", campo, err)
+	}
+	return t, nil
+}
+
+// parseDataArquivo interpreta uma data de arquivo importado, apenas nos formatos absolutos "YYYY-MM-DD" ou "DD/MM/YYYY":
+// expressões relativas ("amanhã") não fazem sentido em um arquivo e confundiriam a coluna de fim com a de nome.
+func parseDataArquivo(valor, campo string) (time.Time, error) {
+	valor = strings.TrimSpace(valor)
+	for _, layout := range []string{dataLayout, "02/01/2006"} {
+		if t, err := time.ParseInLocation(layout, valor, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("formato de data inválido para %s: '%s'. Use YYYY-MM-DD ou DD/MM/YYYY", campo, valor)
+}
+
+// normalizarTipo valida o tipo de um dia não letivo; vazio equivale a feriado.
+func normalizarTipo(tipo string) (string, error) {
+	switch t := strings.ToLower(strings.TrimSpace(tipo)); t {
+	case "":
+		return models.NonSchoolDayHoliday, nil
+	case models.NonSchoolDayHoliday, models.NonSchoolDayRecess, models.NonSchoolDayPlanning:
+		return t, nil
+	default:
+		return "", fmt.Errorf("tipo '%s' inválido. Use feriado, recesso ou planejamento", tipo)
+	}
+}
+
+// AdicionarDiaNaoLetivo registra um dia ou período sem aulas no calendário escolar.
+// inicioStr e fimStr são expressões do pacote datas ("YYYY-MM-DD", "DD/MM/YYYY", "15/08"); fimStr vazio registra um único dia.
+// tipo é feriado (padrão), recesso ou planejamento. O ano letivo é o ano da data de início.
+func AdicionarDiaNaoLetivo(nome, inicioStr, fimStr, tipo string) (models.NonSchoolDay, error) {
+	if strings.TrimSpace(nome) == "" {
+		return models.NonSchoolDay{}, errors.New("o nome do dia não letivo é obrigatório")
+	}
+	inicio, err := parseData(inicioStr, "início")
+	if err != nil {
+		return models.NonSchoolDay{}, err
+	}
+	fim := inicio
+	if strings.TrimSpace(fimStr) != "" {
+		if fim, err = parseData(fimStr, "fim"); err != nil {
+			return models.NonSchoolDay{}, err
+		}
+	}
+	if fim.Before(inicio) {
+		return models.NonSchoolDay{}, errors.New("a data de fim não pode ser anterior à de início")
+	}
+	tipoNormalizado, err := normalizarTipo(tipo)
+	if err != nil {
+		return models.NonSchoolDay{}, err
+	}
+
+	calendario, err := calendarioStore()
+	if err != nil {
+		return models.NonSchoolDay{}, err
+	}
+	dia, err := calendario.SaveNonSchoolDay(models.NonSchoolDay{Name: strings.TrimSpace(nome), Kind: tipoNormalizado, StartDate: inicio, EndDate: fim})
+	if err != nil {
+		return models.NonSchoolDay{}, fmt.Errorf("erro ao salvar o dia não letivo: %w", err)
+	}
+	return dia, nil
+}
+
+// ListarDiasNaoLetivos lista os dias não letivos do ano letivo em ordem de data. Com ano 0, lista todos.
+func ListarDiasNaoLetivos(ano int) ([]models.NonSchoolDay, error) {
+	calendario, err := calendarioStore()
+	if err != nil {
+		return nil, err
+	}
+	anoLetivo := ""
+	if ano != 0 {
+		anoLetivo = fmt.Sprint(ano)
+	}
+	dias, err := calendario.ListNonSchoolDays(anoLetivo)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os dias não letivos: %w", err)
+	}
+	return dias, nil
+}
+
+// RemoverDiaNaoLetivo exclui um dia não letivo do calendário.
+func RemoverDiaNaoLetivo(id string) error {
+	calendario, err := calendarioStore()
+	if err != nil {
+		return err
+	}
+	if err := calendario.DeleteNonSchoolDay(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("dia não letivo com ID '%s' não encontrado", id)
+		}
+		return fmt.Errorf("erro ao remover o dia não letivo: %w", err)
+	}
+	return nil
+}
+
+// --- Consulta ---
+
+// DiasNaoLetivos é um conjunto de dias não letivos carregado para consultas repetidas,
+// como as feitas ao gerar as aulas de um bimestre.
+type DiasNaoLetivos []models.NonSchoolDay
+
+// CarregarDiasNaoLetivos carrega os dias não letivos que tocam o intervalo de datas [inicio, fim] (inclusivo).
+func CarregarDiasNaoLetivos(inicio, fim time.Time) (DiasNaoLetivos, error) {
+	calendario, err := calendarioStore()
+	if err != nil {
+		return nil, err
+	}
+	dias, err := calendario.ListNonSchoolDaysBetween(inicio, fim)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar o calendário escolar: %w", err)
+	}
+	return DiasNaoLetivos(dias), nil
+}
+
+// Buscar devolve o dia não letivo que inclui a data de dia (no fuso de dia), se houver.
+func (d DiasNaoLetivos) Buscar(dia time.Time) (models.NonSchoolDay, bool) {
+	data := dia.Format(dataLayout)
+	for _, naoLetivo := range d {
+		if naoLetivo.StartDate.Format(dataLayout) <= data && data <= naoLetivo.EndDate.Format(dataLayout) {
+			return naoLetivo, true
+		}
+	}
+	return models.NonSchoolDay{}, false
+}
+
+// EhDiaLetivo informa se a data de dia não está em nenhum dos dias não letivos do conjunto.
+func (d DiasNaoLetivos) EhDiaLetivo(dia time.Time) bool {
+	_, naoLetivo := d.Buscar(dia)
+	return !naoLetivo
+}
+
+// EhDiaLetivo consulta o calendário escolar para uma única data.
+func EhDiaLetivo(dia time.Time) (bool, error) {
+	dias, err := CarregarDiasNaoLetivos(dia, dia)
+	if err != nil {
+		return false, err
+	}
+	return dias.EhDiaLetivo(dia), nil
+}
+
+// ProximoDiaLetivo devolve dia, se for letivo, ou o primeiro dia letivo seguinte, mantendo o horário de dia.
+// Serve para adiar uma ocorrência que cairia em um feriado ou recesso.
+func ProximoDiaLetivo(dia time.Time) (time.Time, error) {
+	calendario, err := calendarioStore()
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Cada iteração salta um período não letivo; períodos encadeados (ex: feriado emendado a um recesso) são seguidos.
+	for i := 0; i < 366; i++ {
+		dias, err := calendario.ListNonSchoolDaysBetween(dia, dia)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("erro ao consultar o calendário escolar: %w", err)
+		}
+		if len(dias) == 0 {
+			return dia, nil
+		}
+		ultimo := dias[0].EndDate
+		for _, d := range dias[1:] {
+			if d.EndDate.After(ultimo) {
+				ultimo = d.EndDate
+			}
+		}
+		dia = time.Date(ultimo.Year(), ultimo.Month(), ultimo.Day()+1, dia.Hour(), dia.Minute(), dia.Second(), dia.Nanosecond(), dia.Location())
+	}
+	return time.Time{}, fmt.Errorf("nenhum dia letivo encontrado no ano seguinte a %s", dia.Format(dataLayout))
+}
+
+// --- Importação ---
+
+// ResultadoImportacao resume a importação de uma lista de feriados.
+type ResultadoImportacao struct {
+	Criados   int      // Dias não letivos novos.
+	Ignorados int      // Linhas ou VEVENTs já cadastrados ou inválidos.
+	Avisos    []string // Motivo de cada item inválido.
+}
+
+func (r *ResultadoImportacao) avisar(formato string, args ...interface{}) {
+	r.Ignorados++
+	r.Avisos = append(r.Avisos, fmt.Sprintf(formato, args...))
+}
+
+// salvarImportado grava um dia não letivo importado, a menos que já exista um com o mesmo nome e as mesmas datas.
+// Assim, importar o mesmo arquivo duas vezes não duplica nada.
+func salvarImportado(calendario store.SchoolCalendarStore, dia models.NonSchoolDay, resultado *ResultadoImportacao) error {
+	existentes, err := calendario.ListNonSchoolDaysBetween(dia.StartDate, dia.EndDate)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar o calendário escolar: %w", err)
+	}
+	for _, e := range existentes {
+		if strings.EqualFold(e.Name, dia.Name) && e.StartDate.Format(dataLayout) == dia.StartDate.Format(dataLayout) &&
+			e.EndDate.Format(dataLayout) == dia.EndDate.Format(dataLayout) {
+			resultado.Ignorados++
+			return nil
+		}
+	}
+	if _, err := calendario.SaveNonSchoolDay(dia); err != nil {
+		return fmt.Errorf("erro ao salvar '%s': %w", dia.Name, err)
+	}
+	resultado.Criados++
+	return nil
+}
+
+// ImportarCSV importa uma lista de feriados em CSV, separada por vírgula ou ponto e vírgula.
+// Cada linha tem "data,nome[,tipo]" ou "inicio,fim,nome[,tipo]", com datas em YYYY-MM-DD ou DD/MM/YYYY.
+// Uma primeira linha de cabeçalho é ignorada. Linhas inválidas são puladas e listadas em Avisos.
+func ImportarCSV(r io.Reader) (ResultadoImportacao, error) {
+	var resultado ResultadoImportacao
+	conteudo, err := io.ReadAll(r)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler o arquivo: %w", err)
+	}
+	leitor := csv.NewReader(bytes.NewReader(conteudo))
+	primeiraLinha, _, _ := bufio.NewReader(bytes.NewReader(conteudo)).ReadLine()
+	if bytes.Contains(primeiraLinha, []byte(";")) {
+		leitor.Comma = ';'
+	}
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+	linhas, err := leitor.ReadAll()
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler o CSV: %w", err)
+	}
+
+	calendario, err := calendarioStore()
+	if err != nil {
+		return resultado, err
+	}
+	for i, campos := range linhas {
+		if len(campos) == 0 || (len(campos) == 1 && strings.TrimSpace(campos[0]) == "") {
+			continue
+		}
+		inicio, err := parseDataArquivo(campos[0], "início")
+		if err != nil {
+			if i == 0 {
+				continue // Cabeçalho.
+			}
+			resultado.avisar("linha %d: %v", i+1, err)
+			continue
+		}
+		resto := campos[1:]
+		fim := inicio
+		if len(resto) > 0 {
+			if f, err := parseDataArquivo(resto[0], "fim"); err == nil {
+				fim, resto = f, resto[1:]
+			}
+		}
+		if len(resto) == 0 || strings.TrimSpace(resto[0]) == "" {
+			resultado.avisar("linha %d: nome ausente", i+1)
+			continue
+		}
+		if fim.Before(inicio) {
+			resultado.avisar("linha %d: a data de fim é anterior à de início", i+1)
+			continue
+		}
+		tipo := ""
+		if len(resto) > 1 {
+			tipo = resto[1]
+		}
+		tipoNormalizado, err := normalizarTipo(tipo)
+		if err != nil {
+			resultado.avisar("linha %d: %v", i+1, err)
+			continue
+		}
+		dia := models.NonSchoolDay{Name: strings.TrimSpace(resto[0]), Kind: tipoNormalizado, StartDate: inicio, EndDate: fim}
+		if err := salvarImportado(calendario, dia, &resultado); err != nil {
+			return resultado, err
+		}
+	}
+	return resultado, nil
+}
+
+// ImportarICS importa os VEVENTs de um calendário iCalendar (ex: feriados exportados do Google Agenda) como feriados.
+// DTSTART e DTEND definem o período; com datas sem hora (VALUE=DATE), DTEND é exclusivo, como no RFC 5545.
+// VEVENTs sem SUMMARY ou DTSTART são ignorados; regras de recorrência não são expandidas.
+func ImportarICS(r io.Reader) (ResultadoImportacao, error) {
+	var resultado ResultadoImportacao
+	linhas, err := desdobrarLinhasICS(r)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler o arquivo: %w", err)
+	}
+	if len(linhas) == 0 || !strings.EqualFold(strings.TrimSpace(linhas[0]), "BEGIN:VCALENDAR") {
+		return resultado, errors.New("o arquivo não é um calendário iCalendar")
+	}
+	calendario, err := calendarioStore()
+	if err != nil {
+		return resultado, err
+	}
+
+	type vevent struct {
+		nome, inicio, fim string
+		fimEhData, rrule  bool
+	}
+	var atual *vevent
+	aninhados := 0
+	for _, linha := range linhas {
+		nome, valor, ok := strings.Cut(linha, ":")
+		if !ok {
+			continue
+		}
+		propriedade, parametros, _ := strings.Cut(strings.ToUpper(nome), ";")
+		switch {
+		case propriedade == "BEGIN" && strings.EqualFold(valor, "VEVENT") && atual == nil:
+			atual = &vevent{}
+		case propriedade == "BEGIN" && atual != nil:
+			aninhados++
+		case propriedade == "END" && atual != nil && aninhados > 0:
+			aninhados--
+		case propriedade == "END" && strings.EqualFold(valor, "VEVENT") && atual != nil:
+			v := *atual
+			atual = nil
+			if v.nome == "" || v.inicio == "" {
+				resultado.avisar("VEVENT sem SUMMARY ou DTSTART ignorado")
+				continue
+			}
+			inicio, err := time.ParseInLocation("20060102", dataICS(v.inicio), time.Local)
+			if err != nil {
+				resultado.avisar("'%s': DTSTART inválido", v.nome)
+				continue
+			}
+			fim := inicio
+			if v.fim != "" {
+				if f, err := time.ParseInLocation("20060102", dataICS(v.fim), time.Local); err == nil {
+					fim = f
+					// Um DTEND de data (ou à meia-noite) marca o dia seguinte ao último dia do evento.
+					if v.fimEhData || strings.HasSuffix(strings.TrimSuffix(v.fim, "Z"), "T000000") {
+						fim = fim.AddDate(0, 0, -1)
+					}
+				}
+			}
+			if fim.Before(inicio) {
+				fim = inicio
+			}
+			if v.rrule {
+				resultado.Avisos = append(resultado.Avisos, fmt.Sprintf("'%s': regra de recorrência ignorada; apenas %s foi importado", v.nome, inicio.Format(dataLayout)))
+			}
+			dia := models.NonSchoolDay{Name: v.nome, Kind: models.NonSchoolDayHoliday, StartDate: inicio, EndDate: fim}
+			if err := salvarImportado(calendario, dia, &resultado); err != nil {
+				return resultado, err
+			}
+		case atual == nil || aninhados > 0:
+			// Propriedades fora de um VEVENT ou de componentes aninhados (ex: VALARM).
+		case propriedade == "SUMMARY":
+			atual.nome = strings.TrimSpace(desescaparTextoICS(valor))
+		case propriedade == "DTSTART":
+			atual.inicio = strings.TrimSpace(valor)
+		case propriedade == "DTEND":
+			atual.fim = strings.TrimSpace(valor)
+			atual.fimEhData = strings.Contains(parametros, "VALUE=DATE") && !strings.Contains(parametros, "VALUE=DATE-TIME") || !strings.Contains(valor, "T")
+		case propriedade == "RRULE":
+			atual.rrule = true
+		}
+	}
+	return resultado, nil
+}
+
+// desdobrarLinhasICS lê as linhas de conteúdo, juntando as continuações (linhas iniciadas por espaço ou tab).
+func desdobrarLinhasICS(r io.Reader) ([]string, error) {
+	var linhas []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		linha := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(linha, " ") || strings.HasPrefix(linha, "\t")) && len(linhas) > 0 {
+			linhas[len(linhas)-1] += linha[1:]
+			continue
+		}
+		if strings.TrimSpace(linha) != "" {
+			linhas = append(linhas, linha)
+		}
+	}
+	return linhas, scanner.Err()
+}
+
+// dataICS devolve a parte de data ("YYYYMMDD") de um valor DATE ou DATE-TIME.
+func dataICS(valor string) string {
+	if len(valor) > 8 {
+		return valor[:8]
+	}
+	return valor
+}
+
+// desescaparTextoICS desfaz os escapes de valores TEXT; quebras de linha viram espaços.
+func desescaparTextoICS(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ").Replace(s)
+}
@@ -0,0 +1,205 @@
+package calendario
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/db"
+)
+
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes do calendário escolar.
+func TestMain(m *testing.M) {
+	db.InitTestDB("calendario")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
+func limparCalendario(t *testing.T) {
+	t.Helper()
+	if _, err := db.GetDB().Exec("DELETE FROM non_school_days"); err != nil {
+		t.Fatalf("Falha ao limpar non_school_days: %v", err)
+	}
+}
+
+// descreverDias lista os dias cadastrados como "2024-02-12..2024-02-13 Carnaval (feriado)".
+func descreverDias(t *testing.T, ano int) string {
+	t.Helper()
+	dias, err := ListarDiasNaoLetivos(ano)
+	if err != nil {
+		t.Fatalf("ListarDiasNaoLetivos falhou: %v", err)
+	}
+	var descricoes []string
+	for _, d := range dias {
+		descricoes = append(descricoes, d.StartDate.Format(dataLayout)+".."+d.EndDate.Format(dataLayout)+" "+d.Name+" ("+d.Kind+")")
+	}
+	return strings.Join(descricoes, "|")
+}
+
+func TestAdicionarDiaNaoLetivo(t *testing.T) {
+	limparCalendario(t)
+
+	if _, err := AdicionarDiaNaoLetivo("Carnaval", "2024-02-12", "13/02/2024", ""); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	recesso, err := AdicionarDiaNaoLetivo("Recesso de julho", "2024-07-15", "2024-07-26", "Recesso")
+	if err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	if recesso.AcademicYear != "2024" {
+		t.Errorf("Ano letivo esperado 2024, obtido %q", recesso.AcademicYear)
+	}
+	if _, err := AdicionarDiaNaoLetivo("Confraternização", "2025-01-01", "", ""); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+
+	invalidos := []struct{ nome, inicio, fim, tipo, trecho string }{
+		{"", "2024-04-21", "", "", "obrigatório"},
+		{"Tiradentes", "21-13-2024", "", "", "formato de data inválido"},
+		{"Tiradentes", "2024-04-21", "2024-04-20", "", "anterior"},
+		{"Tiradentes", "2024-04-21", "", "folga", "tipo"},
+	}
+	for _, c := range invalidos {
+		if _, err := AdicionarDiaNaoLetivo(c.nome, c.inicio, c.fim, c.tipo); err == nil || !strings.Contains(err.Error(), c.trecho) {
+			t.Errorf("AdicionarDiaNaoLetivo(%q, %q, %q, %q): esperado erro contendo %q, obtido %v", c.nome, c.inicio, c.fim, c.tipo, c.trecho, err)
+		}
+	}
+
+	esperado := "2024-02-12..2024-02-13 Carnaval (feriado)|2024-07-15..2024-07-26 Recesso de julho (recesso)"
+	if obtido := descreverDias(t, 2024); obtido != esperado {
+		t.Errorf("Dias de 2024 inesperados:\nesperado %s\nobtido   %s", esperado, obtido)
+	}
+	if todos, _ := ListarDiasNaoLetivos(0); len(todos) != 3 {
+		t.Errorf("Esperados 3 dias em todos os anos, obtido %d", len(todos))
+	}
+
+	if err := RemoverDiaNaoLetivo(recesso.ID); err != nil {
+		t.Fatalf("RemoverDiaNaoLetivo falhou: %v", err)
+	}
+	if err := RemoverDiaNaoLetivo(recesso.ID); err == nil || !strings.Contains(err.Error(), "não encontrado") {
+		t.Errorf("Esperado erro de dia não encontrado, obtido %v", err)
+	}
+}
+
+func TestProximoDiaLetivo(t *testing.T) {
+	limparCalendario(t)
+	// Feriado na sexta emendado a um recesso que começa no sábado seguinte.
+	if _, err := AdicionarDiaNaoLetivo("Feriado", "2024-11-15", "", ""); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	if _, err := AdicionarDiaNaoLetivo("Recesso", "2024-11-16", "2024-11-18", "recesso"); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+
+	casos := []struct{ dia, esperado string }{
+		{"2024-11-14 08:00", "2024-11-14 08:00"},
+		{"2024-11-15 08:00", "2024-11-19 08:00"},
+		{"2024-11-17 13:30", "2024-11-19 13:30"},
+	}
+	for _, c := range casos {
+		dia, _ := time.ParseInLocation("2006-01-02 15:04", c.dia, time.Local)
+		obtido, err := ProximoDiaLetivo(dia)
+		if err != nil {
+			t.Fatalf("ProximoDiaLetivo falhou: %v", err)
+		}
+		if obtido.Format("2006-01-02 15:04") != c.esperado {
+			t.Errorf("ProximoDiaLetivo(%s): esperado %s, obtido %s", c.dia, c.esperado, obtido.Format("2006-01-02 15:04"))
+		}
+	}
+
+	dias, err := CarregarDiasNaoLetivos(time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local), time.Date(2024, 11, 16, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("CarregarDiasNaoLetivos falhou: %v", err)
+	}
+	if len(dias) != 2 {
+		t.Fatalf("Esperados 2 dias não letivos, obtido %d", len(dias))
+	}
+	if d, ok := dias.Buscar(time.Date(2024, 11, 17, 10, 0, 0, 0, time.Local)); !ok || d.Name != "Recesso" {
+		t.Errorf("Buscar(17/11) deveria encontrar o recesso, obtido %+v (%v)", d, ok)
+	}
+	if !dias.EhDiaLetivo(time.Date(2024, 11, 19, 0, 0, 0, 0, time.Local)) {
+		t.Error("19/11 deveria ser dia letivo")
+	}
+}
+
+func TestImportarCSV(t *testing.T) {
+	limparCalendario(t)
+	csv := `data;nome;tipo
+2024-01-01;Confraternização Universal
+12/02/2024;13/02/2024;Carnaval;feriado
+2024-07-15;2024-07-26;Recesso escolar;recesso
+2024-13-01;Data inválida
+2024-09-07;
+2024-10-14;Conselho de classe;folga
+`
+	resultado, err := ImportarCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ImportarCSV falhou: %v", err)
+	}
+	if resultado.Criados != 3 || resultado.Ignorados != 3 || len(resultado.Avisos) != 3 {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+	esperado := "2024-01-01..2024-01-01 Confraternização Universal (feriado)|2024-02-12..2024-02-13 Carnaval (feriado)|2024-07-15..2024-07-26 Recesso escolar (recesso)"
+	if obtido := descreverDias(t, 2024); obtido != esperado {
+		t.Errorf("Dias importados inesperados:\nesperado %s\nobtido   %s", esperado, obtido)
+	}
+
+	t.Run("Reimportar não duplica", func(t *testing.T) {
+		resultado, err := ImportarCSV(strings.NewReader("2024-01-01,Confraternização Universal\n2024-11-20,Consciência Negra\n"))
+		if err != nil {
+			t.Fatalf("ImportarCSV falhou: %v", err)
+		}
+		if resultado.Criados != 1 || resultado.Ignorados != 1 || len(resultado.Avisos) != 0 {
+			t.Errorf("Resultado inesperado: %+v", resultado)
+		}
+	})
+}
+
+func TestImportarICS(t *testing.T) {
+	limparCalendario(t)
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240212",
+		"DTEND;VALUE=DATE:20240214",
+		"SUMMARY:Carnaval",
+		"BEGIN:VALARM",
+		"SUMMARY:Lembrete",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240421",
+		"SUMMARY:Tiradentes\\, feriado",
+		"  nacional",
+		"RRULE:FREQ=YEARLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20240507T080000",
+		"DTEND:20240507T120000",
+		"SUMMARY:Planejamento",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240601",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	resultado, err := ImportarICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("ImportarICS falhou: %v", err)
+	}
+	if resultado.Criados != 3 || resultado.Ignorados != 1 || len(resultado.Avisos) != 2 {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+	esperado := "2024-02-12..2024-02-13 Carnaval (feriado)|2024-04-21..2024-04-21 Tiradentes, feriado nacional (feriado)|2024-05-07..2024-05-07 Planejamento (feriado)"
+	if obtido := descreverDias(t, 2024); obtido != esperado {
+		t.Errorf("Dias importados inesperados:\nesperado %s\nobtido   %s", esperado, obtido)
+	}
+
+	if _, err := ImportarICS(strings.NewReader("data;nome\n")); err == nil {
+		t.Error("Esperado erro ao importar um arquivo que não é iCalendar")
+	}
+}
@@ -0,0 +1,473 @@
+package horario
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// A grade horária é persistida pelo store.TimetableStore (tabelas "timetable_slots" e "timetable_occurrences").
+// Cada ocorrência gerada liga um horário, em um dia letivo do bimestre, a uma aula do AulaStore
+// e a um evento da agenda. O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
+
+const (
+	horaLayout = "15:04"
+	dataLayout = "2006-01-02"
+)
+
+// erroHorarioNaoEncontrado padroniza a mensagem de erro para IDs de horário inexistentes.
+func erroHorarioNaoEncontrado(id string) error {
+	return fmt.Errorf("horário com ID '%s' não encontrado", id)
+}
+
+// diasDaSemana aceita os nomes dos dias em português, com ou sem acento e com ou sem "-feira".
+var diasDaSemana = map[string]time.Weekday{
+	"dom": time.Sunday, "domingo": time.Sunday,
+	"seg": time.Monday, "segunda": time.Monday,
+	"ter": time.Tuesday, "terça": time.Tuesday, "terca": time.Tuesday,
+	"qua": time.Wednesday, "quarta": time.Wednesday,
+	"qui": time.Thursday, "quinta": time.Thursday,
+	"sex": time.Friday, "sexta": time.Friday,
+	"sáb": time.Saturday, "sab": time.Saturday, "sábado": time.Saturday, "sabado": time.Saturday,
+}
+
+// abreviacoesDosDias abrevia os dias da semana em português, na ordem de time.Weekday.
+var abreviacoesDosDias = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+// parseDiaDaSemana interpreta um dia da semana em português (ex: "seg", "terça-feira").
+func parseDiaDaSemana(s string) (time.Weekday, error) {
+	chave := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "-feira")
+	dia, ok := diasDaSemana[chave]
+	if !ok {
+		return 0, fmt.Errorf("dia da semana inválido: '%s'. Use seg, ter, qua, qui, sex, sáb ou dom", s)
+	}
+	return dia, nil
+}
+
+// parseHora valida um horário "HH:MM" e devolve o deslocamento desde a meia-noite.
+func parseHora(valor, campo string) (time.Duration, error) {
+	t, err := time.Parse(horaLayout, strings.TrimSpace(valor))
+	if err != nil {
+		return 0, fmt.Errorf("formato de horário inválido para %s: '%s'. Use HH:MM", campo, valor)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// horarioNoDia devolve o horário de relógio do dia correspondente a um deslocamento desde a meia-noite.
+func horarioNoDia(dia time.Time, deslocamento time.Duration) time.Time {
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), int(deslocamento/time.Hour), int(deslocamento%time.Hour/time.Minute), 0, 0, time.Local)
+}
+
+// stores devolve os stores usados pela grade horária sobre o banco inicializado.
+func stores() (store.TimetableStore, store.AulaStore, store.TermStore, error) {
+	conn := db.GetDB()
+	if conn == nil {
+		return nil, nil, nil, errors.New("banco de dados não inicializado")
+	}
+	return store.NewSQLiteTimetableStore(conn), store.NewSQLiteAulaStore(conn), store.NewSQLiteTermStore(conn), nil
+}
+
+// buscarBimestre busca o bimestre (Term) pelo ID.
+func buscarBimestre(terms store.TermStore, id string) (models.Term, error) {
+	if strings.TrimSpace(id) == "" {
+		return models.Term{}, errors.New("o ID do bimestre é obrigatório")
+	}
+	term, err := terms.GetTermByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Term{}, fmt.Errorf("bimestre com ID '%s' não encontrado", id)
+		}
+		return models.Term{}, fmt.Errorf("erro ao buscar bimestre: %w", err)
+	}
+	return term, nil
+}
+
+// buscarHorario busca um horário da grade pelo ID.
+func buscarHorario(grade store.TimetableStore, id string) (models.TimetableSlot, error) {
+	slot, err := grade.GetSlotByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TimetableSlot{}, erroHorarioNaoEncontrado(id)
+		}
+		return models.TimetableSlot{}, fmt.Errorf("erro ao buscar horário: %w", err)
+	}
+	return slot, nil
+}
+
+// validarHorario confere os campos de um horário e se ele se sobrepõe a outro horário do mesmo bimestre e dia.
+// Um professor não está em duas turmas ao mesmo tempo, então a sobreposição vale para todas as turmas.
+func validarHorario(grade store.TimetableStore, slot models.TimetableSlot) error {
+	if strings.TrimSpace(slot.ClassID) == "" || strings.TrimSpace(slot.Subject) == "" {
+		return errors.New("turma e disciplina são obrigatórias")
+	}
+	inicio, err := parseHora(slot.StartTime, "início")
+	if err != nil {
+		return err
+	}
+	fim, err := parseHora(slot.EndTime, "término")
+	if err != nil {
+		return err
+	}
+	if fim <= inicio {
+		return errors.New("o horário de término deve ser posterior ao de início")
+	}
+
+	existentes, err := grade.ListSlots(slot.TermID, "")
+	if err != nil {
+		return fmt.Errorf("erro ao listar a grade horária: %w", err)
+	}
+	referencia := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, outro := range existentes {
+		if outro.ID == slot.ID || outro.Weekday != slot.Weekday {
+			continue
+		}
+		outroInicio, _ := parseHora(outro.StartTime, "início")
+		outroFim, _ := parseHora(outro.EndTime, "término")
+		if models.IntervalsOverlap(referencia.Add(inicio), referencia.Add(fim), referencia.Add(outroInicio), referencia.Add(outroFim)) {
+			return fmt.Errorf("o horário se sobrepõe a %s %s-%s (%s, %s)", abreviacoesDosDias[outro.Weekday], outro.StartTime, outro.EndTime, outro.ClassID, outro.Subject)
+		}
+	}
+	return nil
+}
+
+// AdicionarHorario cadastra um horário semanal na grade do bimestre bimestreID.
+// diaStr é o dia da semana em português ("seg", "terça", ...) e inicioStr/fimStr usam o formato "HH:MM".
+// As aulas e eventos só são criados por GerarAulas.
+func AdicionarHorario(bimestreID, turma, disciplina, diaStr, inicioStr, fimStr, local string) (models.TimetableSlot, error) {
+	grade, _, terms, err := stores()
+	if err != nil {
+		return models.TimetableSlot{}, err
+	}
+	term, err := buscarBimestre(terms, bimestreID)
+	if err != nil {
+		return models.TimetableSlot{}, err
+	}
+	dia, err := parseDiaDaSemana(diaStr)
+	if err != nil {
+		return models.TimetableSlot{}, err
+	}
+
+	slot := models.TimetableSlot{
+		TermID:    term.ID,
+		ClassID:   strings.TrimSpace(turma),
+		Subject:   strings.TrimSpace(disciplina),
+		Weekday:   dia,
+		StartTime: strings.TrimSpace(inicioStr),
+		EndTime:   strings.TrimSpace(fimStr),
+		Location:  strings.TrimSpace(local),
+	}
+	if err := validarHorario(grade, slot); err != nil {
+		return models.TimetableSlot{}, err
+	}
+	salvo, err := grade.SaveSlot(slot)
+	if err != nil {
+		return models.TimetableSlot{}, fmt.Errorf("erro ao salvar o horário: %w", err)
+	}
+	return salvo, nil
+}
+
+// ListarHorarios lista a grade do bimestre, de segunda a domingo, opcionalmente filtrada por turma.
+func ListarHorarios(bimestreID, turma string) ([]models.TimetableSlot, error) {
+	grade, _, terms, err := stores()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := buscarBimestre(terms, bimestreID); err != nil {
+		return nil, err
+	}
+	slots, err := grade.ListSlots(bimestreID, strings.TrimSpace(turma))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar a grade horária: %w", err)
+	}
+	return slots, nil
+}
+
+// EditarHorario altera os campos informados (não vazios) de um horário.
+// As aulas e eventos já gerados só mudam na próxima execução de GerarAulas, que atualiza apenas as ocorrências futuras.
+func EditarHorario(id, novaTurma, novaDisciplina, novoDiaStr, novoInicioStr, novoFimStr, novoLocal string) (models.TimetableSlot, error) {
+	grade, _, _, err := stores()
+	if err != nil {
+		return models.TimetableSlot{}, err
+	}
+	slot, err := buscarHorario(grade, id)
+	if err != nil {
+		return models.TimetableSlot{}, err
+	}
+
+	if strings.TrimSpace(novaTurma) != "" {
+		slot.ClassID = strings.TrimSpace(novaTurma)
+	}
+	if strings.TrimSpace(novaDisciplina) != "" {
+		slot.Subject = strings.TrimSpace(novaDisciplina)
+	}
+	if strings.TrimSpace(novoDiaStr) != "" {
+		if slot.Weekday, err = parseDiaDaSemana(novoDiaStr); err != nil {
+			return models.TimetableSlot{}, err
+		}
+	}
+	if strings.TrimSpace(novoInicioStr) != "" {
+		slot.StartTime = strings.TrimSpace(novoInicioStr)
+	}
+	if strings.TrimSpace(novoFimStr) != "" {
+		slot.EndTime = strings.TrimSpace(novoFimStr)
+	}
+	if strings.TrimSpace(novoLocal) != "" {
+		slot.Location = strings.TrimSpace(novoLocal)
+	}
+
+	if err := validarHorario(grade, slot); err != nil {
+		return models.TimetableSlot{}, err
+	}
+	salvo, err := grade.SaveSlot(slot)
+	if err != nil {
+		return models.TimetableSlot{}, fmt.Errorf("erro ao salvar o horário: %w", err)
+	}
+	return salvo, nil
+}
+
+// RemoverHorario exclui um horário da grade junto com as aulas e eventos futuros gerados a partir dele.
+// Aulas que já aconteceram são mantidas. Retorna quantas ocorrências futuras foram removidas.
+func RemoverHorario(id string) (int, error) {
+	return removerHorario(id, time.Now())
+}
+
+func removerHorario(id string, agora time.Time) (int, error) {
+	grade, aulas, _, err := stores()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := buscarHorario(grade, id); err != nil {
+		return 0, err
+	}
+	ocorrencias, err := grade.ListOccurrences(id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar as aulas geradas: %w", err)
+	}
+	removidas := 0
+	for _, o := range ocorrencias {
+		if o.StartTime.Before(agora) {
+			continue
+		}
+		if err := removerOcorrencia(grade, aulas, o); err != nil {
+			return removidas, err
+		}
+		removidas++
+	}
+	if err := grade.DeleteSlot(id); err != nil {
+		return removidas, fmt.Errorf("erro ao remover o horário: %w", err)
+	}
+	return removidas, nil
+}
+
+// ResultadoGeracao resume uma execução de GerarAulas.
+type ResultadoGeracao struct {
+	Criadas     int // Ocorrências novas: aula e evento criados.
+	Atualizadas int // Ocorrências futuras alteradas para refletir a grade atual.
+	Removidas   int // Ocorrências futuras que não fazem mais parte da grade.
+	Mantidas    int // Ocorrências futuras que já estavam de acordo com a grade.
+	Passadas    int // Ocorrências que já aconteceram e não foram tocadas.
+}
+
+// GerarAulas materializa a grade do bimestre: para cada horário e cada dia letivo entre StartDate e EndDate
+// com o mesmo dia da semana, cria uma aula (models.Lesson) no AulaStore e o evento correspondente na agenda.
+// Dias letivos já passados que ainda não têm aula também são preenchidos.
+// Executar novamente após alterar a grade atualiza somente as ocorrências futuras; aulas passadas,
+// com seus tópicos, planos e observações, são preservadas. Nas ocorrências futuras atualizadas,
+// tópico, plano e observações também são mantidos.
+func GerarAulas(bimestreID string) (ResultadoGeracao, error) {
+	return gerarAulas(bimestreID, time.Now())
+}
+
+func gerarAulas(bimestreID string, agora time.Time) (ResultadoGeracao, error) {
+	var resultado ResultadoGeracao
+	grade, aulas, terms, err := stores()
+	if err != nil {
+		return resultado, err
+	}
+	term, err := buscarBimestre(terms, bimestreID)
+	if err != nil {
+		return resultado, err
+	}
+	slots, err := grade.ListSlots(term.ID, "")
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao listar a grade horária: %w", err)
+	}
+	naoLetivos, err := calendario.CarregarDiasNaoLetivos(term.StartDate, term.EndDate)
+	if err != nil {
+		return resultado, err
+	}
+	for _, slot := range slots {
+		if err := sincronizarHorario(grade, aulas, term, naoLetivos, slot, agora, &resultado); err != nil {
+			return resultado, err
+		}
+	}
+	return resultado, nil
+}
+
+// diasLetivos devolve os dias (meia-noite local) do bimestre que caem no dia da semana informado,
+// exceto os dias não letivos do calendário escolar. As datas do bimestre são inclusivas.
+func diasLetivos(term models.Term, dia time.Weekday, naoLetivos calendario.DiasNaoLetivos) []time.Time {
+	primeiro := time.Date(term.StartDate.Year(), term.StartDate.Month(), term.StartDate.Day(), 0, 0, 0, 0, time.Local)
+	ultimo := time.Date(term.EndDate.Year(), term.EndDate.Month(), term.EndDate.Day(), 0, 0, 0, 0, time.Local)
+	var dias []time.Time
+	for d := primeiro.AddDate(0, 0, (int(dia)-int(primeiro.Weekday())+7)%7); !d.After(ultimo); d = d.AddDate(0, 0, 7) {
+		if !naoLetivos.EhDiaLetivo(d) {
+			continue
+		}
+		dias = append(dias, d)
+	}
+	return dias
+}
+
+// sincronizarHorario faz as ocorrências de um horário coincidirem com a grade: cria as que faltam em qualquer
+// dia letivo do bimestre e atualiza ou remove as futuras. Ocorrências passadas já existentes não são tocadas.
+func sincronizarHorario(grade store.TimetableStore, aulas store.AulaStore, term models.Term, naoLetivos calendario.DiasNaoLetivos, slot models.TimetableSlot, agora time.Time, resultado *ResultadoGeracao) error {
+	inicioSlot, _ := parseHora(slot.StartTime, "início")
+	fimSlot, _ := parseHora(slot.EndTime, "término")
+
+	existentes, err := grade.ListOccurrences(slot.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao listar as aulas geradas: %w", err)
+	}
+	porData := make(map[string]models.TimetableOccurrence, len(existentes))
+	for _, o := range existentes {
+		porData[o.Date] = o
+	}
+
+	for _, dia := range diasLetivos(term, slot.Weekday, naoLetivos) {
+		data := dia.Format(dataLayout)
+		inicio, fim := horarioNoDia(dia, inicioSlot), horarioNoDia(dia, fimSlot)
+		existente, ok := porData[data]
+		delete(porData, data)
+
+		switch {
+		case ok && existente.StartTime.Before(agora):
+			resultado.Passadas++
+		case ok:
+			alterada, err := atualizarOcorrencia(grade, aulas, term, slot, existente, inicio, fim)
+			if err != nil {
+				return err
+			}
+			if alterada {
+				resultado.Atualizadas++
+			} else {
+				resultado.Mantidas++
+			}
+		default:
+			// Dias sem aula gerada recebem a ocorrência mesmo que já tenham passado (ex: grade cadastrada no meio do bimestre).
+			if err := criarOcorrencia(grade, aulas, term, slot, data, inicio, fim); err != nil {
+				return err
+			}
+			resultado.Criadas++
+		}
+	}
+
+	// O que sobrou não corresponde mais à grade (dia da semana, datas do bimestre ou calendário escolar alterados).
+	for _, o := range porData {
+		if o.StartTime.Before(agora) {
+			resultado.Passadas++
+			continue
+		}
+		if err := removerOcorrencia(grade, aulas, o); err != nil {
+			return err
+		}
+		resultado.Removidas++
+	}
+	return nil
+}
+
+// eventoDaAula monta o evento de agenda de uma ocorrência da grade.
+func eventoDaAula(term models.Term, slot models.TimetableSlot, inicio, fim time.Time) models.Event {
+	return models.Event{
+		Title:       fmt.Sprintf("Aula de %s (%s)", slot.Subject, slot.ClassID),
+		Description: fmt.Sprintf("Gerado pela grade horária do bimestre '%s'.", term.Name),
+		StartTime:   inicio,
+		EndTime:     fim,
+		Location:    slot.Location,
+	}
+}
+
+func criarOcorrencia(grade store.TimetableStore, aulas store.AulaStore, term models.Term, slot models.TimetableSlot, data string, inicio, fim time.Time) error {
+	aula, err := aulas.SaveLesson(models.Lesson{Subject: slot.Subject, ClassID: slot.ClassID, Date: inicio})
+	if err != nil {
+		return fmt.Errorf("erro ao criar a aula de %s: %w", data, err)
+	}
+	eventoID, err := db.CreateEvent(eventoDaAula(term, slot, inicio, fim))
+	if err != nil {
+		return fmt.Errorf("erro ao criar o evento da aula de %s: %w", data, err)
+	}
+	return grade.SaveOccurrence(models.TimetableOccurrence{SlotID: slot.ID, Date: data, StartTime: inicio, LessonID: aula.ID, EventID: eventoID})
+}
+
+// atualizarOcorrencia ajusta a aula e o evento de uma ocorrência futura, recriando-os se tiverem sido
+// removidos manualmente. Informa se algo mudou.
+func atualizarOcorrencia(grade store.TimetableStore, aulas store.AulaStore, term models.Term, slot models.TimetableSlot, o models.TimetableOccurrence, inicio, fim time.Time) (bool, error) {
+	alterada := !o.StartTime.Equal(inicio)
+
+	aula, err := aulas.GetLessonByID(o.LessonID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		aula = models.Lesson{ID: o.LessonID}
+		alterada = true
+	case err != nil:
+		return false, fmt.Errorf("erro ao buscar a aula de %s: %w", o.Date, err)
+	}
+	if aula.Subject != slot.Subject || aula.ClassID != slot.ClassID || !aula.Date.Equal(inicio) {
+		aula.Subject, aula.ClassID, aula.Date = slot.Subject, slot.ClassID, inicio
+		if _, err := aulas.SaveLesson(aula); err != nil {
+			return false, fmt.Errorf("erro ao atualizar a aula de %s: %w", o.Date, err)
+		}
+		alterada = true
+	}
+
+	esperado := eventoDaAula(term, slot, inicio, fim)
+	evento, err := db.GetEvent(o.EventID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		esperado.ID = o.EventID
+		if _, err := db.CreateEvent(esperado); err != nil {
+			return false, fmt.Errorf("erro ao recriar o evento da aula de %s: %w", o.Date, err)
+		}
+		alterada = true
+	case err != nil:
+		return false, fmt.Errorf("erro ao buscar o evento da aula de %s: %w", o.Date, err)
+	case evento.Title != esperado.Title || evento.Location != esperado.Location ||
+		!evento.StartTime.Equal(inicio) || !evento.EndTime.Equal(fim):
+		evento.Title, evento.Location, evento.StartTime, evento.EndTime = esperado.Title, esperado.Location, inicio, fim
+		evento.UpdatedAt = time.Now()
+		if err := db.UpdateEvent(evento); err != nil {
+			return false, fmt.Errorf("erro ao atualizar o evento da aula de %s: %w", o.Date, err)
+		}
+		alterada = true
+	}
+
+	if alterada {
+		o.StartTime = inicio
+		if err := grade.SaveOccurrence(o); err != nil {
+			return false, err
+		}
+	}
+	return alterada, nil
+}
+
+// removerOcorrencia exclui a aula e o evento de uma ocorrência e o registro que os liga à grade.
+func removerOcorrencia(grade store.TimetableStore, aulas store.AulaStore, o models.TimetableOccurrence) error {
+	if _, err := aulas.GetLessonByID(o.LessonID); err == nil {
+		if err := aulas.DeleteLesson(o.LessonID); err != nil {
+			return fmt.Errorf("erro ao remover a aula de %s: %w", o.Date, err)
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao buscar a aula de %s: %w", o.Date, err)
+	}
+	if err := db.DeleteEvent(o.EventID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao remover o evento da aula de %s: %w", o.Date, err)
+	}
+	return grade.DeleteOccurrence(o.SlotID, o.Date)
+}
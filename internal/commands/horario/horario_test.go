@@ -0,0 +1,294 @@
+package horario
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/agenda"
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes da grade horária.
+func TestMain(m *testing.M) {
+	db.InitTestDB("horario")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
+// novoBimestre limpa as tabelas usadas pela grade e cadastra um bimestre de agosto de 2024.
+func novoBimestre(t *testing.T) models.Term {
+	t.Helper()
+	for _, tabela := range []string{"timetable_slots", "timetable_occurrences", "lessons", "events", "terms", "non_school_days"} {
+		if _, err := db.GetDB().Exec("DELETE FROM " + tabela); err != nil {
+			t.Fatalf("Falha ao limpar %s: %v", tabela, err)
+		}
+	}
+	term, err := store.NewSQLiteTermStore(db.GetDB()).SaveTerm(models.Term{
+		Name:      "3º Bimestre",
+		StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("SaveTerm falhou: %v", err)
+	}
+	return term
+}
+
+// aulasGeradas descreve as aulas do banco em ordem de data, como "2024-08-05 08:00 7B Matemática [tópico]".
+func aulasGeradas(t *testing.T) []string {
+	t.Helper()
+	aulas, err := store.NewSQLiteAulaStore(db.GetDB()).ListLessons("", "", "", "", "")
+	if err != nil {
+		t.Fatalf("ListLessons falhou: %v", err)
+	}
+	var descricoes []string
+	for _, a := range aulas {
+		d := a.Date.In(time.Local).Format("2006-01-02 15:04") + " " + a.ClassID + " " + a.Subject
+		if a.Topic != "" {
+			d += " [" + a.Topic + "]"
+		}
+		descricoes = append(descricoes, d)
+	}
+	return descricoes
+}
+
+func TestAdicionarHorario(t *testing.T) {
+	term := novoBimestre(t)
+
+	if _, err := AdicionarHorario(term.ID, "7B", "Matemática", "segunda-feira", "08:00", "08:50", "Sala 3"); err != nil {
+		t.Fatalf("AdicionarHorario falhou: %v", err)
+	}
+
+	invalidos := []struct {
+		nome, bimestre, turma, dia, inicio, fim, trecho string
+	}{
+		{"bimestre inexistente", "nao-existe", "7B", "seg", "09:00", "09:50", "não encontrado"},
+		{"dia inválido", term.ID, "7B", "segundona", "09:00", "09:50", "dia da semana"},
+		{"horário inválido", term.ID, "7B", "seg", "9h", "09:50", "HH:MM"},
+		{"fim antes do início", term.ID, "7B", "seg", "09:50", "09:00", "posterior"},
+		{"sem turma", term.ID, "", "seg", "09:00", "09:50", "obrigatórias"},
+		{"sobreposição com outra turma", term.ID, "8A", "seg", "08:30", "09:20", "sobrepõe a seg 08:00-08:50 (7B, Matemática)"},
+	}
+	for _, c := range invalidos {
+		t.Run(c.nome, func(t *testing.T) {
+			_, err := AdicionarHorario(c.bimestre, c.turma, "Matemática", c.dia, c.inicio, c.fim, "")
+			if err == nil || !strings.Contains(err.Error(), c.trecho) {
+				t.Errorf("Esperado erro contendo %q, obtido %v", c.trecho, err)
+			}
+		})
+	}
+
+	// Horários encostados não se sobrepõem.
+	if _, err := AdicionarHorario(term.ID, "8A", "História", "seg", "08:50", "09:40", ""); err != nil {
+		t.Errorf("AdicionarHorario de horário encostado falhou: %v", err)
+	}
+	slots, err := ListarHorarios(term.ID, "")
+	if err != nil || len(slots) != 2 {
+		t.Errorf("Esperados 2 horários, obtido %d (%v)", len(slots), err)
+	}
+}
+
+func TestGerarAulas(t *testing.T) {
+	term := novoBimestre(t)
+	matematica, err := AdicionarHorario(term.ID, "7B", "Matemática", "seg", "08:00", "08:50", "Sala 3")
+	if err != nil {
+		t.Fatalf("AdicionarHorario falhou: %v", err)
+	}
+	historia, err := AdicionarHorario(term.ID, "8A", "História", "qua", "10:00", "10:50", "")
+	if err != nil {
+		t.Fatalf("AdicionarHorario falhou: %v", err)
+	}
+
+	antesDoBimestre := time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local)
+	resultado, err := gerarAulas(term.ID, antesDoBimestre)
+	if err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	if resultado != (ResultadoGeracao{Criadas: 8}) {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+	_, totalEventos, _ := db.ListEvents(nil, "", "", 0, 1)
+	if aulas := aulasGeradas(t); len(aulas) != 8 || totalEventos != 8 {
+		t.Fatalf("Esperadas 8 aulas e 8 eventos, obtido %d aulas e %d eventos: %v", len(aulas), totalEventos, aulas)
+	}
+
+	t.Run("Gerar novamente não duplica", func(t *testing.T) {
+		resultado, err := gerarAulas(term.ID, antesDoBimestre)
+		if err != nil {
+			t.Fatalf("gerarAulas falhou: %v", err)
+		}
+		if resultado != (ResultadoGeracao{Mantidas: 8}) {
+			t.Errorf("Resultado inesperado: %+v", resultado)
+		}
+	})
+
+	t.Run("Aulas aparecem uma única vez na visão da agenda", func(t *testing.T) {
+		inicio := time.Date(2024, 8, 5, 0, 0, 0, 0, time.Local)
+		itens, err := agenda.ItensDoPeriodo(inicio, inicio.AddDate(0, 0, 7), 50*time.Minute)
+		if err != nil {
+			t.Fatalf("ItensDoPeriodo falhou: %v", err)
+		}
+		if len(itens) != 2 || itens[0].Tipo != agenda.TipoAula || itens[1].Tipo != agenda.TipoAula {
+			t.Errorf("Esperadas 2 aulas na semana, obtido %+v", itens)
+		}
+	})
+
+	// Em 15/08, com as aulas de 19/08 e 21/08 já planejadas, Matemática passa para terça e História muda de horário.
+	meioDoBimestre := time.Date(2024, 8, 15, 12, 0, 0, 0, time.Local)
+	aulaStore := store.NewSQLiteAulaStore(db.GetDB())
+	ocorrencias, _ := store.NewSQLiteTimetableStore(db.GetDB()).ListOccurrencesBetween(time.Date(2024, 8, 19, 0, 0, 0, 0, time.Local), time.Date(2024, 8, 22, 0, 0, 0, 0, time.Local))
+	for _, o := range ocorrencias {
+		aula, err := aulaStore.GetLessonByID(o.LessonID)
+		if err != nil {
+			t.Fatalf("GetLessonByID falhou: %v", err)
+		}
+		aula.Topic = "planejada"
+		if _, err := aulaStore.SaveLesson(aula); err != nil {
+			t.Fatalf("SaveLesson falhou: %v", err)
+		}
+	}
+	if _, err := EditarHorario(matematica.ID, "", "", "ter", "09:00", "09:50", ""); err != nil {
+		t.Fatalf("EditarHorario falhou: %v", err)
+	}
+	if _, err := EditarHorario(historia.ID, "", "", "", "10:10", "11:00", ""); err != nil {
+		t.Fatalf("EditarHorario falhou: %v", err)
+	}
+
+	resultado, err = gerarAulas(term.ID, meioDoBimestre)
+	if err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	// As terças que já passaram (06/08 e 13/08) também recebem a aula de Matemática.
+	esperado := ResultadoGeracao{Criadas: 4, Atualizadas: 2, Removidas: 2, Passadas: 4}
+	if resultado != esperado {
+		t.Errorf("Resultado inesperado: %+v, esperado %+v", resultado, esperado)
+	}
+	esperadas := []string{
+		"2024-08-05 08:00 7B Matemática",
+		"2024-08-06 09:00 7B Matemática",
+		"2024-08-07 10:00 8A História",
+		"2024-08-12 08:00 7B Matemática",
+		"2024-08-13 09:00 7B Matemática",
+		"2024-08-14 10:00 8A História",
+		"2024-08-20 09:00 7B Matemática",
+		"2024-08-21 10:10 8A História [planejada]",
+		"2024-08-27 09:00 7B Matemática",
+		"2024-08-28 10:10 8A História",
+	}
+	if obtidas := aulasGeradas(t); strings.Join(obtidas, "|") != strings.Join(esperadas, "|") {
+		t.Errorf("Aulas após regenerar:\nesperado %v\nobtido   %v", esperadas, obtidas)
+	}
+	eventos, err := agenda.ListarEventos("custom", "2024-08-21", "2024-08-21", "inicio", "asc")
+	if err != nil || len(eventos) != 1 || eventos[0].StartTime.Format("15:04") != "10:10" || eventos[0].EndTime.Format("15:04") != "11:00" {
+		t.Errorf("Evento de 21/08 não acompanhou a grade: %+v (%v)", eventos, err)
+	}
+
+	t.Run("Remover horário mantém as aulas passadas", func(t *testing.T) {
+		removidas, err := removerHorario(matematica.ID, meioDoBimestre)
+		if err != nil {
+			t.Fatalf("removerHorario falhou: %v", err)
+		}
+		if removidas != 2 {
+			t.Errorf("Esperadas 2 aulas futuras removidas, obtido %d", removidas)
+		}
+		obtidas := strings.Join(aulasGeradas(t), "|")
+		if !strings.Contains(obtidas, "2024-08-12 08:00 7B Matemática") || strings.Contains(obtidas, "2024-08-20") {
+			t.Errorf("Aulas inesperadas após remover o horário: %s", obtidas)
+		}
+		if _, err := removerHorario(matematica.ID, meioDoBimestre); err == nil {
+			t.Error("Esperado erro ao remover horário inexistente")
+		}
+	})
+}
+
+func TestGerarAulasNoMeioDoBimestre(t *testing.T) {
+	term := novoBimestre(t)
+	if _, err := AdicionarHorario(term.ID, "7B", "Matemática", "seg", "08:00", "08:50", ""); err != nil {
+		t.Fatalf("AdicionarHorario falhou: %v", err)
+	}
+
+	// Grade cadastrada em 15/08: as segundas que já passaram também recebem aula e evento.
+	meioDoBimestre := time.Date(2024, 8, 15, 12, 0, 0, 0, time.Local)
+	resultado, err := gerarAulas(term.ID, meioDoBimestre)
+	if err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	if resultado != (ResultadoGeracao{Criadas: 4}) {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+	esperadas := "2024-08-05 08:00 7B Matemática|2024-08-12 08:00 7B Matemática|2024-08-19 08:00 7B Matemática|2024-08-26 08:00 7B Matemática"
+	if obtidas := strings.Join(aulasGeradas(t), "|"); obtidas != esperadas {
+		t.Errorf("Aulas inesperadas:\nesperado %s\nobtido   %s", esperadas, obtidas)
+	}
+	if _, totalEventos, _ := db.ListEvents(nil, "", "", 0, 1); totalEventos != 4 {
+		t.Errorf("Esperados 4 eventos, obtido %d", totalEventos)
+	}
+
+	// Gerar de novo não mexe nas aulas que já aconteceram.
+	resultado, err = gerarAulas(term.ID, meioDoBimestre)
+	if err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	if resultado != (ResultadoGeracao{Passadas: 2, Mantidas: 2}) {
+		t.Errorf("Resultado inesperado ao gerar novamente: %+v", resultado)
+	}
+}
+
+func TestDiasLetivos(t *testing.T) {
+	term := models.Term{StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 8, 29, 0, 0, 0, 0, time.UTC)}
+	quintas := func(naoLetivos calendario.DiasNaoLetivos) string {
+		var dias []string
+		for _, d := range diasLetivos(term, time.Thursday, naoLetivos) {
+			dias = append(dias, d.Format(dataLayout))
+		}
+		return strings.Join(dias, ",")
+	}
+	// As datas do bimestre são inclusivas: 01/08 e 29/08 são quintas-feiras.
+	if got := quintas(nil); got != "2024-08-01,2024-08-08,2024-08-15,2024-08-22,2024-08-29" {
+		t.Errorf("diasLetivos inesperados: %s", got)
+	}
+	naoLetivos := calendario.DiasNaoLetivos{
+		{Name: "Feriado municipal", StartDate: time.Date(2024, 8, 15, 0, 0, 0, 0, time.Local), EndDate: time.Date(2024, 8, 15, 0, 0, 0, 0, time.Local)},
+		{Name: "Recesso", StartDate: time.Date(2024, 8, 26, 0, 0, 0, 0, time.Local), EndDate: time.Date(2024, 8, 30, 0, 0, 0, 0, time.Local)},
+	}
+	if got := quintas(naoLetivos); got != "2024-08-01,2024-08-08,2024-08-22" {
+		t.Errorf("diasLetivos com dias não letivos inesperados: %s", got)
+	}
+}
+
+func TestGerarAulasPulaDiasNaoLetivos(t *testing.T) {
+	term := novoBimestre(t)
+	if _, err := AdicionarHorario(term.ID, "7B", "Matemática", "seg", "08:00", "08:50", ""); err != nil {
+		t.Fatalf("AdicionarHorario falhou: %v", err)
+	}
+	if _, err := calendario.AdicionarDiaNaoLetivo("Recesso", "2024-08-12", "2024-08-16", "recesso"); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+
+	antesDoBimestre := time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local)
+	if _, err := gerarAulas(term.ID, antesDoBimestre); err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	esperadas := "2024-08-05 08:00 7B Matemática|2024-08-19 08:00 7B Matemática|2024-08-26 08:00 7B Matemática"
+	if obtidas := strings.Join(aulasGeradas(t), "|"); obtidas != esperadas {
+		t.Errorf("Aulas inesperadas:\nesperado %s\nobtido   %s", esperadas, obtidas)
+	}
+
+	// Um feriado cadastrado depois da geração remove a aula futura do dia.
+	if _, err := calendario.AdicionarDiaNaoLetivo("Feriado municipal", "26/08/2024", "", ""); err != nil {
+		t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+	}
+	resultado, err := gerarAulas(term.ID, antesDoBimestre)
+	if err != nil {
+		t.Fatalf("gerarAulas falhou: %v", err)
+	}
+	if resultado != (ResultadoGeracao{Removidas: 1, Mantidas: 2}) {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+}
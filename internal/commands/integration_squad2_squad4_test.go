@@ -2,19 +2,27 @@ package commands_test // Using _test package to avoid import cycles if any, and
 
 import (
 	"fmt"
-	"strings"
+	"os"
 	"testing"
 	"time"
 
 	"vickgenda-cli/internal/commands/agenda"
 	"vickgenda-cli/internal/commands/rotina"
 	"vickgenda-cli/internal/commands/tarefa"
-	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/db"
 )
 
 const testLayoutDate = "2006-01-02"
 const testLayoutDateTime = "2006-01-02 15:04"
 
+// TestMain inicializa um banco SQLite em memória compartilhado pelos módulos do Squad 2.
+func TestMain(m *testing.M) {
+	db.InitTestDB("integration_squad2")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
 // Helper function to clear all stores for Squad 2 modules
 func cleanupSquad2Stores() {
 	tarefa.LimparTarefasStore()
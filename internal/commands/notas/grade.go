@@ -7,6 +7,7 @@ import (
 	"strings" // Added missing import
 	"time"
 
+	"vickgenda-cli/internal/datas"
 	"vickgenda-cli/internal/models"
 	"vickgenda-cli/internal/store"
 )
@@ -52,11 +53,11 @@ func LancarNota(alunoID, bimestreID, disciplina, avaliacaoDesc string, valorNota
 
 	var dataAvaliacao time.Time
 	if dataStr != "" {
-		layout := "02-01-2006"
-		dataAvaliacao, err = time.Parse(layout, dataStr)
+		dataAvaliacao, err = datas.Data(dataStr)
 		if err != nil {
-			return models.Grade{}, fmt.Errorf("formato de data inválido: %s. Use dd-mm-aaaa", dataStr)
+			return models.Grade{}, fmt.Errorf("formato de data inválido: %w", err)
 		}
+		dataAvaliacao = datas.DataUTC(dataAvaliacao)
 	} else {
 		dataAvaliacao = time.Now()
 	}
@@ -233,11 +234,11 @@ func EditarNota(idNota string, novoValor *float64, novoPeso *float64, novaDesc *
 		}
 	}
 	if novaDataStr != nil {
-		layout := "02-01-2006"
-		dataAvaliacao, errDate := time.Parse(layout, *novaDataStr)
+		dataAvaliacao, errDate := datas.Data(*novaDataStr)
 		if errDate != nil {
-			return models.Grade{}, fmt.Errorf("formato de nova data inválido: %s. Use dd-mm-aaaa: %w", *novaDataStr, errDate)
+			return models.Grade{}, fmt.Errorf("formato de nova data inválido: %w", errDate)
 		}
+		dataAvaliacao = datas.DataUTC(dataAvaliacao)
 		if !grade.Date.Equal(dataAvaliacao) {
 			grade.Date = dataAvaliacao
 			algoAlterado = true
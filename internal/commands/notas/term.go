@@ -4,8 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"time"
 
+	"vickgenda-cli/internal/datas"
 	"vickgenda-cli/internal/models"
 	"vickgenda-cli/internal/store"
 )
@@ -23,15 +23,15 @@ func ConfigurarBimestreAdicionar(anoLetivo int, nome, inicioStr, fimStr string)
 		return models.Term{}, errors.New("nome, data de início, data de fim e ano letivo são obrigatórios")
 	}
 
-	layout := "02-01-2006"
-	dataInicio, err := time.Parse(layout, inicioStr)
+	dataInicio, err := datas.Data(inicioStr)
 	if err != nil {
-		return models.Term{}, fmt.Errorf("formato de data de início inválido: %s. Use dd-mm-aaaa", inicioStr)
+		return models.Term{}, fmt.Errorf("formato de data de início inválido: %w", err)
 	}
-	dataFim, err := time.Parse(layout, fimStr)
+	dataFim, err := datas.Data(fimStr)
 	if err != nil {
-		return models.Term{}, fmt.Errorf("formato de data de fim inválido: %s. Use dd-mm-aaaa", fimStr)
+		return models.Term{}, fmt.Errorf("formato de data de fim inválido: %w", err)
 	}
+	dataInicio, dataFim = datas.DataUTC(dataInicio), datas.DataUTC(dataFim)
 
 	if dataFim.Before(dataInicio) {
 		return models.Term{}, errors.New("data de fim não pode ser anterior à data de início")
@@ -0,0 +1,119 @@
+package projeto
+
+import (
+	"fmt"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// O progresso de um projeto é derivado das suas tarefas, incluindo as subtarefas: quantas estão concluídas do total,
+// quantas estão atrasadas e quanto tempo foi registrado nelas (cronômetro e registros manuais).
+
+// Progresso resume a situação das tarefas de um projeto.
+type Progresso struct {
+	Projeto     models.Project
+	Tarefas     int           // Total de tarefas do projeto.
+	Concluidas  int           // Tarefas concluídas.
+	EmAndamento int           // Tarefas com status "Em Andamento".
+	Atrasadas   int           // Tarefas não concluídas com prazo anterior a hoje.
+	Tempo       time.Duration // Tempo registrado nas tarefas do projeto; um cronômetro rodando conta até agora.
+}
+
+// Percentual devolve a porcentagem de tarefas concluídas, arredondada para baixo; 0 se o projeto não tiver tarefas.
+func (p Progresso) Percentual() int {
+	if p.Tarefas == 0 {
+		return 0
+	}
+	return p.Concluidas * 100 / p.Tarefas
+}
+
+// String formata o progresso como "concluídas/total (percentual)", por exemplo "3/10 (30%)".
+func (p Progresso) String() string {
+	return fmt.Sprintf("%d/%d (%d%%)", p.Concluidas, p.Tarefas, p.Percentual())
+}
+
+// PrazoVencido informa se o projeto está ativo e o seu prazo é anterior ao dia de agora.
+func (p Progresso) PrazoVencido(agora time.Time) bool {
+	return p.Projeto.Status == models.ProjectStatusActive && antesDoDia(p.Projeto.DueDate, agora)
+}
+
+// antesDoDia informa se a data prazo, quando definida, é de um dia anterior ao de agora. Compara os dias civis,
+// pois as tarefas geradas por rotinas gravam o prazo à meia-noite UTC e as demais, à meia-noite local.
+func antesDoDia(prazo, agora time.Time) bool {
+	return !prazo.IsZero() && datas.DataUTC(prazo).Before(datas.DataUTC(agora))
+}
+
+// CalcularProgresso calcula o progresso de projeto a partir das tarefas e registros de tempo fornecidos.
+// Tarefas de outros projetos, e registros de tempo dessas tarefas, são ignorados, então é possível passar todas.
+func CalcularProgresso(projeto models.Project, tarefas []models.Task, registros []models.TaskTimeEntry, agora time.Time) Progresso {
+	progresso := Progresso{Projeto: projeto}
+	doProjeto := make(map[string]bool)
+	for _, t := range tarefas {
+		if t.ProjectID != projeto.ID {
+			continue
+		}
+		doProjeto[t.ID] = true
+		progresso.Tarefas++
+		if t.Status == models.TaskStatusCompleted {
+			progresso.Concluidas++
+			continue
+		}
+		if t.Status == models.TaskStatusInProgress {
+			progresso.EmAndamento++
+		}
+		if antesDoDia(t.DueDate, agora) {
+			progresso.Atrasadas++
+		}
+	}
+	for _, r := range registros {
+		if doProjeto[r.TaskID] {
+			progresso.Tempo += r.Duration(agora)
+		}
+	}
+	return progresso
+}
+
+// CarregarProgresso calcula o progresso do projeto ref (nome ou ID).
+func CarregarProgresso(ref string) (Progresso, error) {
+	projeto, err := BuscarProjeto(ref)
+	if err != nil {
+		return Progresso{}, err
+	}
+	tarefas, _, err := db.ListTasks(map[string]interface{}{"project_id": projeto.ID}, "", "", 0, 0)
+	if err != nil {
+		return Progresso{}, fmt.Errorf("erro ao listar as tarefas do projeto: %w", err)
+	}
+	registros, err := db.ListTimeEntries("")
+	if err != nil {
+		return Progresso{}, fmt.Errorf("erro ao listar o tempo registrado: %w", err)
+	}
+	return CalcularProgresso(projeto, tarefas, registros, datas.Agora()), nil
+}
+
+// CarregarProgressos calcula o progresso de cada projeto listado por ListarProjetos(statusFilter).
+func CarregarProgressos(statusFilter string) ([]Progresso, error) {
+	projetos, err := ListarProjetos(statusFilter)
+	if err != nil {
+		return nil, err
+	}
+	if len(projetos) == 0 {
+		return nil, nil
+	}
+	tarefas, _, err := db.ListTasks(nil, "", "", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tarefas: %w", err)
+	}
+	registros, err := db.ListTimeEntries("")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar o tempo registrado: %w", err)
+	}
+	agora := datas.Agora()
+	progressos := make([]Progresso, 0, len(projetos))
+	for _, p := range projetos {
+		progressos = append(progressos, CalcularProgresso(p, tarefas, registros, agora))
+	}
+	return progressos, nil
+}
@@ -0,0 +1,306 @@
+package projeto
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Os projetos são persistidos na tabela "projects" do banco SQLite através das funções
+// db.CreateProject, db.GetProject, db.ListProjects, db.UpdateProject e db.DeleteProject.
+// Tarefas, eventos e rotinas pertencem a no máximo um projeto (campo ProjectID); o progresso
+// de um projeto é derivado das suas tarefas.
+// O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
+
+// erroProjetoNaoEncontrado padroniza a mensagem de erro para projetos inexistentes.
+func erroProjetoNaoEncontrado(ref string) error {
+	return fmt.Errorf("projeto '%s' não encontrado", ref)
+}
+
+// statusProjeto mapeia os status aceitos na CLI, em minúsculas e com ou sem acento, para os status gravados.
+var statusProjeto = map[string]string{
+	"ativo":     models.ProjectStatusActive,
+	"concluido": models.ProjectStatusCompleted,
+	"concluído": models.ProjectStatusCompleted,
+	"arquivado": models.ProjectStatusArchived,
+}
+
+// normalizarStatus valida um status de projeto, sem diferenciar maiúsculas nem acentos.
+func normalizarStatus(status string) (string, error) {
+	if s, ok := statusProjeto[strings.ToLower(strings.TrimSpace(status))]; ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("status '%s' inválido. Use Ativo, Concluído ou Arquivado", status)
+}
+
+// validarNomeUnico falha se outro projeto, diferente de exceto, já usar o nome (sem diferenciar maiúsculas).
+func validarNomeUnico(nome, exceto string) error {
+	projetos, _, err := db.ListProjects(nil, "", "", 0, 0)
+	if err != nil {
+		return fmt.Errorf("erro ao listar projetos: %w", err)
+	}
+	for _, p := range projetos {
+		if p.ID != exceto && strings.EqualFold(p.Name, nome) {
+			return fmt.Errorf("já existe um projeto chamado '%s'", p.Name)
+		}
+	}
+	return nil
+}
+
+// CriarProjeto cria um projeto ativo. O nome é obrigatório e único (sem diferenciar maiúsculas).
+// prazoStr é uma expressão de data do pacote datas (ex: "fim do mês", "20/10"); se vazia, o projeto não tem prazo.
+func CriarProjeto(nome, prazoStr, descricao string) (models.Project, error) {
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return models.Project{}, errors.New("o nome do projeto é obrigatório")
+	}
+	if err := validarNomeUnico(nome, ""); err != nil {
+		return models.Project{}, err
+	}
+	var prazo time.Time
+	if prazoStr != "" {
+		var err error
+		if prazo, err = datas.Data(prazoStr); err != nil {
+			return models.Project{}, fmt.Errorf("formato de data inválido para --prazo: %w", err)
+		}
+	}
+
+	now := time.Now()
+	projeto := models.Project{
+		Name:        nome,
+		Description: strings.TrimSpace(descricao),
+		DueDate:     prazo,
+		Status:      models.ProjectStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	id, err := db.CreateProject(projeto)
+	if err != nil {
+		return models.Project{}, fmt.Errorf("erro ao salvar o projeto: %w", err)
+	}
+	projeto.ID = id
+	return projeto, nil
+}
+
+// ListarProjetos lista os projetos em ordem alfabética. statusFilter (opcional) aceita Ativo, Concluído ou Arquivado.
+func ListarProjetos(statusFilter string) ([]models.Project, error) {
+	filters := map[string]interface{}{}
+	if statusFilter != "" {
+		status, err := normalizarStatus(statusFilter)
+		if err != nil {
+			return nil, err
+		}
+		filters["status"] = status
+	}
+	projetos, _, err := db.ListProjects(filters, "", "", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar projetos: %w", err)
+	}
+	return projetos, nil
+}
+
+// BuscarProjeto encontra um projeto pelo ID ou pelo nome (sem diferenciar maiúsculas).
+func BuscarProjeto(ref string) (models.Project, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return models.Project{}, errors.New("informe o nome ou o ID do projeto")
+	}
+	projeto, err := db.GetProject(ref)
+	if err == nil {
+		return projeto, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.Project{}, fmt.Errorf("erro ao buscar o projeto: %w", err)
+	}
+	projetos, _, err := db.ListProjects(nil, "", "", 0, 0)
+	if err != nil {
+		return models.Project{}, fmt.Errorf("erro ao buscar o projeto: %w", err)
+	}
+	for _, p := range projetos {
+		if strings.EqualFold(p.Name, ref) {
+			return p, nil
+		}
+	}
+	return models.Project{}, erroProjetoNaoEncontrado(ref)
+}
+
+// EditarProjeto altera os campos informados de um projeto, identificado pelo nome ou ID.
+// Campos vazios não são alterados; pelo menos um deve ser informado.
+// novoStatus aceita Ativo, Concluído ou Arquivado.
+func EditarProjeto(ref, novoNome, novoPrazoStr, novaDesc, novoStatus string) (models.Project, error) {
+	projeto, err := BuscarProjeto(ref)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	updated := false
+	if nome := strings.TrimSpace(novoNome); nome != "" {
+		if err := validarNomeUnico(nome, projeto.ID); err != nil {
+			return models.Project{}, err
+		}
+		projeto.Name = nome
+		updated = true
+	}
+	if novoPrazoStr != "" {
+		prazo, err := datas.Data(novoPrazoStr)
+		if err != nil {
+			return models.Project{}, fmt.Errorf("formato de data inválido para novo prazo: %w", err)
+		}
+		projeto.DueDate = prazo
+		updated = true
+	}
+	if novaDesc != "" {
+		projeto.Description = strings.TrimSpace(novaDesc)
+		updated = true
+	}
+	if novoStatus != "" {
+		status, err := normalizarStatus(novoStatus)
+		if err != nil {
+			return models.Project{}, err
+		}
+		projeto.Status = status
+		updated = true
+	}
+	if !updated {
+		return models.Project{}, errors.New("nenhuma alteração especificada")
+	}
+
+	projeto.UpdatedAt = time.Now()
+	if err := db.UpdateProject(projeto); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Project{}, erroProjetoNaoEncontrado(ref)
+		}
+		return models.Project{}, fmt.Errorf("erro ao salvar o projeto: %w", err)
+	}
+	return projeto, nil
+}
+
+// RemoverProjeto remove um projeto, identificado pelo nome ou ID. As tarefas, eventos e rotinas do projeto
+// são mantidos, sem projeto.
+func RemoverProjeto(ref string) error {
+	projeto, err := BuscarProjeto(ref)
+	if err != nil {
+		return err
+	}
+	if err := db.DeleteProject(projeto.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroProjetoNaoEncontrado(ref)
+		}
+		return fmt.Errorf("erro ao remover o projeto: %w", err)
+	}
+	return nil
+}
+
+// resolverProjetoID devolve o ID do projeto ref; ref vazio devolve "" (nenhum projeto).
+func resolverProjetoID(ref string) (string, error) {
+	if strings.TrimSpace(ref) == "" {
+		return "", nil
+	}
+	projeto, err := BuscarProjeto(ref)
+	if err != nil {
+		return "", err
+	}
+	return projeto.ID, nil
+}
+
+// AtribuirTarefa coloca a tarefa tarefaID no projeto ref (nome ou ID). Com ref vazio, a tarefa deixa de ter projeto.
+func AtribuirTarefa(tarefaID, ref string) (models.Task, error) {
+	projetoID, err := resolverProjetoID(ref)
+	if err != nil {
+		return models.Task{}, err
+	}
+	tarefa, err := db.GetTask(tarefaID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Task{}, fmt.Errorf("tarefa com ID '%s' não encontrada", tarefaID)
+		}
+		return models.Task{}, fmt.Errorf("erro ao buscar a tarefa: %w", err)
+	}
+	tarefa.ProjectID = projetoID
+	tarefa.UpdatedAt = time.Now()
+	if err := db.UpdateTask(tarefa); err != nil {
+		return models.Task{}, fmt.Errorf("erro ao salvar a tarefa: %w", err)
+	}
+	return tarefa, nil
+}
+
+// AtribuirEvento coloca o evento eventoID no projeto ref (nome ou ID). Com ref vazio, o evento deixa de ter projeto.
+// Em um evento recorrente, a alteração vale para a série inteira.
+func AtribuirEvento(eventoID, ref string) (models.Event, error) {
+	projetoID, err := resolverProjetoID(ref)
+	if err != nil {
+		return models.Event{}, err
+	}
+	evento, err := db.GetEvent(eventoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Event{}, fmt.Errorf("evento com ID '%s' não encontrado", eventoID)
+		}
+		return models.Event{}, fmt.Errorf("erro ao buscar o evento: %w", err)
+	}
+	evento.ProjectID = projetoID
+	evento.UpdatedAt = time.Now()
+	if err := db.UpdateEvent(evento); err != nil {
+		return models.Event{}, fmt.Errorf("erro ao salvar o evento: %w", err)
+	}
+	return evento, nil
+}
+
+// AtribuirRotina coloca o modelo de rotina rotinaID no projeto ref (nome ou ID); as tarefas e eventos gerados
+// a partir de então pertencem ao projeto. Com ref vazio, a rotina deixa de ter projeto.
+func AtribuirRotina(rotinaID, ref string) (models.Routine, error) {
+	projetoID, err := resolverProjetoID(ref)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	rotina, err := db.GetRoutine(rotinaID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Routine{}, fmt.Errorf("modelo de rotina com ID '%s' não encontrado", rotinaID)
+		}
+		return models.Routine{}, fmt.Errorf("erro ao buscar o modelo de rotina: %w", err)
+	}
+	rotina.ProjectID = projetoID
+	rotina.UpdatedAt = time.Now()
+	if err := db.UpdateRoutine(rotina); err != nil {
+		return models.Routine{}, fmt.Errorf("erro ao salvar o modelo de rotina: %w", err)
+	}
+	return rotina, nil
+}
+
+// EventosDoProjeto lista os eventos (e mestres de séries recorrentes) do projeto, em ordem de início.
+func EventosDoProjeto(projetoID string) ([]models.Event, error) {
+	eventos, _, err := db.ListEvents(map[string]interface{}{"project_id": projetoID}, "start_time", "asc", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os eventos do projeto: %w", err)
+	}
+	return eventos, nil
+}
+
+// RotinasDoProjeto lista os modelos de rotina do projeto, em ordem alfabética.
+func RotinasDoProjeto(projetoID string) ([]models.Routine, error) {
+	rotinas, _, err := db.ListRoutines(map[string]interface{}{"project_id": projetoID}, "", "", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar as rotinas do projeto: %w", err)
+	}
+	return rotinas, nil
+}
+
+// LimparProjetosStore remove todos os projetos do banco de dados.
+// Esta função é primariamente destinada a ser usada em testes para garantir um estado limpo.
+func LimparProjetosStore() {
+	conn := db.GetDB()
+	if conn == nil {
+		return
+	}
+	if _, err := conn.Exec("DELETE FROM projects"); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao limpar projetos: %v\n", err)
+	}
+}
@@ -0,0 +1,161 @@
+package projeto
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes de projeto.
+func TestMain(m *testing.M) {
+	db.InitTestDB("projeto")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
+// criarTarefaTeste grava uma tarefa diretamente no banco, já no projeto informado.
+func criarTarefaTeste(t *testing.T, descricao, status, projetoID string, prazo time.Time) models.Task {
+	t.Helper()
+	tarefa := models.Task{Description: descricao, Status: status, Priority: 2, DueDate: prazo, ProjectID: projetoID}
+	id, err := db.CreateTask(tarefa)
+	if err != nil {
+		t.Fatalf("CreateTask falhou: %v", err)
+	}
+	tarefa.ID = id
+	return tarefa
+}
+
+func TestCriarEditarRemoverProjeto(t *testing.T) {
+	LimparProjetosStore()
+	defer datas.UsarRelogio(datas.Fixo(time.Date(2024, 10, 1, 9, 0, 0, 0, time.Local)))()
+
+	feira, err := CriarProjeto("  Feira de Ciências ", "20/10", "Feira anual do 7º ano")
+	if err != nil {
+		t.Fatalf("CriarProjeto falhou: %v", err)
+	}
+	if feira.Name != "Feira de Ciências" || feira.Status != models.ProjectStatusActive || feira.DueDate.Format("2006-01-02") != "2024-10-20" {
+		t.Errorf("Projeto criado inesperado: %+v", feira)
+	}
+	if _, err := CriarProjeto("FEIRA DE CIÊNCIAS", "", ""); err == nil || !strings.Contains(err.Error(), "já existe") {
+		t.Errorf("Esperado erro de nome repetido, obtido %v", err)
+	}
+	if _, err := CriarProjeto(" ", "", ""); err == nil {
+		t.Errorf("CriarProjeto sem nome: esperado erro")
+	}
+	if _, err := CriarProjeto("Escola A", "dia de são nunca", ""); err == nil {
+		t.Errorf("CriarProjeto com prazo inválido: esperado erro")
+	}
+
+	if p, err := BuscarProjeto("feira de ciências"); err != nil || p.ID != feira.ID {
+		t.Errorf("BuscarProjeto pelo nome: obtido %+v (%v)", p, err)
+	}
+	if p, err := BuscarProjeto(feira.ID); err != nil || p.Name != feira.Name {
+		t.Errorf("BuscarProjeto pelo ID: obtido %+v (%v)", p, err)
+	}
+	if _, err := BuscarProjeto("Inexistente"); err == nil || !strings.Contains(err.Error(), "não encontrado") {
+		t.Errorf("Esperado erro de projeto não encontrado, obtido %v", err)
+	}
+
+	escola, err := CriarProjeto("Escola A", "", "")
+	if err != nil {
+		t.Fatalf("CriarProjeto falhou: %v", err)
+	}
+	if _, err := EditarProjeto(escola.ID, "feira de ciências", "", "", ""); err == nil {
+		t.Errorf("EditarProjeto para um nome em uso: esperado erro")
+	}
+	if _, err := EditarProjeto(escola.ID, "", "", "", "pausado"); err == nil {
+		t.Errorf("EditarProjeto com status inválido: esperado erro")
+	}
+	if _, err := EditarProjeto(escola.ID, "", "", "", ""); err == nil {
+		t.Errorf("EditarProjeto sem alterações: esperado erro")
+	}
+	if p, err := EditarProjeto("escola a", "", "", "", "concluido"); err != nil || p.Status != models.ProjectStatusCompleted {
+		t.Errorf("EditarProjeto do status: obtido %+v (%v)", p, err)
+	}
+	if ativos, _ := ListarProjetos("Ativo"); len(ativos) != 1 || ativos[0].ID != feira.ID {
+		t.Errorf("Esperado apenas o projeto ativo, obtido %+v", ativos)
+	}
+	if todos, _ := ListarProjetos(""); len(todos) != 2 || todos[0].Name != "Escola A" {
+		t.Errorf("Esperados os dois projetos em ordem alfabética, obtido %+v", todos)
+	}
+
+	tarefa := criarTarefaTeste(t, "Reservar o pátio", models.TaskStatusPending, "", time.Time{})
+	if tarefa, err = AtribuirTarefa(tarefa.ID, "Feira de Ciências"); err != nil || tarefa.ProjectID != feira.ID {
+		t.Fatalf("AtribuirTarefa falhou: %+v (%v)", tarefa, err)
+	}
+	if err := RemoverProjeto("feira de ciências"); err != nil {
+		t.Fatalf("RemoverProjeto falhou: %v", err)
+	}
+	if salva, err := db.GetTask(tarefa.ID); err != nil || salva.ProjectID != "" {
+		t.Errorf("A tarefa deveria continuar existindo, sem projeto: %+v (%v)", salva, err)
+	}
+	if err := RemoverProjeto(feira.ID); err == nil {
+		t.Errorf("Remover um projeto já removido: esperado erro")
+	}
+}
+
+func TestCarregarProgresso(t *testing.T) {
+	LimparProjetosStore()
+	if _, err := db.GetDB().Exec("DELETE FROM tasks"); err != nil {
+		t.Fatalf("Falha ao limpar tarefas: %v", err)
+	}
+	agora := time.Date(2024, 10, 15, 9, 0, 0, 0, time.Local)
+	defer datas.UsarRelogio(datas.Fixo(agora))()
+
+	feira, err := CriarProjeto("Feira de Ciências", "ontem", "")
+	if err != nil {
+		t.Fatalf("CriarProjeto falhou: %v", err)
+	}
+	ontem := time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC) // Como as tarefas geradas por rotinas gravam o prazo.
+	criarTarefaTeste(t, "Reservar o pátio", models.TaskStatusCompleted, feira.ID, ontem)
+	atrasada := criarTarefaTeste(t, "Comprar cartolinas", models.TaskStatusInProgress, feira.ID, ontem)
+	criarTarefaTeste(t, "Montar as bancadas", models.TaskStatusPending, feira.ID, agora)
+	criarTarefaTeste(t, "Planejar aula", models.TaskStatusPending, "", ontem)
+	if _, err := db.CreateTimeEntry(models.TaskTimeEntry{TaskID: atrasada.ID, StartTime: agora.Add(-90 * time.Minute), EndTime: agora.Add(-time.Hour)}); err != nil {
+		t.Fatalf("CreateTimeEntry falhou: %v", err)
+	}
+
+	progresso, err := CarregarProgresso("feira de ciências")
+	if err != nil {
+		t.Fatalf("CarregarProgresso falhou: %v", err)
+	}
+	if progresso.String() != "1/3 (33%)" || progresso.EmAndamento != 1 || progresso.Atrasadas != 1 || progresso.Tempo != 30*time.Minute {
+		t.Errorf("Progresso inesperado: %s %+v", progresso, progresso)
+	}
+	if !progresso.PrazoVencido(agora) {
+		t.Errorf("O prazo de ontem de um projeto ativo deveria estar vencido")
+	}
+	if progressos, _ := CarregarProgressos(""); len(progressos) != 1 || progressos[0].Tarefas != 3 {
+		t.Errorf("CarregarProgressos inesperado: %+v", progressos)
+	}
+}
+
+func TestCalcularProgresso(t *testing.T) {
+	agora := time.Date(2024, 10, 15, 9, 0, 0, 0, time.UTC)
+	vazio := CalcularProgresso(models.Project{ID: "p"}, nil, nil, agora)
+	if vazio.Percentual() != 0 || vazio.String() != "0/0 (0%)" {
+		t.Errorf("Projeto sem tarefas: obtido %s", vazio)
+	}
+
+	concluido := models.Project{ID: "p", Status: models.ProjectStatusCompleted, DueDate: agora.AddDate(0, 0, -3)}
+	tarefas := []models.Task{
+		{ID: "a", ProjectID: "p", Status: models.TaskStatusCompleted, DueDate: agora.AddDate(0, 0, -5)},
+		{ID: "b", ProjectID: "p", Status: models.TaskStatusCompleted},
+		{ID: "c", ProjectID: "p", Status: models.TaskStatusPending, DueDate: agora},
+		{ID: "d", ProjectID: "outro", Status: models.TaskStatusPending, DueDate: agora.AddDate(0, 0, -1)},
+	}
+	registros := []models.TaskTimeEntry{{TaskID: "d", StartTime: agora.Add(-time.Hour), EndTime: agora}}
+	progresso := CalcularProgresso(concluido, tarefas, registros, agora)
+	if progresso.Percentual() != 66 || progresso.Atrasadas != 0 || progresso.Tempo != 0 {
+		t.Errorf("Progresso inesperado: %s %+v", progresso, progresso)
+	}
+	if progresso.PrazoVencido(agora) {
+		t.Errorf("O prazo de um projeto concluído não deveria ser considerado vencido")
+	}
+}
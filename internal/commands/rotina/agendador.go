@@ -0,0 +1,189 @@
+package rotina
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Execução automática das rotinas: cada modelo com frequência automática tem um NextRunTime;
+// ExecutarPendentes gera as tarefas de todos os modelos cujo NextRunTime já passou e avança o NextRunTime
+// de acordo com a frequência. É o que 'rotina executar-pendentes' (para cron ou systemd) e 'vickgenda daemon' chamam.
+
+const (
+	// lockExecucao é o nome da trava que impede duas execuções simultâneas, mesmo em processos diferentes.
+	lockExecucao = "rotina.executar-pendentes"
+	// validadeLock é o tempo após o qual a trava de uma execução interrompida (ex: processo encerrado) é descartada.
+	validadeLock = 10 * time.Minute
+	// limiteRecuperacao limita quantas execuções perdidas de um mesmo modelo são recuperadas de uma vez;
+	// as restantes ficam para a próxima execução.
+	limiteRecuperacao = 100
+)
+
+// ErrExecucaoEmAndamento indica que outra execução de rotinas detém a trava.
+var ErrExecucaoEmAndamento = errors.New("outra execução de rotinas já está em andamento")
+
+// ProximaExecucao calcula a primeira execução de frequencia estritamente posterior a apos.
+// A gramática das frequências está em frequencia.go. Rotinas "manual" não têm próxima execução.
+func ProximaExecucao(frequencia string, apos time.Time) (time.Time, error) {
+	f, err := ParseFrequencia(frequencia)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("frequência '%s' inválida: %w", frequencia, err)
+	}
+	return f.Proxima(apos)
+}
+
+// ProximasExecucoes prevê as n primeiras execuções de frequencia a partir de inicio (inclusive),
+// sem salvar nada; serve para conferir uma frequência antes de criar o modelo.
+// Com inicio zero, a simulação começa à meia-noite de hoje. Fora do cron, cada execução mantém o horário
+// de inicio, como ExecutarPendentes faz com o horário do NextRunTime do modelo.
+func ProximasExecucoes(frequencia string, inicio time.Time, n int) ([]time.Time, error) {
+	f, err := validarFrequencia(frequencia)
+	if err != nil {
+		return nil, err
+	}
+	if inicio.IsZero() {
+		hoje := time.Now()
+		inicio = time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.Local)
+	}
+	primeira, err := f.Primeira(inicio)
+	if err != nil {
+		return nil, err
+	}
+	return continuarExecucoes(f, primeira, n)
+}
+
+// ProximasExecucoesModelo prevê as n próximas execuções agendadas de um modelo, começando pelo seu NextRunTime.
+func ProximasExecucoesModelo(id string, n int) ([]time.Time, error) {
+	modelo, err := GetModeloRotinaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := validarFrequencia(modelo.Frequency)
+	if err != nil {
+		return nil, err
+	}
+	if f.Manual() || modelo.NextRunTime.IsZero() {
+		return nil, fmt.Errorf("a rotina '%s' é manual e não tem execuções agendadas", modelo.Name)
+	}
+	// O banco devolve os horários em UTC; a frequência é calculada no fuso local.
+	return continuarExecucoes(f, modelo.NextRunTime.In(time.Local), n)
+}
+
+func continuarExecucoes(f Frequencia, primeira time.Time, n int) ([]time.Time, error) {
+	if n <= 0 {
+		return nil, errors.New("a quantidade de execuções deve ser maior que zero")
+	}
+	execucoes := []time.Time{primeira}
+	for len(execucoes) < n {
+		proxima, err := f.Proxima(execucoes[len(execucoes)-1])
+		if err != nil {
+			return nil, err
+		}
+		execucoes = append(execucoes, proxima)
+	}
+	return execucoes, nil
+}
+
+// ExecucaoRotina registra uma execução de um modelo: a ocorrência (o NextRunTime que venceu) e as tarefas geradas.
+type ExecucaoRotina struct {
+	Modelo     models.Routine
+	Ocorrencia time.Time
+	Tarefas    []models.Task
+	Eventos    []models.Event // Eventos do pacote do modelo, criados com as tarefas.
+	Erro       error          // Preenchido quando a execução falhou; o NextRunTime do modelo não é avançado.
+	// JaExecutada indica que as tarefas da ocorrência já tinham sido geradas (ex: com 'rotina gerar-tarefas');
+	// nada foi criado e o NextRunTime avançou normalmente.
+	JaExecutada bool
+}
+
+// ResultadoExecucao resume uma chamada de ExecutarPendentes.
+type ResultadoExecucao struct {
+	Execucoes []ExecucaoRotina
+	// Atrasados lista os modelos que ainda têm execuções vencidas após atingir limiteRecuperacao.
+	Atrasados []models.Routine
+}
+
+// ExecutarPendentes gera as tarefas de todos os modelos de rotina cujo NextRunTime já passou e avança o
+// NextRunTime conforme a frequência. Execuções perdidas (ex: computador desligado) são recuperadas:
+// cada ocorrência vencida gera suas tarefas, com a data da ocorrência em {data}.
+// Uma trava no banco garante que duas chamadas simultâneas nunca gerem as mesmas tarefas;
+// se outra execução estiver em andamento, retorna ErrExecucaoEmAndamento.
+func ExecutarPendentes() (ResultadoExecucao, error) {
+	return executarPendentes(time.Now())
+}
+
+func executarPendentes(agora time.Time) (ResultadoExecucao, error) {
+	var resultado ResultadoExecucao
+	dono := uuid.NewString()
+	obtida, err := db.AcquireLock(lockExecucao, dono, validadeLock)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao obter a trava de execução: %w", err)
+	}
+	if !obtida {
+		return resultado, ErrExecucaoEmAndamento
+	}
+	defer db.ReleaseLock(lockExecucao, dono)
+
+	// A lista é lida já com a trava obtida, então reflete o NextRunTime gravado pela execução anterior.
+	// O filtro é feito aqui, e não no SQL, porque os horários podem ter sido gravados em fusos diferentes.
+	modelos, _, err := db.ListRoutines(nil, "next_run_time", "asc", 0, 0)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao listar modelos de rotina: %w", err)
+	}
+	for _, modelo := range modelos {
+		if modelo.NextRunTime.IsZero() || modelo.NextRunTime.After(agora) || strings.EqualFold(modelo.Frequency, "manual") {
+			continue
+		}
+		if !executarModelo(modelo, agora, &resultado) {
+			resultado.Atrasados = append(resultado.Atrasados, modelo)
+		}
+	}
+	return resultado, nil
+}
+
+// executarModelo gera as ocorrências vencidas de um modelo, até limiteRecuperacao.
+// Retorna false se ainda restarem ocorrências vencidas.
+func executarModelo(modelo models.Routine, agora time.Time, resultado *ResultadoExecucao) bool {
+	// O banco devolve os horários em UTC; dias da semana e do mês são calculados no fuso local.
+	ocorrencia := modelo.NextRunTime.In(time.Local)
+	for i := 0; i < limiteRecuperacao; i++ {
+		if ocorrencia.After(agora) {
+			return true
+		}
+		execucao := ExecucaoRotina{Modelo: modelo, Ocorrencia: ocorrencia}
+		proxima, err := ProximaExecucao(modelo.Frequency, ocorrencia)
+		if err != nil {
+			execucao.Erro = err
+			resultado.Execucoes = append(resultado.Execucoes, execucao)
+			return true
+		}
+		// O NextRunTime avança antes da geração: se o processo for interrompido entre os dois passos,
+		// a ocorrência é perdida, mas nunca gerada duas vezes.
+		if err := db.UpdateRoutineNextRun(modelo.ID, proxima); err != nil {
+			execucao.Erro = fmt.Errorf("erro ao avançar a próxima execução: %w", err)
+			resultado.Execucoes = append(resultado.Execucoes, execucao)
+			return true
+		}
+		pacote, err := GerarPacoteFromModelo(modelo.ID, ocorrencia.Format("2006-01-02"), false)
+		execucao.Tarefas, execucao.Eventos, execucao.Erro = pacote.Tarefas, pacote.Eventos, err
+		if errors.Is(execucao.Erro, ErrOcorrenciaJaExecutada) {
+			execucao.Erro, execucao.JaExecutada = nil, true
+		}
+		if execucao.Erro != nil {
+			// Devolve o NextRunTime para que a ocorrência seja tentada de novo na próxima execução.
+			db.UpdateRoutineNextRun(modelo.ID, ocorrencia)
+			resultado.Execucoes = append(resultado.Execucoes, execucao)
+			return true
+		}
+		resultado.Execucoes = append(resultado.Execucoes, execucao)
+		ocorrencia = proxima
+	}
+	return ocorrencia.After(agora)
+}
@@ -0,0 +1,171 @@
+package rotina
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+func TestProximaExecucao(t *testing.T) {
+	// 2024-01-31 é uma quarta-feira.
+	base := time.Date(2024, 1, 31, 7, 30, 0, 0, time.Local)
+	casos := []struct {
+		frequencia, esperado string
+	}{
+		{"diaria", "2024-02-01 07:30"},
+		{"semanal:seg,qua,sex", "2024-02-02 07:30"},
+		{"semanal:qua", "2024-02-07 07:30"},
+		{"Semanal:sáb", "2024-02-03 07:30"},
+		{"mensal:15", "2024-02-15 07:30"},
+		{"mensal:31", "2024-02-29 07:30"}, // Fevereiro de 2024 termina no dia 29.
+		{"quinzenal", "2024-02-14 07:30"},
+		{"cron:0 7 * * seg-sex", "2024-02-01 07:00"},
+	}
+	for _, c := range casos {
+		proxima, err := ProximaExecucao(c.frequencia, base)
+		if err != nil {
+			t.Errorf("ProximaExecucao(%q) falhou: %v", c.frequencia, err)
+			continue
+		}
+		if got := proxima.Format(dateTimeLayoutRotina); got != c.esperado {
+			t.Errorf("ProximaExecucao(%q): esperado %s, obtido %s", c.frequencia, c.esperado, got)
+		}
+	}
+
+	for _, freq := range []string{"manual", "semanal:xyz", "mensal:99", "anual", "mensal:6a-seg"} {
+		if _, err := ProximaExecucao(freq, base); err == nil {
+			t.Errorf("ProximaExecucao(%q): esperado erro", freq)
+		}
+	}
+}
+
+func TestProximasExecucoes(t *testing.T) {
+	inicio := time.Date(2024, 1, 31, 7, 30, 0, 0, time.Local)
+	execucoes, err := ProximasExecucoes("semanal:seg,sex", inicio, 3)
+	if err != nil {
+		t.Fatalf("ProximasExecucoes falhou: %v", err)
+	}
+	var got []string
+	for _, e := range execucoes {
+		got = append(got, e.Format(dateTimeLayoutRotina))
+	}
+	if esperado := "2024-02-02 07:30|2024-02-05 07:30|2024-02-09 07:30"; strings.Join(got, "|") != esperado {
+		t.Errorf("ProximasExecucoes: esperado %s, obtido %s", esperado, strings.Join(got, "|"))
+	}
+	// Sem início, a simulação parte da meia-noite de hoje, e não do horário atual.
+	hoje := time.Now()
+	meiaNoite := time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.Local)
+	execucoes, err = ProximasExecucoes("diaria", time.Time{}, 2)
+	if err != nil || len(execucoes) != 2 || !execucoes[0].Equal(meiaNoite) || !execucoes[1].Equal(meiaNoite.AddDate(0, 0, 1)) {
+		t.Errorf("ProximasExecucoes sem início: obtido %v (%v)", execucoes, err)
+	}
+	if _, err := ProximasExecucoes("semanal:xyz", inicio, 3); err == nil || !strings.Contains(err.Error(), "formato de frequência inválido") {
+		t.Errorf("Esperado erro de formato de frequência, obtido %v", err)
+	}
+
+	LimparRotinasStore()
+	modelo, _ := CriarModeloRotina("Reunião", "mensal:ultima-sexta", "Reunião {data}", 2, "", "2024-01-01 14:00")
+	execucoes, err = ProximasExecucoesModelo(modelo.ID, 2)
+	if err != nil {
+		t.Fatalf("ProximasExecucoesModelo falhou: %v", err)
+	}
+	if len(execucoes) != 2 || !execucoes[0].Equal(time.Date(2024, 1, 26, 14, 0, 0, 0, time.Local)) ||
+		!execucoes[1].Equal(time.Date(2024, 2, 23, 14, 0, 0, 0, time.Local)) {
+		t.Errorf("ProximasExecucoesModelo: obtido %v", execucoes)
+	}
+	manual, _ := CriarModeloRotina("Manual", "manual", "Manual", 2, "", "")
+	if _, err := ProximasExecucoesModelo(manual.ID, 2); err == nil {
+		t.Errorf("Esperado erro para rotina manual")
+	}
+}
+
+func TestExecutarPendentes(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+
+	agora := time.Date(2024, 3, 6, 10, 0, 0, 0, time.Local)
+	diaria, err := CriarModeloRotina("Chamada", "diaria", "Fazer chamada {data}", 2, "", "2024-03-04 08:00")
+	if err != nil {
+		t.Fatalf("CriarModeloRotina falhou: %v", err)
+	}
+	futura, _ := CriarModeloRotina("Futura", "diaria", "Não deve rodar", 2, "", "2024-03-06 11:00")
+	_, _ = CriarModeloRotina("Manual", "manual", "Não deve rodar", 2, "", "")
+	// Frequências inválidas são recusadas por CriarModeloRotina; simula um modelo antigo gravado direto no banco.
+	invalida := models.Routine{Name: "Inválida", Frequency: "semanal:xyz", TaskDescription: "Não deve rodar", TaskPriority: 2,
+		NextRunTime: time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local), CreatedAt: agora, UpdatedAt: agora}
+	if invalida.ID, err = db.CreateRoutine(invalida); err != nil {
+		t.Fatalf("CreateRoutine falhou: %v", err)
+	}
+
+	resultado, err := executarPendentes(agora)
+	if err != nil {
+		t.Fatalf("executarPendentes falhou: %v", err)
+	}
+	var geradas []string
+	erros := 0
+	for _, e := range resultado.Execucoes {
+		if e.Erro != nil {
+			erros++
+			if e.Modelo.ID != invalida.ID {
+				t.Errorf("Erro inesperado em '%s': %v", e.Modelo.Name, e.Erro)
+			}
+			continue
+		}
+		for _, tarefaGerada := range e.Tarefas {
+			geradas = append(geradas, tarefaGerada.Description)
+		}
+	}
+	// As execuções perdidas de 04/03 e 05/03 são recuperadas junto com a de hoje.
+	esperadas := "Fazer chamada 2024-03-04|Fazer chamada 2024-03-05|Fazer chamada 2024-03-06"
+	if got := strings.Join(geradas, "|"); got != esperadas {
+		t.Errorf("Tarefas geradas inesperadas:\nesperado %s\nobtido   %s", esperadas, got)
+	}
+	if erros != 1 {
+		t.Errorf("Esperado 1 erro (frequência inválida), obtido %d", erros)
+	}
+
+	if m, _ := GetModeloRotinaByID(diaria.ID); !m.NextRunTime.Equal(time.Date(2024, 3, 7, 8, 0, 0, 0, time.Local)) {
+		t.Errorf("NextRunTime não avançou para 07/03 08:00: %v", m.NextRunTime)
+	}
+	if m, _ := GetModeloRotinaByID(futura.ID); !m.NextRunTime.Equal(time.Date(2024, 3, 6, 11, 0, 0, 0, time.Local)) {
+		t.Errorf("NextRunTime de rotina futura não deveria mudar: %v", m.NextRunTime)
+	}
+	if m, _ := GetModeloRotinaByID(invalida.ID); !m.NextRunTime.Equal(time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local)) {
+		t.Errorf("NextRunTime de rotina com erro não deveria mudar: %v", m.NextRunTime)
+	}
+
+	t.Run("Executar de novo não duplica", func(t *testing.T) {
+		RemoverModeloRotina(invalida.ID)
+		resultado, err := executarPendentes(agora)
+		if err != nil {
+			t.Fatalf("executarPendentes falhou: %v", err)
+		}
+		if len(resultado.Execucoes) != 0 {
+			t.Errorf("Nenhuma execução esperada, obtido %+v", resultado.Execucoes)
+		}
+	})
+
+	t.Run("Trava impede execuções simultâneas", func(t *testing.T) {
+		obtida, err := db.AcquireLock(lockExecucao, "outro-processo", time.Minute)
+		if err != nil || !obtida {
+			t.Fatalf("AcquireLock falhou: %v (%v)", err, obtida)
+		}
+		if _, err := executarPendentes(agora.AddDate(0, 0, 1)); !errors.Is(err, ErrExecucaoEmAndamento) {
+			t.Errorf("Esperado ErrExecucaoEmAndamento, obtido %v", err)
+		}
+		if m, _ := GetModeloRotinaByID(diaria.ID); !m.NextRunTime.Equal(time.Date(2024, 3, 7, 8, 0, 0, 0, time.Local)) {
+			t.Errorf("NextRunTime não deveria mudar sem a trava: %v", m.NextRunTime)
+		}
+		if err := db.ReleaseLock(lockExecucao, "outro-processo"); err != nil {
+			t.Fatalf("ReleaseLock falhou: %v", err)
+		}
+		if _, err := executarPendentes(agora.AddDate(0, 0, 1)); err != nil {
+			t.Errorf("executarPendentes após liberar a trava falhou: %v", err)
+		}
+	})
+}
@@ -0,0 +1,417 @@
+package rotina
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Gramática das frequências de rotina (maiúsculas e acentos são ignorados):
+//
+//	manual                      só com 'rotina gerar-tarefas'
+//	diaria                      todos os dias
+//	semanal:seg,qua,sex         dias da semana; aceita nomes completos e intervalos (seg-sex)
+//	quinzenal                   a cada 14 dias
+//	a-cada:3d                   a cada N dias (d), semanas (s), meses (m) ou horas (h)
+//	mensal:15 | mensal:1,15     dias do mês; em meses mais curtos, o último dia do mês
+//	mensal:ultimo-dia           último dia do mês
+//	mensal:primeiro-dia-util    primeiro dia útil (segunda a sexta) do mês
+//	mensal:ultimo-dia-util      último dia útil (segunda a sexta) do mês
+//	mensal:2a-terca             N-ésimo dia da semana do mês (1a a 5a)
+//	mensal:ultima-sexta         último dia da semana do mês
+//	cron:0 7 * * seg-sex        expressão cron de 5 campos (minuto hora dia mês dia-da-semana)
+//
+// Exceto no cron, o horário das execuções é o da primeira execução (NextRunTime) do modelo.
+
+// horizonteFrequencia limita a busca pela próxima execução; regras que não ocorrem nesse prazo são inválidas.
+const horizonteFrequencia = 8 * 366
+
+// Frequencia é uma frequência de rotina já interpretada por ParseFrequencia.
+type Frequencia struct {
+	texto  string
+	manual bool
+	// Apenas um dos campos abaixo é usado, conforme a forma da frequência.
+	diaValido func(dia time.Time) bool       // Regras por dia, no horário da execução anterior.
+	somar     func(apos time.Time) time.Time // Regras por intervalo a partir da execução anterior.
+	cron      *expressaoCron                 // Expressão cron, que define também o horário.
+}
+
+// String devolve o texto original da frequência.
+func (f Frequencia) String() string { return f.texto }
+
+// Manual informa se a frequência é "manual", sem execuções agendadas.
+func (f Frequencia) Manual() bool { return f.manual }
+
+// Proxima calcula a primeira execução estritamente posterior a apos.
+// Fora do cron, o horário de apos é mantido.
+func (f Frequencia) Proxima(apos time.Time) (time.Time, error) {
+	switch {
+	case f.manual:
+		return time.Time{}, errors.New("rotinas manuais não têm próxima execução")
+	case f.somar != nil:
+		return f.somar(apos), nil
+	case f.cron != nil:
+		return f.cron.proxima(apos)
+	}
+	for i := 1; i <= horizonteFrequencia; i++ {
+		if dia := apos.AddDate(0, 0, i); f.diaValido(dia) {
+			return dia, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("a frequência '%s' não ocorre nos próximos anos", f.texto)
+}
+
+// Primeira calcula a primeira execução em aPartirDe ou depois dele: o próprio aPartirDe, se a regra o aceitar.
+// Serve para alinhar a primeira execução de um modelo à frequência (ex: "semanal:seg" a partir de uma quarta).
+func (f Frequencia) Primeira(aPartirDe time.Time) (time.Time, error) {
+	switch {
+	case f.manual:
+		return time.Time{}, errors.New("rotinas manuais não têm próxima execução")
+	case f.somar != nil:
+		return aPartirDe, nil
+	case f.cron != nil:
+		return f.cron.proxima(aPartirDe.Add(-time.Minute))
+	case f.diaValido(aPartirDe):
+		return aPartirDe, nil
+	}
+	return f.Proxima(aPartirDe)
+}
+
+// ParseFrequencia interpreta e valida uma frequência de rotina; veja a gramática no início deste arquivo.
+func ParseFrequencia(texto string) (Frequencia, error) {
+	f := Frequencia{texto: strings.TrimSpace(texto)}
+	normalizado := normalizarFrequencia(texto)
+	tipo, valor, temValor := strings.Cut(normalizado, ":")
+	if !temValor && strings.Count(normalizado, " ") == 4 {
+		// Uma expressão cron sem o prefixo "cron:".
+		tipo, valor, temValor = "cron", normalizado, true
+	}
+	valor = strings.TrimSpace(valor)
+
+	var err error
+	switch tipo {
+	case "manual", "diaria":
+		if temValor {
+			return Frequencia{}, fmt.Errorf("a frequência '%s' não aceita parâmetros", tipo)
+		}
+		f.manual = tipo == "manual"
+		f.diaValido = func(time.Time) bool { return true }
+	case "quinzenal":
+		if temValor {
+			return Frequencia{}, errors.New("a frequência 'quinzenal' não aceita parâmetros. Para outros intervalos, use a-cada:<n>d")
+		}
+		f.somar = func(t time.Time) time.Time { return t.AddDate(0, 0, 14) }
+	case "semanal":
+		f.diaValido, err = parseSemanal(valor)
+	case "mensal":
+		f.diaValido, err = parseMensal(valor)
+	case "a-cada":
+		f.somar, err = parseIntervalo(valor)
+	case "cron":
+		f.cron, err = parseCron(valor)
+	default:
+		return Frequencia{}, fmt.Errorf("tipo de frequência '%s' desconhecido. Use manual, diaria, semanal:<dias>, quinzenal, a-cada:<n><unidade>, mensal:<regra> ou cron:<expressão>", tipo)
+	}
+	if err != nil {
+		return Frequencia{}, err
+	}
+	if !f.manual {
+		// Rejeita regras válidas na forma, mas que nunca ocorrem (ex: cron para 30 de fevereiro).
+		if _, err := f.Proxima(time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local)); err != nil {
+			return Frequencia{}, err
+		}
+	}
+	return f, nil
+}
+
+var removerAcentos = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c", "ª", "a", "º", "o")
+
+func normalizarFrequencia(texto string) string {
+	return removerAcentos.Replace(strings.ToLower(strings.Join(strings.Fields(texto), " ")))
+}
+
+// nomesDosDias mapeia abreviações e nomes (sem acento) dos dias da semana.
+var nomesDosDias = map[string]time.Weekday{
+	"dom": time.Sunday, "domingo": time.Sunday,
+	"seg": time.Monday, "segunda": time.Monday, "segunda-feira": time.Monday,
+	"ter": time.Tuesday, "terca": time.Tuesday, "terca-feira": time.Tuesday,
+	"qua": time.Wednesday, "quarta": time.Wednesday, "quarta-feira": time.Wednesday,
+	"qui": time.Thursday, "quinta": time.Thursday, "quinta-feira": time.Thursday,
+	"sex": time.Friday, "sexta": time.Friday, "sexta-feira": time.Friday,
+	"sab": time.Saturday, "sabado": time.Saturday,
+}
+
+const dicaDiasDaSemana = "Use dom, seg, ter, qua, qui, sex ou sab (ou o nome completo)"
+
+// parseDiasDaSemana interpreta uma lista de dias ("seg,qua" ou "seg-sex").
+func parseDiasDaSemana(valor string) (map[time.Weekday]bool, error) {
+	if strings.TrimSpace(valor) == "" {
+		return nil, fmt.Errorf("informe os dias da semana, ex: semanal:seg,qua,sex. %s", dicaDiasDaSemana)
+	}
+	dias := make(map[time.Weekday]bool)
+	for _, item := range strings.Split(valor, ",") {
+		item = strings.TrimSpace(item)
+		if dia, ok := nomesDosDias[item]; ok {
+			dias[dia] = true
+			continue
+		}
+		de, ate, intervalo := strings.Cut(item, "-")
+		inicio, okInicio := nomesDosDias[de]
+		fim, okFim := nomesDosDias[ate]
+		if !intervalo || !okInicio || !okFim {
+			return nil, fmt.Errorf("dia da semana '%s' desconhecido. %s", item, dicaDiasDaSemana)
+		}
+		for d := inicio; ; d = (d + 1) % 7 {
+			dias[d] = true
+			if d == fim {
+				break
+			}
+		}
+	}
+	return dias, nil
+}
+
+func parseSemanal(valor string) (func(time.Time) bool, error) {
+	dias, err := parseDiasDaSemana(valor)
+	if err != nil {
+		return nil, err
+	}
+	return func(t time.Time) bool { return dias[t.Weekday()] }, nil
+}
+
+// parseIntervalo interpreta "<n><unidade>" de a-cada, com unidade d, s, m ou h.
+func parseIntervalo(valor string) (func(time.Time) time.Time, error) {
+	erro := fmt.Errorf("intervalo '%s' inválido. Use a-cada:<n><unidade>, com unidade d (dias), s (semanas), m (meses) ou h (horas), ex: a-cada:3d", valor)
+	if len(valor) < 2 {
+		return nil, erro
+	}
+	n, err := strconv.Atoi(valor[:len(valor)-1])
+	if err != nil || n <= 0 {
+		return nil, erro
+	}
+	switch valor[len(valor)-1] {
+	case 'd':
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, n) }, nil
+	case 's':
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, 7*n) }, nil
+	case 'm':
+		return func(t time.Time) time.Time { return somarMeses(t, n) }, nil
+	case 'h':
+		return func(t time.Time) time.Time { return t.Add(time.Duration(n) * time.Hour) }, nil
+	}
+	return nil, erro
+}
+
+// somarMeses soma n meses a t; se o dia não existir no mês de destino (ex: 31), usa o último dia do mês.
+func somarMeses(t time.Time, n int) time.Time {
+	primeiro := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	dia := t.Day()
+	if ultimo := diasNoMes(primeiro); dia > ultimo {
+		dia = ultimo
+	}
+	return primeiro.AddDate(0, 0, dia-1)
+}
+
+func diasNoMes(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func ehDiaUtil(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// parseMensal interpreta uma lista de regras mensais separadas por vírgula; basta uma delas aceitar o dia.
+func parseMensal(valor string) (func(time.Time) bool, error) {
+	if strings.TrimSpace(valor) == "" {
+		return nil, errors.New("informe o dia do mês, ex: mensal:15, mensal:ultimo-dia-util ou mensal:2a-terca")
+	}
+	var regras []func(time.Time) bool
+	for _, item := range strings.Split(valor, ",") {
+		regra, err := parseRegraMensal(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		regras = append(regras, regra)
+	}
+	return func(t time.Time) bool {
+		for _, regra := range regras {
+			if regra(t) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func parseRegraMensal(item string) (func(time.Time) bool, error) {
+	switch item {
+	case "ultimo-dia":
+		return func(t time.Time) bool { return t.Day() == diasNoMes(t) }, nil
+	case "primeiro-dia-util":
+		return func(t time.Time) bool {
+			return ehDiaUtil(t) && (t.Day() == 1 || (t.Day() <= 3 && t.Weekday() == time.Monday))
+		}, nil
+	case "ultimo-dia-util":
+		return func(t time.Time) bool {
+			restantes := diasNoMes(t) - t.Day()
+			return ehDiaUtil(t) && (restantes == 0 || (restantes <= 2 && t.Weekday() == time.Friday))
+		}, nil
+	}
+	if dia, err := strconv.Atoi(item); err == nil {
+		if dia < 1 || dia > 31 {
+			return nil, fmt.Errorf("dia do mês %d fora do intervalo 1 a 31", dia)
+		}
+		return func(t time.Time) bool {
+			return t.Day() == dia || (dia > diasNoMes(t) && t.Day() == diasNoMes(t))
+		}, nil
+	}
+
+	ordinal, nome, ok := strings.Cut(item, "-")
+	diaDaSemana, okDia := nomesDosDias[nome]
+	if !ok || !okDia {
+		return nil, fmt.Errorf("regra mensal '%s' desconhecida. Use um dia (1 a 31), ultimo-dia, primeiro-dia-util, ultimo-dia-util, <n>a-<dia da semana> (ex: 2a-terca) ou ultima-<dia da semana>", item)
+	}
+	if ordinal == "ultima" || ordinal == "ultimo" {
+		return func(t time.Time) bool { return t.Weekday() == diaDaSemana && t.Day()+7 > diasNoMes(t) }, nil
+	}
+	n, err := strconv.Atoi(strings.TrimRight(ordinal, "ao"))
+	if err != nil || n < 1 || n > 5 {
+		return nil, fmt.Errorf("ordinal '%s' inválido em '%s'. Use de 1a a 5a, ou ultima", ordinal, item)
+	}
+	return func(t time.Time) bool { return t.Weekday() == diaDaSemana && (t.Day()-1)/7+1 == n }, nil
+}
+
+// --- Expressões cron ---
+
+// expressaoCron é uma expressão cron de 5 campos: minuto, hora, dia do mês, mês e dia da semana.
+type expressaoCron struct {
+	minutos, horas, dias, meses, diasDaSemana map[int]bool
+	// Como no cron, quando dia do mês e dia da semana são ambos restritos, basta um deles coincidir.
+	diaQualquer, diaDaSemanaQualquer bool
+}
+
+var camposCron = []struct {
+	nome     string
+	min, max int
+}{
+	{"minuto", 0, 59}, {"hora", 0, 23}, {"dia do mês", 1, 31}, {"mês", 1, 12}, {"dia da semana", 0, 7},
+}
+
+func parseCron(valor string) (*expressaoCron, error) {
+	campos := strings.Fields(valor)
+	if len(campos) != 5 {
+		return nil, fmt.Errorf("a expressão cron '%s' deve ter 5 campos: minuto hora dia-do-mês mês dia-da-semana, ex: cron:0 7 * * seg-sex", valor)
+	}
+	conjuntos := make([]map[int]bool, 5)
+	for i, campo := range campos {
+		conjunto, err := parseCampoCron(campo, i)
+		if err != nil {
+			return nil, err
+		}
+		conjuntos[i] = conjunto
+	}
+	if conjuntos[4][7] {
+		conjuntos[4][0] = true // 7 também é domingo.
+	}
+	return &expressaoCron{
+		minutos: conjuntos[0], horas: conjuntos[1], dias: conjuntos[2], meses: conjuntos[3], diasDaSemana: conjuntos[4],
+		diaQualquer: campos[2] == "*", diaDaSemanaQualquer: campos[4] == "*",
+	}, nil
+}
+
+// parseCampoCron interpreta um campo cron: "*", valores, intervalos "a-b" e passos "*/n" ou "a-b/n", separados por vírgula.
+// No dia da semana, também aceita os nomes dos dias (dom a sab).
+func parseCampoCron(campo string, indice int) (map[int]bool, error) {
+	def := camposCron[indice]
+	valorDoCampo := func(s string) (int, error) {
+		if indice == 4 {
+			if dia, ok := nomesDosDias[s]; ok {
+				return int(dia), nil
+			}
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("valor '%s' inválido no campo %s da expressão cron", s, def.nome)
+		}
+		if v < def.min || v > def.max {
+			return 0, fmt.Errorf("valor %d fora do intervalo %d-%d no campo %s da expressão cron", v, def.min, def.max, def.nome)
+		}
+		return v, nil
+	}
+
+	conjunto := make(map[int]bool)
+	for _, item := range strings.Split(campo, ",") {
+		faixa, passoStr, temPasso := strings.Cut(item, "/")
+		passo := 1
+		if temPasso {
+			var err error
+			if passo, err = strconv.Atoi(passoStr); err != nil || passo <= 0 {
+				return nil, fmt.Errorf("passo '%s' inválido no campo %s da expressão cron", passoStr, def.nome)
+			}
+		}
+		inicio, fim := def.min, def.max
+		if faixa != "*" {
+			de, ate, temIntervalo := strings.Cut(faixa, "-")
+			var err error
+			if inicio, err = valorDoCampo(de); err != nil {
+				return nil, err
+			}
+			fim = inicio
+			if temIntervalo {
+				if fim, err = valorDoCampo(ate); err != nil {
+					return nil, err
+				}
+			} else if temPasso {
+				fim = def.max
+			}
+			if fim < inicio {
+				return nil, fmt.Errorf("intervalo '%s' invertido no campo %s da expressão cron", faixa, def.nome)
+			}
+		}
+		for v := inicio; v <= fim; v += passo {
+			conjunto[v] = true
+		}
+	}
+	return conjunto, nil
+}
+
+func (c *expressaoCron) diaCoincide(t time.Time) bool {
+	if !c.meses[int(t.Month())] {
+		return false
+	}
+	dia, diaDaSemana := c.dias[t.Day()], c.diasDaSemana[int(t.Weekday())]
+	switch {
+	case c.diaQualquer && c.diaDaSemanaQualquer:
+		return true
+	case c.diaQualquer:
+		return diaDaSemana
+	case c.diaDaSemanaQualquer:
+		return dia
+	}
+	return dia || diaDaSemana
+}
+
+// proxima devolve o primeiro minuto estritamente posterior a apos que coincide com a expressão.
+func (c *expressaoCron) proxima(apos time.Time) (time.Time, error) {
+	inicio := time.Date(apos.Year(), apos.Month(), apos.Day(), apos.Hour(), apos.Minute(), 0, 0, apos.Location()).Add(time.Minute)
+	for i := 0; i <= horizonteFrequencia; i++ {
+		dia := time.Date(inicio.Year(), inicio.Month(), inicio.Day()+i, 0, 0, 0, 0, inicio.Location())
+		if !c.diaCoincide(dia) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if !c.horas[h] {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if t := time.Date(dia.Year(), dia.Month(), dia.Day(), h, m, 0, 0, dia.Location()); c.minutos[m] && !t.Before(inicio) {
+					return t, nil
+				}
+			}
+		}
+	}
+	return time.Time{}, errors.New("a expressão cron não ocorre nos próximos anos")
+}
@@ -0,0 +1,113 @@
+package rotina
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrequenciaProxima(t *testing.T) {
+	// 2024-01-31 é uma quarta-feira.
+	base := time.Date(2024, 1, 31, 7, 30, 0, 0, time.Local)
+	casos := []struct {
+		frequencia string
+		esperadas  []string // Execuções sucessivas após base.
+	}{
+		{"semanal:segunda,Quarta-Feira", []string{"2024-02-05 07:30", "2024-02-07 07:30"}},
+		{"semanal:sex-seg", []string{"2024-02-02 07:30", "2024-02-03 07:30", "2024-02-04 07:30", "2024-02-05 07:30", "2024-02-09 07:30"}},
+		{"quinzenal", []string{"2024-02-14 07:30", "2024-02-28 07:30"}},
+		{"a-cada:3d", []string{"2024-02-03 07:30", "2024-02-06 07:30"}},
+		{"a-cada:2s", []string{"2024-02-14 07:30"}},
+		{"a-cada:1m", []string{"2024-02-29 07:30", "2024-03-29 07:30"}},
+		{"a-cada:12h", []string{"2024-01-31 19:30", "2024-02-01 07:30"}},
+		{"mensal:1,15", []string{"2024-02-01 07:30", "2024-02-15 07:30", "2024-03-01 07:30"}},
+		{"mensal:ultimo-dia", []string{"2024-02-29 07:30", "2024-03-31 07:30"}},
+		// 30/03/2024 é sábado; 01/06/2024 é sábado.
+		{"mensal:ultimo-dia-util", []string{"2024-02-29 07:30", "2024-03-29 07:30", "2024-04-30 07:30"}},
+		{"mensal:primeiro-dia-util", []string{"2024-02-01 07:30", "2024-03-01 07:30", "2024-04-01 07:30", "2024-05-01 07:30", "2024-06-03 07:30"}},
+		{"mensal:2a-terca", []string{"2024-02-13 07:30", "2024-03-12 07:30"}},
+		{"mensal:1ª-segunda", []string{"2024-02-05 07:30", "2024-03-04 07:30"}},
+		{"mensal:5a-sex", []string{"2024-03-29 07:30", "2024-05-31 07:30"}},
+		{"mensal:última-sexta", []string{"2024-02-23 07:30", "2024-03-29 07:30"}},
+		{"cron:*/30 8-9 * * *", []string{"2024-01-31 08:00", "2024-01-31 08:30", "2024-01-31 09:00", "2024-01-31 09:30", "2024-02-01 08:00"}},
+		{"0 18 * * sex", []string{"2024-02-02 18:00", "2024-02-09 18:00"}},
+		// Dia do mês e dia da semana restritos: basta um deles coincidir, como no cron.
+		{"cron:0 7 13 * 5", []string{"2024-02-02 07:00", "2024-02-09 07:00", "2024-02-13 07:00"}},
+		{"cron:15 6 29 2 *", []string{"2024-02-29 06:15", "2028-02-29 06:15"}},
+	}
+	for _, c := range casos {
+		f, err := ParseFrequencia(c.frequencia)
+		if err != nil {
+			t.Errorf("ParseFrequencia(%q) falhou: %v", c.frequencia, err)
+			continue
+		}
+		var obtidas []string
+		atual := base
+		for range c.esperadas {
+			if atual, err = f.Proxima(atual); err != nil {
+				t.Fatalf("Proxima(%q) falhou: %v", c.frequencia, err)
+			}
+			obtidas = append(obtidas, atual.Format(dateTimeLayoutRotina))
+		}
+		if got, esperado := strings.Join(obtidas, " | "), strings.Join(c.esperadas, " | "); got != esperado {
+			t.Errorf("%q:\nesperado %s\nobtido   %s", c.frequencia, esperado, got)
+		}
+	}
+}
+
+func TestFrequenciaPrimeira(t *testing.T) {
+	inicio := time.Date(2024, 1, 31, 7, 30, 0, 0, time.Local) // Quarta-feira.
+	casos := []struct {
+		frequencia, esperada string
+	}{
+		{"diaria", "2024-01-31 07:30"},
+		{"semanal:qua", "2024-01-31 07:30"},
+		{"semanal:seg", "2024-02-05 07:30"},
+		{"a-cada:3d", "2024-01-31 07:30"},
+		{"cron:30 7 * * *", "2024-01-31 07:30"},
+		{"cron:0 7 * * *", "2024-02-01 07:00"},
+	}
+	for _, c := range casos {
+		f, err := ParseFrequencia(c.frequencia)
+		if err != nil {
+			t.Errorf("ParseFrequencia(%q) falhou: %v", c.frequencia, err)
+			continue
+		}
+		primeira, err := f.Primeira(inicio)
+		if err != nil {
+			t.Errorf("Primeira(%q) falhou: %v", c.frequencia, err)
+			continue
+		}
+		if got := primeira.Format(dateTimeLayoutRotina); got != c.esperada {
+			t.Errorf("Primeira(%q): esperado %s, obtido %s", c.frequencia, c.esperada, got)
+		}
+	}
+}
+
+func TestParseFrequenciaErros(t *testing.T) {
+	casos := []struct {
+		frequencia, trecho string
+	}{
+		{"anual", "tipo de frequência 'anual' desconhecido"},
+		{"diaria:2", "não aceita parâmetros"},
+		{"semanal:", "informe os dias da semana"},
+		{"semanal:seg,xyz", "dia da semana 'xyz' desconhecido"},
+		{"mensal:99", "fora do intervalo 1 a 31"},
+		{"mensal:6a-terca", "ordinal '6a' inválido"},
+		{"mensal:penultima-sexta", "ordinal 'penultima' inválido"},
+		{"mensal:dia-15", "regra mensal 'dia-15' desconhecida"},
+		{"a-cada:0d", "intervalo '0d' inválido"},
+		{"a-cada:3x", "intervalo '3x' inválido"},
+		{"cron:0 7 * *", "deve ter 5 campos"},
+		{"cron:0 25 * * *", "valor 25 fora do intervalo 0-23 no campo hora"},
+		{"cron:*/0 * * * *", "passo '0' inválido no campo minuto"},
+		{"cron:0 7 * * sex-seg", "intervalo 'sex-seg' invertido no campo dia da semana"},
+		{"cron:0 0 30 2 *", "não ocorre"},
+	}
+	for _, c := range casos {
+		_, err := ParseFrequencia(c.frequencia)
+		if err == nil || !strings.Contains(err.Error(), c.trecho) {
+			t.Errorf("ParseFrequencia(%q): esperado erro contendo %q, obtido %v", c.frequencia, c.trecho, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package rotina
+
+import (
+	"fmt"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// O histórico de execuções (tabela "routine_runs") é gravado por GerarTarefasFromModelo a cada geração,
+// manual ou agendada, e removido junto com o modelo.
+
+// HistoricoExecucoes retorna as execuções de um modelo de rotina, da ocorrência mais antiga para a mais recente.
+func HistoricoExecucoes(modeloID string) ([]models.RoutineRun, error) {
+	if _, err := GetModeloRotinaByID(modeloID); err != nil {
+		return nil, err
+	}
+	execucoes, err := db.ListRoutineRuns(modeloID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar o histórico de execuções: %w", err)
+	}
+	return execucoes, nil
+}
+
+// EstatisticaRotina resume o que um modelo de rotina produziu.
+type EstatisticaRotina struct {
+	Modelo            models.Routine
+	Execucoes         int    // Execuções registradas, incluindo as forçadas.
+	UltimaOcorrencia  string // Data ("YYYY-MM-DD") da ocorrência mais recente executada; vazia se nunca executou.
+	TarefasGeradas    int    // Tarefas geradas que ainda existem.
+	TarefasConcluidas int    // Tarefas geradas já concluídas.
+	TarefasRemovidas  int    // Tarefas geradas que foram removidas depois.
+	EventosGerados    int    // Eventos criados pelo pacote do modelo, incluindo os já removidos.
+}
+
+// TaxaConclusao devolve a fração (0 a 1) das tarefas geradas, ainda existentes, que foram concluídas.
+// Sem tarefas, devolve 0.
+func (e EstatisticaRotina) TaxaConclusao() float64 {
+	if e.TarefasGeradas == 0 {
+		return 0
+	}
+	return float64(e.TarefasConcluidas) / float64(e.TarefasGeradas)
+}
+
+// EstatisticasRotinas calcula, para cada modelo de rotina, o número de execuções e a situação das tarefas geradas.
+// Os modelos seguem a ordem de ListarModelosRotina (por nome).
+func EstatisticasRotinas() ([]EstatisticaRotina, error) {
+	modelos, err := ListarModelosRotina("nome", "asc")
+	if err != nil {
+		return nil, err
+	}
+	execucoes, err := db.ListRoutineRuns("")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar o histórico de execuções: %w", err)
+	}
+	tarefas, err := tarefa.ListarTarefas("", 0, "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	statusPorID := make(map[string]string, len(tarefas))
+	for _, t := range tarefas {
+		statusPorID[t.ID] = t.Status
+	}
+
+	porModelo := make(map[string]*EstatisticaRotina, len(modelos))
+	estatisticas := make([]EstatisticaRotina, len(modelos))
+	for i, m := range modelos {
+		estatisticas[i].Modelo = m
+		porModelo[m.ID] = &estatisticas[i]
+	}
+	for _, execucao := range execucoes {
+		e, ok := porModelo[execucao.RoutineID]
+		if !ok {
+			continue
+		}
+		e.Execucoes++
+		e.EventosGerados += len(execucao.EventIDs)
+		if execucao.OccurrenceDate > e.UltimaOcorrencia {
+			e.UltimaOcorrencia = execucao.OccurrenceDate
+		}
+		for _, id := range execucao.TaskIDs {
+			status, existe := statusPorID[id]
+			switch {
+			case !existe:
+				e.TarefasRemovidas++
+			case status == models.TaskStatusCompleted:
+				e.TarefasGeradas++
+				e.TarefasConcluidas++
+			default:
+				e.TarefasGeradas++
+			}
+		}
+	}
+	return estatisticas, nil
+}
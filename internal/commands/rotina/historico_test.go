@@ -0,0 +1,124 @@
+package rotina
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/tarefa"
+)
+
+func TestGeracaoIdempotente(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+	modelo, _ := CriarModeloRotina("Chamada", "manual", "Fazer chamada {data}", 2, "", "")
+
+	primeira, err := GerarTarefasFromModelo(modelo.ID, "2024-03-05")
+	if err != nil {
+		t.Fatalf("GerarTarefasFromModelo falhou: %v", err)
+	}
+	if _, err := GerarTarefasFromModelo(modelo.ID, "2024-03-05"); !errors.Is(err, ErrOcorrenciaJaExecutada) {
+		t.Errorf("Esperado ErrOcorrenciaJaExecutada ao repetir a data, obtido %v", err)
+	}
+	if tarefas, _ := tarefa.ListarTarefas("", 0, "", "", "", ""); len(tarefas) != 1 {
+		t.Errorf("Repetir a data não deveria criar tarefas, obtido %d", len(tarefas))
+	}
+
+	forcadas, err := RegerarTarefasFromModelo(modelo.ID, "2024-03-05")
+	if err != nil {
+		t.Fatalf("RegerarTarefasFromModelo falhou: %v", err)
+	}
+	if forcadas[0].ID == primeira[0].ID {
+		t.Errorf("A geração forçada deveria criar uma tarefa nova")
+	}
+	// Forçar uma data ainda não executada é uma execução normal.
+	if _, err := RegerarTarefasFromModelo(modelo.ID, "2024-03-06"); err != nil {
+		t.Fatalf("RegerarTarefasFromModelo falhou: %v", err)
+	}
+
+	historico, err := HistoricoExecucoes(modelo.ID)
+	if err != nil {
+		t.Fatalf("HistoricoExecucoes falhou: %v", err)
+	}
+	if len(historico) != 3 {
+		t.Fatalf("Esperado 3 execuções no histórico, obtido %d", len(historico))
+	}
+	esperado := []struct {
+		data    string
+		tarefa  string
+		forcada bool
+	}{{"2024-03-05", primeira[0].ID, false}, {"2024-03-05", forcadas[0].ID, true}, {"2024-03-06", "", false}}
+	for i, e := range esperado {
+		h := historico[i]
+		if h.OccurrenceDate != e.data || h.Forced != e.forcada || len(h.TaskIDs) != 1 || (e.tarefa != "" && h.TaskIDs[0] != e.tarefa) {
+			t.Errorf("Execução %d inesperada: %+v", i, h)
+		}
+	}
+	if _, err := HistoricoExecucoes("id-inexistente"); err == nil {
+		t.Errorf("Esperado erro para modelo inexistente")
+	}
+}
+
+func TestExecutarPendentesPulaOcorrenciaJaGerada(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+	modelo, _ := CriarModeloRotina("Diário de classe", "diaria", "Preencher diário {data}", 2, "", "2024-03-04 18:00")
+	if _, err := GerarTarefasFromModelo(modelo.ID, "2024-03-05"); err != nil {
+		t.Fatalf("GerarTarefasFromModelo falhou: %v", err)
+	}
+
+	resultado, err := executarPendentes(time.Date(2024, 3, 6, 8, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("executarPendentes falhou: %v", err)
+	}
+	if len(resultado.Execucoes) != 2 {
+		t.Fatalf("Esperado 2 execuções (04/03 e 05/03), obtido %+v", resultado.Execucoes)
+	}
+	if e := resultado.Execucoes[1]; e.Erro != nil || !e.JaExecutada || len(e.Tarefas) != 0 {
+		t.Errorf("A ocorrência de 05/03 deveria ser ignorada por já ter sido gerada: %+v", e)
+	}
+	if m, _ := GetModeloRotinaByID(modelo.ID); !m.NextRunTime.Equal(time.Date(2024, 3, 6, 18, 0, 0, 0, time.Local)) {
+		t.Errorf("NextRunTime deveria avançar para 06/03 18:00: %v", m.NextRunTime)
+	}
+	if tarefas, _ := tarefa.ListarTarefas("", 0, "", "", "", ""); len(tarefas) != 2 {
+		t.Errorf("Esperado 2 tarefas (uma por data), obtido %d", len(tarefas))
+	}
+}
+
+func TestEstatisticasRotinas(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+	chamada, _ := CriarModeloRotina("Chamada", "manual", "Chamada {data}", 2, "", "")
+	_, _ = CriarModeloRotina("Nunca executada", "manual", "Nada", 2, "", "")
+
+	var ids []string
+	for _, data := range []string{"2024-03-04", "2024-03-05", "2024-03-06", "2024-03-07"} {
+		geradas, err := GerarTarefasFromModelo(chamada.ID, data)
+		if err != nil {
+			t.Fatalf("GerarTarefasFromModelo(%s) falhou: %v", data, err)
+		}
+		ids = append(ids, geradas[0].ID)
+	}
+	tarefa.ConcluirTarefa(ids[0])
+	tarefa.ConcluirTarefa(ids[1])
+	tarefa.RemoverTarefa(ids[3])
+
+	estatisticas, err := EstatisticasRotinas()
+	if err != nil {
+		t.Fatalf("EstatisticasRotinas falhou: %v", err)
+	}
+	if len(estatisticas) != 2 {
+		t.Fatalf("Esperado 2 modelos, obtido %d", len(estatisticas))
+	}
+	e := estatisticas[0]
+	if e.Modelo.ID != chamada.ID || e.Execucoes != 4 || e.UltimaOcorrencia != "2024-03-07" ||
+		e.TarefasGeradas != 3 || e.TarefasConcluidas != 2 || e.TarefasRemovidas != 1 {
+		t.Errorf("Estatística inesperada para 'Chamada': %+v", e)
+	}
+	if taxa := e.TaxaConclusao(); taxa < 0.66 || taxa > 0.67 {
+		t.Errorf("Taxa de conclusão: esperado 2/3, obtido %v", taxa)
+	}
+	if vazia := estatisticas[1]; vazia.Execucoes != 0 || vazia.UltimaOcorrencia != "" || vazia.TaxaConclusao() != 0 {
+		t.Errorf("Estatística inesperada para modelo nunca executado: %+v", vazia)
+	}
+}
@@ -0,0 +1,216 @@
+package rotina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"vickgenda-cli/internal/commands/calendario"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/store"
+)
+
+// A descrição e as tags das tarefas de um modelo de rotina são templates do text/template, avaliados com um
+// ContextoModelo na geração das tarefas. Exemplos:
+//
+//	Corrigir provas do {{.Bimestre.Nome}} (semana {{.Bimestre.Semana}}) - entregar até {{somarDiasUteis .Dia 3 | dataBR}}
+//	Preparar aulas de {{.DiaDaSemana}}: {{juntar .Disciplinas ", "}}
+//	{{range .Aulas}}{{.Horario}} {{.Turma}} {{.Disciplina}}; {{end}}
+//
+// Os placeholders antigos {data} e {nome_rotina} continuam aceitos e equivalem a {{.Data}} e {{.NomeRotina}}.
+// Os templates são validados ao salvar o modelo (ValidarModeloTexto), não apenas na geração.
+
+// ContextoModelo são os dados disponíveis nos templates de descrição e tags das tarefas.
+type ContextoModelo struct {
+	NomeRotina  string           // Nome do modelo de rotina.
+	Data        string           // Data base (YYYY-MM-DD).
+	Dia         time.Time        // Data base (meia-noite local), para as funções de datas.
+	DiaDaSemana string           // Ex: "segunda-feira".
+	Semana      int              // Semana do ano (ISO 8601).
+	Mes         string           // Nome do mês, ex: "março".
+	Ano         int              // Ano da data base.
+	Bimestre    BimestreContexto // Bimestre que contém a data base; vazio se não houver.
+	Aulas       []AulaContexto   // Aulas da data base, em ordem de horário.
+	Turmas      []string         // Turmas com aula na data base, sem repetição.
+	Disciplinas []string         // Disciplinas com aula na data base, sem repetição.
+}
+
+// BimestreContexto descreve, nos templates, o bimestre (Term) da data base.
+type BimestreContexto struct {
+	Nome   string
+	Inicio time.Time
+	Fim    time.Time
+	Semana int // Semana do bimestre: 1 nos sete primeiros dias, 2 nos sete seguintes, e assim por diante.
+}
+
+// AulaContexto descreve, nos templates, uma aula da data base.
+type AulaContexto struct {
+	Disciplina string
+	Turma      string
+	Topico     string
+	Horario    string // "HH:MM"
+}
+
+var (
+	nomesDosDiasCompletos = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+	nomesDosMeses         = [...]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+)
+
+// funcoesModelo são as funções disponíveis nos templates, além das nativas do text/template.
+var funcoesModelo = template.FuncMap{
+	"somarDias":      func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) },
+	"somarSemanas":   func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) },
+	"somarMeses":     func(t time.Time, n int) time.Time { return somarMeses(t, n) },
+	"somarDiasUteis": somarDiasUteis,
+	"dataBR":         func(t time.Time) string { return t.Format("02/01/2006") },
+	"dataCurta":      func(t time.Time) string { return t.Format("02/01") },
+	"dataISO":        func(t time.Time) string { return t.Format("2006-01-02") },
+	"diaDaSemana":    func(t time.Time) string { return nomesDosDiasCompletos[t.Weekday()] },
+	"nomeDoMes":      func(t time.Time) string { return nomesDosMeses[t.Month()-1] },
+	"maiusculas":     strings.ToUpper,
+	"minusculas":     strings.ToLower,
+	"juntar":         func(itens []string, separador string) string { return strings.Join(itens, separador) },
+}
+
+// somarDiasUteis avança n dias úteis: pula sábados, domingos e os dias não letivos do calendário escolar.
+func somarDiasUteis(t time.Time, n int) (time.Time, error) {
+	if n < 0 {
+		return time.Time{}, errors.New("somarDiasUteis não aceita valores negativos")
+	}
+	// Uma semana por dia útil, mais uma folga para recessos, cobre qualquer calendário razoável.
+	naoLetivos, err := calendario.CarregarDiasNaoLetivos(t, t.AddDate(0, 0, 7*n+60))
+	if err != nil {
+		return time.Time{}, err
+	}
+	for i := 0; i < 7*n+60 && n > 0; i++ {
+		t = t.AddDate(0, 0, 1)
+		if ehDiaUtil(t) && naoLetivos.EhDiaLetivo(t) {
+			n--
+		}
+	}
+	if n > 0 {
+		return time.Time{}, errors.New("dias úteis insuficientes no calendário escolar")
+	}
+	return t, nil
+}
+
+// placeholdersAntigos encontra {data} e {nome_rotina}, ignorando as ações {{...}} do template.
+var placeholdersAntigos = regexp.MustCompile(`\{\{.*?\}\}|\{(data|nome_rotina)\}`)
+
+func converterPlaceholdersAntigos(texto string) string {
+	return placeholdersAntigos.ReplaceAllStringFunc(texto, func(trecho string) string {
+		switch trecho {
+		case "{data}":
+			return "{{.Data}}"
+		case "{nome_rotina}":
+			return "{{.NomeRotina}}"
+		}
+		return trecho
+	})
+}
+
+// renderizarModelo avalia um template de descrição ou tag com o contexto informado.
+func renderizarModelo(nome, texto string, contexto ContextoModelo) (string, error) {
+	tmpl, err := template.New(nome).Funcs(funcoesModelo).Option("missingkey=error").Parse(converterPlaceholdersAntigos(texto))
+	if err != nil {
+		return "", err
+	}
+	var saida bytes.Buffer
+	if err := tmpl.Execute(&saida, contexto); err != nil {
+		return "", err
+	}
+	return saida.String(), nil
+}
+
+// ValidarModeloTexto confere se texto é um template válido para a descrição ou as tags das tarefas:
+// a sintaxe, as funções e os campos usados, avaliando-o com um contexto de exemplo.
+func ValidarModeloTexto(texto string) error {
+	hoje := time.Now()
+	dia := time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.Local)
+	exemplo := novoContexto("Exemplo", dia)
+	exemplo.Bimestre = BimestreContexto{Nome: "1º Bimestre", Inicio: dia, Fim: dia.AddDate(0, 2, 0), Semana: 1}
+	exemplo.Aulas = []AulaContexto{{Disciplina: "Matemática", Turma: "7A", Topico: "Frações", Horario: "07:30"}}
+	exemplo.Turmas, exemplo.Disciplinas = []string{"7A"}, []string{"Matemática"}
+	_, err := renderizarModelo("modelo", texto, exemplo)
+	return err
+}
+
+// validarModelosTarefa valida a descrição e as tags de um modelo antes de salvá-lo.
+func validarModelosTarefa(descTarefa string, tags []string) error {
+	if err := ValidarModeloTexto(descTarefa); err != nil {
+		return fmt.Errorf("descrição modelo inválida: %w", err)
+	}
+	for _, tag := range tags {
+		if err := ValidarModeloTexto(tag); err != nil {
+			return fmt.Errorf("tag modelo '%s' inválida: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func novoContexto(nomeRotina string, dia time.Time) ContextoModelo {
+	_, semana := dia.ISOWeek()
+	return ContextoModelo{
+		NomeRotina:  nomeRotina,
+		Data:        dia.Format("2006-01-02"),
+		Dia:         dia,
+		DiaDaSemana: nomesDosDiasCompletos[dia.Weekday()],
+		Semana:      semana,
+		Mes:         nomesDosMeses[dia.Month()-1],
+		Ano:         dia.Year(),
+	}
+}
+
+// montarContexto reúne os dados de uma data base: o bimestre que a contém e as aulas do dia.
+func montarContexto(nomeRotina string, dataBase time.Time) (ContextoModelo, error) {
+	dia := time.Date(dataBase.Year(), dataBase.Month(), dataBase.Day(), 0, 0, 0, 0, time.Local)
+	contexto := novoContexto(nomeRotina, dia)
+	conn := db.GetDB()
+	if conn == nil {
+		return contexto, errors.New("banco de dados não inicializado")
+	}
+
+	terms, err := store.NewSQLiteTermStore(conn).ListTermsByYear(dia.Year())
+	if err != nil {
+		return contexto, fmt.Errorf("erro ao buscar o bimestre: %w", err)
+	}
+	for _, term := range terms {
+		// As datas do bimestre são datas de calendário, inclusivas.
+		inicio := time.Date(term.StartDate.Year(), term.StartDate.Month(), term.StartDate.Day(), 0, 0, 0, 0, time.Local)
+		fim := time.Date(term.EndDate.Year(), term.EndDate.Month(), term.EndDate.Day(), 0, 0, 0, 0, time.Local)
+		if !dia.Before(inicio) && !dia.After(fim) {
+			semana := int(dia.Sub(inicio).Hours()/24+0.5)/7 + 1
+			contexto.Bimestre = BimestreContexto{Nome: term.Name, Inicio: inicio, Fim: fim, Semana: semana}
+			break
+		}
+	}
+
+	// A consulta abrange um dia a mais de cada lado e o filtro pelo dia local é feito aqui,
+	// porque as datas das aulas são gravadas em UTC.
+	periodo := dia.AddDate(0, 0, -1).Format("02-01-2006") + ":" + dia.AddDate(0, 0, 1).Format("02-01-2006")
+	aulas, err := store.NewSQLiteAulaStore(conn).ListLessons("", "", periodo, "", "")
+	if err != nil {
+		return contexto, fmt.Errorf("erro ao buscar as aulas do dia: %w", err)
+	}
+	turmas, disciplinas := make(map[string]bool), make(map[string]bool)
+	for _, aula := range aulas {
+		inicio := aula.Date.In(time.Local)
+		if inicio.Format("2006-01-02") != contexto.Data {
+			continue
+		}
+		contexto.Aulas = append(contexto.Aulas, AulaContexto{Disciplina: aula.Subject, Turma: aula.ClassID, Topico: aula.Topic, Horario: inicio.Format("15:04")})
+		if !turmas[aula.ClassID] {
+			turmas[aula.ClassID] = true
+			contexto.Turmas = append(contexto.Turmas, aula.ClassID)
+		}
+		if !disciplinas[aula.Subject] {
+			disciplinas[aula.Subject] = true
+			contexto.Disciplinas = append(contexto.Disciplinas, aula.Subject)
+		}
+	}
+	return contexto, nil
+}
@@ -0,0 +1,128 @@
+package rotina
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+func TestParseTagsRotinaComTemplates(t *testing.T) {
+	got := parseTagsRotina(` rotina , {{juntar .Turmas ","}},{{.Mes}} `)
+	esperado := []string{"rotina", `{{juntar .Turmas ","}}`, "{{.Mes}}"}
+	if !reflect.DeepEqual(got, esperado) {
+		t.Errorf("parseTagsRotina: esperado %q, obtido %q", esperado, got)
+	}
+}
+
+func TestValidarModeloTexto(t *testing.T) {
+	validos := []string{
+		"Texto simples",
+		"Chamada {data} - {nome_rotina}",
+		"Entregar até {{somarDias .Dia 3 | dataBR}} ({{diaDaSemana (somarSemanas .Dia 1)}})",
+		`{{range .Aulas}}{{.Horario}} {{.Turma}};{{end}} {{juntar .Disciplinas ", "}}`,
+		"{{with .Bimestre.Nome}}{{.}} - semana {{$.Bimestre.Semana}}{{end}}",
+	}
+	for _, texto := range validos {
+		if err := ValidarModeloTexto(texto); err != nil {
+			t.Errorf("ValidarModeloTexto(%q) falhou: %v", texto, err)
+		}
+	}
+
+	invalidos := []string{
+		"{{.Data",               // Sintaxe.
+		"{{prazo .Dia}}",        // Função inexistente.
+		"{{.Turma}}",            // Campo inexistente.
+		"{{somarDias .Data 3}}", // .Data é texto, não data.
+		"{{range .Aulas}}{{.Sala}}{{end}}",
+	}
+	for _, texto := range invalidos {
+		if err := ValidarModeloTexto(texto); err == nil {
+			t.Errorf("ValidarModeloTexto(%q): esperado erro", texto)
+		}
+	}
+}
+
+func TestModelosValidadosAoSalvar(t *testing.T) {
+	LimparRotinasStore()
+	if _, err := CriarModeloRotina("Inválido", "manual", "Semana {{.Semana", 2, "", ""); err == nil || !strings.Contains(err.Error(), "descrição modelo inválida") {
+		t.Errorf("Esperado erro de descrição modelo inválida, obtido %v", err)
+	}
+	if _, err := CriarModeloRotina("Inválido", "manual", "Ok", 2, "{{.Turma}}", ""); err == nil || !strings.Contains(err.Error(), "tag modelo '{{.Turma}}' inválida") {
+		t.Errorf("Esperado erro de tag modelo inválida, obtido %v", err)
+	}
+
+	modelo, err := CriarModeloRotina("Válido", "manual", "Semana {{.Semana}}", 2, "", "")
+	if err != nil {
+		t.Fatalf("CriarModeloRotina falhou: %v", err)
+	}
+	if _, err := EditarModeloRotina(modelo.ID, "", "", "{{end}}", 0, "", ""); err == nil {
+		t.Errorf("EditarModeloRotina com descrição inválida: esperado erro")
+	}
+	if m, _ := GetModeloRotinaByID(modelo.ID); m.TaskDescription != "Semana {{.Semana}}" {
+		t.Errorf("Descrição não deveria mudar após erro de validação: %q", m.TaskDescription)
+	}
+}
+
+func TestGerarTarefasComTemplate(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+	conn := db.GetDB()
+	for _, tabela := range []string{"terms", "lessons"} {
+		if _, err := conn.Exec("DELETE FROM " + tabela); err != nil {
+			t.Fatalf("Falha ao limpar %s: %v", tabela, err)
+		}
+	}
+
+	_, err := store.NewSQLiteTermStore(conn).SaveTerm(models.Term{Name: "1º Bimestre",
+		StartDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("SaveTerm falhou: %v", err)
+	}
+	aulas := store.NewSQLiteAulaStore(conn)
+	// 12/03/2024 é uma terça-feira; a aula do dia seguinte não entra no contexto.
+	for _, aula := range []models.Lesson{
+		{Subject: "Matemática", ClassID: "7A", Date: time.Date(2024, 3, 12, 7, 30, 0, 0, time.Local)},
+		{Subject: "Ciências", ClassID: "8B", Date: time.Date(2024, 3, 12, 9, 0, 0, 0, time.Local)},
+		{Subject: "Matemática", ClassID: "8B", Date: time.Date(2024, 3, 12, 10, 0, 0, 0, time.Local)},
+		{Subject: "História", ClassID: "9C", Date: time.Date(2024, 3, 13, 7, 30, 0, 0, time.Local)},
+	} {
+		if _, err := aulas.SaveLesson(aula); err != nil {
+			t.Fatalf("SaveLesson falhou: %v", err)
+		}
+	}
+
+	modelo, err := CriarModeloRotina("Revisão", "manual",
+		`{{.NomeRotina}} de {{.DiaDaSemana}} ({data}), semana {{.Semana}}, {{.Bimestre.Nome}} semana {{.Bimestre.Semana}}: {{juntar .Disciplinas ", "}} - entregar até {{somarDiasUteis .Dia 3 | dataBR}}`,
+		2, `rotina,{{juntar .Turmas ","}},{{.Mes}}`, "")
+	if err != nil {
+		t.Fatalf("CriarModeloRotina falhou: %v", err)
+	}
+	tarefas, err := GerarTarefasFromModelo(modelo.ID, "2024-03-12")
+	if err != nil {
+		t.Fatalf("GerarTarefasFromModelo falhou: %v", err)
+	}
+	esperada := "Revisão de terça-feira (2024-03-12), semana 11, 1º Bimestre semana 2: Matemática, Ciências - entregar até 15/03/2024"
+	if tarefas[0].Description != esperada {
+		t.Errorf("Descrição inesperada:\nesperado %s\nobtido   %s", esperada, tarefas[0].Description)
+	}
+	if esperadas := []string{"rotina", "7A", "8B", "março"}; !reflect.DeepEqual(tarefas[0].Tags, esperadas) {
+		t.Errorf("Tags: esperado %q, obtido %q", esperadas, tarefas[0].Tags)
+	}
+
+	t.Run("Sem bimestre nem aulas", func(t *testing.T) {
+		modelo, _ := CriarModeloRotina("Férias", "manual", "{{.Bimestre.Nome}}|{{len .Aulas}}|{{.Mes}}", 2, "", "")
+		tarefas, err := GerarTarefasFromModelo(modelo.ID, "2024-07-15")
+		if err != nil {
+			t.Fatalf("GerarTarefasFromModelo falhou: %v", err)
+		}
+		if tarefas[0].Description != "|0|julho" {
+			t.Errorf("Descrição inesperada: %q", tarefas[0].Description)
+		}
+	})
+}
@@ -0,0 +1,316 @@
+package rotina
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/models"
+)
+
+// Além da tarefa principal (TaskDescription), um modelo de rotina pode gerar um pacote: tarefas adicionais
+// (models.RoutineTaskTemplate) e eventos (models.RoutineEventTemplate), todos criados na mesma transação.
+// Ex: "Fechamento do bimestre" gera "Lançar notas", "Imprimir boletins" (prazo +3u) e o evento
+// "Conselho de classe" (+7d 14:00, 2h).
+//
+// Prazos e inícios são deslocamentos relativos à data base da geração (já adiada para o dia letivo):
+//
+//	+Nd        N dias corridos (ex: "+2d"; "-1d" é a véspera; "0d" é a própria data base)
+//	+Ns        N semanas
+//	+Nu        N dias úteis, pulando fins de semana e dias não letivos (como somarDiasUteis)
+//	... HH:MM  horário, obrigatório no início dos eventos e não aceito no prazo das tarefas (ex: "+7d 14:00")
+//	HH:MM      horário na própria data base
+//
+// Na linha de comando, cada item do pacote é uma string com campos separados por "|" (um "|" dentro de {{...}}
+// pertence ao template):
+//
+//	--tarefa "descrição|prazo|prioridade|tags"    ex: "Imprimir boletins|+3u|1|boletim,{{.Bimestre.Nome}}"
+//	--evento "título|início|duração|local"        ex: "Conselho de classe|+7d 14:00|2h|Sala dos professores"
+//
+// Apenas o primeiro campo é obrigatório na tarefa; no evento, título e início são obrigatórios e a duração padrão é 1h.
+
+// duracaoPadraoEvento é a duração dos eventos do pacote sem duração informada na linha de comando.
+const duracaoPadraoEvento = "1h"
+
+// deslocamento é um prazo ou início relativo à data base.
+type deslocamento struct {
+	n       int
+	unidade byte // 'd' (dias), 's' (semanas) ou 'u' (dias úteis).
+	horario bool
+	hora    int
+	minuto  int
+}
+
+// parseDeslocamento interpreta um deslocamento ("+2d", "+1s", "+3u", "+7d 14:00", "14:00"). Vazio equivale a "0d".
+func parseDeslocamento(texto string) (deslocamento, error) {
+	d := deslocamento{unidade: 'd'}
+	campos := strings.Fields(strings.ToLower(texto))
+	if len(campos) > 2 {
+		return d, fmt.Errorf("deslocamento '%s' inválido", texto)
+	}
+	if len(campos) > 0 && strings.Contains(campos[len(campos)-1], ":") {
+		horario, err := time.Parse("15:04", campos[len(campos)-1])
+		if err != nil {
+			return d, fmt.Errorf("horário '%s' inválido; use HH:MM", campos[len(campos)-1])
+		}
+		d.horario, d.hora, d.minuto = true, horario.Hour(), horario.Minute()
+		campos = campos[:len(campos)-1]
+	}
+	if len(campos) == 0 {
+		return d, nil
+	}
+	if len(campos) > 1 {
+		return d, fmt.Errorf("deslocamento '%s' inválido", texto)
+	}
+	campo := campos[0]
+	if len(campo) < 2 || !strings.ContainsRune("dsu", rune(campo[len(campo)-1])) {
+		return d, fmt.Errorf("deslocamento '%s' inválido; use +Nd, +Ns ou +Nu", campo)
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(campo[:len(campo)-1], "+"))
+	if err != nil {
+		return d, fmt.Errorf("deslocamento '%s' inválido; use +Nd, +Ns ou +Nu", campo)
+	}
+	d.n, d.unidade = n, campo[len(campo)-1]
+	if d.unidade == 'u' && d.n < 0 {
+		return d, fmt.Errorf("deslocamento '%s' inválido: dias úteis não aceitam valores negativos", campo)
+	}
+	return d, nil
+}
+
+// aplicar calcula a data (e o horário, se houver) do deslocamento a partir de dia, à meia-noite local.
+func (d deslocamento) aplicar(dia time.Time) (time.Time, error) {
+	var err error
+	switch d.unidade {
+	case 's':
+		dia = dia.AddDate(0, 0, 7*d.n)
+	case 'u':
+		if dia, err = somarDiasUteis(dia, d.n); err != nil {
+			return time.Time{}, err
+		}
+	default:
+		dia = dia.AddDate(0, 0, d.n)
+	}
+	if d.horario {
+		dia = time.Date(dia.Year(), dia.Month(), dia.Day(), d.hora, d.minuto, 0, 0, time.Local)
+	}
+	return dia, nil
+}
+
+// dividirForaDeAcoes separa texto em sep, ignorando os separadores dentro de ações de template ({{...}}).
+// Os campos são devolvidos sem espaços nas bordas.
+func dividirForaDeAcoes(texto string, sep byte) []string {
+	var campos []string
+	inicio, profundidade := 0, 0
+	for i := 0; i < len(texto); i++ {
+		switch {
+		case strings.HasPrefix(texto[i:], "{{"):
+			profundidade++
+			i++
+		case strings.HasPrefix(texto[i:], "}}") && profundidade > 0:
+			profundidade--
+			i++
+		case texto[i] == sep && profundidade == 0:
+			campos = append(campos, strings.TrimSpace(texto[inicio:i]))
+			inicio = i + 1
+		}
+	}
+	return append(campos, strings.TrimSpace(texto[inicio:]))
+}
+
+// ParseModeloTarefa interpreta uma tarefa do pacote no formato "descrição|prazo|prioridade|tags" e a valida.
+func ParseModeloTarefa(spec string) (models.RoutineTaskTemplate, error) {
+	campos := dividirForaDeAcoes(spec, '|')
+	if len(campos) > 4 {
+		return models.RoutineTaskTemplate{}, fmt.Errorf("tarefa '%s' inválida: use \"descrição|prazo|prioridade|tags\"", spec)
+	}
+	campos = append(campos, make([]string, 4-len(campos))...)
+	item := models.RoutineTaskTemplate{Description: campos[0], DueOffset: campos[1], Tags: parseTagsRotina(campos[3])}
+	if campos[2] != "" {
+		prioridade, err := strconv.Atoi(campos[2])
+		if err != nil {
+			return models.RoutineTaskTemplate{}, fmt.Errorf("prioridade '%s' inválida na tarefa '%s'", campos[2], campos[0])
+		}
+		item.Priority = prioridade
+	}
+	if err := validarModeloTarefaPacote(item); err != nil {
+		return models.RoutineTaskTemplate{}, err
+	}
+	return item, nil
+}
+
+// ParseModeloEvento interpreta um evento do pacote no formato "título|início|duração|local" e o valida.
+func ParseModeloEvento(spec string) (models.RoutineEventTemplate, error) {
+	campos := dividirForaDeAcoes(spec, '|')
+	if len(campos) > 4 {
+		return models.RoutineEventTemplate{}, fmt.Errorf("evento '%s' inválido: use \"título|início|duração|local\"", spec)
+	}
+	campos = append(campos, make([]string, 4-len(campos))...)
+	item := models.RoutineEventTemplate{Title: campos[0], StartOffset: campos[1], Duration: campos[2], Location: campos[3]}
+	if item.Duration == "" {
+		item.Duration = duracaoPadraoEvento
+	}
+	if err := validarModeloEventoPacote(item); err != nil {
+		return models.RoutineEventTemplate{}, err
+	}
+	return item, nil
+}
+
+// validarModeloTarefaPacote confere a descrição, as tags e o prazo de uma tarefa do pacote.
+func validarModeloTarefaPacote(item models.RoutineTaskTemplate) error {
+	if strings.TrimSpace(item.Description) == "" {
+		return errors.New("a descrição das tarefas do pacote é obrigatória")
+	}
+	if err := validarModelosTarefa(item.Description, item.Tags); err != nil {
+		return fmt.Errorf("tarefa '%s': %w", item.Description, err)
+	}
+	if item.Priority < 0 {
+		return fmt.Errorf("tarefa '%s': prioridade %d inválida", item.Description, item.Priority)
+	}
+	prazo, err := parseDeslocamento(item.DueOffset)
+	if err != nil {
+		return fmt.Errorf("tarefa '%s': prazo inválido: %w", item.Description, err)
+	}
+	if prazo.horario {
+		return fmt.Errorf("tarefa '%s': o prazo de uma tarefa não aceita horário", item.Description)
+	}
+	return nil
+}
+
+// validarModeloEventoPacote confere os templates, o início e a duração de um evento do pacote.
+func validarModeloEventoPacote(item models.RoutineEventTemplate) error {
+	if strings.TrimSpace(item.Title) == "" {
+		return errors.New("o título dos eventos do pacote é obrigatório")
+	}
+	for _, campo := range []struct{ nome, texto string }{{"título", item.Title}, {"local", item.Location}, {"descrição", item.Description}} {
+		if err := ValidarModeloTexto(campo.texto); err != nil {
+			return fmt.Errorf("evento '%s': %s modelo inválido: %w", item.Title, campo.nome, err)
+		}
+	}
+	inicio, err := parseDeslocamento(item.StartOffset)
+	if err != nil {
+		return fmt.Errorf("evento '%s': início inválido: %w", item.Title, err)
+	}
+	if !inicio.horario {
+		return fmt.Errorf("evento '%s': informe o horário de início (ex: \"+7d 14:00\")", item.Title)
+	}
+	if duracao, err := time.ParseDuration(item.Duration); err != nil || duracao <= 0 {
+		return fmt.Errorf("evento '%s': duração '%s' inválida; use, por exemplo, 50m, 2h ou 1h30m", item.Title, item.Duration)
+	}
+	return nil
+}
+
+// DefinirPacoteModelo substitui as tarefas adicionais e os eventos gerados por um modelo de rotina.
+// Listas vazias removem o pacote, e o modelo volta a gerar apenas a tarefa principal.
+// Os itens são validados antes de salvar, como em ParseModeloTarefa e ParseModeloEvento.
+func DefinirPacoteModelo(id string, tarefas []models.RoutineTaskTemplate, eventos []models.RoutineEventTemplate) (models.Routine, error) {
+	modelo, err := GetModeloRotinaByID(id)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	for _, item := range tarefas {
+		if err := validarModeloTarefaPacote(item); err != nil {
+			return models.Routine{}, err
+		}
+	}
+	for _, item := range eventos {
+		if err := validarModeloEventoPacote(item); err != nil {
+			return models.Routine{}, err
+		}
+	}
+
+	modelo.TaskTemplates, modelo.EventTemplates = tarefas, eventos
+	modelo.UpdatedAt = time.Now()
+	if err := salvarModeloRotina(modelo); err != nil {
+		return models.Routine{}, err
+	}
+	return modelo, nil
+}
+
+// renderizarTags avalia as tags modelo. Uma tag modelo pode gerar várias tags (ex: {{juntar .Turmas ","}});
+// tags vazias são descartadas.
+func renderizarTags(tagsModelo []string, contexto ContextoModelo) ([]string, error) {
+	var tags []string
+	for _, tagModelo := range tagsModelo {
+		tag, err := renderizarModelo("tag", tagModelo, contexto)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar a tag '%s': %w", tagModelo, err)
+		}
+		for _, t := range strings.Split(tag, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// montarTarefaPacote gera, sem salvar, uma tarefa do modelo. As tags do item são somadas às TaskTags do modelo,
+// e a tarefa pertence ao projeto do modelo, se houver.
+func montarTarefaPacote(modelo models.Routine, item models.RoutineTaskTemplate, contexto ContextoModelo) (models.Task, error) {
+	descricao, err := renderizarModelo("descricao", item.Description, contexto)
+	if err != nil {
+		return models.Task{}, fmt.Errorf("erro ao gerar a descrição da tarefa: %w", err)
+	}
+	tags, err := renderizarTags(append(append([]string{}, modelo.TaskTags...), item.Tags...), contexto)
+	if err != nil {
+		return models.Task{}, err
+	}
+	var prazo time.Time
+	if item.DueOffset != "" {
+		d, err := parseDeslocamento(item.DueOffset)
+		if err != nil {
+			return models.Task{}, err
+		}
+		if prazo, err = d.aplicar(contexto.Dia); err != nil {
+			return models.Task{}, fmt.Errorf("erro ao calcular o prazo da tarefa: %w", err)
+		}
+		// Prazos de tarefas são datas, gravadas como em tarefa.CriarTarefa.
+		prazo = time.Date(prazo.Year(), prazo.Month(), prazo.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	prioridade := item.Priority
+	if prioridade <= 0 {
+		prioridade = modelo.TaskPriority
+	}
+	t, err := tarefa.MontarTarefa(descricao, prazo, prioridade, tags)
+	if err != nil {
+		return models.Task{}, err
+	}
+	t.ProjectID = modelo.ProjectID
+	return t, nil
+}
+
+// montarEventoPacote gera, sem salvar, um evento do modelo, que pertence ao projeto do modelo, se houver.
+func montarEventoPacote(modelo models.Routine, item models.RoutineEventTemplate, contexto ContextoModelo) (models.Event, error) {
+	evento := models.Event{ProjectID: modelo.ProjectID}
+	for _, campo := range []struct {
+		nome, texto string
+		destino     *string
+	}{{"título", item.Title, &evento.Title}, {"local", item.Location, &evento.Location}, {"descrição", item.Description, &evento.Description}} {
+		texto, err := renderizarModelo("evento", campo.texto, contexto)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("erro ao gerar o %s do evento: %w", campo.nome, err)
+		}
+		*campo.destino = texto
+	}
+	if strings.TrimSpace(evento.Title) == "" {
+		return models.Event{}, errors.New("o título do evento gerado está vazio")
+	}
+	d, err := parseDeslocamento(item.StartOffset)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if evento.StartTime, err = d.aplicar(contexto.Dia); err != nil {
+		return models.Event{}, fmt.Errorf("erro ao calcular o início do evento: %w", err)
+	}
+	duracao, err := time.ParseDuration(item.Duration)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("duração '%s' inválida: %w", item.Duration, err)
+	}
+	evento.EndTime = evento.StartTime.Add(duracao)
+	evento.CreatedAt = time.Now()
+	evento.UpdatedAt = evento.CreatedAt
+	return evento, nil
+}
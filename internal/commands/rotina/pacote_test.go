@@ -0,0 +1,211 @@
+package rotina
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+func TestParseDeslocamento(t *testing.T) {
+	// 12/03/2024 é uma terça-feira, sem dias não letivos no calendário.
+	dia := time.Date(2024, 3, 12, 0, 0, 0, 0, time.Local)
+	casos := []struct {
+		texto, esperado string
+	}{
+		{"", "2024-03-12 00:00"},
+		{"0d", "2024-03-12 00:00"},
+		{"+2d", "2024-03-14 00:00"},
+		{"2d", "2024-03-14 00:00"},
+		{"-1d", "2024-03-11 00:00"},
+		{"+1s", "2024-03-19 00:00"},
+		{"+3u", "2024-03-15 00:00"},
+		{"+4u", "2024-03-18 00:00"},
+		{"+7d 14:00", "2024-03-19 14:00"},
+		{"08:30", "2024-03-12 08:30"},
+	}
+	for _, c := range casos {
+		d, err := parseDeslocamento(c.texto)
+		if err != nil {
+			t.Errorf("parseDeslocamento(%q) falhou: %v", c.texto, err)
+			continue
+		}
+		obtido, err := d.aplicar(dia)
+		if err != nil {
+			t.Errorf("aplicar(%q) falhou: %v", c.texto, err)
+			continue
+		}
+		if got := obtido.Format(dateTimeLayoutRotina); got != c.esperado {
+			t.Errorf("%q: esperado %s, obtido %s", c.texto, c.esperado, got)
+		}
+	}
+
+	for _, texto := range []string{"+2x", "+d", "-1u", "+2d 25:00", "+1d +2d", "+1d 08:00 x"} {
+		if _, err := parseDeslocamento(texto); err == nil {
+			t.Errorf("parseDeslocamento(%q): esperado erro", texto)
+		}
+	}
+}
+
+func TestParseModelosDoPacote(t *testing.T) {
+	item, err := ParseModeloTarefa("Entregar até {{somarDias .Dia 3 | dataBR}}|+3u|1|boletim, {{.Mes}}")
+	if err != nil {
+		t.Fatalf("ParseModeloTarefa falhou: %v", err)
+	}
+	esperado := models.RoutineTaskTemplate{Description: "Entregar até {{somarDias .Dia 3 | dataBR}}", DueOffset: "+3u", Priority: 1, Tags: []string{"boletim", "{{.Mes}}"}}
+	if !reflect.DeepEqual(item, esperado) {
+		t.Errorf("ParseModeloTarefa: esperado %+v, obtido %+v", esperado, item)
+	}
+
+	evento, err := ParseModeloEvento("Conselho de classe|+7d 14:00||Sala dos professores")
+	if err != nil {
+		t.Fatalf("ParseModeloEvento falhou: %v", err)
+	}
+	if evento.Duration != duracaoPadraoEvento || evento.StartOffset != "+7d 14:00" || evento.Location != "Sala dos professores" {
+		t.Errorf("ParseModeloEvento inesperado: %+v", evento)
+	}
+
+	erros := []struct {
+		spec, trecho string
+		evento       bool
+	}{
+		{"|+1d", "descrição das tarefas do pacote é obrigatória", false},
+		{"Boletins|+1d 10:00", "não aceita horário", false},
+		{"Boletins|+1x", "prazo inválido", false},
+		{"Boletins||alta", "prioridade 'alta' inválida", false},
+		{"{{.Turma}}", "descrição modelo inválida", false},
+		{"a|b|c|d|e", "use \"descrição|prazo|prioridade|tags\"", false},
+		{"Conselho|+7d", "informe o horário de início", true},
+		{"Conselho|+7d 14:00|duas horas", "duração 'duas horas' inválida", true},
+		{"Conselho|+7d 14:00|-1h", "duração '-1h' inválida", true},
+		{"Conselho|+7d 14:00|1h|{{.Sala}}", "local modelo inválido", true},
+	}
+	for _, e := range erros {
+		var err error
+		if e.evento {
+			_, err = ParseModeloEvento(e.spec)
+		} else {
+			_, err = ParseModeloTarefa(e.spec)
+		}
+		if err == nil || !strings.Contains(err.Error(), e.trecho) {
+			t.Errorf("%q: esperado erro contendo %q, obtido %v", e.spec, e.trecho, err)
+		}
+	}
+}
+
+func TestGerarPacote(t *testing.T) {
+	LimparRotinasStore()
+	tarefa.LimparTarefasStore()
+	conn := db.GetDB()
+	for _, tabela := range []string{"events", "terms", "lessons", "non_school_days"} {
+		if _, err := conn.Exec("DELETE FROM " + tabela); err != nil {
+			t.Fatalf("Falha ao limpar %s: %v", tabela, err)
+		}
+	}
+
+	modelo, err := CriarModeloRotina("Fechamento", "manual", "Lançar notas ({data})", 2, "fechamento", "")
+	if err != nil {
+		t.Fatalf("CriarModeloRotina falhou: %v", err)
+	}
+	tarefas := []models.RoutineTaskTemplate{{Description: "Imprimir boletins", DueOffset: "+3u", Priority: 1, Tags: []string{"boletim"}}}
+	eventos := []models.RoutineEventTemplate{{Title: "Conselho de classe - {{.NomeRotina}}", StartOffset: "+7d 14:00", Duration: "2h", Location: "Sala 3"}}
+	if _, err := DefinirPacoteModelo(modelo.ID, tarefas, eventos); err != nil {
+		t.Fatalf("DefinirPacoteModelo falhou: %v", err)
+	}
+	if _, err := DefinirPacoteModelo(modelo.ID, []models.RoutineTaskTemplate{{Description: "Sem prazo", DueOffset: "amanhã"}}, nil); err == nil {
+		t.Errorf("DefinirPacoteModelo com prazo inválido: esperado erro")
+	}
+
+	// 12/03/2024 é uma terça-feira.
+	pacote, err := GerarPacoteFromModelo(modelo.ID, "2024-03-12", false)
+	if err != nil {
+		t.Fatalf("GerarPacoteFromModelo falhou: %v", err)
+	}
+	if len(pacote.Tarefas) != 2 || len(pacote.Eventos) != 1 {
+		t.Fatalf("Esperado 2 tarefas e 1 evento, obtido %+v", pacote)
+	}
+	principal, boletins := pacote.Tarefas[0], pacote.Tarefas[1]
+	if principal.Description != "Lançar notas (2024-03-12)" || !principal.DueDate.IsZero() || principal.Priority != 2 {
+		t.Errorf("Tarefa principal inesperada: %+v", principal)
+	}
+	if boletins.Priority != 1 || boletins.DueDate.Format("2006-01-02") != "2024-03-15" || !reflect.DeepEqual(boletins.Tags, []string{"fechamento", "boletim"}) {
+		t.Errorf("Tarefa adicional inesperada: %+v", boletins)
+	}
+	conselho := pacote.Eventos[0]
+	inicio := time.Date(2024, 3, 19, 14, 0, 0, 0, time.Local)
+	if conselho.Title != "Conselho de classe - Fechamento" || conselho.Location != "Sala 3" ||
+		!conselho.StartTime.Equal(inicio) || !conselho.EndTime.Equal(inicio.Add(2*time.Hour)) {
+		t.Errorf("Evento inesperado: %+v", conselho)
+	}
+	if salvo, err := db.GetEvent(conselho.ID); err != nil || salvo.Title != conselho.Title {
+		t.Errorf("Evento não foi salvo: %+v (%v)", salvo, err)
+	}
+	if !reflect.DeepEqual(pacote.Execucao.TaskIDs, []string{principal.ID, boletins.ID}) || !reflect.DeepEqual(pacote.Execucao.EventIDs, []string{conselho.ID}) {
+		t.Errorf("Execução não registrou o pacote: %+v", pacote.Execucao)
+	}
+	if e, _ := EstatisticasRotinas(); len(e) != 1 || e[0].TarefasGeradas != 2 || e[0].EventosGerados != 1 {
+		t.Errorf("Estatísticas inesperadas: %+v", e)
+	}
+
+	t.Run("Falha em um item não cria nada", func(t *testing.T) {
+		// Sem bimestre cadastrado, o título do evento fica vazio e a geração falha depois de montar as tarefas.
+		vazio := []models.RoutineEventTemplate{{Title: "{{.Bimestre.Nome}}", StartOffset: "09:00", Duration: "1h"}}
+		if _, err := DefinirPacoteModelo(modelo.ID, tarefas, vazio); err != nil {
+			t.Fatalf("DefinirPacoteModelo falhou: %v", err)
+		}
+		if _, err := GerarTarefasFromModelo(modelo.ID, "2024-03-13"); err == nil || !strings.Contains(err.Error(), "título do evento gerado está vazio") {
+			t.Fatalf("Esperado erro de título vazio, obtido %v", err)
+		}
+		if todas, _ := tarefa.ListarTarefas("", 0, "", "", "", ""); len(todas) != 2 {
+			t.Errorf("A geração que falhou não deveria criar tarefas; total %d", len(todas))
+		}
+		if execucoes, _ := HistoricoExecucoes(modelo.ID); len(execucoes) != 1 {
+			t.Errorf("A geração que falhou não deveria ser registrada; execuções: %d", len(execucoes))
+		}
+	})
+
+	t.Run("Limpar pacote", func(t *testing.T) {
+		m, err := DefinirPacoteModelo(modelo.ID, nil, nil)
+		if err != nil {
+			t.Fatalf("DefinirPacoteModelo falhou: %v", err)
+		}
+		if len(m.TaskTemplates) != 0 || len(m.EventTemplates) != 0 {
+			t.Errorf("Pacote deveria estar vazio: %+v", m)
+		}
+		geradas, err := GerarTarefasFromModelo(modelo.ID, "2024-03-14")
+		if err != nil || len(geradas) != 1 {
+			t.Errorf("Sem pacote, esperada apenas a tarefa principal: %+v (%v)", geradas, err)
+		}
+	})
+
+	t.Run("Itens gerados pertencem ao projeto do modelo", func(t *testing.T) {
+		projeto.LimparProjetosStore()
+		fechamento, err := projeto.CriarProjeto("Fechamento do bimestre", "", "")
+		if err != nil {
+			t.Fatalf("CriarProjeto falhou: %v", err)
+		}
+		if _, err := DefinirPacoteModelo(modelo.ID, tarefas, eventos); err != nil {
+			t.Fatalf("DefinirPacoteModelo falhou: %v", err)
+		}
+		if _, err := projeto.AtribuirRotina(modelo.ID, "fechamento do bimestre"); err != nil {
+			t.Fatalf("AtribuirRotina falhou: %v", err)
+		}
+		pacote, err := GerarPacoteFromModelo(modelo.ID, "2024-03-15", false)
+		if err != nil {
+			t.Fatalf("GerarPacoteFromModelo falhou: %v", err)
+		}
+		for _, gerada := range pacote.Tarefas {
+			if salva, _ := db.GetTask(gerada.ID); salva.ProjectID != fechamento.ID {
+				t.Errorf("Tarefa '%s' deveria pertencer ao projeto, obtido %q", gerada.Description, salva.ProjectID)
+			}
+		}
+		if salvo, _ := db.GetEvent(pacote.Eventos[0].ID); salvo.ProjectID != fechamento.ID {
+			t.Errorf("Evento deveria pertencer ao projeto, obtido %q", salvo.ProjectID)
+		}
+	})
+}
@@ -1,92 +1,96 @@
 package rotina
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
-	"sort"
+	"os"
 	"strings"
-	"sync"
 	"time"
 
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
-	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/commands/calendario"
 )
 
-// dateTimeLayoutRotina define o formato para parsing de data/hora para rotinas.
+// dateTimeLayoutRotina define o formato canônico de data/hora das rotinas.
 const dateTimeLayoutRotina = "2006-01-02 15:04"
 
-// rotinasStore é o banco de dados em memória para modelos de rotina.
-// Não exportado; acesso gerenciado pelas funções públicas.
-var (
-	rotinasStore = make(map[string]models.Routine)
-	nextRotinaID = 1
-	muRotinas    sync.Mutex // muRotinas protege o acesso concorrente ao rotinasStore e nextRotinaID.
-)
+// Os modelos de rotina são persistidos na tabela "routines" do banco SQLite através das funções
+// db.CreateRoutine, db.GetRoutine, db.ListRoutines, db.UpdateRoutine e db.DeleteRoutine.
+// O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
 
-// generateNewRotinaID gera um ID único para um novo modelo de rotina.
-// Não exportada, detalhe de implementação. Ex: "routine-1".
-func generateNewRotinaID() string {
-	id := fmt.Sprintf("routine-%d", nextRotinaID)
-	nextRotinaID++
-	return id
+// erroRotinaNaoEncontrada padroniza a mensagem de erro para IDs de modelo inexistentes.
+func erroRotinaNaoEncontrada(id string) error {
+	return fmt.Errorf("modelo de rotina com ID '%s' não encontrado", id)
 }
 
-// isValidFrequencyInternal valida o formato da string de frequência.
-// Esta é uma função auxiliar interna.
-// Exemplos válidos: "diaria", "semanal:seg,qua,sex", "mensal:15", "manual".
-func isValidFrequencyInternal(freq string) bool {
-	lowerFreq := strings.ToLower(freq)
-	if lowerFreq == "diaria" || lowerFreq == "manual" {
-		return true
-	}
-	parts := strings.Split(lowerFreq, ":")
-	if len(parts) == 2 {
-		freqType := parts[0]
-		freqValue := parts[1]
-		if (freqType == "semanal" || freqType == "mensal") && len(freqValue) > 0 {
-			// Validação mais detalhada (dias válidos, formato do dia do mês) pode ser adicionada aqui.
-			return true
-		}
+// parseTagsRotina converte uma string de tags separadas por vírgula em um slice, removendo espaços.
+// Vírgulas dentro de ações de template ({{...}}) não separam tags.
+// Retorna nil para uma string vazia.
+func parseTagsRotina(tagsStr string) []string {
+	trimmed := strings.TrimSpace(tagsStr)
+	if trimmed == "" {
+		return nil
+	}
+	return dividirForaDeAcoes(trimmed, ',')
+}
+
+// validarFrequencia interpreta a frequência com ParseFrequencia, padronizando a mensagem de erro.
+func validarFrequencia(freq string) (Frequencia, error) {
+	f, err := ParseFrequencia(freq)
+	if err != nil {
+		return Frequencia{}, fmt.Errorf("formato de frequência inválido: %w", err)
 	}
-	return false
+	return f, nil
 }
 
 // CriarModeloRotina adiciona um novo modelo de rotina ao sistema.
 // nome: Nome descritivo para o modelo.
-// frequencia: Define a recorrência ("diaria", "semanal:dias", "mensal:dia_do_mes", "manual").
-// descTarefa: Modelo para a descrição das tarefas geradas (pode usar placeholders como {nome_rotina}, {data}).
+// frequencia: Define a recorrência ("diaria", "semanal:seg,qua", "mensal:2a-terca", "cron:0 7 * * seg-sex", "manual"...;
+//             veja a gramática completa em frequencia.go).
+// descTarefa: Modelo para a descrição das tarefas geradas: um template do text/template com um ContextoModelo
+//             (ex: "Corrigir provas da semana {{.Semana}}"); os placeholders {nome_rotina} e {data} também são aceitos.
 // prioridadeTarefa: Prioridade padrão para tarefas geradas (1-Alta, 2-Média, 3-Baixa). Padrão 2 se <= 0.
-// tagsTarefaStr: String de tags separadas por vírgula para as tarefas geradas.
-// proximaExecucaoStr: Data/hora ("YYYY-MM-DD HH:MM") da primeira execução.
-//                     Se frequência não for "manual" e este campo for vazio, NextRunTime é time.Now().
+// tagsTarefaStr: String de tags separadas por vírgula para as tarefas geradas; cada tag também é um template.
+// proximaExecucaoStr: Data/hora local a partir da qual a rotina é executada, como expressão do pacote datas
+// ("YYYY-MM-DD HH:MM", "amanhã 7h", "segunda"); sem horário, vale a meia-noite.
+//                     Se frequência não for "manual" e este campo for vazio, usa time.Now().
+//                     NextRunTime é a primeira ocorrência da frequência a partir dessa data (ex: a próxima segunda
+//                     para "semanal:seg"); no cron, o horário também vem da expressão.
 // Retorna o modelo de rotina criado ou um erro de validação.
 func CriarModeloRotina(nome, frequencia, descTarefa string, prioridadeTarefa int, tagsTarefaStr string, proximaExecucaoStr string) (models.Routine, error) {
-	muRotinas.Lock()
-	defer muRotinas.Unlock()
-
 	if strings.TrimSpace(nome) == "" {
 		return models.Routine{}, errors.New("o nome do modelo de rotina é obrigatório")
 	}
-	if !isValidFrequencyInternal(frequencia) { // Alterado para função interna
-		return models.Routine{}, errors.New("formato de frequência inválido. Exemplos: 'diaria', 'semanal:seg,qua', 'mensal:1', 'manual'")
+	freq, err := validarFrequencia(frequencia)
+	if err != nil {
+		return models.Routine{}, err
 	}
 	if strings.TrimSpace(descTarefa) == "" {
 		return models.Routine{}, errors.New("a descrição modelo para tarefas é obrigatória")
 	}
+	tagsTarefa := parseTagsRotina(tagsTarefaStr)
+	if err := validarModelosTarefa(descTarefa, tagsTarefa); err != nil {
+		return models.Routine{}, err
+	}
 
 	var proximaExecucao time.Time
-	var err error
 	// Rotinas manuais não têm NextRunTime por padrão.
-	if strings.ToLower(frequencia) != "manual" {
+	if !freq.Manual() {
 		if proximaExecucaoStr == "" {
-			// Para rotinas automáticas, se não especificado, NextRunTime é agora.
-			// Um sistema de agendamento poderia refinar isso com base na frequência.
+			// Para rotinas automáticas, se não especificado, a primeira ocorrência a partir de agora.
 			proximaExecucao = time.Now()
 		} else {
-			proximaExecucao, err = time.Parse(dateTimeLayoutRotina, proximaExecucaoStr)
+			r, err := datas.DataHora(proximaExecucaoStr)
 			if err != nil {
-				return models.Routine{}, errors.New("formato de data/hora inválido para próxima execução. Use YYYY-MM-DD HH:MM")
+				return models.Routine{}, fmt.Errorf("formato de data/hora inválido para próxima execução: %w", err)
 			}
+			proximaExecucao = r.Momento
+		}
+		if proximaExecucao, err = freq.Primeira(proximaExecucao); err != nil {
+			return models.Routine{}, err
 		}
 	}
 
@@ -95,17 +99,8 @@ func CriarModeloRotina(nome, frequencia, descTarefa string, prioridadeTarefa int
 		prioridadeTarefa = 2 // Padrão: Média
 	}
 
-	var tagsTarefa []string
-	if strings.TrimSpace(tagsTarefaStr) != "" {
-		tagsTarefa = strings.Split(tagsTarefaStr, ",")
-		for i, tag := range tagsTarefa {
-			tagsTarefa[i] = strings.TrimSpace(tag)
-		}
-	}
-
 	now := time.Now()
 	novoModelo := models.Routine{
-		ID:                generateNewRotinaID(),
 		Name:              nome,
 		Description:       "", // Campo de descrição do modelo de rotina, pode ser adicionado como parâmetro.
 		Frequency:         frequencia,
@@ -117,52 +112,37 @@ func CriarModeloRotina(nome, frequencia, descTarefa string, prioridadeTarefa int
 		UpdatedAt:         now,
 	}
 
-	rotinasStore[novoModelo.ID] = novoModelo
+	id, err := db.CreateRoutine(novoModelo)
+	if err != nil {
+		return models.Routine{}, fmt.Errorf("erro ao salvar o modelo de rotina: %w", err)
+	}
+	novoModelo.ID = id
 	return novoModelo, nil
 }
 
 // ListarModelosRotina retorna uma lista de todos os modelos de rotina existentes.
 // sortBy: Campo para ordenação ("nome", "frequencia", "proxima_execucao"). Padrão: "nome".
 // sortOrder: Ordem ("asc", "desc"). Padrão: "asc".
-// Retorna uma lista de modelos ou um erro (atualmente sempre nil).
+// Retorna uma lista de modelos ou um erro de acesso ao banco.
 func ListarModelosRotina(sortBy string, sortOrder string) ([]models.Routine, error) {
-	muRotinas.Lock()
-	defer muRotinas.Unlock()
-
-	var result []models.Routine
-	for _, modelo := range rotinasStore {
-		result = append(result, modelo)
-	}
-
-	if sortBy == "" {
-		sortBy = "nome" // Padrão
+	var column string
+	switch strings.ToLower(sortBy) {
+	case "frequencia":
+		column = "frequency"
+	case "proxima_execucao":
+		column = "next_run_time" // Rotinas sem NextRunTime (ex: manuais) vêm depois.
+	default: // "nome" ou qualquer outro
+		column = "name"
 	}
 	if sortOrder == "" {
 		sortOrder = "asc" // Padrão
 	}
 
-	sort.SliceStable(result, func(i, j int) bool {
-		r1 := result[i]
-		r2 := result[j]
-		var less bool
-		switch strings.ToLower(sortBy) {
-		case "frequencia":
-			less = r1.Frequency < r2.Frequency
-		case "proxima_execucao":
-			// Rotinas com NextRunTime zero (ex: manuais) vêm depois.
-			if r1.NextRunTime.IsZero() { return false }
-			if r2.NextRunTime.IsZero() { return true }
-			less = r1.NextRunTime.Before(r2.NextRunTime)
-		default: // "nome" ou qualquer outro
-			less = r1.Name < r2.Name
-		}
-		if strings.ToLower(sortOrder) == "desc" {
-			return !less
-		}
-		return less
-	})
-
-	return result, nil
+	modelos, _, err := db.ListRoutines(nil, column, sortOrder, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar modelos de rotina: %w", err)
+	}
+	return modelos, nil
 }
 
 // EditarModeloRotina atualiza um modelo de rotina existente.
@@ -171,29 +151,30 @@ func ListarModelosRotina(sortBy string, sortOrder string) ([]models.Routine, err
 // exceto tagsTarefaStr que substitui as tags existentes (string vazia = sem tags).
 // novaProxExecStr: Se fornecida e a rotina não for manual, atualiza NextRunTime.
 //                  Se a frequência for alterada para "manual", NextRunTime é zerado.
+// Quando a frequência ou a próxima execução mudam, NextRunTime é alinhado à frequência, como em CriarModeloRotina.
 // Retorna o modelo atualizado ou um erro se não encontrado, validação falhar, ou nenhuma alteração for feita.
 func EditarModeloRotina(id, novoNome, novaFreq, novaDescTarefa string, novaPrioTarefa int, novasTagsTarefaStr, novaProxExecStr string) (models.Routine, error) {
-	muRotinas.Lock()
-	defer muRotinas.Unlock()
-
-	modelo, existe := rotinasStore[id]
-	if !existe {
-		return models.Routine{}, fmt.Errorf("modelo de rotina com ID '%s' não encontrado", id)
+	modelo, err := GetModeloRotinaByID(id)
+	if err != nil {
+		return models.Routine{}, err
 	}
 
 	updated := false
+	alinhar := false
 	if novoNome != "" {
 		modelo.Name = novoNome
 		updated = true
 	}
 	if novaFreq != "" {
-		if !isValidFrequencyInternal(novaFreq) { // Alterado para função interna
-			return models.Routine{}, errors.New("formato de frequência inválido")
+		freq, err := validarFrequencia(novaFreq)
+		if err != nil {
+			return models.Routine{}, err
 		}
 		modelo.Frequency = novaFreq
 		updated = true
+		alinhar = true
 		// Ajustar NextRunTime se a frequência mudar para/de manual.
-		if strings.ToLower(novaFreq) == "manual" {
+		if freq.Manual() {
 			modelo.NextRunTime = time.Time{} // Zera para manual
 		} else if modelo.NextRunTime.IsZero() && novaProxExecStr == "" {
 			// Se tornou automática, NextRunTime era zero e não foi fornecido novo, default para Now.
@@ -209,15 +190,7 @@ func EditarModeloRotina(id, novoNome, novaFreq, novaDescTarefa string, novaPrioT
 		updated = true
 	}
 	if novasTagsTarefaStr != "" { // Permitir limpar tags
-		var tags []string
-		trimmedTags := strings.TrimSpace(novasTagsTarefaStr)
-		if trimmedTags != "" {
-			tags = strings.Split(trimmedTags, ",")
-			for i, tag := range tags {
-				tags[i] = strings.TrimSpace(tag)
-			}
-		}
-		modelo.TaskTags = tags
+		modelo.TaskTags = parseTagsRotina(novasTagsTarefaStr)
 		updated = true
 	}
 
@@ -225,12 +198,13 @@ func EditarModeloRotina(id, novoNome, novaFreq, novaDescTarefa string, novaPrioT
         if strings.ToLower(modelo.Frequency) == "manual" {
             return models.Routine{}, errors.New("não é possível definir próxima execução para rotina manual")
         }
-        newNextRun, err := time.Parse(dateTimeLayoutRotina, novaProxExecStr)
+        newNextRun, err := datas.DataHora(novaProxExecStr)
         if err != nil {
-            return models.Routine{}, errors.New("formato de data/hora inválido para próxima execução")
+            return models.Routine{}, fmt.Errorf("formato de data/hora inválido para próxima execução: %w", err)
         }
-        modelo.NextRunTime = newNextRun
+        modelo.NextRunTime = newNextRun.Momento
         updated = true
+        alinhar = true
     } else if strings.ToLower(modelo.Frequency) == "manual" {
 		// Se frequência é manual (ou mudou para manual) e não foi fornecida nova data, garante NextRunTime zero.
 		if !modelo.NextRunTime.IsZero() {
@@ -243,25 +217,50 @@ func EditarModeloRotina(id, novoNome, novaFreq, novaDescTarefa string, novaPrioT
 	if !updated {
 		return models.Routine{}, errors.New("nenhuma alteração especificada")
 	}
+	if novaDescTarefa != "" || novasTagsTarefaStr != "" {
+		if err := validarModelosTarefa(modelo.TaskDescription, modelo.TaskTags); err != nil {
+			return models.Routine{}, err
+		}
+	}
+	if alinhar && !modelo.NextRunTime.IsZero() {
+		freq, err := validarFrequencia(modelo.Frequency)
+		if err != nil {
+			return models.Routine{}, err
+		}
+		// O banco devolve os horários em UTC; a frequência é calculada no fuso local.
+		if modelo.NextRunTime, err = freq.Primeira(modelo.NextRunTime.In(time.Local)); err != nil {
+			return models.Routine{}, err
+		}
+	}
 
 	modelo.UpdatedAt = time.Now()
-	rotinasStore[id] = modelo
+	if err := salvarModeloRotina(modelo); err != nil {
+		return models.Routine{}, err
+	}
 	return modelo, nil
 }
 
+// salvarModeloRotina persiste as alterações de um modelo de rotina existente.
+func salvarModeloRotina(modelo models.Routine) error {
+	if err := db.UpdateRoutine(modelo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroRotinaNaoEncontrada(modelo.ID)
+		}
+		return fmt.Errorf("erro ao salvar o modelo de rotina: %w", err)
+	}
+	return nil
+}
+
 // RemoverModeloRotina remove um modelo de rotina do sistema.
 // id: ID do modelo a ser removido.
 // Retorna um erro se o modelo não for encontrado.
 func RemoverModeloRotina(id string) error {
-	muRotinas.Lock()
-	defer muRotinas.Unlock()
-
-	_, existe := rotinasStore[id]
-	if !existe {
-		return fmt.Errorf("modelo de rotina com ID '%s' não encontrado", id)
+	if err := db.DeleteRoutine(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroRotinaNaoEncontrada(id)
+		}
+		return fmt.Errorf("erro ao remover o modelo de rotina: %w", err)
 	}
-
-	delete(rotinasStore, id)
 	return nil
 }
 
@@ -269,74 +268,129 @@ func RemoverModeloRotina(id string) error {
 // Função auxiliar, útil para testes ou acesso por outros pacotes.
 // Retorna o modelo encontrado ou um erro se não existir.
 func GetModeloRotinaByID(id string) (models.Routine, error) {
-    muRotinas.Lock()
-    defer muRotinas.Unlock()
-    modelo, existe := rotinasStore[id]
-    if !existe {
-        return models.Routine{}, fmt.Errorf("modelo de rotina com ID '%s' não encontrado", id)
-    }
-    return modelo, nil
+	modelo, err := db.GetRoutine(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Routine{}, erroRotinaNaoEncontrada(id)
+		}
+		return models.Routine{}, fmt.Errorf("erro ao buscar o modelo de rotina: %w", err)
+	}
+	return modelo, nil
 }
 
-// LimparRotinasStore remove todos os modelos de rotina do armazenamento.
+// LimparRotinasStore remove todos os modelos de rotina e o histórico de execuções do banco de dados.
 // Destinada primariamente para uso em testes.
 func LimparRotinasStore() {
-	muRotinas.Lock()
-	defer muRotinas.Unlock()
-	rotinasStore = make(map[string]models.Routine)
-	nextRotinaID = 1
+	conn := db.GetDB()
+	if conn == nil {
+		return
+	}
+	if _, err := conn.Exec("DELETE FROM routines"); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao limpar modelos de rotina: %v\n", err)
+	}
+	if _, err := conn.Exec("DELETE FROM routine_runs"); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao limpar o histórico de execuções de rotina: %v\n", err)
+	}
 }
 
-// GerarTarefasFromModelo cria tarefas com base em um modelo de rotina específico.
+// ErrOcorrenciaJaExecutada indica que o modelo já gerou as tarefas da data base pedida.
+// A geração é então ignorada, a menos que seja forçada com RegerarTarefasFromModelo.
+var ErrOcorrenciaJaExecutada = errors.New("ocorrência já executada")
+
+// PacoteGerado reúne o que uma execução de um modelo de rotina criou.
+type PacoteGerado struct {
+	Execucao models.RoutineRun // Execução registrada no histórico.
+	Tarefas  []models.Task     // A tarefa principal seguida das tarefas adicionais do pacote.
+	Eventos  []models.Event    // Eventos do pacote.
+}
+
+// GerarTarefasFromModelo cria as tarefas de um modelo de rotina: a tarefa principal e as tarefas adicionais do
+// pacote, junto com os eventos do pacote (veja pacote.go). Tudo é gravado em uma única transação.
 // modeloID: ID do modelo de rotina a ser usado.
-// dataBaseStr: Data base opcional ("YYYY-MM-DD") para substituir placeholders como {data}.
-//              Se vazia, usa a data atual.
-// Retorna uma lista de tarefas criadas (atualmente sempre uma) ou um erro.
-// A lógica de atualização de NextRunTime do modelo é simplificada e comentada,
-// pois um agendador mais complexo seria necessário para o cálculo correto.
+// dataBaseStr: Data base opcional (expressão do pacote datas, ex: "2024-03-15" ou "sexta") dos templates e dos prazos relativos (veja ContextoModelo).
+//              Se vazia, usa a data atual. Se a data cair em um dia não letivo do calendário
+//              escolar (feriado, recesso ou planejamento), é adiada para o próximo dia letivo.
+// Cada geração é registrada no histórico de execuções (models.RoutineRun) com a data base pedida, antes do
+// adiamento por dia não letivo. Se o modelo já gerou as tarefas dessa data, nada é criado e o erro
+// wrapa ErrOcorrenciaJaExecutada; para gerar de novo, use RegerarTarefasFromModelo.
+// Retorna as tarefas criadas (para os eventos, use GerarPacoteFromModelo) ou um erro.
+// NextRunTime do modelo não é alterado aqui: a geração manual não conta como execução agendada.
+// As execuções agendadas, que avançam o NextRunTime, são feitas por ExecutarPendentes.
 func GerarTarefasFromModelo(modeloID string, dataBaseStr string) ([]models.Task, error) {
-	muRotinas.Lock() // Lock para ler o modelo de rotina.
-	modelo, existe := rotinasStore[modeloID]
-	muRotinas.Unlock() // Desbloqueia imediatamente após a leitura.
+	pacote, err := GerarPacoteFromModelo(modeloID, dataBaseStr, false)
+	if err != nil {
+		return nil, err
+	}
+	return pacote.Tarefas, nil
+}
 
-	if !existe {
-		return nil, fmt.Errorf("modelo de rotina com ID '%s' não encontrado", modeloID)
+// RegerarTarefasFromModelo é como GerarTarefasFromModelo, mas gera as tarefas mesmo que a data base
+// já tenha sido executada (opção --forcar). A execução é registrada como forçada.
+func RegerarTarefasFromModelo(modeloID string, dataBaseStr string) ([]models.Task, error) {
+	pacote, err := GerarPacoteFromModelo(modeloID, dataBaseStr, true)
+	if err != nil {
+		return nil, err
+	}
+	return pacote.Tarefas, nil
+}
+
+// GerarPacoteFromModelo é como GerarTarefasFromModelo (ou RegerarTarefasFromModelo, com forcar), mas retorna
+// também os eventos criados e a execução registrada.
+// Se algum template ou prazo falhar, ou o banco recusar algum item, nada é criado.
+func GerarPacoteFromModelo(modeloID string, dataBaseStr string, forcar bool) (PacoteGerado, error) {
+	modelo, err := GetModeloRotinaByID(modeloID)
+	if err != nil {
+		return PacoteGerado{}, err
 	}
 
 	var dataBase time.Time
-	var err error
 	if dataBaseStr != "" {
-		dataBase, err = time.Parse("2006-01-02", dataBaseStr)
+		dataBase, err = datas.Data(dataBaseStr)
 		if err != nil {
-			return nil, fmt.Errorf("formato de data inválido para data base: %w", err)
+			return PacoteGerado{}, fmt.Errorf("formato de data inválido para data base: %w", err)
 		}
 	} else {
 		dataBase = time.Now()
 	}
-
-	// Substituir placeholders na descrição da tarefa.
-	taskDesc := modelo.TaskDescription
-	taskDesc = strings.ReplaceAll(taskDesc, "{data}", dataBase.Format("2006-01-02"))
-	taskDesc = strings.ReplaceAll(taskDesc, "{nome_rotina}", modelo.Name)
-	// Outros placeholders podem ser adicionados aqui.
-
-	tagsStr := strings.Join(modelo.TaskTags, ",")
-
-	// CriarTarefa lida com sua própria concorrência.
-	novaTarefa, err := tarefa.CriarTarefa(taskDesc, "", modelo.TaskPriority, tagsStr)
+	ocorrencia := dataBase.Format("2006-01-02")
+	anterior, err := db.GetLatestRoutineRun(modelo.ID, ocorrencia)
+	switch {
+	case err == nil && !forcar:
+		return PacoteGerado{}, fmt.Errorf("%w: o modelo '%s' já gerou as tarefas de %s em %s", ErrOcorrenciaJaExecutada,
+			modelo.Name, ocorrencia, anterior.CreatedAt.In(time.Local).Format("02/01/2006 15:04"))
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return PacoteGerado{}, fmt.Errorf("erro ao consultar o histórico de execuções: %w", err)
+	}
+	dataBase, err = calendario.ProximoDiaLetivo(dataBase)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao gerar tarefa a partir do modelo '%s': %w", modeloID, err)
+		return PacoteGerado{}, err
 	}
 
-	// A lógica de atualização de NextRunTime está comentada pois requer um agendador.
-	/*
-	if strings.ToLower(modelo.Frequency) != "manual" {
-		muRotinas.Lock()
-		// Lógica de cálculo do próximo NextRunTime (ex: modelo.NextRunTime.Add(24 * time.Hour))
-		// rotinasStore[modeloID] = modelo // Salvar atualização
-		muRotinas.Unlock()
+	// Avaliar os templates do pacote com os dados da data base.
+	contexto, err := montarContexto(modelo.Name, dataBase)
+	if err != nil {
+		return PacoteGerado{}, err
+	}
+	itens := append([]models.RoutineTaskTemplate{{Description: modelo.TaskDescription}}, modelo.TaskTemplates...)
+	pacote := PacoteGerado{Tarefas: make([]models.Task, 0, len(itens)), Eventos: make([]models.Event, 0, len(modelo.EventTemplates))}
+	for _, item := range itens {
+		t, err := montarTarefaPacote(modelo, item, contexto)
+		if err != nil {
+			return PacoteGerado{}, fmt.Errorf("falha ao gerar a tarefa '%s' a partir do modelo '%s': %w", item.Description, modeloID, err)
+		}
+		pacote.Tarefas = append(pacote.Tarefas, t)
+	}
+	for _, item := range modelo.EventTemplates {
+		e, err := montarEventoPacote(modelo, item, contexto)
+		if err != nil {
+			return PacoteGerado{}, fmt.Errorf("falha ao gerar o evento '%s' a partir do modelo '%s': %w", item.Title, modeloID, err)
+		}
+		pacote.Eventos = append(pacote.Eventos, e)
 	}
-	*/
 
-	return []models.Task{novaTarefa}, nil
+	execucao := models.RoutineRun{RoutineID: modelo.ID, OccurrenceDate: ocorrencia, Forced: forcar && anterior.ID != ""}
+	if pacote.Execucao, err = db.CreateRoutineRunWithItems(execucao, pacote.Tarefas, pacote.Eventos); err != nil {
+		return PacoteGerado{}, fmt.Errorf("falha ao salvar as tarefas geradas pelo modelo '%s': %w", modeloID, err)
+	}
+	return pacote, nil
 }
@@ -1,14 +1,25 @@
 package rotina
 
 import (
+	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/commands/calendario"
 	"vickgenda-cli/internal/commands/tarefa" // Needed for checking generated tasks
 )
 
+// TestMain inicializa um banco SQLite em memória compartilhado, usado pelas tarefas geradas.
+func TestMain(m *testing.M) {
+	db.InitTestDB("rotina")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
 // Helper to check if a slice of routines contains a routine with a specific ID
 func containsRoutine(routines []models.Routine, id string) bool {
 	for _, r := range routines {
@@ -60,7 +71,7 @@ func TestCriarModeloRotina(t *testing.T) {
         if modelo.NextRunTime.IsZero() {
             t.Errorf("Esperado NextRunTime não zero para rotina diária com data especificada")
         }
-        parsedNextRun, _ := time.Parse(dateTimeLayoutRotina, proxExec)
+        parsedNextRun, _ := time.ParseInLocation(dateTimeLayoutRotina, proxExec, time.Local)
         if !modelo.NextRunTime.Equal(parsedNextRun) {
              t.Errorf("Esperado NextRunTime %v, obtido %v", parsedNextRun, modelo.NextRunTime)
         }
@@ -247,9 +258,24 @@ func TestGerarTarefasFromModelo(t *testing.T) {
 	})
 
     t.Run("Formato de data base inválido para geração", func(t *testing.T) {
-		_, err := GerarTarefasFromModelo(modelo.ID, "15/03/2024")
+		_, err := GerarTarefasFromModelo(modelo.ID, "15/13/2024")
 		if err == nil || !strings.Contains(err.Error(), "formato de data inválido para data base") {
 			t.Errorf("Esperado erro para formato de data base inválido, obtido: %v", err)
 		}
 	})
+	t.Run("Data base em dia não letivo é adiada", func(t *testing.T) {
+		carnaval, err := calendario.AdicionarDiaNaoLetivo("Carnaval", "2024-02-12", "2024-02-13", "feriado")
+		if err != nil {
+			t.Fatalf("AdicionarDiaNaoLetivo falhou: %v", err)
+		}
+		defer calendario.RemoverDiaNaoLetivo(carnaval.ID)
+
+		tarefasGeradas, err := GerarTarefasFromModelo(modelo.ID, "2024-02-12")
+		if err != nil {
+			t.Fatalf("GerarTarefasFromModelo falhou: %v", err)
+		}
+		if esperado := "Tarefa de Rotina Geradora para 2024-02-14"; tarefasGeradas[0].Description != esperado {
+			t.Errorf("Esperado '%s', obtido '%s'", esperado, tarefasGeradas[0].Description)
+		}
+	})
 }
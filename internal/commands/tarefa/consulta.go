@@ -0,0 +1,339 @@
+package tarefa
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// A linguagem de consulta de tarefas combina termos separados por espaço, todos obrigatórios (E):
+//
+//	status:pendente prio:1 tag:provas prazo<sexta -tag:pessoal
+//
+// Cada termo é "campo", um operador e um valor. Os campos são:
+//   - status: pendente, andamento (ou "em andamento"), concluida e bloqueada (o status derivado das dependências),
+//     como exibidos na listagem: pendente e andamento não incluem as tarefas bloqueadas;
+//   - prio (ou prioridade): 1, 2 e 3, ou alta, media e baixa;
+//   - tag, projeto (nome ou ID) e pai (ID da tarefa pai);
+//   - prazo e criada: expressões do pacote datas ("sexta", "+3d", "15/08"); comparam apenas o dia;
+//   - desc (ou descricao): trecho da descrição. Uma palavra sem campo também procura na descrição;
+//   - aula, prova, turma, aluno e evento: ID da entidade vinculada à tarefa (veja VincularTarefa).
+//
+// O operador ":" (ou "=") testa igualdade; prio, prazo e criada aceitam também <, <=, > e >=.
+// Em status, prio, tag, projeto e nos vínculos, valores separados por vírgula são alternativas (OU): "status:pendente,andamento".
+// O valor "*" testa se o campo está preenchido: "prazo:*" (tem prazo), "pai:*" (é subtarefa).
+// Um "-" no início nega o termo: "-tag:pessoal", "-prazo:*" (sem prazo).
+// Aspas duplas agrupam valores com espaços: prazo<"fim do mês", "reunião de pais".
+
+// camposConsulta mapeia os nomes de campo aceitos, com sinônimos, para o nome canônico.
+var camposConsulta = map[string]string{
+	"status":     "status",
+	"prio":       "prio",
+	"prioridade": "prio",
+	"tag":        "tag",
+	"projeto":    "projeto",
+	"pai":        "pai",
+	"prazo":      "prazo",
+	"criada":     "criada",
+	"desc":       "desc",
+	"descricao":  "desc",
+	"descrição":  "desc",
+	"aula":       models.TaskLinkLesson,
+	"prova":      models.TaskLinkTest,
+	"turma":      models.TaskLinkClass,
+	"aluno":      models.TaskLinkStudent,
+	"evento":     models.TaskLinkEvent,
+}
+
+// camposComparaveis são os campos que aceitam <, <=, > e >=.
+var camposComparaveis = map[string]bool{"prio": true, "prazo": true, "criada": true}
+
+// camposComAlternativas são os campos em que vírgulas separam valores alternativos.
+var camposComAlternativas = map[string]bool{"status": true, "prio": true, "tag": true, "projeto": true,
+	models.TaskLinkLesson: true, models.TaskLinkTest: true, models.TaskLinkClass: true, models.TaskLinkStudent: true, models.TaskLinkEvent: true}
+
+// statusConsulta mapeia os status aceitos na consulta, sem acentos, para os status gravados.
+var statusConsulta = map[string]string{
+	"pendente":     models.TaskStatusPending,
+	"andamento":    models.TaskStatusInProgress,
+	"em andamento": models.TaskStatusInProgress,
+	"em-andamento": models.TaskStatusInProgress,
+	"concluida":    models.TaskStatusCompleted,
+	"bloqueada":    models.TaskStatusBlocked,
+}
+
+// condicaoPreRequisitoAberto testa se a tarefa tem algum pré-requisito não concluído (o argumento é o status concluído).
+const condicaoPreRequisitoAberto = `EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks p ON p.id = d.depends_on_id
+	WHERE d.task_id = tasks.id AND p.status != ?)`
+
+// prioridadesConsulta mapeia os nomes de prioridade aceitos na consulta para os números gravados.
+var prioridadesConsulta = map[string]int{"alta": 1, "media": 2, "média": 2, "baixa": 3}
+
+// reTermo separa um termo em campo, operador e valor. Termos sem campo reconhecível são buscas na descrição.
+var reTermo = regexp.MustCompile(`^([\p{L}]+)(<=|>=|:|=|<|>)(.*)$`)
+
+// termoConsulta é um termo já separado, antes de ser compilado.
+type termoConsulta struct {
+	texto    string // O termo como foi escrito, para as mensagens de erro.
+	negado   bool
+	campo    string // Nome canônico do campo.
+	operador string // ":", "<", "<=", ">" ou ">="; "=" é normalizado para ":".
+	valor    string
+}
+
+// Consulta é uma expressão da linguagem de consulta de tarefas compilada para SQL sobre a tabela tasks.
+type Consulta struct {
+	Expressao string
+	condicao  db.Condition
+}
+
+// Vazia informa se a consulta não tem termos, ou seja, se seleciona todas as tarefas.
+func (c Consulta) Vazia() bool {
+	return c.condicao.SQL == ""
+}
+
+// ParseConsulta interpreta e compila uma expressão da linguagem de consulta.
+// As datas relativas são calculadas com o relógio do pacote datas, e os projetos são procurados no banco.
+// Retorna um erro que aponta o termo inválido.
+func ParseConsulta(expr string) (Consulta, error) {
+	termos, err := separarTermos(expr)
+	if err != nil {
+		return Consulta{}, err
+	}
+	var clausulas []string
+	var args []interface{}
+	for _, t := range termos {
+		sql, a, err := compilarTermo(t)
+		if err != nil {
+			return Consulta{}, fmt.Errorf("termo '%s' inválido: %w", t.texto, err)
+		}
+		// IFNULL faz as comparações com colunas vazias (sem prazo, sem projeto) valerem falso, e não NULL,
+		// para que a negação as inclua: "-prazo<sexta" também lista as tarefas sem prazo.
+		clausula := "IFNULL((" + sql + "), 0)"
+		if t.negado {
+			clausula = "NOT " + clausula
+		}
+		clausulas = append(clausulas, clausula)
+		args = append(args, a...)
+	}
+	return Consulta{Expressao: strings.TrimSpace(expr), condicao: db.Condition{SQL: strings.Join(clausulas, " AND "), Args: args}}, nil
+}
+
+// separarTermos divide a expressão em termos, respeitando as aspas duplas.
+func separarTermos(expr string) ([]termoConsulta, error) {
+	var termos []termoConsulta
+	var atual strings.Builder
+	entreAspas, citadoInteiro := false, false
+	fechar := func() error {
+		texto := atual.String()
+		atual.Reset()
+		citado := citadoInteiro
+		citadoInteiro = false
+		if texto == "" {
+			return nil
+		}
+		t, err := separarTermo(texto, citado)
+		if err != nil {
+			return err
+		}
+		termos = append(termos, t)
+		return nil
+	}
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			if !entreAspas && atual.Len() == 0 {
+				citadoInteiro = true
+			}
+			entreAspas = !entreAspas
+		case unicode.IsSpace(r) && !entreAspas:
+			if err := fechar(); err != nil {
+				return nil, err
+			}
+		default:
+			atual.WriteRune(r)
+		}
+	}
+	if entreAspas {
+		return nil, fmt.Errorf("aspas não fechadas na consulta '%s'", expr)
+	}
+	if err := fechar(); err != nil {
+		return nil, err
+	}
+	return termos, nil
+}
+
+// separarTermo separa um termo em campo, operador e valor. Um termo inteiramente entre aspas
+// (ou sem campo) é uma busca na descrição.
+func separarTermo(texto string, citadoInteiro bool) (termoConsulta, error) {
+	t := termoConsulta{texto: texto}
+	corpo := texto
+	if strings.HasPrefix(corpo, "-") && len(corpo) > 1 {
+		t.negado = true
+		corpo = corpo[1:]
+	}
+	m := reTermo.FindStringSubmatch(corpo)
+	if citadoInteiro || m == nil {
+		t.campo, t.operador, t.valor = "desc", ":", corpo
+		return t, nil
+	}
+	campo, ok := camposConsulta[strings.ToLower(m[1])]
+	if !ok {
+		return termoConsulta{}, fmt.Errorf("campo '%s' desconhecido na consulta. Use status, prio, tag, projeto, pai, prazo, criada, desc, aula, prova, turma, aluno ou evento", m[1])
+	}
+	t.campo, t.operador, t.valor = campo, m[2], strings.TrimSpace(m[3])
+	if t.operador == "=" {
+		t.operador = ":"
+	}
+	if t.operador != ":" && !camposComparaveis[campo] {
+		return termoConsulta{}, fmt.Errorf("o campo '%s' não aceita o operador '%s' (termo '%s')", m[1], t.operador, texto)
+	}
+	if t.valor == "" {
+		return termoConsulta{}, fmt.Errorf("valor vazio no termo '%s'", texto)
+	}
+	return t, nil
+}
+
+// colunasConsulta são as colunas testadas por "campo:*".
+var colunasConsulta = map[string]string{"prazo": "due_date", "projeto": "project_id", "pai": "parent_id"}
+
+// compilarTermo traduz um termo para uma expressão SQL sobre a tabela tasks.
+func compilarTermo(t termoConsulta) (string, []interface{}, error) {
+	if t.valor == "*" && t.operador == ":" {
+		switch t.campo {
+		case "tag":
+			return "json_array_length(tasks.tags) > 0", nil, nil
+		case "prazo", "projeto", "pai":
+			return colunasConsulta[t.campo] + " IS NOT NULL", nil, nil
+		case models.TaskLinkLesson, models.TaskLinkTest, models.TaskLinkClass, models.TaskLinkStudent, models.TaskLinkEvent:
+			return "EXISTS (SELECT 1 FROM task_links l WHERE l.task_id = tasks.id AND l.entity_type = ?)", []interface{}{t.campo}, nil
+		}
+		return "", nil, fmt.Errorf("o campo não aceita o valor '*'")
+	}
+
+	valores := []string{t.valor}
+	if camposComAlternativas[t.campo] {
+		valores = strings.Split(t.valor, ",")
+	}
+	var alternativas []string
+	var args []interface{}
+	for _, v := range valores {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return "", nil, fmt.Errorf("valor vazio")
+		}
+		sql, a, err := compilarValor(t.campo, t.operador, v)
+		if err != nil {
+			return "", nil, err
+		}
+		alternativas = append(alternativas, sql)
+		args = append(args, a...)
+	}
+	if len(alternativas) == 1 {
+		return alternativas[0], args, nil
+	}
+	return "(" + strings.Join(alternativas, " OR ") + ")", args, nil
+}
+
+// operadorSQL traduz um operador da consulta para SQL.
+func operadorSQL(operador string) string {
+	if operador == ":" {
+		return "="
+	}
+	return operador
+}
+
+// compilarValor traduz um único valor de um termo para SQL.
+func compilarValor(campo, operador, valor string) (string, []interface{}, error) {
+	switch campo {
+	case "status":
+		status, ok := statusConsulta[strings.ToLower(semAcentosConsulta.Replace(valor))]
+		if !ok {
+			return "", nil, fmt.Errorf("status '%s' desconhecido. Use pendente, andamento, concluida ou bloqueada", valor)
+		}
+		switch status {
+		case models.TaskStatusBlocked:
+			// Mesma regra de CalcularBloqueios: não concluída, com algum pré-requisito não concluído.
+			return "status != ? AND " + condicaoPreRequisitoAberto, []interface{}{models.TaskStatusCompleted, models.TaskStatusCompleted}, nil
+		case models.TaskStatusCompleted:
+			return "status = ?", []interface{}{status}, nil
+		}
+		// Pendente e em andamento seguem o status exibido: as tarefas bloqueadas ficam de fora.
+		return "status = ? AND NOT " + condicaoPreRequisitoAberto, []interface{}{status, models.TaskStatusCompleted}, nil
+	case "prio":
+		prioridade, ok := prioridadesConsulta[strings.ToLower(valor)]
+		if !ok {
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 || n > 3 {
+				return "", nil, fmt.Errorf("prioridade '%s' inválida. Use 1, 2, 3, alta, media ou baixa", valor)
+			}
+			prioridade = n
+		}
+		return "priority " + operadorSQL(operador) + " ?", []interface{}{prioridade}, nil
+	case "tag":
+		return "EXISTS (SELECT 1 FROM json_each(tasks.tags) WHERE LOWER(json_each.value) = LOWER(?))", []interface{}{valor}, nil
+	case "projeto":
+		p, err := projeto.BuscarProjeto(valor)
+		if err != nil {
+			return "", nil, err
+		}
+		return "project_id = ?", []interface{}{p.ID}, nil
+	case "pai":
+		return "parent_id = ?", []interface{}{valor}, nil
+	case models.TaskLinkLesson, models.TaskLinkTest, models.TaskLinkClass, models.TaskLinkStudent, models.TaskLinkEvent:
+		return "EXISTS (SELECT 1 FROM task_links l WHERE l.task_id = tasks.id AND l.entity_type = ? AND l.entity_id = ?)",
+			[]interface{}{campo, valor}, nil
+	case "prazo", "criada":
+		dia, err := datas.Data(valor)
+		if err != nil {
+			return "", nil, err
+		}
+		coluna := "due_date"
+		if campo == "criada" {
+			coluna = "created_at"
+		}
+		// As datas são gravadas como "YYYY-MM-DD HH:MM:SS...-03:00"; os 10 primeiros caracteres são o dia civil
+		// no fuso em que foram gravadas, o que iguala prazos gravados à meia-noite local e à meia-noite UTC.
+		return "substr(" + coluna + ", 1, 10) " + operadorSQL(operador) + " ?", []interface{}{dia.Format("2006-01-02")}, nil
+	default: // "desc"
+		return `description LIKE ? ESCAPE '\'`, []interface{}{"%" + escaparLike.Replace(valor) + "%"}, nil
+	}
+}
+
+// escaparLike escapa os curingas do LIKE, para que "%" e "_" na consulta sejam procurados literalmente.
+var escaparLike = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// semAcentosConsulta remove os acentos dos status, para aceitar "concluída" e "concluida".
+var semAcentosConsulta = strings.NewReplacer("í", "i", "ú", "u")
+
+// BuscarTarefas lista as tarefas que satisfazem a consulta expr (veja ParseConsulta), ordenadas como em ListarTarefas.
+// Uma consulta vazia lista todas as tarefas.
+func BuscarTarefas(expr string, sortBy string, sortOrder string) ([]models.Task, error) {
+	consulta, err := ParseConsulta(expr)
+	if err != nil {
+		return nil, err
+	}
+	return consulta.Listar(sortBy, sortOrder)
+}
+
+// Listar devolve as tarefas que satisfazem a consulta, ordenadas como em ListarTarefas.
+func (c Consulta) Listar(sortBy string, sortOrder string) ([]models.Task, error) {
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+	tarefas, _, err := db.ListTasks(map[string]interface{}{"condition": c.condicao}, sortColumnTarefa(sortBy), sortOrder, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tarefas: %w", err)
+	}
+	if err := ordenarSeUrgencia(tarefas, sortBy, sortOrder); err != nil {
+		return nil, err
+	}
+	return tarefas, nil
+}
@@ -0,0 +1,151 @@
+package tarefa
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+func TestBuscarTarefas(t *testing.T) {
+	LimparTarefasStore()
+	projeto.LimparProjetosStore()
+	// Terça-feira; "sexta" é 18/10/2024.
+	defer datas.UsarRelogio(datas.Fixo(time.Date(2024, 10, 15, 9, 0, 0, 0, time.Local)))()
+
+	corrigir, _ := CriarTarefa("Corrigir provas 7B", "2024-10-16", 1, "provas")
+	imprimir, _ := CriarTarefa("Imprimir provas", "sexta", 1, "Provas,pessoal")
+	presente, _ := CriarTarefa("Comprar presente", "", 3, "pessoal")
+	lancar, _ := CriarTarefa("Lançar notas 100%", "2024-10-25", 2, "")
+	gabarito, _ := CriarSubtarefa(corrigir.ID, "Revisar gabarito", "", 2, "")
+	// As tarefas geradas por rotinas gravam o prazo à meia-noite UTC.
+	gerada := models.Task{Description: "Gerada pela rotina", Status: models.TaskStatusPending, Priority: 2,
+		DueDate: time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)}
+	gerada.ID, _ = db.CreateTask(gerada)
+
+	if _, err := EditarTarefa(presente.ID, "", "", 0, models.TaskStatusCompleted, ""); err != nil {
+		t.Fatalf("EditarTarefa falhou: %v", err)
+	}
+	if err := AdicionarDependencia(lancar.ID, corrigir.ID); err != nil {
+		t.Fatalf("AdicionarDependencia falhou: %v", err)
+	}
+	if _, err := projeto.CriarProjeto("Feira", "", ""); err != nil {
+		t.Fatalf("CriarProjeto falhou: %v", err)
+	}
+	if _, err := projeto.AtribuirTarefa(imprimir.ID, "feira"); err != nil {
+		t.Fatalf("AtribuirTarefa falhou: %v", err)
+	}
+	for _, v := range [][2]string{{corrigir.ID, "prova:prova123"}, {imprimir.ID, "prova:prova456"}, {gabarito.ID, "turma:7B"}} {
+		if _, err := VincularTarefa(v[0], v[1]); err != nil {
+			t.Fatalf("VincularTarefa falhou: %v", err)
+		}
+	}
+
+	nomes := map[string]string{corrigir.ID: "corrigir", imprimir.ID: "imprimir", presente.ID: "presente",
+		lancar.ID: "lancar", gabarito.ID: "gabarito", gerada.ID: "gerada"}
+	casos := []struct {
+		consulta string
+		esperado string // Nomes das tarefas, em ordem alfabética.
+	}{
+		{"status:pendente prio:1 tag:provas prazo<sexta -tag:pessoal", "corrigir"},
+		{"", "corrigir gabarito gerada imprimir lancar presente"},
+		{"prazo:sexta", "gerada imprimir"},
+		{"prazo<=sexta", "corrigir gerada imprimir"},
+		{"-prazo<sexta", "gabarito gerada imprimir lancar presente"},
+		{"-prazo:*", "gabarito presente"},
+		{"tag:PROVAS", "corrigir imprimir"},
+		{"tag:*", "corrigir imprimir presente"},
+		{"-tag:*", "gabarito gerada lancar"},
+		{"-tag:provas,pessoal", "gabarito gerada lancar"},
+		{"status:bloqueada", "lancar"},
+		{"status:pendente", "corrigir gabarito gerada imprimir"},
+		{"status:pendente,bloqueada", "corrigir gabarito gerada imprimir lancar"},
+		{"-status:pendente", "lancar presente"},
+		{"status:concluída", "presente"},
+		{`status:"em andamento",concluida`, "presente"},
+		{"prio:alta,baixa", "corrigir imprimir presente"},
+		{"prioridade>=2", "gabarito gerada lancar presente"},
+		{"pai:" + corrigir.ID, "gabarito"},
+		{"-pai:*", "corrigir gerada imprimir lancar presente"},
+		{"projeto:feira", "imprimir"},
+		{"-projeto:*", "corrigir gabarito gerada lancar presente"},
+		{"provas", "corrigir imprimir"},
+		{`"notas 100%"`, "lancar"},
+		{"%", "lancar"},
+		{"_", ""},
+		{`-desc:"provas 7B" provas`, "imprimir"},
+		{"prova:prova123", "corrigir"},
+		{"prova:prova123,prova456", "corrigir imprimir"},
+		{"-prova:* -turma:*", "gerada lancar presente"},
+		{"criada>ontem", "corrigir gabarito gerada imprimir lancar presente"},
+	}
+	for _, c := range casos {
+		tarefas, err := BuscarTarefas(c.consulta, "", "")
+		if err != nil {
+			t.Errorf("BuscarTarefas(%q) falhou: %v", c.consulta, err)
+			continue
+		}
+		var obtido []string
+		for _, tarefa := range tarefas {
+			obtido = append(obtido, nomes[tarefa.ID])
+		}
+		sort.Strings(obtido)
+		if strings.Join(obtido, " ") != c.esperado {
+			t.Errorf("BuscarTarefas(%q): esperado [%s], obtido [%s]", c.consulta, c.esperado, strings.Join(obtido, " "))
+		}
+	}
+
+	if tarefas, _ := BuscarTarefas("prio:1", "prazo", "desc"); len(tarefas) != 2 || tarefas[0].ID != imprimir.ID {
+		t.Errorf("BuscarTarefas deveria respeitar a ordenação: %+v", tarefas)
+	}
+}
+
+func TestParseConsultaInvalida(t *testing.T) {
+	casos := map[string]string{
+		"cor:azul":            "campo 'cor' desconhecido",
+		"status:arquivada":    "status 'arquivada' desconhecido",
+		"prio:4":              "prioridade '4' inválida",
+		"tag<provas":          "não aceita o operador '<'",
+		"tag:":                "valor vazio",
+		"tag:provas,":         "valor vazio",
+		"desc:*":              "não aceita o valor '*'",
+		"prazo:depois":        "termo 'prazo:depois' inválido",
+		`"reunião de pais`:    "aspas não fechadas",
+		"projeto:Inexistente": "não encontrado",
+	}
+	for consulta, esperado := range casos {
+		if _, err := ParseConsulta(consulta); err == nil || !strings.Contains(err.Error(), esperado) {
+			t.Errorf("ParseConsulta(%q): esperado erro com '%s', obtido %v", consulta, esperado, err)
+		}
+	}
+	if c, err := ParseConsulta("   "); err != nil || !c.Vazia() {
+		t.Errorf("Uma consulta em branco deveria ser vazia: %+v (%v)", c, err)
+	}
+}
+
+func TestAjustarTags(t *testing.T) {
+	LimparTarefasStore()
+	tarefa, _ := CriarTarefa("Preparar aula", "", 2, "aula,Provas")
+
+	tarefa, err := AjustarTags(tarefa.ID, []string{"urgente", "AULA", " "}, []string{"provas"})
+	if err != nil {
+		t.Fatalf("AjustarTags falhou: %v", err)
+	}
+	if strings.Join(tarefa.Tags, ",") != "aula,urgente" {
+		t.Errorf("Tags inesperadas: %v", tarefa.Tags)
+	}
+	if salva, _ := GetTarefaByID(tarefa.ID); strings.Join(salva.Tags, ",") != "aula,urgente" {
+		t.Errorf("Tags não foram salvas: %v", salva.Tags)
+	}
+	if _, err := AjustarTags(tarefa.ID, nil, []string{""}); err == nil {
+		t.Errorf("AjustarTags sem tags: esperado erro")
+	}
+	if _, err := AjustarTags("id-inexistente", []string{"x"}, nil); err == nil {
+		t.Errorf("AjustarTags de tarefa inexistente: esperado erro")
+	}
+}
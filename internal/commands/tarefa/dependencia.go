@@ -0,0 +1,238 @@
+package tarefa
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Uma tarefa pode depender de outras (pré-requisitos), gravadas na tabela "task_dependencies".
+// Enquanto algum pré-requisito não estiver concluído, a tarefa aparece como "Bloqueada" (models.TaskStatusBlocked);
+// esse status é derivado e nunca é gravado na tarefa.
+
+// AdicionarDependencia faz a tarefa id depender de preRequisitoID.
+// Retorna um erro se alguma das tarefas não existir, se a dependência já existir ou se ela criar um ciclo.
+func AdicionarDependencia(id, preRequisitoID string) error {
+	if _, err := GetTarefaByID(id); err != nil {
+		return err
+	}
+	if _, err := GetTarefaByID(preRequisitoID); err != nil {
+		return fmt.Errorf("pré-requisito inválido: %w", err)
+	}
+	if id == preRequisitoID {
+		return errors.New("uma tarefa não pode depender de si mesma")
+	}
+	deps, err := db.ListTaskDependencies("", "")
+	if err != nil {
+		return fmt.Errorf("erro ao carregar as dependências: %w", err)
+	}
+	for _, d := range deps {
+		if d.TaskID == id && d.DependsOnID == preRequisitoID {
+			return fmt.Errorf("a tarefa '%s' já depende de '%s'", id, preRequisitoID)
+		}
+	}
+	if caminho := caminhoDependencias(deps, preRequisitoID, id); caminho != nil {
+		return fmt.Errorf("a dependência criaria um ciclo: %s", descreverCiclo(append([]string{id}, caminho...)))
+	}
+	if err := db.AddTaskDependency(models.TaskDependency{TaskID: id, DependsOnID: preRequisitoID}); err != nil {
+		return fmt.Errorf("erro ao salvar a dependência: %w", err)
+	}
+	return nil
+}
+
+// caminhoDependencias procura, em largura, uma cadeia de dependências que leve de origem a destino
+// (origem depende de ... que depende de destino). Retorna os IDs do caminho, de origem a destino, ou nil.
+func caminhoDependencias(deps []models.TaskDependency, origem, destino string) []string {
+	preRequisitos := make(map[string][]string)
+	for _, d := range deps {
+		preRequisitos[d.TaskID] = append(preRequisitos[d.TaskID], d.DependsOnID)
+	}
+	anterior := map[string]string{origem: ""}
+	fila := []string{origem}
+	for len(fila) > 0 {
+		atual := fila[0]
+		fila = fila[1:]
+		if atual == destino {
+			var caminho []string
+			for id := destino; id != ""; id = anterior[id] {
+				caminho = append([]string{id}, caminho...)
+			}
+			return caminho
+		}
+		for _, proximo := range preRequisitos[atual] {
+			if _, visitado := anterior[proximo]; !visitado {
+				anterior[proximo] = atual
+				fila = append(fila, proximo)
+			}
+		}
+	}
+	return nil
+}
+
+// descreverCiclo formata uma cadeia de IDs com as descrições das tarefas, por exemplo "'A' → 'B' → 'A'".
+func descreverCiclo(ids []string) string {
+	partes := make([]string, len(ids))
+	for i, id := range ids {
+		partes[i] = fmt.Sprintf("'%s'", id)
+		if t, err := GetTarefaByID(id); err == nil {
+			partes[i] = fmt.Sprintf("'%s'", t.Description)
+		}
+	}
+	return strings.Join(partes, " → ")
+}
+
+// RemoverDependencia desfaz a dependência da tarefa id em preRequisitoID.
+func RemoverDependencia(id, preRequisitoID string) error {
+	if err := db.RemoveTaskDependency(id, preRequisitoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("a tarefa '%s' não depende de '%s'", id, preRequisitoID)
+		}
+		return fmt.Errorf("erro ao remover a dependência: %w", err)
+	}
+	return nil
+}
+
+// ListarPreRequisitos retorna as tarefas das quais a tarefa id depende, na ordem em que as dependências foram criadas.
+func ListarPreRequisitos(id string) ([]models.Task, error) {
+	if _, err := GetTarefaByID(id); err != nil {
+		return nil, err
+	}
+	deps, err := db.ListTaskDependencies(id, "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os pré-requisitos: %w", err)
+	}
+	ids := make([]string, len(deps))
+	for i, d := range deps {
+		ids[i] = d.DependsOnID
+	}
+	return buscarTarefas(ids)
+}
+
+// ListarDependentes retorna as tarefas que dependem da tarefa id.
+func ListarDependentes(id string) ([]models.Task, error) {
+	if _, err := GetTarefaByID(id); err != nil {
+		return nil, err
+	}
+	deps, err := db.ListTaskDependencies("", id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar as tarefas dependentes: %w", err)
+	}
+	ids := make([]string, len(deps))
+	for i, d := range deps {
+		ids[i] = d.TaskID
+	}
+	return buscarTarefas(ids)
+}
+
+// buscarTarefas carrega as tarefas pelos IDs, na mesma ordem.
+func buscarTarefas(ids []string) ([]models.Task, error) {
+	tarefas := make([]models.Task, 0, len(ids))
+	for _, id := range ids {
+		t, err := GetTarefaByID(id)
+		if err != nil {
+			return nil, err
+		}
+		tarefas = append(tarefas, t)
+	}
+	return tarefas, nil
+}
+
+// Bloqueios associa o ID de cada tarefa bloqueada (não concluída, com pré-requisitos abertos)
+// aos IDs dos pré-requisitos que ainda não foram concluídos.
+type Bloqueios map[string][]string
+
+// Bloqueada informa se a tarefa id está bloqueada.
+func (b Bloqueios) Bloqueada(id string) bool {
+	return len(b[id]) > 0
+}
+
+// StatusEfetivo devolve o status exibido para a tarefa: models.TaskStatusBlocked se ela estiver bloqueada,
+// ou o status gravado caso contrário.
+func (b Bloqueios) StatusEfetivo(t models.Task) string {
+	if t.Status != models.TaskStatusCompleted && b.Bloqueada(t.ID) {
+		return models.TaskStatusBlocked
+	}
+	return t.Status
+}
+
+// CalcularBloqueios determina as tarefas bloqueadas a partir das tarefas e das dependências fornecidas.
+// Pré-requisitos ausentes da lista de tarefas são ignorados.
+func CalcularBloqueios(tarefas []models.Task, deps []models.TaskDependency) Bloqueios {
+	status := make(map[string]string, len(tarefas))
+	for _, t := range tarefas {
+		status[t.ID] = t.Status
+	}
+	bloqueios := make(Bloqueios)
+	for _, d := range deps {
+		statusTarefa, ok := status[d.TaskID]
+		if !ok || statusTarefa == models.TaskStatusCompleted {
+			continue
+		}
+		if statusPre, ok := status[d.DependsOnID]; ok && statusPre != models.TaskStatusCompleted {
+			bloqueios[d.TaskID] = append(bloqueios[d.TaskID], d.DependsOnID)
+		}
+	}
+	return bloqueios
+}
+
+// CarregarBloqueios calcula os bloqueios de todas as tarefas do banco.
+func CarregarBloqueios() (Bloqueios, error) {
+	tarefas, _, err := db.ListTasks(nil, "created_at", "asc", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tarefas: %w", err)
+	}
+	deps, err := db.ListTaskDependencies("", "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar as dependências: %w", err)
+	}
+	return CalcularBloqueios(tarefas, deps), nil
+}
+
+// FiltrarProntas mantém apenas as tarefas prontas para serem feitas: não concluídas e sem pré-requisitos abertos.
+func FiltrarProntas(tarefas []models.Task, bloqueios Bloqueios) []models.Task {
+	var prontas []models.Task
+	for _, t := range tarefas {
+		if t.Status != models.TaskStatusCompleted && !bloqueios.Bloqueada(t.ID) {
+			prontas = append(prontas, t)
+		}
+	}
+	return prontas
+}
+
+// tarefasDesbloqueadas retorna as tarefas que dependem de id e que, com id concluída,
+// não têm mais pré-requisitos abertos. Chamada logo após a conclusão de id.
+func tarefasDesbloqueadas(id string) ([]models.Task, error) {
+	dependentes, err := db.ListTaskDependencies("", id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar as tarefas dependentes: %w", err)
+	}
+	var desbloqueadas []models.Task
+	for _, d := range dependentes {
+		t, err := GetTarefaByID(d.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status == models.TaskStatusCompleted {
+			continue
+		}
+		preRequisitos, err := ListarPreRequisitos(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		livre := true
+		for _, p := range preRequisitos {
+			if p.Status != models.TaskStatusCompleted {
+				livre = false
+				break
+			}
+		}
+		if livre {
+			desbloqueadas = append(desbloqueadas, t)
+		}
+	}
+	return desbloqueadas, nil
+}
@@ -0,0 +1,114 @@
+package tarefa
+
+import (
+	"strings"
+	"testing"
+
+	"vickgenda-cli/internal/models"
+)
+
+func TestDependenciasEBloqueio(t *testing.T) {
+	LimparTarefasStore()
+	corrigir, _ := CriarTarefa("Corrigir provas 7B", "", 1, "")
+	revisar, _ := CriarTarefa("Revisar gabarito", "", 2, "")
+	lancar, _ := CriarTarefa("Lançar notas", "", 1, "")
+	boletim, _ := CriarTarefa("Imprimir boletim", "", 3, "")
+
+	for _, d := range [][2]string{{lancar.ID, corrigir.ID}, {lancar.ID, revisar.ID}, {boletim.ID, lancar.ID}} {
+		if err := AdicionarDependencia(d[0], d[1]); err != nil {
+			t.Fatalf("AdicionarDependencia(%s, %s) falhou: %v", d[0], d[1], err)
+		}
+	}
+
+	t.Run("Dependências inválidas", func(t *testing.T) {
+		if err := AdicionarDependencia(corrigir.ID, boletim.ID); err == nil || !strings.Contains(err.Error(), "'Corrigir provas 7B' → 'Imprimir boletim' → 'Lançar notas' → 'Corrigir provas 7B'") {
+			t.Errorf("Esperado erro de ciclo com o caminho, obtido %v", err)
+		}
+		if err := AdicionarDependencia(lancar.ID, lancar.ID); err == nil {
+			t.Errorf("Uma tarefa não deveria depender de si mesma")
+		}
+		if err := AdicionarDependencia(lancar.ID, corrigir.ID); err == nil || !strings.Contains(err.Error(), "já depende") {
+			t.Errorf("Esperado erro de dependência repetida, obtido %v", err)
+		}
+		if err := AdicionarDependencia(lancar.ID, "id-inexistente"); err == nil {
+			t.Errorf("Esperado erro para pré-requisito inexistente")
+		}
+	})
+
+	bloqueios, err := CarregarBloqueios()
+	if err != nil {
+		t.Fatalf("CarregarBloqueios falhou: %v", err)
+	}
+	if bloqueios.StatusEfetivo(lancar) != models.TaskStatusBlocked || bloqueios.StatusEfetivo(boletim) != models.TaskStatusBlocked {
+		t.Errorf("'Lançar notas' e 'Imprimir boletim' deveriam estar bloqueadas: %v", bloqueios)
+	}
+	todas, _ := ListarTarefas("", 0, "", "", "", "")
+	prontas := FiltrarProntas(todas, bloqueios)
+	if len(prontas) != 2 || !containsTask(prontas, corrigir.ID) || !containsTask(prontas, revisar.ID) {
+		t.Errorf("Esperadas como prontas apenas as tarefas sem pré-requisitos, obtido %+v", prontas)
+	}
+	if bloqueadas, _ := ListarTarefas("bloqueada", 0, "", "", "", ""); len(bloqueadas) != 2 {
+		t.Errorf("Filtro de status 'Bloqueada': esperado 2 tarefas, obtido %d", len(bloqueadas))
+	}
+	if _, err := EditarTarefa(corrigir.ID, "", "", 0, "Bloqueada", ""); err == nil {
+		t.Errorf("O status 'Bloqueada' não deveria ser definido manualmente")
+	}
+
+	t.Run("Concluir informa as tarefas desbloqueadas", func(t *testing.T) {
+		conclusao, err := ConcluirTarefaComAvisos(corrigir.ID)
+		if err != nil {
+			t.Fatalf("ConcluirTarefaComAvisos falhou: %v", err)
+		}
+		if len(conclusao.Desbloqueadas) != 0 {
+			t.Errorf("'Lançar notas' ainda depende de 'Revisar gabarito': %+v", conclusao.Desbloqueadas)
+		}
+		conclusao, err = ConcluirTarefaComAvisos(revisar.ID)
+		if err != nil {
+			t.Fatalf("ConcluirTarefaComAvisos falhou: %v", err)
+		}
+		if len(conclusao.Desbloqueadas) != 1 || conclusao.Desbloqueadas[0].ID != lancar.ID {
+			t.Errorf("Esperado 'Lançar notas' desbloqueada, obtido %+v", conclusao.Desbloqueadas)
+		}
+	})
+
+	t.Run("Remover dependência e tarefa", func(t *testing.T) {
+		if err := RemoverDependencia(boletim.ID, lancar.ID); err != nil {
+			t.Fatalf("RemoverDependencia falhou: %v", err)
+		}
+		if err := RemoverDependencia(boletim.ID, lancar.ID); err == nil {
+			t.Errorf("Esperado erro ao remover dependência inexistente")
+		}
+		if err := RemoverTarefa(revisar.ID); err != nil {
+			t.Fatalf("RemoverTarefa falhou: %v", err)
+		}
+		pre, err := ListarPreRequisitos(lancar.ID)
+		if err != nil || len(pre) != 1 || pre[0].ID != corrigir.ID {
+			t.Errorf("A dependência da tarefa removida deveria sumir: %+v (%v)", pre, err)
+		}
+		if dependentes, _ := ListarDependentes(corrigir.ID); len(dependentes) != 1 || dependentes[0].ID != lancar.ID {
+			t.Errorf("Dependentes inesperados: %+v", dependentes)
+		}
+	})
+}
+
+func TestCalcularBloqueios(t *testing.T) {
+	tarefas := []models.Task{
+		{ID: "a", Status: models.TaskStatusPending},
+		{ID: "b", Status: models.TaskStatusCompleted},
+		{ID: "c", Status: models.TaskStatusPending},
+		{ID: "d", Status: models.TaskStatusCompleted},
+	}
+	deps := []models.TaskDependency{
+		{TaskID: "c", DependsOnID: "a"},
+		{TaskID: "c", DependsOnID: "b"},
+		{TaskID: "c", DependsOnID: "fora-da-lista"},
+		{TaskID: "d", DependsOnID: "a"}, // Já concluída: não fica bloqueada.
+	}
+	bloqueios := CalcularBloqueios(tarefas, deps)
+	if len(bloqueios) != 1 || len(bloqueios["c"]) != 1 || bloqueios["c"][0] != "a" {
+		t.Errorf("Bloqueios inesperados: %v", bloqueios)
+	}
+	if bloqueios.StatusEfetivo(tarefas[3]) != models.TaskStatusCompleted {
+		t.Errorf("Tarefa concluída deveria manter o status")
+	}
+}
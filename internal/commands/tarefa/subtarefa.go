@@ -0,0 +1,158 @@
+package tarefa
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// Uma tarefa com ParentID preenchido é uma subtarefa (item de checklist) de outra tarefa.
+// O progresso de uma tarefa pai é derivado das subtarefas diretas: quantas estão concluídas do total.
+
+// Progresso resume a situação das subtarefas diretas de uma tarefa.
+type Progresso struct {
+	Concluidas int
+	Total      int
+}
+
+// String formata o progresso como "concluídas/total", por exemplo "3/10".
+func (p Progresso) String() string {
+	return fmt.Sprintf("%d/%d", p.Concluidas, p.Total)
+}
+
+// CriarSubtarefa cria uma tarefa como subtarefa de paiID, com as mesmas regras de CriarTarefa,
+// no mesmo projeto da tarefa pai. Retorna um erro se a tarefa pai não existir.
+func CriarSubtarefa(paiID string, description string, dueDateStr string, priority int, tagsStr string) (models.Task, error) {
+	pai, err := GetTarefaByID(paiID)
+	if err != nil {
+		return models.Task{}, fmt.Errorf("tarefa pai inválida: %w", err)
+	}
+	return criarTarefa(pai, description, dueDateStr, priority, tagsStr)
+}
+
+// MoverTarefa define a tarefa pai de uma tarefa existente. Com novoPaiID vazio, a tarefa passa ao primeiro nível.
+// Retorna um erro se alguma das tarefas não existir ou se a mudança criar um ciclo
+// (a nova tarefa pai não pode ser a própria tarefa nem uma de suas subtarefas).
+func MoverTarefa(id string, novoPaiID string) (models.Task, error) {
+	tarefa, err := GetTarefaByID(id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if novoPaiID != "" {
+		if err := validarNovoPai(id, novoPaiID); err != nil {
+			return models.Task{}, err
+		}
+	}
+	tarefa.ParentID = novoPaiID
+	tarefa.UpdatedAt = time.Now()
+	if err := salvarTarefa(tarefa); err != nil {
+		return models.Task{}, err
+	}
+	return tarefa, nil
+}
+
+// validarNovoPai sobe a cadeia de tarefas pai a partir de novoPaiID e falha se encontrar id.
+func validarNovoPai(id, novoPaiID string) error {
+	visitados := map[string]bool{}
+	for atual := novoPaiID; atual != ""; {
+		if atual == id {
+			return errors.New("uma tarefa não pode ser subtarefa de si mesma nem de uma de suas subtarefas")
+		}
+		if visitados[atual] {
+			break // Ciclo já existente no banco; não deve acontecer, mas evita laço infinito.
+		}
+		visitados[atual] = true
+		pai, err := GetTarefaByID(atual)
+		if err != nil {
+			return fmt.Errorf("tarefa pai inválida: %w", err)
+		}
+		atual = pai.ParentID
+	}
+	return nil
+}
+
+// ListarSubtarefas retorna as subtarefas diretas de uma tarefa, na ordem de criação.
+func ListarSubtarefas(id string) ([]models.Task, error) {
+	subtarefas, _, err := db.ListTasks(map[string]interface{}{"parent_id": id}, "created_at", "asc", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar subtarefas: %w", err)
+	}
+	return subtarefas, nil
+}
+
+// ProgressoTarefa calcula o progresso das subtarefas diretas de uma tarefa.
+// Uma tarefa sem subtarefas tem progresso 0/0.
+func ProgressoTarefa(id string) (Progresso, error) {
+	if _, err := GetTarefaByID(id); err != nil {
+		return Progresso{}, err
+	}
+	subtarefas, err := ListarSubtarefas(id)
+	if err != nil {
+		return Progresso{}, err
+	}
+	return CalcularProgresso(subtarefas)[id], nil
+}
+
+// CalcularProgresso agrupa as tarefas fornecidas pela tarefa pai e conta as concluídas.
+// O mapa resultante só contém as tarefas pai que têm ao menos uma subtarefa na lista.
+func CalcularProgresso(tarefas []models.Task) map[string]Progresso {
+	progresso := make(map[string]Progresso)
+	for _, t := range tarefas {
+		if t.ParentID == "" {
+			continue
+		}
+		p := progresso[t.ParentID]
+		p.Total++
+		if t.Status == models.TaskStatusCompleted {
+			p.Concluidas++
+		}
+		progresso[t.ParentID] = p
+	}
+	return progresso
+}
+
+// NoTarefa é um nó da árvore de tarefas montada por MontarArvore.
+type NoTarefa struct {
+	Tarefa     models.Task
+	Subtarefas []*NoTarefa
+}
+
+// MontarArvore organiza as tarefas em hierarquia, preservando a ordem da lista em cada nível.
+// Tarefas cuja tarefa pai não está na lista (por exemplo, excluída por um filtro) aparecem como raízes.
+func MontarArvore(tarefas []models.Task) []*NoTarefa {
+	nos := make(map[string]*NoTarefa, len(tarefas))
+	for _, t := range tarefas {
+		nos[t.ID] = &NoTarefa{Tarefa: t}
+	}
+	var raizes []*NoTarefa
+	for _, t := range tarefas {
+		no := nos[t.ID]
+		if pai, ok := nos[t.ParentID]; ok && !ehAncestral(nos, t.ID, t.ParentID) {
+			pai.Subtarefas = append(pai.Subtarefas, no)
+			continue
+		}
+		raizes = append(raizes, no)
+	}
+	return raizes
+}
+
+// ehAncestral informa se id aparece na cadeia de tarefas pai de paiID dentro de nos,
+// o que indicaria um ciclo; nesse caso a tarefa é tratada como raiz.
+func ehAncestral(nos map[string]*NoTarefa, id, paiID string) bool {
+	visitados := map[string]bool{}
+	for atual := paiID; atual != ""; {
+		if atual == id {
+			return true
+		}
+		no, ok := nos[atual]
+		if !ok || visitados[atual] {
+			return false
+		}
+		visitados[atual] = true
+		atual = no.Tarefa.ParentID
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package tarefa
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"vickgenda-cli/internal/models"
+)
+
+func TestSubtarefasEProgresso(t *testing.T) {
+	LimparTarefasStore()
+	feira, _ := CriarTarefa("Preparar feira de ciências", "2024-10-20", 1, "feira")
+	var passos []models.Task
+	for _, desc := range []string{"Reservar o pátio", "Montar estandes", "Convidar os pais"} {
+		passo, err := CriarSubtarefa(feira.ID, desc, "", 0, "")
+		if err != nil {
+			t.Fatalf("CriarSubtarefa falhou: %v", err)
+		}
+		if passo.ParentID != feira.ID {
+			t.Errorf("Esperado pai '%s', obtido '%s'", feira.ID, passo.ParentID)
+		}
+		passos = append(passos, passo)
+	}
+	if _, err := CriarSubtarefa("id-inexistente", "Órfã", "", 0, ""); err == nil || !strings.Contains(err.Error(), "tarefa pai inválida") {
+		t.Errorf("Esperado erro de tarefa pai inválida, obtido %v", err)
+	}
+
+	ConcluirTarefa(passos[0].ID)
+	p, err := ProgressoTarefa(feira.ID)
+	if err != nil {
+		t.Fatalf("ProgressoTarefa falhou: %v", err)
+	}
+	if p.String() != "1/3" {
+		t.Errorf("Esperado progresso 1/3, obtido %s", p)
+	}
+	if p, _ := ProgressoTarefa(passos[1].ID); p.Total != 0 {
+		t.Errorf("Tarefa sem subtarefas deveria ter progresso 0/0, obtido %s", p)
+	}
+
+	t.Run("Concluir pai com subtarefas abertas avisa", func(t *testing.T) {
+		conclusao, err := ConcluirTarefaComAvisos(feira.ID)
+		if err != nil {
+			t.Fatalf("ConcluirTarefaComAvisos falhou: %v", err)
+		}
+		if conclusao.Tarefa.Status != models.TaskStatusCompleted {
+			t.Errorf("A tarefa pai deveria ser concluída mesmo com subtarefas abertas")
+		}
+		if len(conclusao.SubtarefasAbertas) != 2 || conclusao.SubtarefasAbertas[0].ID != passos[1].ID {
+			t.Errorf("Esperadas 2 subtarefas abertas, obtido %+v", conclusao.SubtarefasAbertas)
+		}
+		if _, err := ConcluirTarefa(feira.ID); !errors.Is(err, ErrTarefaJaConcluida) {
+			t.Errorf("Esperado ErrTarefaJaConcluida, obtido %v", err)
+		}
+	})
+
+	t.Run("Mover tarefa", func(t *testing.T) {
+		if _, err := MoverTarefa(feira.ID, passos[1].ID); err == nil {
+			t.Errorf("Mover a tarefa pai para baixo de uma subtarefa deveria falhar")
+		}
+		if _, err := MoverTarefa(feira.ID, feira.ID); err == nil {
+			t.Errorf("Uma tarefa não deveria ser subtarefa de si mesma")
+		}
+		movida, err := MoverTarefa(passos[2].ID, passos[1].ID)
+		if err != nil {
+			t.Fatalf("MoverTarefa falhou: %v", err)
+		}
+		if movida.ParentID != passos[1].ID {
+			t.Errorf("Esperado pai '%s', obtido '%s'", passos[1].ID, movida.ParentID)
+		}
+		if _, err := MoverTarefa(passos[2].ID, ""); err != nil {
+			t.Fatalf("MoverTarefa para o primeiro nível falhou: %v", err)
+		}
+		if t2, _ := GetTarefaByID(passos[2].ID); t2.ParentID != "" {
+			t.Errorf("A tarefa deveria estar no primeiro nível, pai '%s'", t2.ParentID)
+		}
+	})
+}
+
+func TestMontarArvore(t *testing.T) {
+	tarefas := []models.Task{
+		{ID: "a", Description: "A"},
+		{ID: "a1", ParentID: "a", Status: models.TaskStatusCompleted},
+		{ID: "b", Description: "B"},
+		{ID: "a2", ParentID: "a"},
+		{ID: "a2x", ParentID: "a2"},
+		{ID: "orfa", ParentID: "fora-da-lista"},
+		{ID: "c1", ParentID: "c2"}, // Ciclo: as duas viram raízes.
+		{ID: "c2", ParentID: "c1"},
+	}
+	raizes := MontarArvore(tarefas)
+	var ids []string
+	for _, r := range raizes {
+		ids = append(ids, r.Tarefa.ID)
+	}
+	if strings.Join(ids, ",") != "a,b,orfa,c1,c2" {
+		t.Fatalf("Raízes inesperadas: %v", ids)
+	}
+	a := raizes[0]
+	if len(a.Subtarefas) != 2 || a.Subtarefas[0].Tarefa.ID != "a1" || a.Subtarefas[1].Tarefa.ID != "a2" ||
+		len(a.Subtarefas[1].Subtarefas) != 1 || a.Subtarefas[1].Subtarefas[0].Tarefa.ID != "a2x" {
+		t.Errorf("Hierarquia inesperada sob 'a': %+v", a.Subtarefas)
+	}
+
+	progresso := CalcularProgresso(tarefas)
+	if progresso["a"].String() != "1/2" || progresso["a2"].String() != "0/1" {
+		t.Errorf("Progresso inesperado: %v", progresso)
+	}
+	if _, ok := progresso["b"]; ok {
+		t.Errorf("Tarefa sem subtarefas não deveria ter progresso")
+	}
+}
@@ -1,43 +1,71 @@
 package tarefa
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
-	"sort"
+	"os"
 	"strings"
-	"sync"
 	"time"
 
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
-// tarefasStore é o nosso banco de dados em memória para tarefas.
-// Não é exportado, pois o acesso é gerenciado pelas funções públicas deste pacote.
-var (
-	tarefasStore = make(map[string]models.Task)
-	nextTaskID   = 1
-	mu           sync.Mutex // mu protege o acesso concorrente ao tarefasStore e nextTaskID.
-)
+// As tarefas são persistidas na tabela "tasks" do banco SQLite através das funções
+// db.CreateTask, db.GetTask, db.ListTasks, db.UpdateTask e db.DeleteTask.
+// O banco deve ser inicializado com db.InitDB antes do uso das funções deste pacote.
+
+// erroTarefaNaoEncontrada padroniza a mensagem de erro para IDs inexistentes.
+func erroTarefaNaoEncontrada(id string) error {
+	return fmt.Errorf("tarefa com ID '%s' não encontrada", id)
+}
+
+// parseTags converte uma string de tags separadas por vírgula em um slice, removendo espaços.
+// Retorna nil para uma string vazia.
+func parseTags(tagsStr string) []string {
+	trimmed := strings.TrimSpace(tagsStr)
+	if trimmed == "" {
+		return nil
+	}
+	tags := strings.Split(trimmed, ",")
+	for i, tag := range tags {
+		tags[i] = strings.TrimSpace(tag)
+	}
+	return tags
+}
 
-// generateNewTaskID gera um ID único para uma nova tarefa de forma sequencial.
-// Esta função não é exportada pois é um detalhe de implementação.
-// Exemplo: "task-1", "task-2".
-func generateNewTaskID() string {
-	id := fmt.Sprintf("task-%d", nextTaskID)
-	nextTaskID++
-	return id
+// sortColumnTarefa traduz o campo de ordenação aceito pela CLI para a coluna correspondente da tabela tasks.
+func sortColumnTarefa(sortBy string) string {
+	switch strings.ToLower(sortBy) {
+	case "descricao":
+		return "description"
+	case "prazo", "duedate":
+		return "due_date"
+	case "prioridade":
+		return "priority"
+	case "status":
+		return "status"
+	default: // "CreatedAt" ou qualquer outro
+		return "created_at"
+	}
 }
 
 // CriarTarefa adiciona uma nova tarefa ao sistema de gerenciamento de tarefas.
 // Requer uma descrição não vazia.
-// dueDateStr deve estar no formato "YYYY-MM-DD"; se vazio, a tarefa não tem prazo.
+// dueDateStr é uma expressão de data do pacote datas (ex: "amanhã", "sexta", "15/08", "2024-08-15"); se vazio, a tarefa não tem prazo.
 // A prioridade, se não especificada (<=0), assume o valor padrão 2 (Média).
 // tagsStr é uma string de tags separadas por vírgula (ex: "importante,trabalho").
 // Retorna a tarefa criada e armazenada ou um erro se a validação dos campos falhar.
 func CriarTarefa(description string, dueDateStr string, priority int, tagsStr string) (models.Task, error) {
-	mu.Lock()
-	defer mu.Unlock()
+	return criarTarefa(models.Task{}, description, dueDateStr, priority, tagsStr)
+}
 
+// criarTarefa implementa CriarTarefa e CriarSubtarefa; com pai vazio (sem ID), cria uma tarefa de primeiro nível.
+// Uma subtarefa pertence ao mesmo projeto da tarefa pai.
+func criarTarefa(pai models.Task, description string, dueDateStr string, priority int, tagsStr string) (models.Task, error) {
 	if strings.TrimSpace(description) == "" {
 		return models.Task{}, errors.New("a descrição da tarefa é obrigatória")
 	}
@@ -45,139 +73,153 @@ func CriarTarefa(description string, dueDateStr string, priority int, tagsStr st
 	var dueDate time.Time
 	var err error
 	if dueDateStr != "" {
-		dueDate, err = time.Parse("2006-01-02", dueDateStr)
+		dueDate, err = datas.Data(dueDateStr)
 		if err != nil {
-			return models.Task{}, errors.New("formato de data inválido para --prazo. Use YYYY-MM-DD")
+			return models.Task{}, fmt.Errorf("formato de data inválido para --prazo: %w", err)
 		}
 	}
 
-	if priority <= 0 {
-		priority = 2 // Padrão: Média
+	novaTarefa, err := MontarTarefa(description, dueDate, priority, parseTags(tagsStr))
+	if err != nil {
+		return models.Task{}, err
 	}
+	novaTarefa.ParentID = pai.ID
+	novaTarefa.ProjectID = pai.ProjectID
 
-	var tags []string
-	if strings.TrimSpace(tagsStr) != "" {
-		tags = strings.Split(tagsStr, ",")
-		for i, tag := range tags {
-			tags[i] = strings.TrimSpace(tag)
-		}
+	id, err := db.CreateTask(novaTarefa)
+	if err != nil {
+		return models.Task{}, fmt.Errorf("erro ao salvar a tarefa: %w", err)
+	}
+	novaTarefa.ID = id
+	return novaTarefa, nil
+}
+
+// MontarTarefa valida os campos e monta uma nova tarefa pendente, sem salvá-la, com os mesmos padrões de CriarTarefa.
+// Usada por quem precisa gravar a tarefa junto com outros registros, como as rotinas.
+func MontarTarefa(description string, dueDate time.Time, priority int, tags []string) (models.Task, error) {
+	if strings.TrimSpace(description) == "" {
+		return models.Task{}, errors.New("a descrição da tarefa é obrigatória")
+	}
+	if priority <= 0 {
+		priority = 2 // Padrão: Média
 	}
 
 	now := time.Now()
-	novaTarefa := models.Task{
-		ID:          generateNewTaskID(),
+	return models.Task{
 		Description: description,
 		DueDate:     dueDate,
 		Priority:    priority,
-		Status:      "Pendente", // Status inicial padrão para novas tarefas.
+		Status:      models.TaskStatusPending, // Status inicial padrão para novas tarefas.
 		Tags:        tags,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-	}
-
-	tarefasStore[novaTarefa.ID] = novaTarefa
-	return novaTarefa, nil
+	}, nil
 }
 
 // ListarTarefas retorna uma lista de tarefas com base nos filtros e ordenação fornecidos.
 // Todos os parâmetros de filtro são opcionais.
 // statusFilter: filtra tarefas pelo status (ex: "Pendente", "Concluída"). Case-insensitive.
+// O status derivado "Bloqueada" também é aceito e lista as tarefas com pré-requisitos abertos.
 // priorityFilter: filtra tarefas pela prioridade (ex: 1, 2, 3).
-// dueDateFilterStr: filtra tarefas com prazo até a data especificada ("YYYY-MM-DD").
+// dueDateFilterStr: filtra tarefas com prazo até a data especificada (expressão do pacote datas, ex: "sexta").
 // tagFilter: filtra tarefas que contenham a tag especificada. Case-insensitive.
-// sortBy: campo para ordenação ("descricao", "prazo", "prioridade", "status", "CreatedAt"). Padrão: "CreatedAt".
+// sortBy: campo para ordenação ("descricao", "prazo", "prioridade", "status", "urgencia", "CreatedAt"). Padrão: "CreatedAt".
 // sortOrder: ordem de classificação ("asc" para ascendente, "desc" para descendente). Padrão: "asc".
+// Com "urgencia" (veja ContextoUrgencia.Calcular), use "desc" para as tarefas mais urgentes primeiro.
 // Retorna uma lista de tarefas ou um erro se, por exemplo, o formato de data do filtro for inválido.
+// Para filtrar também pelo projeto, use ListarTarefasFiltro.
 func ListarTarefas(statusFilter string, priorityFilter int, dueDateFilterStr string, tagFilter string, sortBy string, sortOrder string) ([]models.Task, error) {
-	mu.Lock()
-	defer mu.Unlock()
+	return ListarTarefasFiltro(FiltroTarefas{
+		Status:     statusFilter,
+		Prioridade: priorityFilter,
+		PrazoAte:   dueDateFilterStr,
+		Tag:        tagFilter,
+		OrdenarPor: sortBy,
+		Ordem:      sortOrder,
+	})
+}
 
-	var result []models.Task
-	for _, tarefa := range tarefasStore {
-		// Aplicar filtros
-		if statusFilter != "" && !strings.EqualFold(tarefa.Status, statusFilter) {
-			continue
-		}
-		if priorityFilter > 0 && tarefa.Priority != priorityFilter {
-			continue
-		}
-		if tagFilter != "" {
-			foundTag := false
-			for _, t := range tarefa.Tags {
-				if strings.EqualFold(t, tagFilter) {
-					foundTag = true
-					break
-				}
-			}
-			if !foundTag {
-				continue
-			}
+// FiltroTarefas reúne os filtros e a ordenação de ListarTarefasFiltro, com o mesmo significado dos
+// parâmetros de ListarTarefas. Os campos vazios (ou 0) não filtram.
+type FiltroTarefas struct {
+	Status     string
+	Prioridade int
+	PrazoAte   string
+	Tag        string
+	Projeto    string // Nome ou ID do projeto (veja projeto.BuscarProjeto).
+	OrdenarPor string
+	Ordem      string
+}
+
+// ListarTarefasFiltro retorna as tarefas que passam por todos os filtros de f, na ordem pedida.
+// Retorna um erro se, por exemplo, o formato de data do filtro for inválido ou o projeto não existir.
+func ListarTarefasFiltro(f FiltroTarefas) ([]models.Task, error) {
+	statusFilter, priorityFilter, dueDateFilterStr, tagFilter := f.Status, f.Prioridade, f.PrazoAte, f.Tag
+	sortBy, sortOrder := f.OrdenarPor, f.Ordem
+	filters := map[string]interface{}{}
+	apenasBloqueadas := strings.EqualFold(statusFilter, models.TaskStatusBlocked)
+	if statusFilter != "" && !apenasBloqueadas {
+		filters["status"] = statusFilter
+	}
+	if priorityFilter > 0 {
+		filters["priority"] = priorityFilter
+	}
+	if tagFilter != "" {
+		filters["tag"] = tagFilter
+	}
+	if f.Projeto != "" {
+		p, err := projeto.BuscarProjeto(f.Projeto)
+		if err != nil {
+			return nil, err
 		}
-		if dueDateFilterStr != "" {
-			dueDateF, err := time.Parse("2006-01-02", dueDateFilterStr)
-			if err != nil {
-				return nil, errors.New("formato de data inválido para filtro de prazo")
-			}
-			// Consider tasks due on or before the filter date, if the task has a due date
-			if !tarefa.DueDate.IsZero() && tarefa.DueDate.After(dueDateF) {
-				continue
-			}
+		filters["project_id"] = p.ID
+	}
+	if dueDateFilterStr != "" {
+		dueDateF, err := datas.Data(dueDateFilterStr)
+		if err != nil {
+			return nil, fmt.Errorf("formato de data inválido para filtro de prazo: %w", err)
 		}
-		result = append(result, tarefa)
+		// Considera tarefas com prazo até o fim do dia informado; tarefas sem prazo são mantidas.
+		filters["due_before"] = dueDateF.Add(24*time.Hour - time.Nanosecond)
 	}
 
-	// Ordenação
-	if sortBy == "" {
-		sortBy = "CreatedAt" // Padrão
-	}
 	if sortOrder == "" {
 		sortOrder = "asc" // Padrão
 	}
 
-	sort.SliceStable(result, func(i, j int) bool {
-		t1 := result[i]
-		t2 := result[j]
-		var less bool
-		switch strings.ToLower(sortBy) {
-		case "descricao":
-			less = t1.Description < t2.Description
-		case "prazo":
-			if t1.DueDate.IsZero() { // Tarefas sem prazo vêm depois
-				return false
-			}
-			if t2.DueDate.IsZero() {
-				return true
-			}
-			less = t1.DueDate.Before(t2.DueDate)
-		case "prioridade":
-			less = t1.Priority < t2.Priority // Menor número = maior prioridade
-		case "status":
-			less = t1.Status < t2.Status
-		default: // "CreatedAt" ou qualquer outro
-			less = t1.CreatedAt.Before(t2.CreatedAt)
+	tarefas, _, err := db.ListTasks(filters, sortColumnTarefa(sortBy), sortOrder, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tarefas: %w", err)
+	}
+	if apenasBloqueadas {
+		bloqueios, err := CarregarBloqueios()
+		if err != nil {
+			return nil, err
 		}
-		if strings.ToLower(sortOrder) == "desc" {
-			return !less
+		var bloqueadas []models.Task
+		for _, t := range tarefas {
+			if bloqueios.StatusEfetivo(t) == models.TaskStatusBlocked {
+				bloqueadas = append(bloqueadas, t)
+			}
 		}
-		return less
-	})
-
-	return result, nil
+		tarefas = bloqueadas
+	}
+	if err := ordenarSeUrgencia(tarefas, sortBy, sortOrder); err != nil {
+		return nil, err
+	}
+	return tarefas, nil
 }
 
 // EditarTarefa atualiza os campos de uma tarefa existente, identificada pelo seu ID.
 // Pelo menos um dos campos a serem alterados (novaDesc, novoPrazoStr, etc.) deve ser fornecido.
-// Se um campo de string opcional for vazio, ele não será alterado.
+// Se um campo de string opcional for vazio, ele não será alterado; um novoPrazoStr só com espaços remove o prazo.
 // Se novaPrioridade for 0 ou negativo, não será alterada.
 // novasTagsStr substitui completamente as tags existentes; se vazia, as tags são mantidas ou limpas dependendo da interpretação desejada (aqui, string vazia de tags = sem tags).
 // Retorna a tarefa atualizada ou um erro se a tarefa não for encontrada, nenhuma alteração for especificada, ou houver erro de formato.
 func EditarTarefa(id string, novaDesc, novoPrazoStr string, novaPrioridade int, novoStatus string, novasTagsStr string) (models.Task, error) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	tarefa, existe := tarefasStore[id]
-	if !existe {
-		return models.Task{}, fmt.Errorf("tarefa com ID '%s' não encontrada", id)
+	tarefa, err := GetTarefaByID(id)
+	if err != nil {
+		return models.Task{}, err
 	}
 
 	updated := false
@@ -185,13 +227,16 @@ func EditarTarefa(id string, novaDesc, novoPrazoStr string, novaPrioridade int,
 		tarefa.Description = novaDesc
 		updated = true
 	}
-	if novoPrazoStr != "" {
-		newDueDate, err := time.Parse("2006-01-02", novoPrazoStr)
+	if strings.TrimSpace(novoPrazoStr) != "" {
+		newDueDate, err := datas.Data(novoPrazoStr)
 		if err != nil {
-			return models.Task{}, errors.New("formato de data inválido para novo prazo. Use YYYY-MM-DD")
+			return models.Task{}, fmt.Errorf("formato de data inválido para novo prazo: %w", err)
 		}
 		tarefa.DueDate = newDueDate
 		updated = true
+	} else if novoPrazoStr != "" { // Como nas tags, uma string só com espaços (ex: " ") remove o prazo.
+		tarefa.DueDate = time.Time{}
+		updated = true
 	}
 	if novaPrioridade > 0 {
 		tarefa.Priority = novaPrioridade
@@ -199,65 +244,155 @@ func EditarTarefa(id string, novaDesc, novoPrazoStr string, novaPrioridade int,
 	}
 	if novoStatus != "" {
 		// Poderia haver uma validação de status aqui (ex: Pendente, Em Andamento, Concluída)
+		if strings.EqualFold(novoStatus, models.TaskStatusBlocked) {
+			return models.Task{}, errors.New("o status 'Bloqueada' é derivado dos pré-requisitos e não pode ser definido manualmente")
+		}
 		tarefa.Status = novoStatus
 		updated = true
 	}
-	if novasTagsStr != "" { // Permitir limpar tags passando uma string que resulte em slice vazio, ex: "" ou " "
-		var tags []string
-		trimmedTagsStr := strings.TrimSpace(novasTagsStr)
-		if trimmedTagsStr != "" { // Só faz split se não for vazia após trim
-			tags = strings.Split(trimmedTagsStr, ",")
-			for i, tag := range tags {
-				tags[i] = strings.TrimSpace(tag)
-			}
-		}
-		tarefa.Tags = tags // Substitui as tags existentes
+	if novasTagsStr != "" { // Permitir limpar tags passando uma string que resulte em slice vazio, ex: " "
+		tarefa.Tags = parseTags(novasTagsStr) // Substitui as tags existentes
 		updated = true
 	}
 
-
 	if !updated {
 		return models.Task{}, errors.New("nenhuma alteração especificada")
 	}
 
 	tarefa.UpdatedAt = time.Now()
-	tarefasStore[id] = tarefa
+	if err := salvarTarefa(tarefa); err != nil {
+		return models.Task{}, err
+	}
+	return tarefa, nil
+}
+
+// AjustarTags adiciona e remove tags de uma tarefa, mantendo as demais. As tags já presentes não são repetidas,
+// e a remoção não diferencia maiúsculas. Retorna um erro se a tarefa não for encontrada ou se nenhuma tag for informada.
+func AjustarTags(id string, adicionar, remover []string) (models.Task, error) {
+	tarefa, err := GetTarefaByID(id)
+	if err != nil {
+		return models.Task{}, err
+	}
+	removidas := make(map[string]bool)
+	for _, tag := range remover {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			removidas[strings.ToLower(tag)] = true
+		}
+	}
+	var tags []string
+	presentes := make(map[string]bool)
+	for _, tag := range tarefa.Tags {
+		if !removidas[strings.ToLower(tag)] {
+			tags = append(tags, tag)
+			presentes[strings.ToLower(tag)] = true
+		}
+	}
+	adicionou := false
+	for _, tag := range adicionar {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		adicionou = true
+		if !presentes[strings.ToLower(tag)] {
+			tags = append(tags, tag)
+			presentes[strings.ToLower(tag)] = true
+		}
+	}
+	if !adicionou && len(removidas) == 0 {
+		return models.Task{}, errors.New("nenhuma tag para adicionar ou remover")
+	}
+
+	tarefa.Tags = tags
+	tarefa.UpdatedAt = time.Now()
+	if err := salvarTarefa(tarefa); err != nil {
+		return models.Task{}, err
+	}
 	return tarefa, nil
 }
 
+// ErrTarefaJaConcluida é retornado ao concluir uma tarefa que já está concluída.
+var ErrTarefaJaConcluida = errors.New("tarefa já está concluída")
+
 // ConcluirTarefa marca uma tarefa especificada pelo ID como "Concluída".
-// Retorna a tarefa atualizada ou um erro se a tarefa não for encontrada ou já estiver concluída.
+// Retorna a tarefa atualizada ou um erro se a tarefa não for encontrada ou já estiver concluída (ErrTarefaJaConcluida).
+// Para saber se ficaram subtarefas abertas e quais tarefas foram desbloqueadas, use ConcluirTarefaComAvisos.
 func ConcluirTarefa(id string) (models.Task, error) {
-	mu.Lock()
-	defer mu.Unlock()
+	conclusao, err := ConcluirTarefaComAvisos(id)
+	return conclusao.Tarefa, err
+}
+
+// Conclusao descreve o resultado de ConcluirTarefaComAvisos.
+type Conclusao struct {
+	Tarefa            models.Task   // A tarefa concluída.
+	SubtarefasAbertas []models.Task // Subtarefas diretas ainda não concluídas; a tarefa é concluída mesmo assim.
+	Desbloqueadas     []models.Task // Tarefas que dependiam desta e não têm mais pré-requisitos abertos.
+	CronometroParado  bool          // Indica se o cronômetro estava rodando nesta tarefa e foi parado.
+}
 
-	tarefa, existe := tarefasStore[id]
-	if !existe {
-		return models.Task{}, fmt.Errorf("tarefa com ID '%s' não encontrada", id)
+// ConcluirTarefaComAvisos é como ConcluirTarefa, mas também informa as subtarefas que continuam abertas,
+// para que a interface avise o usuário, e as tarefas que a conclusão desbloqueou.
+// Se o cronômetro estiver rodando na tarefa, ele é parado.
+func ConcluirTarefaComAvisos(id string) (Conclusao, error) {
+	tarefa, err := GetTarefaByID(id)
+	if err != nil {
+		return Conclusao{}, err
 	}
 
-	if tarefa.Status == "Concluída" {
-		return tarefa, errors.New("tarefa já está concluída")
+	if tarefa.Status == models.TaskStatusCompleted {
+		return Conclusao{Tarefa: tarefa}, ErrTarefaJaConcluida
 	}
 
-	tarefa.Status = "Concluída"
+	tarefa.Status = models.TaskStatusCompleted
 	tarefa.UpdatedAt = time.Now()
-	tarefasStore[id] = tarefa
-	return tarefa, nil
+	if err := salvarTarefa(tarefa); err != nil {
+		return Conclusao{}, err
+	}
+	conclusao := Conclusao{Tarefa: tarefa}
+	if ativo, err := CronometroAtivo(); err == nil && ativo.Registro.TaskID == id {
+		if _, err := PausarTarefa(); err != nil {
+			return conclusao, err
+		}
+		conclusao.CronometroParado = true
+	} else if err != nil && !errors.Is(err, ErrNenhumCronometro) {
+		return conclusao, err
+	}
+	subtarefas, err := ListarSubtarefas(id)
+	if err != nil {
+		return conclusao, err
+	}
+	for _, s := range subtarefas {
+		if s.Status != models.TaskStatusCompleted {
+			conclusao.SubtarefasAbertas = append(conclusao.SubtarefasAbertas, s)
+		}
+	}
+	if conclusao.Desbloqueadas, err = tarefasDesbloqueadas(id); err != nil {
+		return conclusao, err
+	}
+	return conclusao, nil
+}
+
+// salvarTarefa persiste as alterações de uma tarefa existente.
+func salvarTarefa(tarefa models.Task) error {
+	if err := db.UpdateTask(tarefa); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroTarefaNaoEncontrada(tarefa.ID)
+		}
+		return fmt.Errorf("erro ao salvar a tarefa: %w", err)
+	}
+	return nil
 }
 
 // RemoverTarefa remove uma tarefa do sistema, identificada pelo seu ID.
+// As subtarefas não são removidas: passam a pertencer à tarefa pai da removida (ou ao primeiro nível).
 // Retorna um erro se a tarefa não for encontrada.
 func RemoverTarefa(id string) error {
-	mu.Lock()
-	defer mu.Unlock()
-
-	_, existe := tarefasStore[id]
-	if !existe {
-		return fmt.Errorf("tarefa com ID '%s' não encontrada", id)
+	if err := db.DeleteTask(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return erroTarefaNaoEncontrada(id)
+		}
+		return fmt.Errorf("erro ao remover a tarefa: %w", err)
 	}
-
-	delete(tarefasStore, id)
 	return nil
 }
 
@@ -265,23 +400,28 @@ func RemoverTarefa(id string) error {
 // É uma função auxiliar que pode ser usada por outros pacotes ou para testes.
 // Retorna a tarefa encontrada ou um erro se nenhuma tarefa com o ID fornecido existir.
 func GetTarefaByID(id string) (models.Task, error) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	tarefa, existe := tarefasStore[id]
-	if !existe {
-		return models.Task{}, fmt.Errorf("tarefa com ID '%s' não encontrada", id)
+	tarefa, err := db.GetTask(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Task{}, erroTarefaNaoEncontrada(id)
+		}
+		return models.Task{}, fmt.Errorf("erro ao buscar a tarefa: %w", err)
 	}
 	return tarefa, nil
 }
 
-// LimparTarefasStore remove todas as tarefas do armazenamento em memória.
+// LimparTarefasStore remove todas as tarefas do banco de dados, com as suas dependências, vínculos e o tempo registrado.
 // Esta função é primariamente destinada a ser usada em testes para garantir um estado limpo.
 func LimparTarefasStore() {
-	mu.Lock()
-	defer mu.Unlock()
-	tarefasStore = make(map[string]models.Task)
-	nextTaskID = 1
+	conn := db.GetDB()
+	if conn == nil {
+		return
+	}
+	for _, tabela := range []string{"tasks", "task_dependencies", "task_links", "task_time_entries"} {
+		if _, err := conn.Exec("DELETE FROM " + tabela); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro ao limpar %s: %v\n", tabela, err)
+		}
+	}
 }
 
 // ContarTarefas retorna a contagem de tarefas com base nos filtros fornecidos.
@@ -289,32 +429,22 @@ func LimparTarefasStore() {
 // statusFilter: filtra tarefas pelo status. Case-insensitive.
 // priorityFilter: filtra tarefas pela prioridade.
 // tagFilter: filtra tarefas que contenham a tag especificada. Case-insensitive.
-// Retorna o número de tarefas que correspondem aos critérios ou um erro (atualmente sempre nil).
+// Retorna o número de tarefas que correspondem aos critérios ou um erro de acesso ao banco.
 func ContarTarefas(statusFilter string, priorityFilter int, tagFilter string) (int, error) {
-	mu.Lock()
-	defer mu.Unlock()
+	filters := map[string]interface{}{}
+	if statusFilter != "" {
+		filters["status"] = statusFilter
+	}
+	if priorityFilter > 0 {
+		filters["priority"] = priorityFilter
+	}
+	if tagFilter != "" {
+		filters["tag"] = tagFilter
+	}
 
-	count := 0
-	for _, tarefa := range tarefasStore {
-		if statusFilter != "" && !strings.EqualFold(tarefa.Status, statusFilter) {
-			continue
-		}
-		if priorityFilter > 0 && tarefa.Priority != priorityFilter {
-			continue
-		}
-		if tagFilter != "" {
-			foundTag := false
-			for _, t := range tarefa.Tags {
-				if strings.EqualFold(t, tagFilter) {
-					foundTag = true
-					break
-				}
-			}
-			if !foundTag {
-				continue
-			}
-		}
-		count++
+	_, total, err := db.ListTasks(filters, "", "", 1, 1)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar tarefas: %w", err)
 	}
-	return count, nil
+	return total, nil
 }
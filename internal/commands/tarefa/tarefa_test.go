@@ -1,12 +1,23 @@
 package tarefa
 
 import (
+	"os"
+	"strings"
 	"testing"
-	"time"
+
+	"vickgenda-cli/internal/commands/projeto"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
-	"strings"
 )
 
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes de tarefa.
+func TestMain(m *testing.M) {
+	db.InitTestDB("tarefa")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
 // Helper to check if a slice of tasks contains a task with a specific ID
 func containsTask(tasks []models.Task, id string) bool {
 	for _, task := range tasks {
@@ -58,7 +69,7 @@ func TestCriarTarefa(t *testing.T) {
 	})
 
 	t.Run("Formato de prazo inválido", func(t *testing.T) {
-		_, err := CriarTarefa("Tarefa com prazo inválido", "31/12/2024", 1, "")
+		_, err := CriarTarefa("Tarefa com prazo inválido", "31/13/2024", 1, "")
 		if err == nil {
 			t.Error("Esperado erro para formato de prazo inválido, mas não houve erro")
 		} else if !strings.Contains(err.Error(), "formato de data inválido para --prazo") {
@@ -155,6 +166,32 @@ func TestListarTarefas(t *testing.T) {
             t.Errorf("Ordem incorreta: esperado IDs %s, %s, %s; obtido %s, %s, %s", t3.ID, t1.ID, t2.ID, tarefas[0].ID, tarefas[1].ID, tarefas[2].ID)
         }
     })
+
+	t.Run("Filtrar por projeto", func(t *testing.T) {
+		projeto.LimparProjetosStore()
+		feira, err := projeto.CriarProjeto("Feira de Ciências", "", "")
+		if err != nil {
+			t.Fatalf("CriarProjeto falhou: %v", err)
+		}
+		if _, err := projeto.AtribuirTarefa(t1.ID, feira.ID); err != nil {
+			t.Fatalf("AtribuirTarefa falhou: %v", err)
+		}
+		// Uma subtarefa entra no projeto da tarefa pai.
+		sub, err := CriarSubtarefa(t1.ID, "Reservar o pátio", "", 0, "")
+		if err != nil || sub.ProjectID != feira.ID {
+			t.Fatalf("A subtarefa deveria pertencer ao projeto da tarefa pai: %+v (%v)", sub, err)
+		}
+		tarefas, err := ListarTarefasFiltro(FiltroTarefas{Projeto: "feira de ciências"})
+		if err != nil {
+			t.Fatalf("ListarTarefasFiltro falhou: %v", err)
+		}
+		if len(tarefas) != 2 || !containsTask(tarefas, t1.ID) || !containsTask(tarefas, sub.ID) {
+			t.Errorf("Esperadas a tarefa e a subtarefa do projeto, obtido %+v", tarefas)
+		}
+		if _, err := ListarTarefasFiltro(FiltroTarefas{Projeto: "Inexistente"}); err == nil {
+			t.Errorf("Filtrar por um projeto inexistente: esperado erro")
+		}
+	})
 }
 
 func TestEditarTarefa(t *testing.T) {
@@ -192,6 +229,16 @@ func TestEditarTarefa(t *testing.T) {
         }
 	})
 
+	t.Run("Prazo só com espaços remove o prazo", func(t *testing.T) {
+		editada, err := EditarTarefa(tarefaOriginal.ID, "", " ", 0, "", "")
+		if err != nil {
+			t.Fatalf("EditarTarefa falhou: %v", err)
+		}
+		if atual, _ := GetTarefaByID(tarefaOriginal.ID); !editada.DueDate.IsZero() || !atual.DueDate.IsZero() {
+			t.Errorf("Esperado prazo removido, obtido %v", atual.DueDate)
+		}
+	})
+
 	t.Run("Tarefa não encontrada", func(t *testing.T) {
 		_, err := EditarTarefa("id-inexistente", "Nova Desc", "", 0, "", "")
 		if err == nil {
@@ -260,3 +307,37 @@ func TestRemoverTarefa(t *testing.T) {
 		}
 	})
 }
+
+func TestTarefasPersistidas(t *testing.T) {
+	LimparTarefasStore()
+	criada, err := CriarTarefa("Tarefa persistida", "2024-03-01", 1, "Provas, correção")
+	if err != nil {
+		t.Fatalf("CriarTarefa falhou: %v", err)
+	}
+
+	lida, err := GetTarefaByID(criada.ID)
+	if err != nil {
+		t.Fatalf("GetTarefaByID falhou: %v", err)
+	}
+	if lida.Description != criada.Description || lida.Priority != 1 || lida.DueDate.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("Tarefa lida do banco difere da criada: %+v", lida)
+	}
+	if len(lida.Tags) != 2 || lida.Tags[0] != "Provas" || lida.Tags[1] != "correção" {
+		t.Errorf("Tags não persistidas corretamente: %v", lida.Tags)
+	}
+
+	// O filtro de tag não diferencia maiúsculas de minúsculas e exige a tag exata.
+	tarefas, err := ListarTarefas("", 0, "", "provas", "", "")
+	if err != nil || len(tarefas) != 1 {
+		t.Errorf("Esperado 1 tarefa com tag 'provas', obtido %d (erro: %v)", len(tarefas), err)
+	}
+	tarefas, err = ListarTarefas("", 0, "", "prova", "", "")
+	if err != nil || len(tarefas) != 0 {
+		t.Errorf("Esperado 0 tarefas com tag parcial 'prova', obtido %d (erro: %v)", len(tarefas), err)
+	}
+
+	count, err := ContarTarefas(models.TaskStatusPending, 1, "correção")
+	if err != nil || count != 1 {
+		t.Errorf("Esperado contagem 1, obtido %d (erro: %v)", count, err)
+	}
+}
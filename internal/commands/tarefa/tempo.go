@@ -0,0 +1,268 @@
+package tarefa
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// O tempo gasto em cada tarefa é gravado como intervalos (models.TaskTimeEntry) na tabela "task_time_entries":
+// pelo cronômetro (IniciarTarefa/PausarTarefa), do qual só um roda por vez, ou manualmente (RegistrarTempo).
+
+// ErrNenhumCronometro é retornado quando nenhum cronômetro está rodando.
+var ErrNenhumCronometro = errors.New("nenhuma tarefa com o cronômetro rodando")
+
+// duracaoMaximaRegistro limita um registro manual, para evitar erros de digitação como "450m" no lugar de "45m".
+const duracaoMaximaRegistro = 24 * time.Hour
+
+// Cronometro associa um intervalo de trabalho à sua tarefa.
+type Cronometro struct {
+	Tarefa   models.Task
+	Registro models.TaskTimeEntry
+}
+
+// Inicio descreve o resultado de IniciarTarefa.
+type Inicio struct {
+	Cronometro             // O cronômetro iniciado.
+	Pausado    *Cronometro // O cronômetro que estava rodando em outra tarefa e foi pausado; nil se nenhum estava.
+}
+
+// IniciarTarefa liga o cronômetro da tarefa id. Um cronômetro rodando em outra tarefa é pausado antes.
+// Uma tarefa pendente passa a "Em Andamento". Retorna um erro se a tarefa não existir, estiver concluída
+// ou já estiver com o cronômetro rodando.
+func IniciarTarefa(id string) (Inicio, error) {
+	tarefa, err := GetTarefaByID(id)
+	if err != nil {
+		return Inicio{}, err
+	}
+	if tarefa.Status == models.TaskStatusCompleted {
+		return Inicio{}, fmt.Errorf("não é possível iniciar a tarefa '%s': %w", id, ErrTarefaJaConcluida)
+	}
+	if ativo, err := db.GetRunningTimeEntry(); err == nil && ativo.TaskID == id {
+		return Inicio{}, fmt.Errorf("o cronômetro da tarefa '%s' já está rodando desde %s", id, ativo.StartTime.Local().Format("15:04"))
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Inicio{}, fmt.Errorf("erro ao consultar o cronômetro: %w", err)
+	}
+
+	iniciado, pausado, err := db.StartTaskTimer(id, datas.Agora())
+	if err != nil {
+		return Inicio{}, fmt.Errorf("erro ao iniciar o cronômetro: %w", err)
+	}
+	if tarefa.Status == models.TaskStatusPending {
+		tarefa.Status = models.TaskStatusInProgress
+		tarefa.UpdatedAt = time.Now()
+		if err := salvarTarefa(tarefa); err != nil {
+			return Inicio{}, err
+		}
+	}
+	inicio := Inicio{Cronometro: Cronometro{Tarefa: tarefa, Registro: iniciado}}
+	if pausado.ID != "" {
+		anterior, err := GetTarefaByID(pausado.TaskID)
+		if err != nil {
+			anterior = models.Task{ID: pausado.TaskID}
+		}
+		inicio.Pausado = &Cronometro{Tarefa: anterior, Registro: pausado}
+	}
+	return inicio, nil
+}
+
+// PausarTarefa para o cronômetro que está rodando e devolve o intervalo encerrado.
+// Retorna ErrNenhumCronometro se nenhum cronômetro estiver rodando.
+func PausarTarefa() (Cronometro, error) {
+	registro, err := db.StopTaskTimer(datas.Agora())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Cronometro{}, ErrNenhumCronometro
+		}
+		return Cronometro{}, fmt.Errorf("erro ao pausar o cronômetro: %w", err)
+	}
+	tarefa, err := GetTarefaByID(registro.TaskID)
+	if err != nil {
+		return Cronometro{Tarefa: models.Task{ID: registro.TaskID}, Registro: registro}, nil
+	}
+	return Cronometro{Tarefa: tarefa, Registro: registro}, nil
+}
+
+// CronometroAtivo devolve o cronômetro que está rodando.
+// Retorna ErrNenhumCronometro se nenhum cronômetro estiver rodando.
+func CronometroAtivo() (Cronometro, error) {
+	registro, err := db.GetRunningTimeEntry()
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Cronometro{}, ErrNenhumCronometro
+		}
+		return Cronometro{}, fmt.Errorf("erro ao consultar o cronômetro: %w", err)
+	}
+	tarefa, err := GetTarefaByID(registro.TaskID)
+	if err != nil {
+		return Cronometro{}, err
+	}
+	return Cronometro{Tarefa: tarefa, Registro: registro}, nil
+}
+
+// RegistrarTempo registra manualmente duracaoStr (ex: "45m", "1h30m", "1h30") de trabalho na tarefa id.
+// dataStr (expressão do pacote datas, opcional) indica quando: com horário ("ontem 14h"), o intervalo começa nele;
+// sem horário, começa à meia-noite do dia. Vazia, o intervalo termina agora.
+func RegistrarTempo(id, duracaoStr, dataStr string) (models.TaskTimeEntry, error) {
+	if _, err := GetTarefaByID(id); err != nil {
+		return models.TaskTimeEntry{}, err
+	}
+	duracao, err := ParseDuracao(duracaoStr)
+	if err != nil {
+		return models.TaskTimeEntry{}, err
+	}
+	if duracao > duracaoMaximaRegistro {
+		return models.TaskTimeEntry{}, fmt.Errorf("duração '%s' maior que %s; registre um dia por vez", duracaoStr, FormatarDuracao(duracaoMaximaRegistro))
+	}
+
+	registro := models.TaskTimeEntry{TaskID: id, Manual: true}
+	if strings.TrimSpace(dataStr) == "" {
+		registro.EndTime = datas.Agora()
+		registro.StartTime = registro.EndTime.Add(-duracao)
+	} else {
+		r, err := datas.DataHora(dataStr)
+		if err != nil {
+			return models.TaskTimeEntry{}, fmt.Errorf("formato de data inválido para --data: %w", err)
+		}
+		registro.StartTime = r.Momento
+		registro.EndTime = r.Momento.Add(duracao)
+	}
+	if registro.ID, err = db.CreateTimeEntry(registro); err != nil {
+		return models.TaskTimeEntry{}, fmt.Errorf("erro ao registrar o tempo: %w", err)
+	}
+	return registro, nil
+}
+
+// reHorasMinutos reconhece durações como "1h30", em que o "m" final foi omitido.
+var reHorasMinutos = regexp.MustCompile(`^\d+h\d+$`)
+
+// ParseDuracao interpreta uma duração positiva no formato de time.ParseDuration ("45m", "1h30m", "2h"),
+// aceitando também "1h30".
+func ParseDuracao(valor string) (time.Duration, error) {
+	normalizado := strings.ToLower(strings.TrimSpace(valor))
+	if reHorasMinutos.MatchString(normalizado) {
+		normalizado += "m"
+	}
+	d, err := time.ParseDuration(normalizado)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("duração '%s' inválida. Use, por exemplo, 45m, 1h30m ou 2h", valor)
+	}
+	return d, nil
+}
+
+// FormatarDuracao formata d em horas e minutos, arredondando para o minuto: "45m", "1h05m", "12h00m".
+func FormatarDuracao(d time.Duration) string {
+	minutos := int(d.Round(time.Minute) / time.Minute)
+	if minutos < 60 {
+		return fmt.Sprintf("%dm", minutos)
+	}
+	return fmt.Sprintf("%dh%02dm", minutos/60, minutos%60)
+}
+
+// TempoTarefa devolve o tempo total registrado na tarefa id, incluindo o cronômetro, se estiver rodando nela.
+func TempoTarefa(id string) (time.Duration, error) {
+	registros, err := db.ListTimeEntries(id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar o tempo registrado: %w", err)
+	}
+	return CalcularTempos(registros, datas.Agora())[id], nil
+}
+
+// CalcularTempos soma a duração dos registros por tarefa; um cronômetro rodando conta até agora.
+func CalcularTempos(registros []models.TaskTimeEntry, agora time.Time) map[string]time.Duration {
+	tempos := make(map[string]time.Duration)
+	for _, r := range registros {
+		tempos[r.TaskID] += r.Duration(agora)
+	}
+	return tempos
+}
+
+// TempoPorChave é o tempo total de uma tarefa ou tag, em ResumoTempo.
+type TempoPorChave struct {
+	Chave string // ID da tarefa ou nome da tag.
+	Nome  string // Descrição da tarefa ou nome da tag.
+	Tempo time.Duration
+}
+
+// SemTag agrupa, em ResumoTempo.PorTag, o tempo das tarefas sem tags.
+const SemTag = "(sem tag)"
+
+// ResumoTempo resume o tempo registrado nas tarefas, para o relatório de produtividade.
+type ResumoTempo struct {
+	Total     time.Duration
+	PorTarefa []TempoPorChave // Em ordem decrescente de tempo.
+	PorTag    []TempoPorChave // Em ordem decrescente de tempo; uma tarefa com várias tags conta em cada uma.
+
+	// Tempo médio registrado por tarefa concluída, considerando as ConcluidasComTempo tarefas concluídas
+	// que têm algum tempo registrado.
+	MediaConclusao     time.Duration
+	ConcluidasComTempo int
+}
+
+// ResumirTempo calcula o ResumoTempo das tarefas com os registros fornecidos.
+// Registros de tarefas ausentes da lista são ignorados.
+func ResumirTempo(tarefas []models.Task, registros []models.TaskTimeEntry, agora time.Time) ResumoTempo {
+	tempos := CalcularTempos(registros, agora)
+	var resumo ResumoTempo
+	porTag := make(map[string]time.Duration)
+	var somaConcluidas time.Duration
+	for _, t := range tarefas {
+		tempo := tempos[t.ID]
+		if tempo == 0 {
+			continue
+		}
+		resumo.Total += tempo
+		resumo.PorTarefa = append(resumo.PorTarefa, TempoPorChave{Chave: t.ID, Nome: t.Description, Tempo: tempo})
+		if len(t.Tags) == 0 {
+			porTag[SemTag] += tempo
+		}
+		for _, tag := range t.Tags {
+			porTag[tag] += tempo
+		}
+		if t.Status == models.TaskStatusCompleted {
+			somaConcluidas += tempo
+			resumo.ConcluidasComTempo++
+		}
+	}
+	for tag, tempo := range porTag {
+		resumo.PorTag = append(resumo.PorTag, TempoPorChave{Chave: tag, Nome: tag, Tempo: tempo})
+	}
+	ordenarPorTempo(resumo.PorTarefa)
+	ordenarPorTempo(resumo.PorTag)
+	if resumo.ConcluidasComTempo > 0 {
+		resumo.MediaConclusao = somaConcluidas / time.Duration(resumo.ConcluidasComTempo)
+	}
+	return resumo
+}
+
+// ordenarPorTempo ordena por tempo decrescente e, no empate, por nome.
+func ordenarPorTempo(itens []TempoPorChave) {
+	sort.SliceStable(itens, func(i, j int) bool {
+		if itens[i].Tempo != itens[j].Tempo {
+			return itens[i].Tempo > itens[j].Tempo
+		}
+		return itens[i].Nome < itens[j].Nome
+	})
+}
+
+// CarregarResumoTempo calcula o ResumoTempo das tarefas do banco. Com projetoFilter (nome ou ID do projeto),
+// considera apenas as tarefas do projeto.
+func CarregarResumoTempo(projetoFilter string) (ResumoTempo, error) {
+	tarefas, err := ListarTarefasFiltro(FiltroTarefas{Projeto: projetoFilter})
+	if err != nil {
+		return ResumoTempo{}, err
+	}
+	registros, err := db.ListTimeEntries("")
+	if err != nil {
+		return ResumoTempo{}, fmt.Errorf("erro ao listar o tempo registrado: %w", err)
+	}
+	return ResumirTempo(tarefas, registros, datas.Agora()), nil
+}
@@ -0,0 +1,111 @@
+package tarefa
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/models"
+)
+
+func TestCronometroERegistroManual(t *testing.T) {
+	LimparTarefasStore()
+	agora := time.Date(2024, 3, 13, 9, 0, 0, 0, time.Local)
+	defer datas.UsarRelogio(func() time.Time { return agora })()
+
+	corrigir, _ := CriarTarefa("Corrigir provas 7B", "", 1, "escola-a,provas")
+	planejar, _ := CriarTarefa("Planejar aula", "", 2, "escola-b")
+
+	if _, err := PausarTarefa(); !errors.Is(err, ErrNenhumCronometro) {
+		t.Fatalf("Esperado ErrNenhumCronometro, obtido %v", err)
+	}
+	inicio, err := IniciarTarefa(corrigir.ID)
+	if err != nil {
+		t.Fatalf("IniciarTarefa falhou: %v", err)
+	}
+	if inicio.Pausado != nil || inicio.Tarefa.Status != models.TaskStatusInProgress {
+		t.Errorf("Esperada a tarefa em andamento e nenhum cronômetro pausado: %+v", inicio)
+	}
+	if _, err := IniciarTarefa(corrigir.ID); err == nil || !strings.Contains(err.Error(), "já está rodando") {
+		t.Errorf("Esperado erro ao iniciar de novo a mesma tarefa, obtido %v", err)
+	}
+
+	agora = agora.Add(40 * time.Minute)
+	inicio, err = IniciarTarefa(planejar.ID)
+	if err != nil {
+		t.Fatalf("IniciarTarefa falhou: %v", err)
+	}
+	if inicio.Pausado == nil || inicio.Pausado.Tarefa.ID != corrigir.ID || inicio.Pausado.Registro.Duration(agora) != 40*time.Minute {
+		t.Errorf("Esperado o cronômetro de '%s' pausado após 40m, obtido %+v", corrigir.Description, inicio.Pausado)
+	}
+
+	agora = agora.Add(20 * time.Minute)
+	if ativo, err := CronometroAtivo(); err != nil || ativo.Tarefa.ID != planejar.ID {
+		t.Errorf("Esperado o cronômetro rodando em '%s', obtido %+v (%v)", planejar.Description, ativo, err)
+	}
+	if tempo, _ := TempoTarefa(planejar.ID); tempo != 20*time.Minute {
+		t.Errorf("O cronômetro rodando deveria contar até agora: %v", tempo)
+	}
+	conclusao, err := ConcluirTarefaComAvisos(planejar.ID)
+	if err != nil || !conclusao.CronometroParado {
+		t.Fatalf("Concluir a tarefa deveria parar o cronômetro: %+v (%v)", conclusao, err)
+	}
+	if _, err := CronometroAtivo(); !errors.Is(err, ErrNenhumCronometro) {
+		t.Errorf("Nenhum cronômetro deveria estar rodando, obtido %v", err)
+	}
+	if _, err := IniciarTarefa(planejar.ID); !errors.Is(err, ErrTarefaJaConcluida) {
+		t.Errorf("Uma tarefa concluída não deveria ser iniciada, obtido %v", err)
+	}
+
+	registro, err := RegistrarTempo(corrigir.ID, "1h30", "ontem 14h")
+	if err != nil {
+		t.Fatalf("RegistrarTempo falhou: %v", err)
+	}
+	if !registro.Manual || registro.StartTime.Format("2006-01-02 15:04") != "2024-03-12 14:00" || registro.Duration(agora) != 90*time.Minute {
+		t.Errorf("Registro manual inesperado: %+v", registro)
+	}
+	for _, duracao := range []string{"", "0m", "-5m", "uma hora", "25h"} {
+		if _, err := RegistrarTempo(corrigir.ID, duracao, ""); err == nil {
+			t.Errorf("RegistrarTempo(%q): esperado erro", duracao)
+		}
+	}
+	if tempo, _ := TempoTarefa(corrigir.ID); FormatarDuracao(tempo) != "2h10m" {
+		t.Errorf("Esperado 2h10m em '%s', obtido %s", corrigir.Description, FormatarDuracao(tempo))
+	}
+}
+
+func TestResumirTempo(t *testing.T) {
+	agora := time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC)
+	tarefas := []models.Task{
+		{ID: "a", Description: "Corrigir provas", Status: models.TaskStatusCompleted, Tags: []string{"escola-a", "provas"}},
+		{ID: "b", Description: "Planejar aula", Status: models.TaskStatusCompleted, Tags: []string{"escola-b"}},
+		{ID: "c", Description: "Organizar armário", Status: models.TaskStatusPending},
+		{ID: "d", Description: "Sem tempo", Status: models.TaskStatusCompleted},
+	}
+	registros := []models.TaskTimeEntry{
+		{TaskID: "a", StartTime: agora.Add(-5 * time.Hour), EndTime: agora.Add(-3 * time.Hour)},
+		{TaskID: "b", StartTime: agora.Add(-3 * time.Hour), EndTime: agora.Add(-2 * time.Hour)},
+		{TaskID: "c", StartTime: agora.Add(-30 * time.Minute)}, // Cronômetro rodando.
+		{TaskID: "fora-da-lista", StartTime: agora.Add(-time.Hour), EndTime: agora},
+	}
+	resumo := ResumirTempo(tarefas, registros, agora)
+
+	if resumo.Total != 3*time.Hour+30*time.Minute {
+		t.Errorf("Total inesperado: %v", resumo.Total)
+	}
+	if resumo.ConcluidasComTempo != 2 || resumo.MediaConclusao != 90*time.Minute {
+		t.Errorf("Média de conclusão inesperada: %v em %d tarefas", resumo.MediaConclusao, resumo.ConcluidasComTempo)
+	}
+	var tags []string
+	for _, tag := range resumo.PorTag {
+		tags = append(tags, tag.Nome+"="+FormatarDuracao(tag.Tempo))
+	}
+	if strings.Join(tags, ",") != "escola-a=2h00m,provas=2h00m,escola-b=1h00m,(sem tag)=30m" {
+		t.Errorf("Tempo por tag inesperado: %v", tags)
+	}
+	if len(resumo.PorTarefa) != 3 || resumo.PorTarefa[0].Chave != "a" || resumo.PorTarefa[2].Chave != "c" {
+		t.Errorf("Tempo por tarefa inesperado: %+v", resumo.PorTarefa)
+	}
+}
@@ -0,0 +1,330 @@
+package tarefa
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// A urgência de uma tarefa é uma pontuação calculada, como a "urgency" do Taskwarrior: a soma de critérios
+// (prioridade, proximidade do prazo, idade, dependências, tags) multiplicados por pesos configuráveis.
+// Os pesos alterados pelo usuário são gravados na tabela "settings", com as chaves "urgencia.<peso>".
+
+// prefixoPesoUrgencia é o prefixo das chaves dos pesos na tabela "settings".
+const prefixoPesoUrgencia = "urgencia."
+
+// prefixoPesoTag é o prefixo dos pesos de tags específicas (ex: "tag.provas").
+const prefixoPesoTag = "tag."
+
+// CoeficienteUrgencia descreve um dos pesos da urgência.
+type CoeficienteUrgencia struct {
+	Nome      string  // Nome do peso, usado em 'tarefa urgencia definir'.
+	Padrao    float64 // Valor usado enquanto o peso não for alterado.
+	Descricao string  // Como o peso entra no cálculo.
+}
+
+// CoeficientesUrgencia são os pesos da urgência, na ordem em que são exibidos.
+// Além deles, "tag.<nome>" soma um peso às tarefas com a tag (ex: "tag.provas" = 3).
+var CoeficientesUrgencia = []CoeficienteUrgencia{
+	{"prioridade", 6.0, "vezes 1 (prioridade 1), 0.65 (prioridade 2) ou 0.3 (prioridade 3)"},
+	{"prazo", 12.0, "vezes a proximidade do prazo: 1 com 7 dias ou mais de atraso, caindo até 0.2 com 14 dias ou mais de antecedência"},
+	{"idade", 2.0, "vezes a idade da tarefa em dias dividida por 365 (no máximo 1)"},
+	{"bloqueando", 8.0, "se a tarefa é pré-requisito de tarefas abertas"},
+	{"bloqueada", -5.0, "se a tarefa tem pré-requisitos abertos"},
+	{"andamento", 4.0, "se a tarefa está em andamento"},
+	{"tags", 1.0, "vezes 0.8 (1 tag), 0.9 (2 tags) ou 1 (3 tags ou mais)"},
+}
+
+// PesosUrgencia associa o nome de cada peso ao seu valor.
+type PesosUrgencia map[string]float64
+
+// PesosPadrao devolve os pesos sem as alterações do usuário.
+func PesosPadrao() PesosUrgencia {
+	pesos := make(PesosUrgencia, len(CoeficientesUrgencia))
+	for _, c := range CoeficientesUrgencia {
+		pesos[c.Nome] = c.Padrao
+	}
+	return pesos
+}
+
+// CarregarPesos devolve os pesos padrão com as alterações gravadas pelo usuário.
+func CarregarPesos() (PesosUrgencia, error) {
+	pesos := PesosPadrao()
+	gravados, err := db.ListSettings(prefixoPesoUrgencia)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar os pesos da urgência: %w", err)
+	}
+	for chave, valor := range gravados {
+		nome := strings.TrimPrefix(chave, prefixoPesoUrgencia)
+		peso, err := strconv.ParseFloat(valor, 64)
+		if err != nil {
+			return nil, fmt.Errorf("peso da urgência '%s' inválido no banco: '%s'", nome, valor)
+		}
+		pesos[nome] = peso
+	}
+	return pesos, nil
+}
+
+// normalizarNomePeso valida o nome de um peso: um dos CoeficientesUrgencia ou "tag.<nome>".
+func normalizarNomePeso(nome string) (string, error) {
+	nome = strings.ToLower(strings.TrimSpace(nome))
+	if strings.HasPrefix(nome, prefixoPesoTag) && strings.TrimSpace(strings.TrimPrefix(nome, prefixoPesoTag)) != "" {
+		return nome, nil
+	}
+	var nomes []string
+	for _, c := range CoeficientesUrgencia {
+		if c.Nome == nome {
+			return nome, nil
+		}
+		nomes = append(nomes, c.Nome)
+	}
+	return "", fmt.Errorf("peso '%s' desconhecido. Use %s ou tag.<nome>", nome, strings.Join(nomes, ", "))
+}
+
+// DefinirPeso grava o valor de um peso da urgência (ex: DefinirPeso("prazo", "15"), DefinirPeso("tag.provas", "3")).
+// O valor aceita vírgula decimal ("1,5") e pode ser negativo, para diminuir a urgência; NaN e infinito são recusados.
+func DefinirPeso(nome, valorStr string) (float64, error) {
+	nome, err := normalizarNomePeso(nome)
+	if err != nil {
+		return 0, err
+	}
+	valor, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(valorStr), ",", ".", 1), 64)
+	if err != nil || math.IsNaN(valor) || math.IsInf(valor, 0) {
+		return 0, fmt.Errorf("valor '%s' inválido para o peso '%s'. Use um número, como 2 ou 1.5", valorStr, nome)
+	}
+	if err := db.SetSetting(prefixoPesoUrgencia+nome, strconv.FormatFloat(valor, 'f', -1, 64)); err != nil {
+		return 0, fmt.Errorf("erro ao gravar o peso: %w", err)
+	}
+	return valor, nil
+}
+
+// RestaurarPeso volta o peso ao valor padrão (um peso "tag.<nome>" deixa de existir).
+// Retorna um erro se o peso não tiver sido alterado.
+func RestaurarPeso(nome string) error {
+	nome, err := normalizarNomePeso(nome)
+	if err != nil {
+		return err
+	}
+	if err := db.DeleteSetting(prefixoPesoUrgencia + nome); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("o peso '%s' não foi alterado", nome)
+		}
+		return fmt.Errorf("erro ao restaurar o peso: %w", err)
+	}
+	return nil
+}
+
+// ComponenteUrgencia é a contribuição de um critério para a urgência de uma tarefa.
+type ComponenteUrgencia struct {
+	Criterio string  // Nome do peso (ex: "prazo", "tag.provas").
+	Valor    float64 // Peso vezes o fator do critério.
+}
+
+// Urgencia é a urgência calculada de uma tarefa, com a contribuição de cada critério.
+type Urgencia struct {
+	Total       float64
+	Componentes []ComponenteUrgencia // Critérios com contribuição diferente de zero, da maior para a menor.
+}
+
+// String formata a urgência com uma casa decimal (ex: "14.2").
+func (u Urgencia) String() string {
+	return strconv.FormatFloat(u.Total, 'f', 1, 64)
+}
+
+// Detalhes descreve os componentes da urgência (ex: "prazo 9.6, prioridade 6.0"), omitindo os que
+// arredondam para 0.0 (como a idade de uma tarefa recém-criada).
+func (u Urgencia) Detalhes() string {
+	var partes []string
+	for _, c := range u.Componentes {
+		if abs(c.Valor) < 0.05 {
+			continue
+		}
+		partes = append(partes, fmt.Sprintf("%s %.1f", c.Criterio, c.Valor))
+	}
+	return strings.Join(partes, ", ")
+}
+
+// ContextoUrgencia reúne o que o cálculo da urgência precisa além da própria tarefa.
+type ContextoUrgencia struct {
+	Pesos      PesosUrgencia
+	Bloqueios  Bloqueios
+	Agora      time.Time
+	bloqueando map[string]bool
+}
+
+// NovoContextoUrgencia monta o contexto a partir dos pesos, dos bloqueios de todas as tarefas e do instante atual.
+func NovoContextoUrgencia(pesos PesosUrgencia, bloqueios Bloqueios, agora time.Time) ContextoUrgencia {
+	bloqueando := make(map[string]bool)
+	for _, preRequisitos := range bloqueios {
+		for _, id := range preRequisitos {
+			bloqueando[id] = true
+		}
+	}
+	return ContextoUrgencia{Pesos: pesos, Bloqueios: bloqueios, Agora: agora, bloqueando: bloqueando}
+}
+
+// CarregarContextoUrgencia monta o contexto com os pesos gravados, os bloqueios do banco e o relógio do pacote datas.
+func CarregarContextoUrgencia() (ContextoUrgencia, error) {
+	pesos, err := CarregarPesos()
+	if err != nil {
+		return ContextoUrgencia{}, err
+	}
+	bloqueios, err := CarregarBloqueios()
+	if err != nil {
+		return ContextoUrgencia{}, err
+	}
+	return NovoContextoUrgencia(pesos, bloqueios, datas.Agora()), nil
+}
+
+// Calcular devolve a urgência da tarefa. Tarefas concluídas têm urgência zero.
+func (c ContextoUrgencia) Calcular(t models.Task) Urgencia {
+	var u Urgencia
+	if t.Status == models.TaskStatusCompleted {
+		return u
+	}
+	somar := func(criterio string, fator float64) {
+		if valor := c.Pesos[criterio] * fator; valor != 0 {
+			u.Componentes = append(u.Componentes, ComponenteUrgencia{Criterio: criterio, Valor: valor})
+			u.Total += valor
+		}
+	}
+
+	somar("prioridade", fatorPrioridade(t.Priority))
+	if !t.DueDate.IsZero() {
+		somar("prazo", fatorPrazo(t.DueDate, c.Agora))
+	}
+	if !t.CreatedAt.IsZero() {
+		idade := c.Agora.Sub(t.CreatedAt).Hours() / 24 / 365
+		if idade > 1 {
+			idade = 1
+		}
+		if idade > 0 {
+			somar("idade", idade)
+		}
+	}
+	if c.bloqueando[t.ID] {
+		somar("bloqueando", 1)
+	}
+	if c.Bloqueios.Bloqueada(t.ID) {
+		somar("bloqueada", 1)
+	}
+	if t.Status == models.TaskStatusInProgress {
+		somar("andamento", 1)
+	}
+	switch n := len(t.Tags); {
+	case n == 1:
+		somar("tags", 0.8)
+	case n == 2:
+		somar("tags", 0.9)
+	case n >= 3:
+		somar("tags", 1)
+	}
+	for _, tag := range t.Tags {
+		somar(prefixoPesoTag+strings.ToLower(tag), 1)
+	}
+
+	sort.SliceStable(u.Componentes, func(i, j int) bool {
+		return abs(u.Componentes[i].Valor) > abs(u.Componentes[j].Valor)
+	})
+	return u
+}
+
+// fatorPrioridade converte a prioridade (1-Alta, 2-Média, 3-Baixa) no fator do peso "prioridade".
+func fatorPrioridade(prioridade int) float64 {
+	switch {
+	case prioridade <= 1:
+		return 1
+	case prioridade == 2:
+		return 0.65
+	}
+	return 0.3
+}
+
+// fatorPrazo cresce linearmente de 0.2, com 14 dias ou mais de antecedência, até 1, com 7 dias ou mais de atraso.
+// Só o dia civil do prazo é considerado.
+func fatorPrazo(prazo, agora time.Time) float64 {
+	atraso := datas.DataUTC(agora).Sub(datas.DataUTC(prazo)).Hours() / 24
+	switch {
+	case atraso >= 7:
+		return 1
+	case atraso >= -14:
+		return (atraso+14)*0.8/21 + 0.2
+	}
+	return 0.2
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// OrdenarPorUrgencia ordena as tarefas pela urgência, da maior para a menor (ou da menor para a maior,
+// com sortOrder "asc"). Tarefas empatadas mantêm a ordem recebida.
+func OrdenarPorUrgencia(tarefas []models.Task, c ContextoUrgencia, sortOrder string) {
+	urgencias := make(map[string]float64, len(tarefas))
+	for _, t := range tarefas {
+		urgencias[t.ID] = c.Calcular(t).Total
+	}
+	crescente := strings.EqualFold(sortOrder, "asc")
+	sort.SliceStable(tarefas, func(i, j int) bool {
+		a, b := urgencias[tarefas[i].ID], urgencias[tarefas[j].ID]
+		if crescente {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+// ordenarSeUrgencia aplica OrdenarPorUrgencia quando sortBy é "urgencia", que não é uma coluna do banco.
+func ordenarSeUrgencia(tarefas []models.Task, sortBy, sortOrder string) error {
+	if !strings.EqualFold(sortBy, "urgencia") {
+		return nil
+	}
+	contexto, err := CarregarContextoUrgencia()
+	if err != nil {
+		return err
+	}
+	OrdenarPorUrgencia(tarefas, contexto, sortOrder)
+	return nil
+}
+
+// TarefaUrgente é uma tarefa com a sua urgência calculada.
+type TarefaUrgente struct {
+	Tarefa   models.Task
+	Urgencia Urgencia
+}
+
+// ProximasTarefas devolve as n tarefas mais urgentes entre as prontas para serem feitas
+// (não concluídas e sem pré-requisitos abertos), da mais urgente para a menos urgente.
+// Com n <= 0, devolve todas as tarefas prontas.
+func ProximasTarefas(n int) ([]TarefaUrgente, error) {
+	tarefas, err := ListarTarefas("", 0, "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	contexto, err := CarregarContextoUrgencia()
+	if err != nil {
+		return nil, err
+	}
+	prontas := FiltrarProntas(tarefas, contexto.Bloqueios)
+	OrdenarPorUrgencia(prontas, contexto, "desc")
+	if n > 0 && len(prontas) > n {
+		prontas = prontas[:n]
+	}
+	proximas := make([]TarefaUrgente, 0, len(prontas))
+	for _, t := range prontas {
+		proximas = append(proximas, TarefaUrgente{Tarefa: t, Urgencia: contexto.Calcular(t)})
+	}
+	return proximas, nil
+}
@@ -0,0 +1,144 @@
+package tarefa
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+func limparPesos(t *testing.T) {
+	if _, err := db.GetDB().Exec("DELETE FROM settings"); err != nil {
+		t.Fatalf("Falha ao limpar os pesos: %v", err)
+	}
+}
+
+func TestCalcularUrgencia(t *testing.T) {
+	agora := time.Date(2024, 8, 14, 10, 0, 0, 0, time.Local)
+	dia := func(d int) time.Time { return time.Date(2024, 8, 14+d, 0, 0, 0, 0, time.Local) }
+	pesos := PesosPadrao()
+	pesos["tag.provas"] = 3
+	contexto := NovoContextoUrgencia(pesos, Bloqueios{"bloqueada": {"bloqueando"}}, agora)
+
+	casos := []struct {
+		nome     string
+		tarefa   models.Task
+		esperado float64
+	}{
+		{"prioridade 1", models.Task{ID: "a", Priority: 1, Status: models.TaskStatusPending}, 6},
+		{"prioridade 3", models.Task{ID: "a", Priority: 3, Status: models.TaskStatusPending}, 1.8},
+		{"prazo hoje", models.Task{ID: "a", Priority: 3, DueDate: dia(0), Status: models.TaskStatusPending}, 1.8 + 12*(14*0.8/21+0.2)},
+		{"prazo distante", models.Task{ID: "a", Priority: 3, DueDate: dia(30), Status: models.TaskStatusPending}, 1.8 + 12*0.2},
+		{"prazo vencido há 10 dias", models.Task{ID: "a", Priority: 3, DueDate: dia(-10), Status: models.TaskStatusPending}, 1.8 + 12},
+		{"idade", models.Task{ID: "a", Priority: 3, CreatedAt: agora.AddDate(-2, 0, 0), Status: models.TaskStatusPending}, 1.8 + 2},
+		{"em andamento", models.Task{ID: "a", Priority: 3, Status: models.TaskStatusInProgress}, 1.8 + 4},
+		{"bloqueando", models.Task{ID: "bloqueando", Priority: 3, Status: models.TaskStatusPending}, 1.8 + 8},
+		{"bloqueada", models.Task{ID: "bloqueada", Priority: 3, Status: models.TaskStatusPending}, 1.8 - 5},
+		{"tags", models.Task{ID: "a", Priority: 3, Tags: []string{"Provas", "7B"}, Status: models.TaskStatusPending}, 1.8 + 0.9 + 3},
+		{"concluída", models.Task{ID: "a", Priority: 1, DueDate: dia(-10), Status: models.TaskStatusCompleted}, 0},
+	}
+	for _, c := range casos {
+		if obtido := contexto.Calcular(c.tarefa).Total; math.Abs(obtido-c.esperado) > 1e-9 {
+			t.Errorf("%s: esperado %.4f, obtido %.4f", c.nome, c.esperado, obtido)
+		}
+	}
+
+	u := contexto.Calcular(models.Task{ID: "a", Priority: 2, DueDate: dia(-10), Tags: []string{"provas"}, Status: models.TaskStatusPending})
+	if u.Componentes[0].Criterio != "prazo" || u.String() != "19.7" || !strings.HasPrefix(u.Detalhes(), "prazo 12.0, prioridade 3.9, tag.provas 3.0") {
+		t.Errorf("Componentes inesperados: %s (%s)", u, u.Detalhes())
+	}
+}
+
+func TestPesosUrgencia(t *testing.T) {
+	limparPesos(t)
+	defer limparPesos(t)
+
+	if valor, err := DefinirPeso("Prazo", "15"); err != nil || valor != 15 {
+		t.Fatalf("DefinirPeso(prazo) falhou: %v %v", valor, err)
+	}
+	if _, err := DefinirPeso("tag.Provas", "2,5"); err != nil {
+		t.Fatalf("DefinirPeso(tag.Provas) falhou: %v", err)
+	}
+	pesos, err := CarregarPesos()
+	if err != nil {
+		t.Fatalf("CarregarPesos falhou: %v", err)
+	}
+	if pesos["prazo"] != 15 || pesos["tag.provas"] != 2.5 || pesos["prioridade"] != 6 {
+		t.Errorf("Pesos inesperados: %+v", pesos)
+	}
+
+	for nome, valor := range map[string]string{"pressa": "1", "tag.": "1", "idade": "muito",
+		"prazo": "NaN", "tag.provas": "Inf", "bloqueada": "-infinity", "bloqueando": "1e400"} {
+		if _, err := DefinirPeso(nome, valor); err == nil {
+			t.Errorf("DefinirPeso(%q, %q): esperado erro", nome, valor)
+		}
+	}
+
+	if err := RestaurarPeso("prazo"); err != nil {
+		t.Fatalf("RestaurarPeso falhou: %v", err)
+	}
+	if err := RestaurarPeso("prazo"); err == nil || !strings.Contains(err.Error(), "não foi alterado") {
+		t.Errorf("Esperado erro ao restaurar um peso não alterado, obtido %v", err)
+	}
+	if pesos, _ := CarregarPesos(); pesos["prazo"] != 12 {
+		t.Errorf("Esperado o peso padrão do prazo, obtido %v", pesos["prazo"])
+	}
+}
+
+func TestProximasTarefas(t *testing.T) {
+	LimparTarefasStore()
+	limparPesos(t)
+	defer datas.UsarRelogio(datas.Fixo(time.Date(2024, 8, 14, 10, 0, 0, 0, time.Local)))()
+
+	planejar, _ := CriarTarefa("Planejar bimestre", "2024-09-30", 3, "")
+	corrigir, _ := CriarTarefa("Corrigir provas", "2024-08-15", 2, "provas")
+	lancar, _ := CriarTarefa("Lançar notas", "2024-08-16", 1, "")
+	ligar, _ := CriarTarefa("Ligar para a coordenação", "2024-08-01", 1, "")
+	if err := AdicionarDependencia(lancar.ID, corrigir.ID); err != nil {
+		t.Fatalf("AdicionarDependencia falhou: %v", err)
+	}
+	if _, err := ConcluirTarefa(ligar.ID); err != nil {
+		t.Fatalf("ConcluirTarefa falhou: %v", err)
+	}
+
+	proximas, err := ProximasTarefas(0)
+	if err != nil {
+		t.Fatalf("ProximasTarefas falhou: %v", err)
+	}
+	var ids []string
+	for _, p := range proximas {
+		ids = append(ids, p.Tarefa.ID)
+	}
+	// "Corrigir provas" bloqueia "Lançar notas" (que fica de fora, como a concluída) e vence primeiro.
+	if strings.Join(ids, ",") != corrigir.ID+","+planejar.ID {
+		t.Errorf("Ordem inesperada: %v", ids)
+	}
+	if proximas[0].Urgencia.Total <= proximas[1].Urgencia.Total {
+		t.Errorf("Urgências fora de ordem: %s, %s", proximas[0].Urgencia, proximas[1].Urgencia)
+	}
+	if um, _ := ProximasTarefas(1); len(um) != 1 || um[0].Tarefa.ID != corrigir.ID {
+		t.Errorf("ProximasTarefas(1) inesperado: %+v", um)
+	}
+
+	// Um peso negativo para a tag muda a ordem.
+	if _, err := DefinirPeso("tag.provas", "-20"); err != nil {
+		t.Fatalf("DefinirPeso falhou: %v", err)
+	}
+	if um, _ := ProximasTarefas(1); len(um) != 1 || um[0].Tarefa.ID != planejar.ID {
+		t.Errorf("Esperada 'Planejar bimestre' com o peso negativo, obtido %+v", um)
+	}
+	limparPesos(t)
+
+	listadas, err := ListarTarefas("", 0, "", "", "urgencia", "desc")
+	if err != nil || len(listadas) != 4 || listadas[0].ID != corrigir.ID || listadas[3].ID != ligar.ID {
+		t.Errorf("ListarTarefas por urgência inesperado: %+v (%v)", listadas, err)
+	}
+	buscadas, err := BuscarTarefas("-status:concluida", "urgencia", "asc")
+	if err != nil || len(buscadas) != 3 || buscadas[2].ID != corrigir.ID {
+		t.Errorf("BuscarTarefas por urgência crescente inesperado: %+v (%v)", buscadas, err)
+	}
+}
@@ -0,0 +1,165 @@
+package tarefa
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// Uma tarefa pode ser vinculada a entidades do sistema, gravadas na tabela "task_links": aulas, provas, turmas,
+// alunos e eventos da agenda, como em "corrigir a prova prova123" ou "preparar a aula 3 da 7B".
+// Os vínculos são escritos como "tipo:id" ("prova:prova123", "turma:7B", "aluno:<ID>").
+// Aulas, alunos e eventos precisam existir no banco. As provas e as turmas ainda não são gravadas no banco
+// (as turmas são identificadas pelo nome, como em models.Lesson.ClassID), então os seus IDs são aceitos como informados.
+
+// TiposVinculo são os tipos de entidade aceitos nos vínculos, na ordem em que são exibidos.
+var TiposVinculo = []string{models.TaskLinkLesson, models.TaskLinkTest, models.TaskLinkClass, models.TaskLinkStudent, models.TaskLinkEvent}
+
+// ParseVinculo interpreta um vínculo no formato "tipo:id" (ex: "prova:prova123"). O tipo não diferencia maiúsculas.
+func ParseVinculo(ref string) (tipo, entidadeID string, err error) {
+	partes := strings.SplitN(strings.TrimSpace(ref), ":", 2)
+	if len(partes) != 2 || strings.TrimSpace(partes[1]) == "" {
+		return "", "", fmt.Errorf("vínculo '%s' inválido. Use tipo:id, com tipo %s (ex: prova:prova123)", ref, strings.Join(TiposVinculo, ", "))
+	}
+	tipo = strings.ToLower(strings.TrimSpace(partes[0]))
+	for _, t := range TiposVinculo {
+		if t == tipo {
+			return tipo, strings.TrimSpace(partes[1]), nil
+		}
+	}
+	return "", "", fmt.Errorf("tipo de vínculo '%s' desconhecido. Use %s", partes[0], strings.Join(TiposVinculo, ", "))
+}
+
+// VincularTarefa vincula a tarefa id à entidade ref ("tipo:id").
+// Retorna um erro se a tarefa não existir, se a aula, o aluno ou o evento não existirem ou se o vínculo já existir.
+func VincularTarefa(id, ref string) (models.TaskLink, error) {
+	tipo, entidadeID, err := ParseVinculo(ref)
+	if err != nil {
+		return models.TaskLink{}, err
+	}
+	if _, err := GetTarefaByID(id); err != nil {
+		return models.TaskLink{}, err
+	}
+	if _, err := DescreverEntidade(tipo, entidadeID); err != nil {
+		return models.TaskLink{}, err
+	}
+	existentes, err := db.ListTaskLinks(id, tipo, entidadeID)
+	if err != nil {
+		return models.TaskLink{}, fmt.Errorf("erro ao carregar os vínculos: %w", err)
+	}
+	if len(existentes) > 0 {
+		return models.TaskLink{}, fmt.Errorf("a tarefa '%s' já está vinculada a %s:%s", id, tipo, entidadeID)
+	}
+	vinculo := models.TaskLink{TaskID: id, EntityType: tipo, EntityID: entidadeID}
+	if err := db.AddTaskLink(vinculo); err != nil {
+		return models.TaskLink{}, fmt.Errorf("erro ao salvar o vínculo: %w", err)
+	}
+	return vinculo, nil
+}
+
+// DesvincularTarefa remove o vínculo da tarefa id com a entidade ref ("tipo:id").
+// Retorna um erro se o vínculo não existir.
+func DesvincularTarefa(id, ref string) error {
+	tipo, entidadeID, err := ParseVinculo(ref)
+	if err != nil {
+		return err
+	}
+	if err := db.RemoveTaskLink(id, tipo, entidadeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("a tarefa '%s' não está vinculada a %s:%s", id, tipo, entidadeID)
+		}
+		return fmt.Errorf("erro ao remover o vínculo: %w", err)
+	}
+	return nil
+}
+
+// ListarVinculos devolve os vínculos da tarefa id, agrupados na ordem de TiposVinculo.
+func ListarVinculos(id string) ([]models.TaskLink, error) {
+	vinculos, err := db.ListTaskLinks(id, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os vínculos: %w", err)
+	}
+	ordem := make(map[string]int, len(TiposVinculo))
+	for i, t := range TiposVinculo {
+		ordem[t] = i
+	}
+	sort.SliceStable(vinculos, func(i, j int) bool {
+		return ordem[vinculos[i].EntityType] < ordem[vinculos[j].EntityType]
+	})
+	return vinculos, nil
+}
+
+// TarefasVinculadas devolve as tarefas vinculadas à entidade do tipo e ID informados, ordenadas pelo prazo
+// (as sem prazo por último). Com apenasAbertas, omite as tarefas concluídas.
+func TarefasVinculadas(tipo, entidadeID string, apenasAbertas bool) ([]models.Task, error) {
+	vinculos, err := db.ListTaskLinks("", tipo, entidadeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os vínculos: %w", err)
+	}
+	var tarefas []models.Task
+	for _, v := range vinculos {
+		t, err := GetTarefaByID(v.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if apenasAbertas && t.Status == models.TaskStatusCompleted {
+			continue
+		}
+		tarefas = append(tarefas, t)
+	}
+	sort.SliceStable(tarefas, func(i, j int) bool {
+		a, b := tarefas[i].DueDate, tarefas[j].DueDate
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+	return tarefas, nil
+}
+
+// DescreverEntidade devolve um texto que identifica a entidade para a exibição de um vínculo,
+// como "Aula de Matemática: Equações (7B, 12/08/2024 10:00)". Retorna um erro se a aula, o aluno ou o evento
+// não existirem; provas e turmas são descritas apenas pelo ID.
+func DescreverEntidade(tipo, entidadeID string) (string, error) {
+	conn := db.GetDB()
+	switch tipo {
+	case models.TaskLinkLesson:
+		aula, err := store.NewSQLiteAulaStore(conn).GetLessonByID(entidadeID)
+		if err != nil {
+			return "", erroEntidade(err, "a aula", fmt.Sprintf("aula com ID '%s' não encontrada", entidadeID))
+		}
+		return fmt.Sprintf("Aula de %s: %s (%s, %s)", aula.Subject, aula.Topic, aula.ClassID, aula.Date.Local().Format("02/01/2006 15:04")), nil
+	case models.TaskLinkStudent:
+		aluno, err := store.NewSQLiteStudentStore(conn).GetStudentByID(entidadeID)
+		if err != nil {
+			return "", erroEntidade(err, "o aluno", fmt.Sprintf("aluno com ID '%s' não encontrado", entidadeID))
+		}
+		return "Aluno " + aluno.Name, nil
+	case models.TaskLinkEvent:
+		evento, err := db.GetEvent(entidadeID)
+		if err != nil {
+			return "", erroEntidade(err, "o evento", fmt.Sprintf("evento com ID '%s' não encontrado", entidadeID))
+		}
+		return fmt.Sprintf("Evento %s (%s)", evento.Title, evento.StartTime.Local().Format("02/01/2006 15:04")), nil
+	case models.TaskLinkTest:
+		return "Prova " + entidadeID, nil
+	case models.TaskLinkClass:
+		return "Turma " + entidadeID, nil
+	}
+	return "", fmt.Errorf("tipo de vínculo '%s' desconhecido", tipo)
+}
+
+// erroEntidade traduz o erro da busca de uma entidade: naoEncontrada se ela não existir,
+// ou uma mensagem que cita entidade ("a aula") nos demais casos.
+func erroEntidade(err error, entidade, naoEncontrada string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(naoEncontrada)
+	}
+	return fmt.Errorf("erro ao buscar %s: %w", entidade, err)
+}
@@ -0,0 +1,98 @@
+package tarefa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+func TestVinculosDeTarefas(t *testing.T) {
+	LimparTarefasStore()
+	conn := db.GetDB()
+	aula, err := store.NewSQLiteAulaStore(conn).SaveLesson(models.Lesson{Subject: "Matemática", Topic: "Frações", ClassID: "7B",
+		Date: time.Date(2024, 8, 6, 7, 30, 0, 0, time.Local)})
+	if err != nil {
+		t.Fatalf("SaveLesson falhou: %v", err)
+	}
+	aluno, err := store.NewSQLiteStudentStore(conn).SaveStudent(models.Student{Name: "Ana Souza"})
+	if err != nil {
+		t.Fatalf("SaveStudent falhou: %v", err)
+	}
+	eventoID, err := db.CreateEvent(models.Event{Title: "Reunião de pais", StartTime: time.Date(2024, 8, 9, 19, 0, 0, 0, time.Local),
+		EndTime: time.Date(2024, 8, 9, 20, 0, 0, 0, time.Local)})
+	if err != nil {
+		t.Fatalf("CreateEvent falhou: %v", err)
+	}
+
+	preparar, _ := CriarTarefa("Preparar aula 3 da 7B", "", 2, "")
+	corrigir, _ := CriarTarefa("Corrigir prova123", "2024-08-10", 1, "")
+	revisar, _ := CriarTarefa("Revisar prova123", "2024-08-08", 2, "")
+	ligar, _ := CriarTarefa("Ligar para os pais", "", 2, "")
+
+	for _, v := range [][2]string{
+		{preparar.ID, "aula:" + aula.ID}, {preparar.ID, "turma:7B"}, {corrigir.ID, "PROVA:prova123"},
+		{revisar.ID, "prova:prova123"}, {ligar.ID, "aluno:" + aluno.ID}, {ligar.ID, "evento:" + eventoID},
+	} {
+		if _, err := VincularTarefa(v[0], v[1]); err != nil {
+			t.Fatalf("VincularTarefa(%s) falhou: %v", v[1], err)
+		}
+	}
+
+	t.Run("Vínculos inválidos", func(t *testing.T) {
+		casos := map[string]string{
+			"prova123":              "inválido",
+			"prova:":                "inválido",
+			"livro:123":             "tipo de vínculo 'livro' desconhecido",
+			"aula:id-inexistente":   "aula com ID 'id-inexistente' não encontrada",
+			"aluno:id-inexistente":  "aluno com ID 'id-inexistente' não encontrado",
+			"evento:id-inexistente": "evento com ID 'id-inexistente' não encontrado",
+			"prova:prova123":        "já está vinculada",
+		}
+		for ref, esperado := range casos {
+			if _, err := VincularTarefa(corrigir.ID, ref); err == nil || !strings.Contains(err.Error(), esperado) {
+				t.Errorf("VincularTarefa(%q): esperado erro com '%s', obtido %v", ref, esperado, err)
+			}
+		}
+		if _, err := VincularTarefa("id-inexistente", "turma:7B"); err == nil {
+			t.Errorf("VincularTarefa de tarefa inexistente: esperado erro")
+		}
+	})
+
+	vinculos, err := ListarVinculos(preparar.ID)
+	if err != nil || len(vinculos) != 2 || vinculos[0].EntityType != models.TaskLinkLesson || vinculos[1].EntityID != "7B" {
+		t.Errorf("ListarVinculos inesperado: %+v (%v)", vinculos, err)
+	}
+	if descricao, err := DescreverEntidade(models.TaskLinkLesson, aula.ID); err != nil || descricao != "Aula de Matemática: Frações (7B, 06/08/2024 07:30)" {
+		t.Errorf("DescreverEntidade da aula: obtido %q (%v)", descricao, err)
+	}
+	if descricao, _ := DescreverEntidade(models.TaskLinkStudent, aluno.ID); descricao != "Aluno Ana Souza" {
+		t.Errorf("DescreverEntidade do aluno: obtido %q", descricao)
+	}
+
+	if _, err := ConcluirTarefa(corrigir.ID); err != nil {
+		t.Fatalf("ConcluirTarefa falhou: %v", err)
+	}
+	if abertas, _ := TarefasVinculadas(models.TaskLinkTest, "prova123", true); len(abertas) != 1 || abertas[0].ID != revisar.ID {
+		t.Errorf("Esperada apenas a tarefa aberta da prova, obtido %+v", abertas)
+	}
+	if todas, _ := TarefasVinculadas(models.TaskLinkTest, "prova123", false); len(todas) != 2 || todas[0].ID != revisar.ID {
+		t.Errorf("Esperadas as duas tarefas da prova, ordenadas pelo prazo, obtido %+v", todas)
+	}
+
+	if err := DesvincularTarefa(preparar.ID, "turma:7B"); err != nil {
+		t.Fatalf("DesvincularTarefa falhou: %v", err)
+	}
+	if err := DesvincularTarefa(preparar.ID, "turma:7B"); err == nil || !strings.Contains(err.Error(), "não está vinculada") {
+		t.Errorf("Esperado erro de vínculo inexistente, obtido %v", err)
+	}
+	if err := RemoverTarefa(ligar.ID); err != nil {
+		t.Fatalf("RemoverTarefa falhou: %v", err)
+	}
+	if tarefas, _ := TarefasVinculadas(models.TaskLinkStudent, aluno.ID, false); len(tarefas) != 0 {
+		t.Errorf("Os vínculos da tarefa removida deveriam ser removidos: %+v", tarefas)
+	}
+}
@@ -0,0 +1,217 @@
+// Package datas interpreta as expressões de data aceitas pelas flags e argumentos da CLI,
+// em português: "hoje", "amanhã", "sexta", "próxima segunda", "+3d", "fim do mês", "15/08", "15/08 14h"
+// e os formatos numéricos "YYYY-MM-DD", "DD/MM/YYYY" e "DD-MM-YYYY".
+//
+// As expressões relativas são calculadas a partir de um relógio de referência (Relogio), que os testes
+// substituem por um relógio fixo com UsarRelogio. Analisar recebe a referência explicitamente.
+package datas
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exemplos resume as expressões aceitas, para mensagens de ajuda e de erro.
+const Exemplos = "hoje, amanhã, sexta, próxima segunda, +3d, fim do mês, 15/08, 15/08 14h ou YYYY-MM-DD"
+
+// Relogio devolve o instante de referência das expressões relativas.
+type Relogio func() time.Time
+
+var relogio Relogio = time.Now
+
+// UsarRelogio troca o relógio de referência e devolve uma função que restaura o anterior.
+// Usado em testes: defer datas.UsarRelogio(datas.Fixo(t))().
+func UsarRelogio(r Relogio) (restaurar func()) {
+	anterior := relogio
+	relogio = r
+	return func() { relogio = anterior }
+}
+
+// Fixo devolve um relógio que sempre marca t.
+func Fixo(t time.Time) Relogio {
+	return func() time.Time { return t }
+}
+
+// Agora devolve o instante atual segundo o relógio de referência.
+func Agora() time.Time {
+	return relogio()
+}
+
+// Resultado é uma expressão interpretada.
+type Resultado struct {
+	Momento    time.Time // Data (à meia-noite, se a expressão não tiver horário) no fuso da referência.
+	TemHorario bool      // Indica se a expressão informou um horário (ex: "15/08 14h").
+}
+
+// Data interpreta expr em relação ao relógio de referência e devolve o início do dia resultante;
+// um horário na expressão é ignorado.
+func Data(expr string) (time.Time, error) {
+	r, err := Analisar(expr, Agora())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return inicioDoDia(r.Momento), nil
+}
+
+// DataHora interpreta expr em relação ao relógio de referência, mantendo o horário, se houver.
+func DataHora(expr string) (Resultado, error) {
+	return Analisar(expr, Agora())
+}
+
+var (
+	reHorario      = regexp.MustCompile(`^(\d{1,2})(?:h(\d{2})?|:(\d{2}))$`)
+	reDiaDaSemana  = regexp.MustCompile(`^(?:(proxima|proximo)\s+)?([a-z]+)(?:-feira|\s+feira)?(\s+que\s+vem)?$`)
+	reDeslocamento = regexp.MustCompile(`^(?:([+-])\s*|em\s+)(\d+)\s*([a-z]+)$`)
+	reMes          = regexp.MustCompile(`^(fim|final|inicio|comeco) do (proximo )?mes$`)
+	reDiaMes       = regexp.MustCompile(`^\d{1,2}/\d{1,2}$`)
+)
+
+// semAcentos remove os acentos usados nas expressões, para aceitar "amanha" e "amanhã", "proxima" e "próxima".
+var semAcentos = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c")
+
+var diasDaSemana = map[string]time.Weekday{
+	"domingo": time.Sunday, "dom": time.Sunday,
+	"segunda": time.Monday, "seg": time.Monday,
+	"terca": time.Tuesday, "ter": time.Tuesday,
+	"quarta": time.Wednesday, "qua": time.Wednesday,
+	"quinta": time.Thursday, "qui": time.Thursday,
+	"sexta": time.Friday, "sex": time.Friday,
+	"sabado": time.Saturday, "sab": time.Saturday,
+}
+
+// layoutsNumericos são os formatos numéricos completos aceitos, com o dia e o mês de um ou dois dígitos.
+var layoutsNumericos = []string{"2006-1-2", "2/1/2006", "2-1-2006"}
+
+// Analisar interpreta expr em relação a ref. Expressões aceitas (sem diferenciar maiúsculas nem acentos):
+//   - "hoje", "amanhã", "depois de amanhã", "ontem";
+//   - um dia da semana ("sexta", "sex", "sexta-feira"): a próxima ocorrência, podendo ser hoje;
+//     "próxima sexta" (ou "sexta que vem") é sempre depois de hoje;
+//   - deslocamentos "+3d", "-1d", "+2s" (semanas), "+1m" (meses) e "em 3 dias";
+//   - "fim do mês", "início do mês", "fim do próximo mês";
+//   - "15/08" (no ano de ref), "15/08/2024", "15-08-2024" e "2024-08-15".
+//
+// Qualquer uma delas pode terminar com um horário: "14h", "14h30", "14:30" ou "às 14h".
+// Um horário sozinho ("14h") se refere a hoje.
+func Analisar(expr string, ref time.Time) (Resultado, error) {
+	campos := strings.Fields(semAcentos.Replace(strings.ToLower(expr)))
+	if len(campos) == 0 {
+		return Resultado{}, fmt.Errorf("data vazia. Use, por exemplo, %s", Exemplos)
+	}
+
+	var hora, minuto int
+	temHorario := false
+	if m := reHorario.FindStringSubmatch(campos[len(campos)-1]); m != nil {
+		hora, _ = strconv.Atoi(m[1])
+		minuto, _ = strconv.Atoi("0" + m[2] + m[3]) // No máximo um dos dois grupos é preenchido.
+		if hora > 23 || minuto > 59 {
+			return Resultado{}, fmt.Errorf("horário '%s' inválido", campos[len(campos)-1])
+		}
+		temHorario = true
+		campos = campos[:len(campos)-1]
+		if n := len(campos); n > 0 && (campos[n-1] == "as" || campos[n-1] == "a") {
+			campos = campos[:n-1]
+		}
+	}
+	texto := strings.Join(campos, " ")
+
+	dia, ok := analisarDia(texto, ref)
+	if !ok {
+		return Resultado{}, fmt.Errorf("data '%s' não reconhecida. Use, por exemplo, %s", strings.TrimSpace(expr), Exemplos)
+	}
+	return Resultado{
+		Momento:    time.Date(dia.Year(), dia.Month(), dia.Day(), hora, minuto, 0, 0, ref.Location()),
+		TemHorario: temHorario,
+	}, nil
+}
+
+// analisarDia interpreta a parte da expressão sem o horário e devolve o início do dia correspondente.
+// Texto vazio (só horário) é hoje.
+func analisarDia(texto string, ref time.Time) (time.Time, bool) {
+	hoje := inicioDoDia(ref)
+	switch texto {
+	case "", "hoje":
+		return hoje, true
+	case "amanha":
+		return hoje.AddDate(0, 0, 1), true
+	case "depois de amanha":
+		return hoje.AddDate(0, 0, 2), true
+	case "ontem":
+		return hoje.AddDate(0, 0, -1), true
+	}
+
+	if m := reDiaDaSemana.FindStringSubmatch(texto); m != nil {
+		if alvo, ok := diasDaSemana[m[2]]; ok {
+			dias := (int(alvo) - int(hoje.Weekday()) + 7) % 7
+			if dias == 0 && (m[1] != "" || m[3] != "") {
+				dias = 7
+			}
+			return hoje.AddDate(0, 0, dias), true
+		}
+	}
+
+	if m := reDeslocamento.FindStringSubmatch(texto); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, false
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "d", "dia", "dias":
+			return hoje.AddDate(0, 0, n), true
+		case "s", "sem", "semana", "semanas":
+			return hoje.AddDate(0, 0, 7*n), true
+		case "m", "mes", "meses":
+			return somarMeses(hoje, n), true
+		}
+		return time.Time{}, false
+	}
+
+	if m := reMes.FindStringSubmatch(texto); m != nil {
+		inicioMes := time.Date(hoje.Year(), hoje.Month(), 1, 0, 0, 0, 0, hoje.Location())
+		if m[2] != "" {
+			inicioMes = inicioMes.AddDate(0, 1, 0)
+		}
+		if m[1] == "fim" || m[1] == "final" {
+			return inicioMes.AddDate(0, 1, -1), true
+		}
+		return inicioMes, true
+	}
+
+	if reDiaMes.MatchString(texto) {
+		texto += "/" + strconv.Itoa(hoje.Year())
+	}
+	for _, layout := range layoutsNumericos {
+		if t, err := time.ParseInLocation(layout, texto, hoje.Location()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// somarMeses soma n meses a t, limitando o dia ao último dia do mês resultante (31/01 + 1 mês = 28 ou 29/02).
+func somarMeses(t time.Time, n int) time.Time {
+	primeiro := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, n, 0)
+	ultimoDia := primeiro.AddDate(0, 1, -1).Day()
+	dia := t.Day()
+	if dia > ultimoDia {
+		dia = ultimoDia
+	}
+	return time.Date(primeiro.Year(), primeiro.Month(), dia, 0, 0, 0, 0, t.Location())
+}
+
+// DataUTC devolve a meia-noite UTC do dia civil de t. Usado pelos cadastros que sempre gravaram datas
+// sem horário em UTC (bimestres, notas, filtros de aulas), para que "15/08" e "2024-08-15" continuem idênticos.
+func DataUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// inicioDoDia devolve a meia-noite do dia de t, no fuso de t.
+func inicioDoDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,93 @@
+package datas
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalisar(t *testing.T) {
+	// 13/03/2024 é uma quarta-feira.
+	ref := time.Date(2024, 3, 13, 10, 30, 0, 0, time.Local)
+	casos := []struct {
+		expr, esperado string
+		horario        bool
+	}{
+		{"hoje", "2024-03-13 00:00", false},
+		{"Amanhã", "2024-03-14 00:00", false},
+		{"amanha", "2024-03-14 00:00", false},
+		{"depois de amanhã", "2024-03-15 00:00", false},
+		{"ontem", "2024-03-12 00:00", false},
+		{"sexta", "2024-03-15 00:00", false},
+		{"sexta-feira", "2024-03-15 00:00", false},
+		{"quarta", "2024-03-13 00:00", false},
+		{"próxima quarta", "2024-03-20 00:00", false},
+		{"quarta que vem", "2024-03-20 00:00", false},
+		{"próxima segunda", "2024-03-18 00:00", false},
+		{"sáb", "2024-03-16 00:00", false},
+		{"+3d", "2024-03-16 00:00", false},
+		{"-1d", "2024-03-12 00:00", false},
+		{"+2s", "2024-03-27 00:00", false},
+		{"+1m", "2024-04-13 00:00", false},
+		{"em 10 dias", "2024-03-23 00:00", false},
+		{"fim do mês", "2024-03-31 00:00", false},
+		{"início do mês", "2024-03-01 00:00", false},
+		{"fim do próximo mês", "2024-04-30 00:00", false},
+		{"15/08", "2024-08-15 00:00", false},
+		{"5/8", "2024-08-05 00:00", false},
+		{"15/08/2025", "2025-08-15 00:00", false},
+		{"15-08-2025", "2025-08-15 00:00", false},
+		{"2025-08-15", "2025-08-15 00:00", false},
+		{"15/08 14h", "2024-08-15 14:00", true},
+		{"amanhã às 7h30", "2024-03-14 07:30", true},
+		{"2024-03-05 14:30", "2024-03-05 14:30", true},
+		{"  Próxima   Sexta  16h ", "2024-03-15 16:00", true},
+		{"14h", "2024-03-13 14:00", true},
+	}
+	for _, c := range casos {
+		r, err := Analisar(c.expr, ref)
+		if err != nil {
+			t.Errorf("Analisar(%q) falhou: %v", c.expr, err)
+			continue
+		}
+		if got := r.Momento.Format("2006-01-02 15:04"); got != c.esperado || r.TemHorario != c.horario {
+			t.Errorf("Analisar(%q) = %s (horário: %v); esperado %s (horário: %v)", c.expr, got, r.TemHorario, c.esperado, c.horario)
+		}
+	}
+
+	for _, expr := range []string{"", "depois", "31/02", "15/13/2024", "+3x", "sexta 25h", "14h61", "2024/03/05", "amanhã de manhã"} {
+		if _, err := Analisar(expr, ref); err == nil {
+			t.Errorf("Analisar(%q): esperado erro", expr)
+		}
+	}
+}
+
+func TestSomarMesesLimitaODia(t *testing.T) {
+	ref := time.Date(2024, 1, 31, 9, 0, 0, 0, time.Local)
+	r, err := Analisar("+1m", ref)
+	if err != nil || r.Momento.Format("2006-01-02") != "2024-02-29" {
+		t.Errorf("31/01 + 1 mês: esperado 2024-02-29, obtido %v (%v)", r.Momento, err)
+	}
+}
+
+func TestRelogioFixo(t *testing.T) {
+	defer UsarRelogio(Fixo(time.Date(2024, 12, 30, 23, 0, 0, 0, time.Local)))()
+
+	d, err := Data("amanhã 10h")
+	if err != nil {
+		t.Fatalf("Data falhou: %v", err)
+	}
+	if d.Format("2006-01-02 15:04") != "2024-12-31 00:00" {
+		t.Errorf("Data deveria usar o relógio fixo e ignorar o horário: %v", d)
+	}
+	r, err := DataHora("+2d 08:00")
+	if err != nil || r.Momento.Format("2006-01-02 15:04") != "2025-01-01 08:00" {
+		t.Errorf("DataHora inesperado: %v (%v)", r.Momento, err)
+	}
+	if u := DataUTC(d); u.Location() != time.UTC || u.Format("2006-01-02 15:04") != "2024-12-31 00:00" {
+		t.Errorf("DataUTC deveria manter o dia civil: %v", u)
+	}
+	if _, err := Data("quando der"); err == nil || !strings.Contains(err.Error(), Exemplos) {
+		t.Errorf("A mensagem de erro deveria listar os exemplos, obtido %v", err)
+	}
+}
@@ -19,8 +19,20 @@ import (
 
 var db *sql.DB
 
-// InitDB initializes the database connection and creates tables if they don't exist.
+// InitDB initializes the database connection and applies any pending schema migrations.
 func InitDB(dbPath string) error { // Modified to accept dbPath
+	if err := OpenDB(dbPath); err != nil {
+		return err
+	}
+	if err := Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+// OpenDB opens the database connection without touching the schema.
+// It is used by commands that manage migrations explicitly, such as "db migrate".
+func OpenDB(dbPath string) error {
 	if dbPath == "" { // If dbPath is empty, use the default production path
 		configDir, err := os.UserConfigDir()
 		if err != nil {
@@ -45,11 +57,17 @@ func InitDB(dbPath string) error { // Modified to accept dbPath
 		return fmt.Errorf("failed to open database at %s: %w", dbPath, sqlErr)
 	}
 
+	// The journal triggers and the session of the running command live in the TEMP schema of
+	// the connection, so every query must go through the same one.
+	db.SetMaxOpenConns(1)
+
 	if err := db.Ping(); err != nil { // Use := to declare err locally
 		return fmt.Errorf("failed to ping database at %s: %w", dbPath, err)
 	}
-
-	return createTables()
+	if err := installJournal(db); err != nil {
+		return fmt.Errorf("failed to install the operation journal: %w", err)
+	}
+	return nil
 }
 
 // GetDB returns the initialized database instance.
@@ -57,179 +75,6 @@ func GetDB() *sql.DB {
 	return db
 }
 
-// createTables creates the necessary tables in the database if they don't already exist.
-func createTables() error {
-	questionsTableSQL := `
-	CREATE TABLE IF NOT EXISTS questions (
-		id TEXT PRIMARY KEY,
-		subject TEXT NOT NULL,
-		topic TEXT NOT NULL,
-		difficulty TEXT NOT NULL,
-		question_text TEXT NOT NULL,
-		answer_options TEXT,
-		correct_answers TEXT NOT NULL,
-		question_type TEXT NOT NULL,
-		source TEXT,
-		tags TEXT,
-		created_at TIMESTAMP NOT NULL,
-		last_used_at TIMESTAMP,
-		author TEXT
-	);`
-
-	_, err := db.Exec(questionsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create questions table: %w", err)
-	}
-
-	tasksTableSQL := `
-	CREATE TABLE IF NOT EXISTS tasks (
-		id TEXT PRIMARY KEY,
-		description TEXT NOT NULL,
-		due_date TIMESTAMP,
-		priority INTEGER,
-		status TEXT,
-		tags TEXT, -- Store as JSON array
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(tasksTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create tasks table: %w", err)
-	}
-
-	eventsTableSQL := `
-	CREATE TABLE IF NOT EXISTS events (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		description TEXT,
-		start_time TIMESTAMP NOT NULL,
-		end_time TIMESTAMP NOT NULL,
-		location TEXT,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(eventsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create events table: %w", err)
-	}
-
-	routinesTableSQL := `
-	CREATE TABLE IF NOT EXISTS routines (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		description TEXT,
-		frequency TEXT,
-		task_description TEXT,
-		task_priority INTEGER,
-		task_tags TEXT, -- Store as JSON array
-		next_run_time TIMESTAMP,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(routinesTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create routines table: %w", err)
-	}
-
-	termsTableSQL := `
-	CREATE TABLE IF NOT EXISTS terms (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		academic_year TEXT,
-		start_date TIMESTAMP,
-		end_date TIMESTAMP,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(termsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create terms table: %w", err)
-	}
-
-	studentsTableSQL := `
-	CREATE TABLE IF NOT EXISTS students (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		class_id TEXT,
-		email TEXT,
-		date_of_birth TIMESTAMP,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(studentsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create students table: %w", err)
-	}
-
-	lessonsTableSQL := `
-	CREATE TABLE IF NOT EXISTS lessons (
-		id TEXT PRIMARY KEY,
-		subject TEXT,
-		topic TEXT,
-		date TIMESTAMP,
-		class_id TEXT,
-		plan TEXT,
-		observations TEXT,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(lessonsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create lessons table: %w", err)
-	}
-
-	gradesTableSQL := `
-	CREATE TABLE IF NOT EXISTS grades (
-		id TEXT PRIMARY KEY,
-		student_id TEXT,
-		term_id TEXT,
-		subject TEXT,
-		description TEXT,
-		value REAL,
-		weight REAL,
-		date TIMESTAMP,
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(gradesTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create grades table: %w", err)
-	}
-
-	classesTableSQL := `
-	CREATE TABLE IF NOT EXISTS classes (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		level TEXT,
-		academic_year TEXT,
-		term_ids TEXT, -- Store as JSON array
-		subject_ids TEXT, -- Store as JSON array
-		student_ids TEXT, -- Store as JSON array
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(classesTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create classes table: %w", err)
-	}
-
-	subjectsTableSQL := `
-	CREATE TABLE IF NOT EXISTS subjects (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		description TEXT,
-		teacher_ids TEXT, -- Store as JSON array
-		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP
-	);`
-	_, err = db.Exec(subjectsTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create subjects table: %w", err)
-	}
-
-	return nil
-}
-
 // --- CRUD Functions for Question Model ---
 
 // CreateQuestion adds a new question to the database.
@@ -583,98 +428,1742 @@ func ListQuestions(filters map[string]interface{}, sortBy string, order string,
 
 // --- CRUD Functions for Task Model ---
 
+// taskColumns is the column list shared by every task SELECT, in scanTask order.
+const taskColumns = "id, description, due_date, priority, status, tags, created_at, updated_at, parent_id, project_id"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx, so inserts can run inside a transaction.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// scanTask reads a task row selected with taskColumns.
+func scanTask(row rowScanner) (models.Task, error) {
+	var t models.Task
+	var dueDate, updatedAt sql.NullTime
+	var status, tagsJSON, parentID, projectID sql.NullString
+	var priority sql.NullInt64
+
+	if err := row.Scan(&t.ID, &t.Description, &dueDate, &priority, &status, &tagsJSON, &t.CreatedAt, &updatedAt, &parentID, &projectID); err != nil {
+		return models.Task{}, err
+	}
+	t.ParentID = parentID.String
+	t.ProjectID = projectID.String
+	if dueDate.Valid {
+		t.DueDate = dueDate.Time
+	}
+	if updatedAt.Valid {
+		t.UpdatedAt = updatedAt.Time
+	}
+	t.Priority = int(priority.Int64)
+	t.Status = status.String
+	if tagsJSON.Valid && tagsJSON.String != "" {
+		if err := json.Unmarshal([]byte(tagsJSON.String), &t.Tags); err != nil {
+			return models.Task{}, fmt.Errorf("failed to unmarshal Tags for task ID %s: %w", t.ID, err)
+		}
+	}
+	return t, nil
+}
+
+// nullableTime converts a zero time.Time into a SQL NULL.
+func nullableTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// nullableString maps an empty string to SQL NULL, for optional columns with unique indexes.
+func nullableString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
 // CreateTask adds a new task to the database.
+// It generates a new UUID for task.ID if it's empty and sets CreatedAt/UpdatedAt if they are zero.
 func CreateTask(task models.Task) (string, error) {
-	log.Printf("CreateTask called with: %+v", task)
-	return "", errors.New("CreateTask not implemented")
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	return insertTask(db, task)
+}
+
+// insertTask fills the defaults documented in CreateTask and inserts task using ex.
+func insertTask(ex execer, task models.Task) (string, error) {
+	if task.ID == "" {
+		task.ID = uuid.NewString()
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	if task.UpdatedAt.IsZero() {
+		task.UpdatedAt = task.CreatedAt
+	}
+
+	tagsJSON, err := json.Marshal(task.Tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Tags: %w", err)
+	}
+
+	_, err = ex.Exec(`
+		INSERT INTO tasks (id, description, due_date, priority, status, tags, created_at, updated_at, parent_id, project_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, task.ID, task.Description, nullableTime(task.DueDate), task.Priority, task.Status, string(tagsJSON), task.CreatedAt, task.UpdatedAt,
+		nullableString(task.ParentID), nullableString(task.ProjectID))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute insert statement for task: %w", err)
+	}
+	return task.ID, nil
 }
 
 // GetTask retrieves a task by its ID.
+// The returned error wraps sql.ErrNoRows when no task has the given ID.
 func GetTask(id string) (models.Task, error) {
-	log.Printf("GetTask called with id: %s", id)
-	return models.Task{}, errors.New("GetTask not implemented")
+	if db == nil {
+		return models.Task{}, errors.New("database is not initialized")
+	}
+	t, err := scanTask(db.QueryRow("SELECT "+taskColumns+" FROM tasks WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Task{}, fmt.Errorf("task with ID %s not found: %w", id, err)
+		}
+		return models.Task{}, fmt.Errorf("failed to scan task row: %w", err)
+	}
+	return t, nil
+}
+
+// Condition is a boolean SQL expression over the columns of a table, with the arguments of its placeholders.
+// It lets callers that compile their own queries, like the task query language of the tarefa package,
+// filter a List function without a filter key per operator. The SQL must come from code, never from user input.
+type Condition struct {
+	SQL  string
+	Args []interface{}
 }
 
 // ListTasks retrieves a paginated and filtered list of tasks.
+// Supported filters:
+//   - "status" (string): case-insensitive status match.
+//   - "priority" (int): exact priority; values <= 0 are ignored.
+//   - "tag" (string): tasks whose tags contain the value, case-insensitive.
+//   - "due_before" (time.Time): tasks due on or before the time; tasks without a due date are kept.
+//   - "description" (string): substring match on the description.
+//   - "parent_id" (string): the direct subtasks of the given task.
+//   - "project_id" (string): the tasks of the given project.
+//   - "condition" (Condition): an arbitrary boolean expression over the tasks columns, ANDed with the others.
+//
+// sortBy must be one of description, due_date, priority, status, created_at or updated_at;
+// tasks without a due date always sort last when sorting by due_date.
+// Unlike ListQuestions, a limit <= 0 returns every matching task.
 func ListTasks(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Task, int, error) {
-	log.Printf("ListTasks called with filters: %+v, sortBy: %s, order: %s, limit: %d, page: %d", filters, sortBy, order, limit, page)
-	return nil, 0, errors.New("ListTasks not implemented")
+	if db == nil {
+		return nil, 0, errors.New("database is not initialized")
+	}
+
+	whereClauses := []string{}
+	args := []interface{}{}
+
+	for key, value := range filters {
+		switch key {
+		case "status":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "LOWER(status) = LOWER(?)")
+				args = append(args, v)
+			}
+		case "priority":
+			if v, ok := value.(int); ok && v > 0 {
+				whereClauses = append(whereClauses, "priority = ?")
+				args = append(args, v)
+			}
+		case "tag":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM json_each(tasks.tags) WHERE LOWER(json_each.value) = LOWER(?))")
+				args = append(args, v)
+			}
+		case "due_before":
+			if v, ok := value.(time.Time); ok && !v.IsZero() {
+				whereClauses = append(whereClauses, "(due_date IS NULL OR due_date <= ?)")
+				args = append(args, v)
+			}
+		case "description":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "description LIKE ?")
+				args = append(args, "%"+v+"%")
+			}
+		case "parent_id", "project_id":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, key+" = ?")
+				args = append(args, v)
+			}
+		case "condition":
+			if v, ok := value.(Condition); ok && v.SQL != "" {
+				whereClauses = append(whereClauses, "("+v.SQL+")")
+				args = append(args, v.Args...)
+			}
+		default:
+			return nil, 0, fmt.Errorf("invalid task filter: %s", key)
+		}
+	}
+
+	whereString := ""
+	if len(whereClauses) > 0 {
+		whereString = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var totalCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks"+whereString, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	if totalCount == 0 {
+		return []models.Task{}, 0, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString("SELECT " + taskColumns + " FROM tasks" + whereString)
+
+	direction := " ASC"
+	if strings.ToUpper(order) == "DESC" {
+		direction = " DESC"
+	}
+	switch strings.ToLower(sortBy) {
+	case "":
+		queryBuilder.WriteString(" ORDER BY created_at" + direction)
+	case "due_date":
+		queryBuilder.WriteString(" ORDER BY due_date IS NULL, due_date" + direction + ", created_at ASC")
+	case "description", "priority", "status", "created_at", "updated_at":
+		queryBuilder.WriteString(fmt.Sprintf(" ORDER BY %s%s, created_at ASC", strings.ToLower(sortBy), direction))
+	default:
+		return nil, 0, fmt.Errorf("invalid sort_by column: %s", sortBy)
+	}
+
+	if limit > 0 {
+		if page <= 0 {
+			page = 1
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" LIMIT %d OFFSET %d", limit, (page-1)*limit))
+	}
+
+	rows, err := db.Query(queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task during list: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating task rows: %w", err)
+	}
+
+	return tasks, totalCount, nil
 }
 
 // UpdateTask updates an existing task in the database.
+// It returns sql.ErrNoRows if no task with the given ID is found.
 func UpdateTask(task models.Task) error {
-	log.Printf("UpdateTask called with: %+v", task)
-	return errors.New("UpdateTask not implemented")
+	if task.ID == "" {
+		return errors.New("cannot update task without ID")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	tagsJSON, err := json.Marshal(task.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Tags for update: %w", err)
+	}
+	if task.UpdatedAt.IsZero() {
+		task.UpdatedAt = time.Now()
+	}
+
+	res, err := db.Exec(`
+		UPDATE tasks SET
+			description = ?, due_date = ?, priority = ?, status = ?, tags = ?, updated_at = ?, parent_id = ?, project_id = ?
+		WHERE id = ?
+	`, task.Description, nullableTime(task.DueDate), task.Priority, task.Status, string(tagsJSON), task.UpdatedAt,
+		nullableString(task.ParentID), nullableString(task.ProjectID), task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update statement for task ID %s: %w", task.ID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for task ID %s: %w", task.ID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-// DeleteTask removes a task from the database by its ID.
+// DeleteTask removes a task from the database by its ID, along with its dependency links, entity links and time entries;
+// its subtasks move up to the task's parent.
+// It returns sql.ErrNoRows if no task with the given ID is found.
 func DeleteTask(id string) error {
-	log.Printf("DeleteTask called with id: %s", id)
-	return errors.New("DeleteTask not implemented")
+	if id == "" {
+		return errors.New("cannot delete task without ID: ID cannot be empty")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction to delete task %s: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	// Subtasks are not deleted: they move up to the parent of the deleted task (or to the top level).
+	var parentID sql.NullString
+	if err := tx.QueryRow("SELECT parent_id FROM tasks WHERE id = ?", id).Scan(&parentID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE tasks SET parent_id = ? WHERE parent_id = ?", parentID, id); err != nil {
+		return fmt.Errorf("failed to detach subtasks of task %s: %w", id, err)
+	}
+	// Dependency links in either direction go away with the task.
+	if _, err := tx.Exec("DELETE FROM task_dependencies WHERE task_id = ? OR depends_on_id = ?", id, id); err != nil {
+		return fmt.Errorf("failed to delete dependencies of task %s: %w", id, err)
+	}
+	if _, err := tx.Exec("DELETE FROM task_links WHERE task_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete links of task %s: %w", id, err)
+	}
+	if _, err := tx.Exec("DELETE FROM task_time_entries WHERE task_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete time entries of task %s: %w", id, err)
+	}
+	if _, err := tx.Exec("DELETE FROM tasks WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete task %s: %w", id, err)
+	}
+	return tx.Commit()
 }
 
-// --- CRUD Functions for Event Model ---
+// --- Task Dependencies ---
 
-// CreateEvent adds a new event to the database.
-func CreateEvent(event models.Event) (string, error) {
-	log.Printf("CreateEvent called with: %+v", event)
-	return "", errors.New("CreateEvent not implemented")
+// AddTaskDependency records that taskID depends on dependsOnID. CreatedAt is set if it is zero.
+// It does not check that the tasks exist nor look for cycles; callers validate the graph first.
+// Adding an existing dependency fails with a primary key violation.
+func AddTaskDependency(dep models.TaskDependency) error {
+	if dep.TaskID == "" || dep.DependsOnID == "" {
+		return errors.New("task dependency requires both task IDs")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now()
+	}
+	if _, err := db.Exec("INSERT INTO task_dependencies (task_id, depends_on_id, created_at) VALUES (?, ?, ?)",
+		dep.TaskID, dep.DependsOnID, dep.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert dependency of task %s on %s: %w", dep.TaskID, dep.DependsOnID, err)
+	}
+	return nil
 }
 
-// GetEvent retrieves an event by its ID.
-func GetEvent(id string) (models.Event, error) {
-	log.Printf("GetEvent called with id: %s", id)
-	return models.Event{}, errors.New("GetEvent not implemented")
+// RemoveTaskDependency deletes the dependency of taskID on dependsOnID.
+// Returns sql.ErrNoRows if there is no such dependency.
+func RemoveTaskDependency(taskID, dependsOnID string) error {
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	res, err := db.Exec("DELETE FROM task_dependencies WHERE task_id = ? AND depends_on_id = ?", taskID, dependsOnID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dependency of task %s on %s: %w", taskID, dependsOnID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for dependency of task %s on %s: %w", taskID, dependsOnID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-// ListEvents retrieves a paginated and filtered list of events.
-func ListEvents(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Event, int, error) {
-	log.Printf("ListEvents called with filters: %+v, sortBy: %s, order: %s, limit: %d, page: %d", filters, sortBy, order, limit, page)
-	return nil, 0, errors.New("ListEvents not implemented")
+// ListTaskDependencies returns dependency links ordered by creation time.
+// A non-empty taskID keeps only the prerequisites of that task; a non-empty dependsOnID keeps only
+// the tasks that depend on it. With both empty, every dependency is returned.
+func ListTaskDependencies(taskID, dependsOnID string) ([]models.TaskDependency, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := "SELECT task_id, depends_on_id, created_at FROM task_dependencies"
+	var conditions []string
+	var args []interface{}
+	if taskID != "" {
+		conditions = append(conditions, "task_id = ?")
+		args = append(args, taskID)
+	}
+	if dependsOnID != "" {
+		conditions = append(conditions, "depends_on_id = ?")
+		args = append(args, dependsOnID)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := db.Query(query+" ORDER BY created_at ASC, task_id ASC, depends_on_id ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []models.TaskDependency
+	for rows.Next() {
+		var dep models.TaskDependency
+		if err := rows.Scan(&dep.TaskID, &dep.DependsOnID, &dep.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of task dependencies: %w", err)
+	}
+	return deps, nil
 }
 
-// UpdateEvent updates an existing event in the database.
-func UpdateEvent(event models.Event) error {
-	log.Printf("UpdateEvent called with: %+v", event)
-	return errors.New("UpdateEvent not implemented")
+// --- Task Links ---
+
+// AddTaskLink records a link from a task to an entity. CreatedAt is set if it is zero.
+// It does not check that the task or the entity exist; callers validate them first.
+// Adding an existing link fails with a primary key violation.
+func AddTaskLink(link models.TaskLink) error {
+	if link.TaskID == "" || link.EntityType == "" || link.EntityID == "" {
+		return errors.New("task link requires the task ID, the entity type and the entity ID")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+	if _, err := db.Exec("INSERT INTO task_links (task_id, entity_type, entity_id, created_at) VALUES (?, ?, ?, ?)",
+		link.TaskID, link.EntityType, link.EntityID, link.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert link of task %s to %s %s: %w", link.TaskID, link.EntityType, link.EntityID, err)
+	}
+	return nil
 }
 
-// DeleteEvent removes an event from the database by its ID.
-func DeleteEvent(id string) error {
-	log.Printf("DeleteEvent called with id: %s", id)
-	return errors.New("DeleteEvent not implemented")
+// RemoveTaskLink deletes the link from taskID to the entity.
+// Returns sql.ErrNoRows if there is no such link.
+func RemoveTaskLink(taskID, entityType, entityID string) error {
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	res, err := db.Exec("DELETE FROM task_links WHERE task_id = ? AND entity_type = ? AND entity_id = ?", taskID, entityType, entityID)
+	if err != nil {
+		return fmt.Errorf("failed to delete link of task %s to %s %s: %w", taskID, entityType, entityID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for link of task %s to %s %s: %w", taskID, entityType, entityID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-// --- CRUD Functions for Routine Model ---
+// ListTaskLinks returns task links ordered by creation time.
+// A non-empty taskID keeps only the links of that task; non-empty entityType and entityID keep only
+// the links to that kind of entity and to that entity. With all empty, every link is returned.
+func ListTaskLinks(taskID, entityType, entityID string) ([]models.TaskLink, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := "SELECT task_id, entity_type, entity_id, created_at FROM task_links"
+	var conditions []string
+	var args []interface{}
+	if taskID != "" {
+		conditions = append(conditions, "task_id = ?")
+		args = append(args, taskID)
+	}
+	if entityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, entityType)
+	}
+	if entityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, entityID)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := db.Query(query+" ORDER BY created_at ASC, task_id ASC, entity_type ASC, entity_id ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task links: %w", err)
+	}
+	defer rows.Close()
 
-// CreateRoutine adds a new routine to the database.
-func CreateRoutine(routine models.Routine) (string, error) {
-	log.Printf("CreateRoutine called with: %+v", routine)
-	return "", errors.New("CreateRoutine not implemented")
+	var links []models.TaskLink
+	for rows.Next() {
+		var link models.TaskLink
+		if err := rows.Scan(&link.TaskID, &link.EntityType, &link.EntityID, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of task links: %w", err)
+	}
+	return links, nil
 }
 
-// GetRoutine retrieves a routine by its ID.
-func GetRoutine(id string) (models.Routine, error) {
-	log.Printf("GetRoutine called with id: %s", id)
-	return models.Routine{}, errors.New("GetRoutine not implemented")
+// --- Task Time Entries ---
+
+// timeEntryColumns is the column list shared by every time entry SELECT, in scanTimeEntry order.
+const timeEntryColumns = "id, task_id, start_time, end_time, manual, created_at"
+
+// scanTimeEntry reads a time entry row selected with timeEntryColumns.
+func scanTimeEntry(row rowScanner) (models.TaskTimeEntry, error) {
+	var e models.TaskTimeEntry
+	var end sql.NullTime
+	if err := row.Scan(&e.ID, &e.TaskID, &e.StartTime, &end, &e.Manual, &e.CreatedAt); err != nil {
+		return models.TaskTimeEntry{}, err
+	}
+	if end.Valid {
+		e.EndTime = end.Time
+	}
+	return e, nil
 }
 
-// ListRoutines retrieves a paginated and filtered list of routines.
-func ListRoutines(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Routine, int, error) {
-	log.Printf("ListRoutines called with filters: %+v, sortBy: %s, order: %s, limit: %d, page: %d", filters, sortBy, order, limit, page)
-	return nil, 0, errors.New("ListRoutines not implemented")
+// CreateTimeEntry records a work interval on a task.
+// It generates a new UUID for entry.ID if it's empty and sets CreatedAt if it is zero.
+// Open entries (zero EndTime) should be created with StartTaskTimer, which keeps a single timer running.
+func CreateTimeEntry(entry models.TaskTimeEntry) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	return insertTimeEntry(db, entry)
 }
 
-// UpdateRoutine updates an existing routine in the database.
-func UpdateRoutine(routine models.Routine) error {
-	log.Printf("UpdateRoutine called with: %+v", routine)
-	return errors.New("UpdateRoutine not implemented")
+// insertTimeEntry validates entry, fills the defaults documented in CreateTimeEntry and inserts it using ex.
+func insertTimeEntry(ex execer, entry models.TaskTimeEntry) (string, error) {
+	if entry.TaskID == "" || entry.StartTime.IsZero() {
+		return "", errors.New("time entry requires a task ID and a start time")
+	}
+	if !entry.EndTime.IsZero() && entry.EndTime.Before(entry.StartTime) {
+		return "", errors.New("time entry cannot end before it starts")
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	_, err := ex.Exec("INSERT INTO task_time_entries ("+timeEntryColumns+") VALUES (?, ?, ?, ?, ?, ?)",
+		entry.ID, entry.TaskID, entry.StartTime, nullableTime(entry.EndTime), entry.Manual, entry.CreatedAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert time entry for task %s: %w", entry.TaskID, err)
+	}
+	return entry.ID, nil
 }
 
-// DeleteRoutine removes a routine from the database by its ID.
-func DeleteRoutine(id string) error {
-	log.Printf("DeleteRoutine called with id: %s", id)
-	return errors.New("DeleteRoutine not implemented")
+// StartTaskTimer opens a time entry for taskID starting at start. A timer already running, on any task,
+// is stopped at start in the same transaction and returned as stopped (zero value if none was running).
+func StartTaskTimer(taskID string, start time.Time) (started, stopped models.TaskTimeEntry, err error) {
+	if db == nil {
+		return models.TaskTimeEntry{}, models.TaskTimeEntry{}, errors.New("database is not initialized")
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return models.TaskTimeEntry{}, models.TaskTimeEntry{}, fmt.Errorf("failed to begin transaction to start timer: %w", err)
+	}
+	defer tx.Rollback()
+
+	stopped, err = stopRunningTimer(tx, start)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.TaskTimeEntry{}, models.TaskTimeEntry{}, err
+	}
+	started = models.TaskTimeEntry{TaskID: taskID, StartTime: start, CreatedAt: start}
+	if started.ID, err = insertTimeEntry(tx, started); err != nil {
+		return models.TaskTimeEntry{}, models.TaskTimeEntry{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.TaskTimeEntry{}, models.TaskTimeEntry{}, fmt.Errorf("failed to commit timer start: %w", err)
+	}
+	return started, stopped, nil
+}
+
+// StopTaskTimer closes the running time entry at end and returns it.
+// Returns sql.ErrNoRows if no timer is running.
+func StopTaskTimer(end time.Time) (models.TaskTimeEntry, error) {
+	if db == nil {
+		return models.TaskTimeEntry{}, errors.New("database is not initialized")
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return models.TaskTimeEntry{}, fmt.Errorf("failed to begin transaction to stop timer: %w", err)
+	}
+	defer tx.Rollback()
+
+	stopped, err := stopRunningTimer(tx, end)
+	if err != nil {
+		return models.TaskTimeEntry{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.TaskTimeEntry{}, fmt.Errorf("failed to commit timer stop: %w", err)
+	}
+	return stopped, nil
+}
+
+// stopRunningTimer closes the open time entry at end (or at its start, if end is earlier) using tx.
+// Returns sql.ErrNoRows if no timer is running.
+func stopRunningTimer(tx *sql.Tx, end time.Time) (models.TaskTimeEntry, error) {
+	entry, err := scanTimeEntry(tx.QueryRow("SELECT " + timeEntryColumns + " FROM task_time_entries WHERE end_time IS NULL ORDER BY start_time DESC LIMIT 1"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TaskTimeEntry{}, err
+		}
+		return models.TaskTimeEntry{}, fmt.Errorf("failed to find the running timer: %w", err)
+	}
+	if end.Before(entry.StartTime) {
+		end = entry.StartTime
+	}
+	entry.EndTime = end
+	if _, err := tx.Exec("UPDATE task_time_entries SET end_time = ? WHERE id = ?", entry.EndTime, entry.ID); err != nil {
+		return models.TaskTimeEntry{}, fmt.Errorf("failed to stop time entry %s: %w", entry.ID, err)
+	}
+	return entry, nil
+}
+
+// GetRunningTimeEntry returns the open time entry, if a timer is running.
+// Returns sql.ErrNoRows if no timer is running.
+func GetRunningTimeEntry() (models.TaskTimeEntry, error) {
+	if db == nil {
+		return models.TaskTimeEntry{}, errors.New("database is not initialized")
+	}
+	entry, err := scanTimeEntry(db.QueryRow("SELECT " + timeEntryColumns + " FROM task_time_entries WHERE end_time IS NULL ORDER BY start_time DESC LIMIT 1"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TaskTimeEntry{}, err
+		}
+		return models.TaskTimeEntry{}, fmt.Errorf("failed to get the running time entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListTimeEntries returns time entries ordered by start time.
+// A non-empty taskID keeps only the entries of that task.
+func ListTimeEntries(taskID string) ([]models.TaskTimeEntry, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := "SELECT " + timeEntryColumns + " FROM task_time_entries"
+	var args []interface{}
+	if taskID != "" {
+		query += " WHERE task_id = ?"
+		args = append(args, taskID)
+	}
+	rows, err := db.Query(query+" ORDER BY start_time ASC, id ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.TaskTimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of time entries: %w", err)
+	}
+	return entries, nil
+}
+
+// --- CRUD Functions for Project Model ---
+
+// projectColumns is the column list shared by every project SELECT, in scanProject order.
+const projectColumns = "id, name, description, due_date, status, created_at, updated_at"
+
+// scanProject reads a project row selected with projectColumns.
+func scanProject(row rowScanner) (models.Project, error) {
+	var p models.Project
+	var description sql.NullString
+	var dueDate, updatedAt sql.NullTime
+	if err := row.Scan(&p.ID, &p.Name, &description, &dueDate, &p.Status, &p.CreatedAt, &updatedAt); err != nil {
+		return models.Project{}, err
+	}
+	p.Description = description.String
+	if dueDate.Valid {
+		p.DueDate = dueDate.Time
+	}
+	if updatedAt.Valid {
+		p.UpdatedAt = updatedAt.Time
+	}
+	return p, nil
+}
+
+// CreateProject adds a new project to the database.
+// It generates a new UUID for project.ID if it's empty, sets CreatedAt/UpdatedAt if they are zero
+// and defaults Status to models.ProjectStatusActive. Names must be unique, ignoring ASCII case.
+func CreateProject(project models.Project) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	if project.ID == "" {
+		project.ID = uuid.NewString()
+	}
+	if project.CreatedAt.IsZero() {
+		project.CreatedAt = time.Now()
+	}
+	if project.UpdatedAt.IsZero() {
+		project.UpdatedAt = project.CreatedAt
+	}
+	if project.Status == "" {
+		project.Status = models.ProjectStatusActive
+	}
+
+	_, err := db.Exec("INSERT INTO projects ("+projectColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		project.ID, project.Name, project.Description, nullableTime(project.DueDate), project.Status, project.CreatedAt, project.UpdatedAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute insert statement for project: %w", err)
+	}
+	return project.ID, nil
+}
+
+// GetProject retrieves a project by its ID.
+// The returned error wraps sql.ErrNoRows when no project has the given ID.
+func GetProject(id string) (models.Project, error) {
+	if db == nil {
+		return models.Project{}, errors.New("database is not initialized")
+	}
+	p, err := scanProject(db.QueryRow("SELECT "+projectColumns+" FROM projects WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Project{}, fmt.Errorf("project with ID %s not found: %w", id, err)
+		}
+		return models.Project{}, fmt.Errorf("failed to scan project row: %w", err)
+	}
+	return p, nil
+}
+
+// ListProjects retrieves a paginated and filtered list of projects.
+// Supported filters:
+//   - "status" (string): case-insensitive status match.
+//   - "name" (string): substring match on the name.
+//
+// sortBy must be one of name, due_date, status, created_at or updated_at; the default is name.
+// Projects without a due date always sort last when sorting by due_date.
+// A limit <= 0 returns every matching project.
+func ListProjects(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Project, int, error) {
+	if db == nil {
+		return nil, 0, errors.New("database is not initialized")
+	}
+
+	whereClauses := []string{}
+	args := []interface{}{}
+
+	for key, value := range filters {
+		switch key {
+		case "status":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "LOWER(status) = LOWER(?)")
+				args = append(args, v)
+			}
+		case "name":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "name LIKE ?")
+				args = append(args, "%"+v+"%")
+			}
+		default:
+			return nil, 0, fmt.Errorf("invalid project filter: %s", key)
+		}
+	}
+
+	whereString := ""
+	if len(whereClauses) > 0 {
+		whereString = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var totalCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM projects"+whereString, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count projects: %w", err)
+	}
+	if totalCount == 0 {
+		return []models.Project{}, 0, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString("SELECT " + projectColumns + " FROM projects" + whereString)
+
+	direction := " ASC"
+	if strings.ToUpper(order) == "DESC" {
+		direction = " DESC"
+	}
+	switch strings.ToLower(sortBy) {
+	case "":
+		queryBuilder.WriteString(" ORDER BY name COLLATE NOCASE" + direction)
+	case "due_date":
+		queryBuilder.WriteString(" ORDER BY due_date IS NULL, due_date" + direction + ", name ASC")
+	case "name", "status", "created_at", "updated_at":
+		queryBuilder.WriteString(fmt.Sprintf(" ORDER BY %s%s, name ASC", strings.ToLower(sortBy), direction))
+	default:
+		return nil, 0, fmt.Errorf("invalid sort_by column: %s", sortBy)
+	}
+
+	if limit > 0 {
+		if page <= 0 {
+			page = 1
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" LIMIT %d OFFSET %d", limit, (page-1)*limit))
+	}
+
+	rows, err := db.Query(queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan project during list: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating project rows: %w", err)
+	}
+
+	return projects, totalCount, nil
+}
+
+// UpdateProject updates an existing project in the database.
+// It returns sql.ErrNoRows if no project with the given ID is found.
+func UpdateProject(project models.Project) error {
+	if project.ID == "" {
+		return errors.New("cannot update project without ID")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if project.UpdatedAt.IsZero() {
+		project.UpdatedAt = time.Now()
+	}
+
+	res, err := db.Exec("UPDATE projects SET name = ?, description = ?, due_date = ?, status = ?, updated_at = ? WHERE id = ?",
+		project.Name, project.Description, nullableTime(project.DueDate), project.Status, project.UpdatedAt, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update statement for project ID %s: %w", project.ID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for project ID %s: %w", project.ID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteProject removes a project from the database by its ID. Its tasks, events and routines are kept
+// and no longer belong to any project.
+// It returns sql.ErrNoRows if no project with the given ID is found.
+func DeleteProject(id string) error {
+	if id == "" {
+		return errors.New("cannot delete project without ID: ID cannot be empty")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction to delete project %s: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM projects WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project %s: %w", id, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for project %s: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	for _, table := range []string{"tasks", "events", "routines"} {
+		if _, err := tx.Exec("UPDATE "+table+" SET project_id = NULL WHERE project_id = ?", id); err != nil {
+			return fmt.Errorf("failed to detach %s from project %s: %w", table, id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// --- CRUD Functions for Event Model ---
+
+// eventColumns is the column list shared by every event SELECT, in scanEvent order.
+const eventColumns = "id, title, description, start_time, end_time, location, created_at, updated_at, recurrence_rule, exception_dates, series_id, original_start_time, ical_uid, project_id"
+
+// scanEvent reads an event row selected with eventColumns.
+func scanEvent(row rowScanner) (models.Event, error) {
+	var e models.Event
+	var description, location, recurrenceRule, exceptionDatesJSON, seriesID, icalUID, projectID sql.NullString
+	var updatedAt, originalStartTime sql.NullTime
+
+	if err := row.Scan(&e.ID, &e.Title, &description, &e.StartTime, &e.EndTime, &location, &e.CreatedAt, &updatedAt,
+		&recurrenceRule, &exceptionDatesJSON, &seriesID, &originalStartTime, &icalUID, &projectID); err != nil {
+		return models.Event{}, err
+	}
+	e.Description = description.String
+	e.Location = location.String
+	e.RecurrenceRule = recurrenceRule.String
+	e.SeriesID = seriesID.String
+	e.ICalUID = icalUID.String
+	e.ProjectID = projectID.String
+	if updatedAt.Valid {
+		e.UpdatedAt = updatedAt.Time
+	}
+	if originalStartTime.Valid {
+		e.OriginalStartTime = originalStartTime.Time
+	}
+	if exceptionDatesJSON.Valid && exceptionDatesJSON.String != "" {
+		if err := json.Unmarshal([]byte(exceptionDatesJSON.String), &e.ExceptionDates); err != nil {
+			return models.Event{}, fmt.Errorf("failed to unmarshal ExceptionDates: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// marshalExceptionDates encodes EXDATE values for the exception_dates column; an empty list is stored as NULL.
+func marshalExceptionDates(dates []time.Time) (sql.NullString, error) {
+	if len(dates) == 0 {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(dates)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal ExceptionDates: %w", err)
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// CreateEvent adds a new event to the database.
+// It generates a new UUID for event.ID if it's empty and sets CreatedAt/UpdatedAt if they are zero.
+func CreateEvent(event models.Event) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	return insertEvent(db, event)
+}
+
+// insertEvent fills the defaults documented in CreateEvent and inserts event using ex.
+func insertEvent(ex execer, event models.Event) (string, error) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.UpdatedAt.IsZero() {
+		event.UpdatedAt = event.CreatedAt
+	}
+
+	exceptionDates, err := marshalExceptionDates(event.ExceptionDates)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = ex.Exec(`
+		INSERT INTO events (id, title, description, start_time, end_time, location, created_at, updated_at,
+			recurrence_rule, exception_dates, series_id, original_start_time, ical_uid, project_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, event.ID, event.Title, event.Description, event.StartTime, event.EndTime, event.Location, event.CreatedAt, event.UpdatedAt,
+		event.RecurrenceRule, exceptionDates, event.SeriesID, nullableTime(event.OriginalStartTime), nullableString(event.ICalUID),
+		nullableString(event.ProjectID))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute insert statement for event: %w", err)
+	}
+	return event.ID, nil
+}
+
+// GetEvent retrieves an event by its ID.
+// The returned error wraps sql.ErrNoRows when no event has the given ID.
+func GetEvent(id string) (models.Event, error) {
+	if db == nil {
+		return models.Event{}, errors.New("database is not initialized")
+	}
+	e, err := scanEvent(db.QueryRow("SELECT "+eventColumns+" FROM events WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Event{}, fmt.Errorf("event with ID %s not found: %w", id, err)
+		}
+		return models.Event{}, fmt.Errorf("failed to scan event row: %w", err)
+	}
+	return e, nil
+}
+
+// ListEvents retrieves a paginated and filtered list of events.
+// Supported filters:
+//   - "range_start" (time.Time): events that end after this time.
+//   - "range_end" (time.Time): events that start before this time.
+//     Together they select every event overlapping [range_start, range_end).
+//     Recurring series (non-empty recurrence_rule) always pass range_start, since later
+//     occurrences may fall in the window; callers expand them against the rule.
+//   - "title" (string): substring match on the title.
+//   - "location" (string): substring match on the location.
+//   - "series_id" (string): occurrences detached from the given recurring event.
+//   - "ical_uid" (string): the event imported with the given iCalendar UID.
+//   - "project_id" (string): the events of the given project.
+//
+// sortBy must be one of start_time, end_time, title, location or created_at; the default is start_time.
+// A limit <= 0 returns every matching event.
+func ListEvents(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Event, int, error) {
+	if db == nil {
+		return nil, 0, errors.New("database is not initialized")
+	}
+
+	whereClauses := []string{}
+	args := []interface{}{}
+
+	for key, value := range filters {
+		switch key {
+		case "range_start":
+			if v, ok := value.(time.Time); ok && !v.IsZero() {
+				whereClauses = append(whereClauses, "(end_time > ? OR COALESCE(recurrence_rule, '') != '')")
+				args = append(args, v)
+			}
+		case "range_end":
+			if v, ok := value.(time.Time); ok && !v.IsZero() {
+				whereClauses = append(whereClauses, "start_time < ?")
+				args = append(args, v)
+			}
+		case "title", "location":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, fmt.Sprintf("%s LIKE ?", key))
+				args = append(args, "%"+v+"%")
+			}
+		case "series_id", "ical_uid", "project_id":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", key))
+				args = append(args, v)
+			}
+		default:
+			return nil, 0, fmt.Errorf("invalid event filter: %s", key)
+		}
+	}
+
+	whereString := ""
+	if len(whereClauses) > 0 {
+		whereString = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var totalCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM events"+whereString, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	if totalCount == 0 {
+		return []models.Event{}, 0, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString("SELECT " + eventColumns + " FROM events" + whereString)
+
+	if sortBy == "" {
+		sortBy = "start_time"
+	}
+	validSortBy := map[string]bool{
+		"start_time": true, "end_time": true, "title": true, "location": true, "created_at": true,
+	}
+	if !validSortBy[strings.ToLower(sortBy)] {
+		return nil, 0, fmt.Errorf("invalid sort_by column: %s", sortBy)
+	}
+	direction := " ASC"
+	if strings.ToUpper(order) == "DESC" {
+		direction = " DESC"
+	}
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY %s%s, start_time ASC", strings.ToLower(sortBy), direction))
+
+	if limit > 0 {
+		if page <= 0 {
+			page = 1
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" LIMIT %d OFFSET %d", limit, (page-1)*limit))
+	}
+
+	rows, err := db.Query(queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan event during list: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating event rows: %w", err)
+	}
+
+	return events, totalCount, nil
+}
+
+// UpdateEvent updates an existing event in the database.
+// It returns sql.ErrNoRows if no event with the given ID is found.
+func UpdateEvent(event models.Event) error {
+	if event.ID == "" {
+		return errors.New("cannot update event without ID")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if event.UpdatedAt.IsZero() {
+		event.UpdatedAt = time.Now()
+	}
+
+	exceptionDates, err := marshalExceptionDates(event.ExceptionDates)
+	if err != nil {
+		return err
+	}
+
+	res, err := db.Exec(`
+		UPDATE events SET
+			title = ?, description = ?, start_time = ?, end_time = ?, location = ?, updated_at = ?,
+			recurrence_rule = ?, exception_dates = ?, series_id = ?, original_start_time = ?, ical_uid = ?, project_id = ?
+		WHERE id = ?
+	`, event.Title, event.Description, event.StartTime, event.EndTime, event.Location, event.UpdatedAt,
+		event.RecurrenceRule, exceptionDates, event.SeriesID, nullableTime(event.OriginalStartTime), nullableString(event.ICalUID),
+		nullableString(event.ProjectID), event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update statement for event ID %s: %w", event.ID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for event ID %s: %w", event.ID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteEvent removes an event from the database by its ID, together with any
+// occurrences detached from it when it is a recurring series.
+// It returns sql.ErrNoRows if no event with the given ID is found.
+func DeleteEvent(id string) error {
+	if id == "" {
+		return errors.New("cannot delete event without ID: ID cannot be empty")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	result, err := db.Exec("DELETE FROM events WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete event %s: %w", id, err)
+	}
+	if _, err := db.Exec("DELETE FROM events WHERE series_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete detached occurrences of event %s: %w", id, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for event %s: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// --- CRUD Functions for Routine Model ---
+
+// routineColumns is the column list shared by every routine SELECT, in scanRoutine order.
+const routineColumns = "id, name, description, frequency, task_description, task_priority, task_tags, next_run_time, created_at, updated_at, task_templates, event_templates, project_id"
+
+// scanRoutine reads a routine row selected with routineColumns.
+func scanRoutine(row rowScanner) (models.Routine, error) {
+	var r models.Routine
+	var description, frequency, taskDescription, tagsJSON, taskTemplatesJSON, eventTemplatesJSON, projectID sql.NullString
+	var taskPriority sql.NullInt64
+	var nextRunTime, updatedAt sql.NullTime
+
+	if err := row.Scan(&r.ID, &r.Name, &description, &frequency, &taskDescription, &taskPriority, &tagsJSON, &nextRunTime, &r.CreatedAt, &updatedAt,
+		&taskTemplatesJSON, &eventTemplatesJSON, &projectID); err != nil {
+		return models.Routine{}, err
+	}
+	r.ProjectID = projectID.String
+	r.Description = description.String
+	r.Frequency = frequency.String
+	r.TaskDescription = taskDescription.String
+	r.TaskPriority = int(taskPriority.Int64)
+	if nextRunTime.Valid {
+		r.NextRunTime = nextRunTime.Time
+	}
+	if updatedAt.Valid {
+		r.UpdatedAt = updatedAt.Time
+	}
+	if tagsJSON.Valid && tagsJSON.String != "" {
+		if err := json.Unmarshal([]byte(tagsJSON.String), &r.TaskTags); err != nil {
+			return models.Routine{}, fmt.Errorf("failed to unmarshal TaskTags for routine ID %s: %w", r.ID, err)
+		}
+	}
+	if taskTemplatesJSON.Valid && taskTemplatesJSON.String != "" {
+		if err := json.Unmarshal([]byte(taskTemplatesJSON.String), &r.TaskTemplates); err != nil {
+			return models.Routine{}, fmt.Errorf("failed to unmarshal TaskTemplates for routine ID %s: %w", r.ID, err)
+		}
+	}
+	if eventTemplatesJSON.Valid && eventTemplatesJSON.String != "" {
+		if err := json.Unmarshal([]byte(eventTemplatesJSON.String), &r.EventTemplates); err != nil {
+			return models.Routine{}, fmt.Errorf("failed to unmarshal EventTemplates for routine ID %s: %w", r.ID, err)
+		}
+	}
+	return r, nil
+}
+
+// marshalRoutineTemplates encodes the task and event templates of a routine as JSON arrays.
+func marshalRoutineTemplates(routine models.Routine) (string, string, error) {
+	taskTemplatesJSON, err := json.Marshal(routine.TaskTemplates)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal TaskTemplates: %w", err)
+	}
+	eventTemplatesJSON, err := json.Marshal(routine.EventTemplates)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal EventTemplates: %w", err)
+	}
+	return string(taskTemplatesJSON), string(eventTemplatesJSON), nil
+}
+
+// CreateRoutine adds a new routine to the database.
+// It generates a new UUID for routine.ID if it's empty and sets CreatedAt/UpdatedAt if they are zero.
+func CreateRoutine(routine models.Routine) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	if routine.ID == "" {
+		routine.ID = uuid.NewString()
+	}
+	if routine.CreatedAt.IsZero() {
+		routine.CreatedAt = time.Now()
+	}
+	if routine.UpdatedAt.IsZero() {
+		routine.UpdatedAt = routine.CreatedAt
+	}
+
+	tagsJSON, err := json.Marshal(routine.TaskTags)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal TaskTags: %w", err)
+	}
+	taskTemplatesJSON, eventTemplatesJSON, err := marshalRoutineTemplates(routine)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO routines (
+			id, name, description, frequency, task_description,
+			task_priority, task_tags, next_run_time, created_at, updated_at,
+			task_templates, event_templates, project_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, routine.ID, routine.Name, routine.Description, routine.Frequency, routine.TaskDescription,
+		routine.TaskPriority, string(tagsJSON), nullableTime(routine.NextRunTime), routine.CreatedAt, routine.UpdatedAt,
+		taskTemplatesJSON, eventTemplatesJSON, nullableString(routine.ProjectID))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute insert statement for routine: %w", err)
+	}
+	return routine.ID, nil
+}
+
+// GetRoutine retrieves a routine by its ID.
+// The returned error wraps sql.ErrNoRows when no routine has the given ID.
+func GetRoutine(id string) (models.Routine, error) {
+	if db == nil {
+		return models.Routine{}, errors.New("database is not initialized")
+	}
+	r, err := scanRoutine(db.QueryRow("SELECT "+routineColumns+" FROM routines WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Routine{}, fmt.Errorf("routine with ID %s not found: %w", id, err)
+		}
+		return models.Routine{}, fmt.Errorf("failed to scan routine row: %w", err)
+	}
+	return r, nil
+}
+
+// ListRoutines retrieves a paginated and filtered list of routines.
+// Supported filters:
+//   - "frequency" (string): case-insensitive frequency match.
+//   - "name" (string): substring match on the name.
+//   - "due_before" (time.Time): routines whose next_run_time is set and on or before the time.
+//   - "project_id" (string): the routines of the given project.
+//
+// sortBy must be one of name, frequency, next_run_time, created_at or updated_at;
+// routines without a next run time always sort last when sorting by next_run_time.
+// A limit <= 0 returns every matching routine.
+func ListRoutines(filters map[string]interface{}, sortBy string, order string, limit int, page int) ([]models.Routine, int, error) {
+	if db == nil {
+		return nil, 0, errors.New("database is not initialized")
+	}
+
+	whereClauses := []string{}
+	args := []interface{}{}
+
+	for key, value := range filters {
+		switch key {
+		case "frequency":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "LOWER(frequency) = LOWER(?)")
+				args = append(args, v)
+			}
+		case "name":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "name LIKE ?")
+				args = append(args, "%"+v+"%")
+			}
+		case "due_before":
+			if v, ok := value.(time.Time); ok && !v.IsZero() {
+				whereClauses = append(whereClauses, "(next_run_time IS NOT NULL AND next_run_time <= ?)")
+				args = append(args, v)
+			}
+		case "project_id":
+			if v, ok := value.(string); ok && v != "" {
+				whereClauses = append(whereClauses, "project_id = ?")
+				args = append(args, v)
+			}
+		default:
+			return nil, 0, fmt.Errorf("invalid routine filter: %s", key)
+		}
+	}
+
+	whereString := ""
+	if len(whereClauses) > 0 {
+		whereString = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var totalCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM routines"+whereString, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count routines: %w", err)
+	}
+	if totalCount == 0 {
+		return []models.Routine{}, 0, nil
+	}
+
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString("SELECT " + routineColumns + " FROM routines" + whereString)
+
+	direction := " ASC"
+	if strings.ToUpper(order) == "DESC" {
+		direction = " DESC"
+	}
+	switch strings.ToLower(sortBy) {
+	case "":
+		queryBuilder.WriteString(" ORDER BY name" + direction)
+	case "next_run_time":
+		queryBuilder.WriteString(" ORDER BY next_run_time IS NULL, next_run_time" + direction + ", name ASC")
+	case "name", "frequency", "created_at", "updated_at":
+		queryBuilder.WriteString(fmt.Sprintf(" ORDER BY %s%s, name ASC", strings.ToLower(sortBy), direction))
+	default:
+		return nil, 0, fmt.Errorf("invalid sort_by column: %s", sortBy)
+	}
+
+	if limit > 0 {
+		if page <= 0 {
+			page = 1
+		}
+		queryBuilder.WriteString(fmt.Sprintf(" LIMIT %d OFFSET %d", limit, (page-1)*limit))
+	}
+
+	rows, err := db.Query(queryBuilder.String(), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list routines: %w", err)
+	}
+	defer rows.Close()
+
+	routines := []models.Routine{}
+	for rows.Next() {
+		r, err := scanRoutine(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan routine during list: %w", err)
+		}
+		routines = append(routines, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating routine rows: %w", err)
+	}
+
+	return routines, totalCount, nil
+}
+
+// UpdateRoutine updates an existing routine in the database.
+// It returns sql.ErrNoRows if no routine with the given ID is found.
+func UpdateRoutine(routine models.Routine) error {
+	if routine.ID == "" {
+		return errors.New("cannot update routine without ID")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	tagsJSON, err := json.Marshal(routine.TaskTags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal TaskTags for update: %w", err)
+	}
+	taskTemplatesJSON, eventTemplatesJSON, err := marshalRoutineTemplates(routine)
+	if err != nil {
+		return err
+	}
+	if routine.UpdatedAt.IsZero() {
+		routine.UpdatedAt = time.Now()
+	}
+
+	res, err := db.Exec(`
+		UPDATE routines SET
+			name = ?, description = ?, frequency = ?, task_description = ?,
+			task_priority = ?, task_tags = ?, next_run_time = ?, updated_at = ?,
+			task_templates = ?, event_templates = ?, project_id = ?
+		WHERE id = ?
+	`, routine.Name, routine.Description, routine.Frequency, routine.TaskDescription,
+		routine.TaskPriority, string(tagsJSON), nullableTime(routine.NextRunTime), routine.UpdatedAt,
+		taskTemplatesJSON, eventTemplatesJSON, nullableString(routine.ProjectID), routine.ID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update statement for routine ID %s: %w", routine.ID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for routine ID %s: %w", routine.ID, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteRoutine removes a routine from the database by its ID.
+// It returns sql.ErrNoRows if no routine with the given ID is found.
+func DeleteRoutine(id string) error {
+	if id == "" {
+		return errors.New("cannot delete routine without ID: ID cannot be empty")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction to delete routine %s: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM routines WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete routine %s: %w", id, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for routine %s: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	// The run history only makes sense for an existing routine; the generated tasks themselves are kept.
+	if _, err := tx.Exec("DELETE FROM routine_runs WHERE routine_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete runs of routine %s: %w", id, err)
+	}
+	return tx.Commit()
+}
+
+// UpdateRoutineNextRun sets only the next_run_time of a routine, leaving concurrent edits to other fields intact.
+// A zero next clears it. It returns sql.ErrNoRows if no routine with the given ID is found.
+func UpdateRoutineNextRun(id string, next time.Time) error {
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	res, err := db.Exec("UPDATE routines SET next_run_time = ?, updated_at = ? WHERE id = ?", nullableTime(next), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update next_run_time for routine ID %s: %w", id, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for routine ID %s: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// --- Routine Runs ---
+
+// routineRunColumns is the column list shared by every routine run SELECT, in scanRoutineRun order.
+const routineRunColumns = "id, routine_id, occurrence_date, task_ids, forced, created_at, event_ids"
+
+// scanRoutineRun reads a routine run row selected with routineRunColumns.
+func scanRoutineRun(row rowScanner) (models.RoutineRun, error) {
+	var r models.RoutineRun
+	var taskIDsJSON, eventIDsJSON sql.NullString
+	if err := row.Scan(&r.ID, &r.RoutineID, &r.OccurrenceDate, &taskIDsJSON, &r.Forced, &r.CreatedAt, &eventIDsJSON); err != nil {
+		return models.RoutineRun{}, err
+	}
+	if taskIDsJSON.Valid && taskIDsJSON.String != "" {
+		if err := json.Unmarshal([]byte(taskIDsJSON.String), &r.TaskIDs); err != nil {
+			return models.RoutineRun{}, fmt.Errorf("failed to unmarshal TaskIDs for routine run ID %s: %w", r.ID, err)
+		}
+	}
+	if eventIDsJSON.Valid && eventIDsJSON.String != "" {
+		if err := json.Unmarshal([]byte(eventIDsJSON.String), &r.EventIDs); err != nil {
+			return models.RoutineRun{}, fmt.Errorf("failed to unmarshal EventIDs for routine run ID %s: %w", r.ID, err)
+		}
+	}
+	return r, nil
+}
+
+// CreateRoutineRun records a routine run.
+// It generates a new UUID for run.ID if it's empty and sets CreatedAt if it is zero.
+func CreateRoutineRun(run models.RoutineRun) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	return insertRoutineRun(db, run)
+}
+
+// insertRoutineRun validates run, fills the defaults documented in CreateRoutineRun and inserts it using ex.
+func insertRoutineRun(ex execer, run models.RoutineRun) (string, error) {
+	if run.RoutineID == "" || run.OccurrenceDate == "" {
+		return "", errors.New("routine run requires a routine ID and an occurrence date")
+	}
+	if run.ID == "" {
+		run.ID = uuid.NewString()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+	taskIDsJSON, err := json.Marshal(run.TaskIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal TaskIDs: %w", err)
+	}
+	eventIDsJSON, err := json.Marshal(run.EventIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal EventIDs: %w", err)
+	}
+	_, err = ex.Exec("INSERT INTO routine_runs ("+routineRunColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		run.ID, run.RoutineID, run.OccurrenceDate, string(taskIDsJSON), run.Forced, run.CreatedAt, string(eventIDsJSON))
+	if err != nil {
+		return "", fmt.Errorf("failed to insert routine run: %w", err)
+	}
+	return run.ID, nil
+}
+
+// CreateRoutineRunWithItems creates the tasks and events generated by a routine run and records the run,
+// all in one transaction: either everything is stored or nothing is.
+// The IDs of the created tasks and events are written back into the tasks and events slices and
+// appended to run.TaskIDs and run.EventIDs; the recorded run is returned.
+func CreateRoutineRunWithItems(run models.RoutineRun, tasks []models.Task, events []models.Event) (models.RoutineRun, error) {
+	if db == nil {
+		return models.RoutineRun{}, errors.New("database is not initialized")
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return models.RoutineRun{}, fmt.Errorf("failed to begin transaction for routine run: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range tasks {
+		id, err := insertTask(tx, tasks[i])
+		if err != nil {
+			return models.RoutineRun{}, err
+		}
+		tasks[i].ID = id
+		run.TaskIDs = append(run.TaskIDs, id)
+	}
+	for i := range events {
+		id, err := insertEvent(tx, events[i])
+		if err != nil {
+			return models.RoutineRun{}, err
+		}
+		events[i].ID = id
+		run.EventIDs = append(run.EventIDs, id)
+	}
+	if run.ID, err = insertRoutineRun(tx, run); err != nil {
+		return models.RoutineRun{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.RoutineRun{}, fmt.Errorf("failed to commit routine run: %w", err)
+	}
+	return run, nil
+}
+
+// GetLatestRoutineRun returns the most recent run of a routine for an occurrence date ("YYYY-MM-DD").
+// The returned error wraps sql.ErrNoRows when the occurrence was never run.
+func GetLatestRoutineRun(routineID, occurrenceDate string) (models.RoutineRun, error) {
+	if db == nil {
+		return models.RoutineRun{}, errors.New("database is not initialized")
+	}
+	row := db.QueryRow("SELECT "+routineRunColumns+" FROM routine_runs WHERE routine_id = ? AND occurrence_date = ? ORDER BY created_at DESC LIMIT 1",
+		routineID, occurrenceDate)
+	run, err := scanRoutineRun(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RoutineRun{}, fmt.Errorf("no run of routine %s on %s: %w", routineID, occurrenceDate, err)
+		}
+		return models.RoutineRun{}, fmt.Errorf("failed to get run of routine %s on %s: %w", routineID, occurrenceDate, err)
+	}
+	return run, nil
+}
+
+// ListRoutineRuns returns the runs of a routine, or of every routine when routineID is empty,
+// ordered by occurrence date and then by creation time.
+func ListRoutineRuns(routineID string) ([]models.RoutineRun, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := "SELECT " + routineRunColumns + " FROM routine_runs"
+	var args []interface{}
+	if routineID != "" {
+		query += " WHERE routine_id = ?"
+		args = append(args, routineID)
+	}
+	rows, err := db.Query(query+" ORDER BY occurrence_date ASC, created_at ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routine runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.RoutineRun
+	for rows.Next() {
+		run, err := scanRoutineRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routine run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of routine runs: %w", err)
+	}
+	return runs, nil
+}
+
+// --- Scheduler Locks ---
+
+// AcquireLock tries to take the named lock for owner until ttl elapses.
+// It succeeds when the lock is free, expired or already held by owner (which renews it),
+// and reports false without error when another owner holds it.
+// The check and the write are a single statement, so concurrent processes sharing the database cannot both win.
+func AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	if db == nil {
+		return false, errors.New("database is not initialized")
+	}
+	now := time.Now()
+	res, err := db.Exec(`
+		INSERT INTO scheduler_locks (name, owner, acquired_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET owner = excluded.owner, acquired_at = excluded.acquired_at, expires_at = excluded.expires_at
+		WHERE scheduler_locks.expires_at <= excluded.acquired_at OR scheduler_locks.owner = excluded.owner
+	`, name, owner, now.UnixMilli(), now.Add(ttl).UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected for lock %s: %w", name, err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// ReleaseLock frees the named lock if owner still holds it. Releasing a lock held by someone else is a no-op.
+func ReleaseLock(name, owner string) error {
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if _, err := db.Exec("DELETE FROM scheduler_locks WHERE name = ? AND owner = ?", name, owner); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", name, err)
+	}
+	return nil
+}
+
+// --- Settings ---
+
+// GetSetting returns the value stored under key.
+// Returns sql.ErrNoRows if the key is not set.
+func GetSetting(key string) (string, error) {
+	if db == nil {
+		return "", errors.New("database is not initialized")
+	}
+	var value string
+	if err := db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", sql.ErrNoRows
+		}
+		return "", fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// SetSetting stores value under key, replacing the previous value.
+func SetSetting(key, value string) error {
+	if key == "" {
+		return errors.New("setting key cannot be empty")
+	}
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	if _, err := db.Exec(`INSERT INTO settings (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`, key, value, time.Now()); err != nil {
+		return fmt.Errorf("failed to set setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteSetting removes key. Returns sql.ErrNoRows if the key is not set.
+func DeleteSetting(key string) error {
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	res, err := db.Exec("DELETE FROM settings WHERE key = ?", key)
+	if err != nil {
+		return fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for setting %s: %w", key, err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListSettings returns the settings whose key starts with prefix (all of them if prefix is empty).
+func ListSettings(prefix string) (map[string]string, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	rows, err := db.Query("SELECT key, value FROM settings WHERE substr(key, 1, ?) = ?", len(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	defer rows.Close()
+	settings := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of settings: %w", err)
+	}
+	return settings, nil
 }
 
 // --- CRUD Functions for Term Model ---
@@ -532,3 +532,598 @@ func TestListQuestions_SortByInvalidColumn(t *testing.T) {
 		t.Errorf("Expected error message to contain '%s', got '%s'", expectedErrorMsg, err.Error())
 	}
 }
+
+func TestRoutineCRUDAndListFilters(t *testing.T) {
+	if _, err := db.Exec("DELETE FROM routines"); err != nil {
+		t.Fatalf("Failed to clear routines table: %v", err)
+	}
+
+	now := time.Now()
+	dueID, err := CreateRoutine(models.Routine{
+		Name: "Correção semanal", Frequency: "semanal:seg", TaskDescription: "Corrigir provas",
+		TaskPriority: 1, TaskTags: []string{"provas"}, NextRunTime: now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateRoutine failed: %v", err)
+	}
+	manualID, err := CreateRoutine(models.Routine{Name: "Avulsa", Frequency: "manual", TaskDescription: "Tarefa avulsa"})
+	if err != nil {
+		t.Fatalf("CreateRoutine (manual) failed: %v", err)
+	}
+
+	got, err := GetRoutine(dueID)
+	if err != nil {
+		t.Fatalf("GetRoutine failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.TaskTags, []string{"provas"}) || got.NextRunTime.IsZero() || got.TaskPriority != 1 {
+		t.Errorf("GetRoutine returned unexpected routine: %+v", got)
+	}
+	manual, err := GetRoutine(manualID)
+	if err != nil {
+		t.Fatalf("GetRoutine (manual) failed: %v", err)
+	}
+	if !manual.NextRunTime.IsZero() {
+		t.Errorf("Expected zero NextRunTime for manual routine, got %v", manual.NextRunTime)
+	}
+
+	due, total, err := ListRoutines(map[string]interface{}{"due_before": now}, "", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListRoutines failed: %v", err)
+	}
+	if total != 1 || len(due) != 1 || due[0].ID != dueID {
+		t.Errorf("Expected only the due routine, got total %d: %+v", total, due)
+	}
+
+	sorted, _, err := ListRoutines(nil, "next_run_time", "DESC", 0, 0)
+	if err != nil {
+		t.Fatalf("ListRoutines sorted failed: %v", err)
+	}
+	if len(sorted) != 2 || sorted[1].ID != manualID {
+		t.Errorf("Expected routine without next_run_time to sort last, got %+v", sorted)
+	}
+
+	got.NextRunTime = time.Time{}
+	got.Name = "Renomeada"
+	if err := UpdateRoutine(got); err != nil {
+		t.Fatalf("UpdateRoutine failed: %v", err)
+	}
+	updated, _ := GetRoutine(dueID)
+	if updated.Name != "Renomeada" || !updated.NextRunTime.IsZero() {
+		t.Errorf("UpdateRoutine did not persist changes: %+v", updated)
+	}
+
+	next := time.Date(2024, 3, 7, 8, 0, 0, 0, time.Local)
+	if err := UpdateRoutineNextRun(dueID, next); err != nil {
+		t.Fatalf("UpdateRoutineNextRun failed: %v", err)
+	}
+	if updated, _ := GetRoutine(dueID); updated.Name != "Renomeada" || !updated.NextRunTime.Equal(next) {
+		t.Errorf("UpdateRoutineNextRun should only change next_run_time: %+v", updated)
+	}
+	if err := UpdateRoutineNextRun("missing", next); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows updating next run of a missing routine, got %v", err)
+	}
+
+	if err := DeleteRoutine(dueID); err != nil {
+		t.Fatalf("DeleteRoutine failed: %v", err)
+	}
+	if err := DeleteRoutine(dueID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting a missing routine, got %v", err)
+	}
+	if err := UpdateRoutine(models.Routine{ID: "missing"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows updating a missing routine, got %v", err)
+	}
+}
+
+func TestEventCRUDAndRangeFilter(t *testing.T) {
+	if _, err := db.Exec("DELETE FROM events"); err != nil {
+		t.Fatalf("Failed to clear events table: %v", err)
+	}
+
+	day := time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local)
+	morningID, err := CreateEvent(models.Event{Title: "Conselho de classe", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(11 * time.Hour), Location: "Sala 3"})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	// Crosses midnight into the next day.
+	nightID, err := CreateEvent(models.Event{Title: "Vigília", StartTime: day.Add(23 * time.Hour), EndTime: day.Add(25 * time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateEvent (night) failed: %v", err)
+	}
+
+	got, err := GetEvent(morningID)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if got.Title != "Conselho de classe" || got.Location != "Sala 3" || !got.StartTime.Equal(day.Add(9*time.Hour)) {
+		t.Errorf("GetEvent returned unexpected event: %+v", got)
+	}
+
+	nextDay, total, err := ListEvents(map[string]interface{}{"range_start": day.AddDate(0, 0, 1), "range_end": day.AddDate(0, 0, 2)}, "", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if total != 1 || len(nextDay) != 1 || nextDay[0].ID != nightID {
+		t.Errorf("Expected only the overlapping night event, got total %d: %+v", total, nextDay)
+	}
+
+	sorted, _, err := ListEvents(nil, "title", "DESC", 0, 0)
+	if err != nil {
+		t.Fatalf("ListEvents sorted failed: %v", err)
+	}
+	if len(sorted) != 2 || sorted[0].ID != nightID {
+		t.Errorf("Expected events sorted by title descending, got %+v", sorted)
+	}
+	if _, _, err := ListEvents(map[string]interface{}{"unknown": 1}, "", "", 0, 0); err == nil {
+		t.Error("Expected error for unknown event filter")
+	}
+
+	got.Title = "Conselho final"
+	if err := UpdateEvent(got); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	updated, _ := GetEvent(morningID)
+	if updated.Title != "Conselho final" {
+		t.Errorf("UpdateEvent did not persist changes: %+v", updated)
+	}
+
+	if err := DeleteEvent(morningID); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if _, err := GetEvent(morningID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if err := DeleteEvent(morningID); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows deleting missing event, got %v", err)
+	}
+}
+
+func TestRoutineRuns(t *testing.T) {
+	for _, table := range []string{"routines", "routine_runs"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+	routineID, err := CreateRoutine(models.Routine{Name: "Chamada", Frequency: "diaria", TaskDescription: "Fazer chamada"})
+	if err != nil {
+		t.Fatalf("CreateRoutine failed: %v", err)
+	}
+
+	if _, err := GetLatestRoutineRun(routineID, "2024-03-04"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows before any run, got %v", err)
+	}
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.Local)
+	if _, err := CreateRoutineRun(models.RoutineRun{RoutineID: routineID, OccurrenceDate: "2024-03-05", TaskIDs: []string{"t3"}, CreatedAt: base.AddDate(0, 0, 1)}); err != nil {
+		t.Fatalf("CreateRoutineRun failed: %v", err)
+	}
+	if _, err := CreateRoutineRun(models.RoutineRun{RoutineID: routineID, OccurrenceDate: "2024-03-04", TaskIDs: []string{"t1"}, CreatedAt: base}); err != nil {
+		t.Fatalf("CreateRoutineRun failed: %v", err)
+	}
+	forcedID, err := CreateRoutineRun(models.RoutineRun{RoutineID: routineID, OccurrenceDate: "2024-03-04", TaskIDs: []string{"t2"}, Forced: true, CreatedAt: base.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateRoutineRun (forced) failed: %v", err)
+	}
+	if _, err := CreateRoutineRun(models.RoutineRun{RoutineID: routineID}); err == nil {
+		t.Error("Expected error for a run without occurrence date")
+	}
+
+	latest, err := GetLatestRoutineRun(routineID, "2024-03-04")
+	if err != nil {
+		t.Fatalf("GetLatestRoutineRun failed: %v", err)
+	}
+	if latest.ID != forcedID || !latest.Forced || !reflect.DeepEqual(latest.TaskIDs, []string{"t2"}) {
+		t.Errorf("Expected the forced run as the latest one, got %+v", latest)
+	}
+
+	runs, err := ListRoutineRuns(routineID)
+	if err != nil {
+		t.Fatalf("ListRoutineRuns failed: %v", err)
+	}
+	var order []string
+	for _, r := range runs {
+		order = append(order, r.TaskIDs[0])
+	}
+	if !reflect.DeepEqual(order, []string{"t1", "t2", "t3"}) {
+		t.Errorf("Expected runs ordered by occurrence and creation, got %v", order)
+	}
+
+	if err := DeleteRoutine(routineID); err != nil {
+		t.Fatalf("DeleteRoutine failed: %v", err)
+	}
+	if runs, _ := ListRoutineRuns(""); len(runs) != 0 {
+		t.Errorf("Expected runs to be deleted with their routine, got %+v", runs)
+	}
+}
+
+func TestCreateRoutineRunWithItems(t *testing.T) {
+	for _, table := range []string{"routines", "routine_runs", "tasks", "events"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+	routine := models.Routine{Name: "Fechamento", Frequency: "manual", TaskDescription: "Lançar notas",
+		TaskTemplates:  []models.RoutineTaskTemplate{{Description: "Imprimir boletins", DueOffset: "+3u", Priority: 1, Tags: []string{"boletim"}}},
+		EventTemplates: []models.RoutineEventTemplate{{Title: "Conselho de classe", StartOffset: "+7d 14:00", Duration: "2h"}}}
+	routineID, err := CreateRoutine(routine)
+	if err != nil {
+		t.Fatalf("CreateRoutine failed: %v", err)
+	}
+	stored, err := GetRoutine(routineID)
+	if err != nil {
+		t.Fatalf("GetRoutine failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored.TaskTemplates, routine.TaskTemplates) || !reflect.DeepEqual(stored.EventTemplates, routine.EventTemplates) {
+		t.Errorf("Templates not stored as given: %+v / %+v", stored.TaskTemplates, stored.EventTemplates)
+	}
+
+	start := time.Date(2024, 3, 11, 14, 0, 0, 0, time.Local)
+	tasks := []models.Task{{Description: "Lançar notas", Status: models.TaskStatusPending}, {Description: "Imprimir boletins", Status: models.TaskStatusPending}}
+	events := []models.Event{{Title: "Conselho de classe", StartTime: start, EndTime: start.Add(2 * time.Hour)}}
+	run, err := CreateRoutineRunWithItems(models.RoutineRun{RoutineID: routineID, OccurrenceDate: "2024-03-04"}, tasks, events)
+	if err != nil {
+		t.Fatalf("CreateRoutineRunWithItems failed: %v", err)
+	}
+	if tasks[0].ID == "" || tasks[1].ID == "" || events[0].ID == "" {
+		t.Fatalf("Expected IDs to be written back, got %+v / %+v", tasks, events)
+	}
+	latest, err := GetLatestRoutineRun(routineID, "2024-03-04")
+	if err != nil {
+		t.Fatalf("GetLatestRoutineRun failed: %v", err)
+	}
+	if latest.ID != run.ID || !reflect.DeepEqual(latest.TaskIDs, []string{tasks[0].ID, tasks[1].ID}) || !reflect.DeepEqual(latest.EventIDs, []string{events[0].ID}) {
+		t.Errorf("Unexpected run %+v", latest)
+	}
+	if _, err := GetEvent(events[0].ID); err != nil {
+		t.Errorf("GetEvent failed for the generated event: %v", err)
+	}
+
+	// A failing item rolls back everything created before it, including the run.
+	failing := []models.Task{{Description: "Nova"}, {ID: tasks[0].ID, Description: "ID repetido"}}
+	if _, err := CreateRoutineRunWithItems(models.RoutineRun{RoutineID: routineID, OccurrenceDate: "2024-03-05"}, failing, nil); err == nil {
+		t.Fatal("Expected error for a duplicated task ID")
+	}
+	if _, total, _ := ListTasks(nil, "created_at", "asc", 0, 0); total != 2 {
+		t.Errorf("Expected the failed run to create no tasks, got %d tasks", total)
+	}
+	if _, err := GetLatestRoutineRun(routineID, "2024-03-05"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected the failed run not to be recorded, got %v", err)
+	}
+}
+
+func TestTaskParentAndDeletePromotesChildren(t *testing.T) {
+	if _, err := db.Exec("DELETE FROM tasks"); err != nil {
+		t.Fatalf("Failed to clear tasks table: %v", err)
+	}
+	create := func(description, parentID string) string {
+		id, err := CreateTask(models.Task{Description: description, Status: models.TaskStatusPending, ParentID: parentID})
+		if err != nil {
+			t.Fatalf("CreateTask(%q) failed: %v", description, err)
+		}
+		return id
+	}
+	root := create("Feira de ciências", "")
+	middle := create("Montar estandes", root)
+	leaf := create("Comprar cartolina", middle)
+
+	children, _, err := ListTasks(map[string]interface{}{"parent_id": root}, "created_at", "asc", 0, 0)
+	if err != nil || len(children) != 1 || children[0].ID != middle || children[0].ParentID != root {
+		t.Fatalf("Expected only %s as child of the root, got %+v (%v)", middle, children, err)
+	}
+
+	if err := DeleteTask(middle); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	promoted, err := GetTask(leaf)
+	if err != nil || promoted.ParentID != root {
+		t.Errorf("Expected the grandchild to move up to the root, got %+v (%v)", promoted, err)
+	}
+	if err := DeleteTask(root); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if promoted, _ := GetTask(leaf); promoted.ParentID != "" {
+		t.Errorf("Expected the task to become top level, got parent %q", promoted.ParentID)
+	}
+	if err := DeleteTask(root); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing task, got %v", err)
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	for _, table := range []string{"tasks", "task_dependencies"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+	var ids []string
+	for _, description := range []string{"Corrigir provas", "Lançar notas", "Imprimir boletim"} {
+		id, err := CreateTask(models.Task{Description: description, Status: models.TaskStatusPending})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	for i, dep := range []models.TaskDependency{{TaskID: ids[1], DependsOnID: ids[0]}, {TaskID: ids[2], DependsOnID: ids[1]}} {
+		dep.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := AddTaskDependency(dep); err != nil {
+			t.Fatalf("AddTaskDependency failed: %v", err)
+		}
+	}
+	if err := AddTaskDependency(models.TaskDependency{TaskID: ids[1], DependsOnID: ids[0]}); err == nil {
+		t.Error("Expected error for a duplicated dependency")
+	}
+
+	if all, err := ListTaskDependencies("", ""); err != nil || len(all) != 2 {
+		t.Fatalf("Expected 2 dependencies, got %+v (%v)", all, err)
+	}
+	if deps, _ := ListTaskDependencies(ids[1], ""); len(deps) != 1 || deps[0].DependsOnID != ids[0] {
+		t.Errorf("Unexpected prerequisites of %s: %+v", ids[1], deps)
+	}
+	if deps, _ := ListTaskDependencies("", ids[1]); len(deps) != 1 || deps[0].TaskID != ids[2] {
+		t.Errorf("Unexpected dependents of %s: %+v", ids[1], deps)
+	}
+
+	if err := DeleteTask(ids[1]); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if all, _ := ListTaskDependencies("", ""); len(all) != 0 {
+		t.Errorf("Expected the links of the deleted task to be removed, got %+v", all)
+	}
+	if err := RemoveTaskDependency(ids[2], ids[1]); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing dependency, got %v", err)
+	}
+}
+
+func TestTaskLinks(t *testing.T) {
+	for _, table := range []string{"tasks", "task_links"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+	var ids []string
+	for _, description := range []string{"Corrigir prova123", "Preparar aula 3 da 7B"} {
+		id, err := CreateTask(models.Task{Description: description, Status: models.TaskStatusPending})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	links := []models.TaskLink{
+		{TaskID: ids[0], EntityType: models.TaskLinkTest, EntityID: "prova123"},
+		{TaskID: ids[0], EntityType: models.TaskLinkClass, EntityID: "7B"},
+		{TaskID: ids[1], EntityType: models.TaskLinkClass, EntityID: "7B"},
+	}
+	for i, link := range links {
+		link.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := AddTaskLink(link); err != nil {
+			t.Fatalf("AddTaskLink failed: %v", err)
+		}
+	}
+	if err := AddTaskLink(links[0]); err == nil {
+		t.Error("Expected error for a duplicated link")
+	}
+	if err := AddTaskLink(models.TaskLink{TaskID: ids[0], EntityType: models.TaskLinkTest}); err == nil {
+		t.Error("Expected error for a link without entity ID")
+	}
+
+	if all, err := ListTaskLinks("", "", ""); err != nil || len(all) != 3 {
+		t.Fatalf("Expected 3 links, got %+v (%v)", all, err)
+	}
+	if own, _ := ListTaskLinks(ids[0], "", ""); len(own) != 2 || own[0].EntityType != models.TaskLinkTest {
+		t.Errorf("Unexpected links of %s: %+v", ids[0], own)
+	}
+	if class, _ := ListTaskLinks("", models.TaskLinkClass, "7B"); len(class) != 2 || class[1].TaskID != ids[1] {
+		t.Errorf("Unexpected links to class 7B: %+v", class)
+	}
+
+	if err := RemoveTaskLink(ids[0], models.TaskLinkClass, "7B"); err != nil {
+		t.Fatalf("RemoveTaskLink failed: %v", err)
+	}
+	if err := RemoveTaskLink(ids[0], models.TaskLinkClass, "7B"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing link, got %v", err)
+	}
+	if err := DeleteTask(ids[0]); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if all, _ := ListTaskLinks("", "", ""); len(all) != 1 || all[0].TaskID != ids[1] {
+		t.Errorf("Expected the links of the deleted task to be removed, got %+v", all)
+	}
+}
+
+func TestTaskTimeEntries(t *testing.T) {
+	for _, table := range []string{"tasks", "task_time_entries"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+	if _, err := GetRunningTimeEntry(); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows without a running timer, got %v", err)
+	}
+	if _, err := StopTaskTimer(time.Now()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows stopping without a running timer, got %v", err)
+	}
+
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	first, stopped, err := StartTaskTimer("task-a", base)
+	if err != nil || stopped.ID != "" {
+		t.Fatalf("StartTaskTimer failed: %v (stopped %+v)", err, stopped)
+	}
+	second, stopped, err := StartTaskTimer("task-b", base.Add(25*time.Minute))
+	if err != nil {
+		t.Fatalf("StartTaskTimer failed: %v", err)
+	}
+	if stopped.ID != first.ID || stopped.Duration(time.Time{}) != 25*time.Minute {
+		t.Errorf("Expected the timer of task-a to be stopped after 25m, got %+v", stopped)
+	}
+	if running, err := GetRunningTimeEntry(); err != nil || running.ID != second.ID {
+		t.Errorf("Expected the timer of task-b to be running, got %+v (%v)", running, err)
+	}
+	if done, err := StopTaskTimer(base.Add(time.Hour)); err != nil || done.ID != second.ID || done.Duration(time.Time{}) != 35*time.Minute {
+		t.Errorf("Unexpected stopped entry %+v (%v)", done, err)
+	}
+
+	if _, err := CreateTimeEntry(models.TaskTimeEntry{TaskID: "task-a", StartTime: base, EndTime: base.Add(-time.Minute)}); err == nil {
+		t.Error("Expected error for an entry ending before it starts")
+	}
+	if _, err := CreateTimeEntry(models.TaskTimeEntry{TaskID: "task-a", StartTime: base.Add(-time.Hour), EndTime: base.Add(-15 * time.Minute), Manual: true}); err != nil {
+		t.Fatalf("CreateTimeEntry failed: %v", err)
+	}
+	entries, err := ListTimeEntries("task-a")
+	if err != nil || len(entries) != 2 || !entries[0].Manual || entries[1].ID != first.ID {
+		t.Fatalf("Unexpected entries of task-a, ordered by start: %+v (%v)", entries, err)
+	}
+	if all, _ := ListTimeEntries(""); len(all) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(all))
+	}
+
+	id, err := CreateTask(models.Task{ID: "task-a", Description: "Corrigir provas", Status: models.TaskStatusPending})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := DeleteTask(id); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if entries, _ := ListTimeEntries("task-a"); len(entries) != 0 {
+		t.Errorf("Expected the entries of the deleted task to be removed, got %+v", entries)
+	}
+}
+
+func TestProjectsAndMembership(t *testing.T) {
+	for _, table := range []string{"projects", "tasks", "events", "routines"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+
+	due := time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC)
+	feiraID, err := CreateProject(models.Project{Name: "Feira de Ciências", DueDate: due})
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if _, err := CreateProject(models.Project{Name: "feira de ciências"}); err == nil {
+		t.Error("Expected project names to be unique regardless of case")
+	}
+	escolaID, err := CreateProject(models.Project{Name: "Escola A", Status: models.ProjectStatusArchived})
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+
+	feira, err := GetProject(feiraID)
+	if err != nil || feira.ID != feiraID || feira.Status != models.ProjectStatusActive || !feira.DueDate.Equal(due) {
+		t.Fatalf("Unexpected project %+v (%v)", feira, err)
+	}
+	if _, err := GetProject("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing project, got %v", err)
+	}
+	if active, _, err := ListProjects(map[string]interface{}{"status": "ativo"}, "", "", 0, 0); err != nil || len(active) != 1 || active[0].ID != feiraID {
+		t.Errorf("Unexpected active projects %+v (%v)", active, err)
+	}
+
+	taskID, err := CreateTask(models.Task{Description: "Reservar o pátio", Status: models.TaskStatusPending, ProjectID: feiraID})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := CreateTask(models.Task{Description: "Sem projeto", Status: models.TaskStatusPending}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	eventID, err := CreateEvent(models.Event{Title: "Montagem", StartTime: due, EndTime: due.Add(time.Hour), ProjectID: feiraID})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	routineID, err := CreateRoutine(models.Routine{Name: "Planejamento", Frequency: "manual", TaskDescription: "Planejar", ProjectID: escolaID})
+	if err != nil {
+		t.Fatalf("CreateRoutine failed: %v", err)
+	}
+
+	if tasks, _, err := ListTasks(map[string]interface{}{"project_id": feiraID}, "", "", 0, 0); err != nil || len(tasks) != 1 || tasks[0].ID != taskID {
+		t.Errorf("Unexpected tasks of the project %+v (%v)", tasks, err)
+	}
+	if events, _, err := ListEvents(map[string]interface{}{"project_id": feiraID}, "", "", 0, 0); err != nil || len(events) != 1 || events[0].ID != eventID {
+		t.Errorf("Unexpected events of the project %+v (%v)", events, err)
+	}
+	if routine, err := GetRoutine(routineID); err != nil || routine.ProjectID != escolaID {
+		t.Errorf("Unexpected routine %+v (%v)", routine, err)
+	}
+
+	if err := DeleteProject(feiraID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	if task, err := GetTask(taskID); err != nil || task.ProjectID != "" {
+		t.Errorf("Expected the task to be kept without a project, got %+v (%v)", task, err)
+	}
+	if event, err := GetEvent(eventID); err != nil || event.ProjectID != "" {
+		t.Errorf("Expected the event to be kept without a project, got %+v (%v)", event, err)
+	}
+	if err := DeleteProject(feiraID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting a missing project, got %v", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	if _, err := db.Exec("DELETE FROM settings"); err != nil {
+		t.Fatalf("Failed to clear settings table: %v", err)
+	}
+	if _, err := GetSetting("urgencia.prazo"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for an unset key, got %v", err)
+	}
+	for key, value := range map[string]string{"urgencia.prazo": "12", "urgencia.tag.provas": "3", "tema": "escuro"} {
+		if err := SetSetting(key, value); err != nil {
+			t.Fatalf("SetSetting failed: %v", err)
+		}
+	}
+	if err := SetSetting("urgencia.prazo", "10.5"); err != nil {
+		t.Fatalf("SetSetting (replace) failed: %v", err)
+	}
+	if value, err := GetSetting("urgencia.prazo"); err != nil || value != "10.5" {
+		t.Errorf("Expected the replaced value 10.5, got %q (%v)", value, err)
+	}
+	if settings, err := ListSettings("urgencia."); err != nil || len(settings) != 2 || settings["urgencia.tag.provas"] != "3" {
+		t.Errorf("Unexpected settings with prefix: %+v (%v)", settings, err)
+	}
+	if err := DeleteSetting("tema"); err != nil {
+		t.Fatalf("DeleteSetting failed: %v", err)
+	}
+	if err := DeleteSetting("tema"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting an unset key, got %v", err)
+	}
+	if err := SetSetting("", "x"); err == nil {
+		t.Error("Expected error for an empty key")
+	}
+}
+
+func TestSchedulerLocks(t *testing.T) {
+	if _, err := db.Exec("DELETE FROM scheduler_locks"); err != nil {
+		t.Fatalf("Failed to clear scheduler_locks table: %v", err)
+	}
+
+	if ok, err := AcquireLock("job", "a", time.Minute); err != nil || !ok {
+		t.Fatalf("Expected owner a to acquire the lock, got %v (%v)", ok, err)
+	}
+	if ok, err := AcquireLock("job", "b", time.Minute); err != nil || ok {
+		t.Errorf("Expected owner b to be refused while a holds the lock, got %v (%v)", ok, err)
+	}
+	if ok, err := AcquireLock("job", "a", time.Minute); err != nil || !ok {
+		t.Errorf("Expected owner a to renew its own lock, got %v (%v)", ok, err)
+	}
+	if ok, err := AcquireLock("other-job", "b", time.Minute); err != nil || !ok {
+		t.Errorf("Expected locks with different names to be independent, got %v (%v)", ok, err)
+	}
+
+	// Releasing someone else's lock is a no-op.
+	if err := ReleaseLock("job", "b"); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+	if ok, _ := AcquireLock("job", "b", time.Minute); ok {
+		t.Error("Expected lock to stay with a after b tried to release it")
+	}
+	if err := ReleaseLock("job", "a"); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+	if ok, err := AcquireLock("job", "b", -time.Millisecond); err != nil || !ok {
+		t.Fatalf("Expected b to acquire the released lock, got %v (%v)", ok, err)
+	}
+	// b's lock has already expired, so a can take it over.
+	if ok, err := AcquireLock("job", "a", time.Minute); err != nil || !ok {
+		t.Errorf("Expected a to take over an expired lock, got %v (%v)", ok, err)
+	}
+}
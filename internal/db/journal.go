@@ -0,0 +1,735 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The operation journal records every row inserted, updated or deleted in the application tables,
+// so that a command can be undone and redone.
+//
+// Triggers on each journaled table write a before/after JSON snapshot of the affected row to
+// journal_changes, tagged with the session started by JournalMark. At the end of a command,
+// RecordOperation groups the changes of its session into one journal_operations row, so commands
+// running at the same time in other processes (the daemon, a cron job) never take each other's changes.
+// UndoOperation and RedoOperation restore the snapshots with the triggers paused, so restoring is not
+// journaled again.
+//
+// The session lives in a TEMP table, which only the connection that created it can see, so the triggers
+// are TEMP triggers as well: each process installs them on its connection when opening the database and
+// after every migration (see syncJournalTriggers), so tables and columns added by later migrations are
+// journaled without further changes. OpenDB pins the pool to that single connection. Changes made by
+// other tools, such as the sqlite3 shell, are not journaled; an undo that meets them fails with
+// ErrJournalConflict.
+
+// journalRetention is how many operations are kept; older ones are discarded when a new one is recorded.
+const journalRetention = 100
+
+// journalTriggerPrefix is the name prefix of the triggers managed by syncJournalTriggers.
+const journalTriggerPrefix = "journal_"
+
+// OperationMark identifies a command in progress, from JournalMark to RecordOperation.
+type OperationMark struct {
+	session  string // Tag of the changes made by the command.
+	position int64  // Last change journaled before the command started.
+}
+
+// unjournaledTables are the tables whose changes are not recorded: bookkeeping, locks and the journal itself.
+var unjournaledTables = map[string]bool{
+	"schema_migrations":  true,
+	"scheduler_locks":    true,
+	"journal_operations": true,
+	"journal_changes":    true,
+	"journal_pause":      true,
+}
+
+var (
+	// ErrNothingToUndo is returned by UndoOperation when every recorded operation is already undone.
+	ErrNothingToUndo = errors.New("no operation to undo")
+	// ErrNothingToRedo is returned by RedoOperation when no operation has been undone.
+	ErrNothingToRedo = errors.New("no operation to redo")
+	// ErrJournalConflict is returned when a row no longer matches the snapshot an undo or redo expects,
+	// because it was changed outside the journal.
+	ErrJournalConflict = errors.New("the data changed after the operation")
+)
+
+// JournalOperation is one command recorded in the journal, with the row changes it made.
+type JournalOperation struct {
+	ID        int64
+	Command   string
+	CreatedAt time.Time
+	UndoneAt  time.Time // Zero while the operation is applied.
+	Changes   []JournalChange
+}
+
+// Undone reports whether the operation has been undone.
+func (o JournalOperation) Undone() bool {
+	return !o.UndoneAt.IsZero()
+}
+
+// JournalChange is the snapshot of one row before and after a change.
+type JournalChange struct {
+	Table  string
+	Key    map[string]interface{} // Primary key columns of the row.
+	Before map[string]interface{} // Nil for inserted rows.
+	After  map[string]interface{} // Nil for deleted rows.
+}
+
+// Kind returns "insert", "update" or "delete".
+func (c JournalChange) Kind() string {
+	switch {
+	case c.Before == nil:
+		return "insert"
+	case c.After == nil:
+		return "delete"
+	}
+	return "update"
+}
+
+// --- Schema ---
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// journalExists reports whether the journal tables have been created.
+func journalExists(q queryer) (bool, error) {
+	var n int
+	if err := q.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'journal_changes'").Scan(&n); err != nil {
+		return false, fmt.Errorf("failed to check the journal tables: %w", err)
+	}
+	return n > 0, nil
+}
+
+// setJournalPaused stops (or resumes) the journal triggers for the rest of the transaction.
+// It does nothing while the journal tables do not exist.
+func setJournalPaused(tx *sql.Tx, paused bool) error {
+	exists, err := journalExists(tx)
+	if err != nil || !exists {
+		return err
+	}
+	stmt := "DELETE FROM journal_pause"
+	if paused {
+		stmt = "INSERT INTO journal_pause (paused) VALUES (1)"
+	}
+	if _, err := tx.Exec(stmt); err != nil {
+		return fmt.Errorf("failed to pause the journal: %w", err)
+	}
+	return nil
+}
+
+// quoteIdent quotes a table or column name for use in SQL.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes a string as an SQL literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// tableColumns returns the columns of table and its primary key columns, in key order.
+// Tables without a declared primary key use the rowid.
+func tableColumns(q queryer, table string) (columns, keys []string, err error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(table)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	keyOrder := map[string]int{}
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan table_info for %s: %w", table, err)
+		}
+		columns = append(columns, name)
+		if pk > 0 {
+			keys = append(keys, name)
+			keyOrder[name] = pk
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating table_info for %s: %w", table, err)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keyOrder[keys[i]] < keyOrder[keys[j]] })
+	if len(keys) == 0 {
+		keys = []string{"rowid"}
+		columns = append([]string{"rowid"}, columns...)
+	}
+	return columns, keys, nil
+}
+
+// jsonObjectSQL builds a json_object() expression with the given columns of the row alias (NEW or OLD).
+func jsonObjectSQL(alias string, columns []string) string {
+	parts := make([]string, 0, 2*len(columns))
+	for _, c := range columns {
+		parts = append(parts, quoteLiteral(c), alias+"."+quoteIdent(c))
+	}
+	return "json_object(" + strings.Join(parts, ", ") + ")"
+}
+
+// journalTriggers returns the CREATE TEMP TRIGGER statements that journal table, by trigger name.
+// The triggers only fire while a session is set (between JournalMark and RecordOperation) and the
+// journal is not paused.
+func journalTriggers(table string, columns, keys []string) map[string]string {
+	name := journalTriggerPrefix + table
+	insertRow := "INSERT INTO journal_changes (session, table_name, row_key, before_row, after_row) VALUES ((SELECT session FROM journal_session), " + quoteLiteral(table) + ", "
+	when := "WHEN EXISTS (SELECT 1 FROM journal_session) AND NOT EXISTS (SELECT 1 FROM journal_pause)"
+
+	var changed []string
+	for _, c := range columns {
+		changed = append(changed, "OLD."+quoteIdent(c)+" IS NOT NEW."+quoteIdent(c))
+	}
+
+	return map[string]string{
+		name + "_insert": fmt.Sprintf("CREATE TEMP TRIGGER %s AFTER INSERT ON %s %s BEGIN %s%s, NULL, %s); END",
+			quoteIdent(name+"_insert"), quoteIdent(table), when, insertRow, jsonObjectSQL("NEW", keys), jsonObjectSQL("NEW", columns)),
+		name + "_update": fmt.Sprintf("CREATE TEMP TRIGGER %s AFTER UPDATE ON %s %s AND (%s) BEGIN %s%s, %s, %s); END",
+			quoteIdent(name+"_update"), quoteIdent(table), when, strings.Join(changed, " OR "), insertRow, jsonObjectSQL("OLD", keys), jsonObjectSQL("OLD", columns), jsonObjectSQL("NEW", columns)),
+		name + "_delete": fmt.Sprintf("CREATE TEMP TRIGGER %s AFTER DELETE ON %s %s BEGIN %s%s, %s, NULL); END",
+			quoteIdent(name+"_delete"), quoteIdent(table), when, insertRow, jsonObjectSQL("OLD", keys), jsonObjectSQL("OLD", columns)),
+	}
+}
+
+// syncJournalTriggers makes the journal triggers of the transaction's connection match the current
+// schema: every application table gets an insert, update and delete trigger listing all of its columns.
+// Outdated triggers are recreated and triggers of dropped tables are removed. Without the journal
+// tables, all journal triggers are dropped.
+func syncJournalTriggers(tx *sql.Tx) error {
+	exists, err := journalExists(tx)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]string{}
+	if exists {
+		if _, err := tx.Exec("CREATE TEMP TABLE IF NOT EXISTS journal_session (session TEXT NOT NULL)"); err != nil {
+			return fmt.Errorf("failed to create the journal session table: %w", err)
+		}
+		rows, err := tx.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+		if err != nil {
+			return fmt.Errorf("failed to list tables: %w", err)
+		}
+		var tables []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan table name: %w", err)
+			}
+			if !unjournaledTables[name] {
+				tables = append(tables, name)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating tables: %w", err)
+		}
+		for _, table := range tables {
+			columns, keys, err := tableColumns(tx, table)
+			if err != nil {
+				return err
+			}
+			for name, stmt := range journalTriggers(table, columns, keys) {
+				desired[name] = stmt
+			}
+		}
+	}
+
+	rows, err := tx.Query("SELECT name, sql FROM sqlite_temp_master WHERE type = 'trigger' AND substr(name, 1, ?) = ?", len(journalTriggerPrefix), journalTriggerPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list journal triggers: %w", err)
+	}
+	current := map[string]string{}
+	for rows.Next() {
+		var name, stmt string
+		if err := rows.Scan(&name, &stmt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan journal trigger: %w", err)
+		}
+		current[name] = stmt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating journal triggers: %w", err)
+	}
+
+	for name, stmt := range current {
+		if desired[name] == stmt {
+			continue
+		}
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS temp." + quoteIdent(name)); err != nil {
+			return fmt.Errorf("failed to drop journal trigger %s: %w", name, err)
+		}
+	}
+	for name, stmt := range desired {
+		if current[name] == stmt {
+			continue
+		}
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create journal trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// installJournal installs the journal triggers on the single connection of conn.
+func installJournal(conn *sql.DB) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction to install the journal: %w", err)
+	}
+	defer tx.Rollback()
+	if err := syncJournalTriggers(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// dropJournalTriggers removes the journal triggers of the transaction's connection.
+func dropJournalTriggers(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT name FROM sqlite_temp_master WHERE type = 'trigger' AND substr(name, 1, ?) = ?", len(journalTriggerPrefix), journalTriggerPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list journal triggers: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan journal trigger: %w", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating journal triggers: %w", err)
+	}
+	for _, name := range names {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS temp." + quoteIdent(name)); err != nil {
+			return fmt.Errorf("failed to drop journal trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// --- Recording ---
+
+// JournalMark starts a new session before a command runs: the changes the command makes on this
+// connection are tagged with it until RecordOperation is called with the returned mark.
+// It returns a zero mark, which records nothing, when the journal tables do not exist.
+func JournalMark() (OperationMark, error) {
+	return markOperation(db)
+}
+
+func markOperation(conn *sql.DB) (OperationMark, error) {
+	if conn == nil {
+		return OperationMark{}, errors.New("database is not initialized")
+	}
+	exists, err := journalExists(conn)
+	if err != nil || !exists {
+		return OperationMark{}, err
+	}
+	mark := OperationMark{session: uuid.NewString()}
+	var position sql.NullInt64
+	if err := conn.QueryRow("SELECT MAX(id) FROM journal_changes").Scan(&position); err != nil {
+		return OperationMark{}, fmt.Errorf("failed to read the journal position: %w", err)
+	}
+	mark.position = position.Int64
+	if _, err := conn.Exec("DELETE FROM journal_session"); err != nil {
+		return OperationMark{}, fmt.Errorf("failed to start the journal session: %w", err)
+	}
+	if _, err := conn.Exec("INSERT INTO journal_session (session) VALUES (?)", mark.session); err != nil {
+		return OperationMark{}, fmt.Errorf("failed to start the journal session: %w", err)
+	}
+	return mark, nil
+}
+
+// RecordOperation ends the session of mark (see JournalMark) and groups its changes into a new
+// operation described by command. Recording an operation discards the undone operations, which can no
+// longer be redone, and the operations beyond the most recent journalRetention. Changes journaled
+// before the command started that still have no operation belong to commands that failed before
+// recording them, and are discarded as well.
+// It returns an operation with ID 0 when there were no changes.
+func RecordOperation(command string, mark OperationMark) (JournalOperation, error) {
+	return recordOperation(db, command, mark)
+}
+
+func recordOperation(conn *sql.DB, command string, mark OperationMark) (JournalOperation, error) {
+	if conn == nil {
+		return JournalOperation{}, errors.New("database is not initialized")
+	}
+	if mark.session == "" {
+		return JournalOperation{}, nil
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to begin transaction to record operation: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The command may have reverted the journal migrations ("db migrate down").
+	exists, err := journalExists(tx)
+	if err != nil || !exists {
+		return JournalOperation{}, err
+	}
+	if _, err := tx.Exec("DELETE FROM journal_session"); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to end the journal session: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM journal_changes WHERE operation_id IS NULL AND id <= ?", mark.position); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to discard unrecorded changes: %w", err)
+	}
+	var pending int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM journal_changes WHERE session = ? AND operation_id IS NULL", mark.session).Scan(&pending); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to count the changes of the operation: %w", err)
+	}
+	if pending == 0 {
+		if err := tx.Commit(); err != nil {
+			return JournalOperation{}, fmt.Errorf("failed to end the journal session: %w", err)
+		}
+		return JournalOperation{}, nil
+	}
+
+	op := JournalOperation{Command: command, CreatedAt: time.Now()}
+	res, err := tx.Exec("INSERT INTO journal_operations (command, created_at) VALUES (?, ?)", op.Command, op.CreatedAt)
+	if err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to record operation: %w", err)
+	}
+	if op.ID, err = res.LastInsertId(); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to get the operation ID: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE journal_changes SET operation_id = ? WHERE session = ? AND operation_id IS NULL", op.ID, mark.session); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to attach changes to operation: %w", err)
+	}
+
+	if err := execAll(tx,
+		"DELETE FROM journal_changes WHERE operation_id IN (SELECT id FROM journal_operations WHERE undone_at IS NOT NULL)",
+		"DELETE FROM journal_operations WHERE undone_at IS NOT NULL",
+		fmt.Sprintf("DELETE FROM journal_operations WHERE id NOT IN (SELECT id FROM journal_operations ORDER BY id DESC LIMIT %d)", journalRetention),
+		"DELETE FROM journal_changes WHERE operation_id IS NOT NULL AND operation_id NOT IN (SELECT id FROM journal_operations)",
+	); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to discard old operations: %w", err)
+	}
+
+	if op.Changes, err = loadJournalChanges(tx, op.ID); err != nil {
+		return JournalOperation{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to commit operation: %w", err)
+	}
+	return op, nil
+}
+
+// --- Reading ---
+
+// ListOperations returns the most recent operations, newest first, with their changes.
+// A limit <= 0 returns every kept operation.
+func ListOperations(limit int) ([]JournalOperation, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := "SELECT id, command, created_at, undone_at FROM journal_operations ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+	var ops []JournalOperation
+	for rows.Next() {
+		op, err := scanJournalOperation(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of operations: %w", err)
+	}
+	for i := range ops {
+		if ops[i].Changes, err = loadJournalChanges(db, ops[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+func scanJournalOperation(row rowScanner) (JournalOperation, error) {
+	var op JournalOperation
+	var undoneAt sql.NullTime
+	if err := row.Scan(&op.ID, &op.Command, &op.CreatedAt, &undoneAt); err != nil {
+		return JournalOperation{}, err
+	}
+	if undoneAt.Valid {
+		op.UndoneAt = undoneAt.Time
+	}
+	return op, nil
+}
+
+// loadJournalChanges returns the changes of an operation in the order they were made.
+func loadJournalChanges(q queryer, operationID int64) ([]JournalChange, error) {
+	rows, err := q.Query("SELECT table_name, row_key, before_row, after_row FROM journal_changes WHERE operation_id = ? ORDER BY id", operationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changes of operation %d: %w", operationID, err)
+	}
+	defer rows.Close()
+
+	var changes []JournalChange
+	for rows.Next() {
+		var c JournalChange
+		var key string
+		var before, after sql.NullString
+		if err := rows.Scan(&c.Table, &key, &before, &after); err != nil {
+			return nil, fmt.Errorf("failed to scan change of operation %d: %w", operationID, err)
+		}
+		if c.Key, err = decodeSnapshot(key); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			if c.Before, err = decodeSnapshot(before.String); err != nil {
+				return nil, err
+			}
+		}
+		if after.Valid {
+			if c.After, err = decodeSnapshot(after.String); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of changes: %w", err)
+	}
+	return changes, nil
+}
+
+// decodeSnapshot decodes a row snapshot written by json_object. Numbers are kept as json.Number,
+// so integers and reals are restored with their original type.
+func decodeSnapshot(s string) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var row map[string]interface{}
+	if err := dec.Decode(&row); err != nil {
+		return nil, fmt.Errorf("invalid row snapshot %q: %w", s, err)
+	}
+	return row, nil
+}
+
+// --- Undo and redo ---
+
+// UndoOperation reverts the most recent operation that is still applied, restoring every row it
+// changed, and marks it as undone. It returns ErrNothingToUndo if there is none, and wraps
+// ErrJournalConflict, leaving the data untouched, if a row was changed afterwards outside the journal.
+func UndoOperation() (JournalOperation, error) {
+	return replayOperation("SELECT id, command, created_at, undone_at FROM journal_operations WHERE undone_at IS NULL ORDER BY id DESC LIMIT 1", true)
+}
+
+// RedoOperation applies again the oldest undone operation (the last one undone) and marks it as applied.
+// It returns ErrNothingToRedo if no operation has been undone, and wraps ErrJournalConflict like UndoOperation.
+func RedoOperation() (JournalOperation, error) {
+	return replayOperation("SELECT id, command, created_at, undone_at FROM journal_operations WHERE undone_at IS NOT NULL ORDER BY id ASC LIMIT 1", false)
+}
+
+// replayOperation undoes or redoes the operation selected by query in a single transaction.
+func replayOperation(query string, undo bool) (JournalOperation, error) {
+	if db == nil {
+		return JournalOperation{}, errors.New("database is not initialized")
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Pausing the triggers is the first write, so no other command changes the data until the commit.
+	if err := setJournalPaused(tx, true); err != nil {
+		return JournalOperation{}, err
+	}
+	op, err := scanJournalOperation(tx.QueryRow(query))
+	if errors.Is(err, sql.ErrNoRows) {
+		if undo {
+			return JournalOperation{}, ErrNothingToUndo
+		}
+		return JournalOperation{}, ErrNothingToRedo
+	}
+	if err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to find the operation: %w", err)
+	}
+	if op.Changes, err = loadJournalChanges(tx, op.ID); err != nil {
+		return JournalOperation{}, err
+	}
+
+	if undo {
+		for i := len(op.Changes) - 1; i >= 0; i-- {
+			c := op.Changes[i]
+			if err := restoreRow(tx, c, c.After, c.Before); err != nil {
+				return JournalOperation{}, err
+			}
+		}
+		op.UndoneAt = time.Now()
+		_, err = tx.Exec("UPDATE journal_operations SET undone_at = ? WHERE id = ?", op.UndoneAt, op.ID)
+	} else {
+		for _, c := range op.Changes {
+			if err := restoreRow(tx, c, c.Before, c.After); err != nil {
+				return JournalOperation{}, err
+			}
+		}
+		op.UndoneAt = time.Time{}
+		_, err = tx.Exec("UPDATE journal_operations SET undone_at = NULL WHERE id = ?", op.ID)
+	}
+	if err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to update operation %d: %w", op.ID, err)
+	}
+
+	if err := setJournalPaused(tx, false); err != nil {
+		return JournalOperation{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return JournalOperation{}, fmt.Errorf("failed to commit: %w", err)
+	}
+	return op, nil
+}
+
+// restoreRow moves the row of change c from the snapshot from to the snapshot to: a nil from inserts
+// the row, a nil to deletes it. The row must still match from, or ErrJournalConflict is returned.
+func restoreRow(tx *sql.Tx, c JournalChange, from, to map[string]interface{}) error {
+	keys := make([]string, 0, len(c.Key))
+	for k := range c.Key {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	table := quoteIdent(c.Table)
+
+	// The row is found by the key values of the snapshot it must currently match.
+	locator := from
+	if locator == nil {
+		locator = to
+	}
+	var where []string
+	var whereArgs []interface{}
+	for _, k := range keys {
+		where = append(where, quoteIdent(k)+" = ?")
+		whereArgs = append(whereArgs, snapshotValue(locator[k]))
+	}
+	whereSQL := strings.Join(where, " AND ")
+
+	current, err := currentSnapshot(tx, c.Table, whereSQL, whereArgs, from, to)
+	if err != nil {
+		return err
+	}
+	if !snapshotMatches(current, from) {
+		return fmt.Errorf("%w: row %s of table %s", ErrJournalConflict, formatKey(c.Key), c.Table)
+	}
+
+	switch {
+	case to == nil:
+		_, err = tx.Exec("DELETE FROM "+table+" WHERE "+whereSQL, whereArgs...)
+	case from == nil:
+		columns := sortedColumns(to)
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		var quoted []string
+		var args []interface{}
+		for _, col := range columns {
+			quoted = append(quoted, quoteIdent(col))
+			args = append(args, snapshotValue(to[col]))
+		}
+		_, err = tx.Exec("INSERT INTO "+table+" ("+strings.Join(quoted, ", ")+") VALUES ("+placeholders+")", args...)
+	default:
+		var set []string
+		var args []interface{}
+		for _, col := range sortedColumns(to) {
+			set = append(set, quoteIdent(col)+" = ?")
+			args = append(args, snapshotValue(to[col]))
+		}
+		_, err = tx.Exec("UPDATE "+table+" SET "+strings.Join(set, ", ")+" WHERE "+whereSQL, append(args, whereArgs...)...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore row %s of table %s: %w", formatKey(c.Key), c.Table, err)
+	}
+	return nil
+}
+
+// currentSnapshot reads the row matched by where as a snapshot with the columns of from (or of to, when
+// the row is expected not to exist). It returns nil if there is no such row.
+func currentSnapshot(tx *sql.Tx, table, where string, args []interface{}, from, to map[string]interface{}) (map[string]interface{}, error) {
+	columns := sortedColumns(from)
+	if from == nil {
+		columns = sortedColumns(to)
+	}
+	parts := make([]string, 0, 2*len(columns))
+	for _, c := range columns {
+		parts = append(parts, quoteLiteral(c), quoteIdent(c))
+	}
+	var s string
+	err := tx.QueryRow("SELECT json_object("+strings.Join(parts, ", ")+") FROM "+quoteIdent(table)+" WHERE "+where, args...).Scan(&s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read row of table %s: %w", table, err)
+	}
+	return decodeSnapshot(s)
+}
+
+// snapshotMatches reports whether the current row matches the expected snapshot (nil meaning no row).
+func snapshotMatches(current, expected map[string]interface{}) bool {
+	if current == nil || expected == nil {
+		return current == nil && expected == nil
+	}
+	for col, v := range expected {
+		if !reflect.DeepEqual(current[col], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotValue converts a decoded snapshot value into a value for a query argument.
+func snapshotValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+func sortedColumns(row map[string]interface{}) []string {
+	columns := make([]string, 0, len(row))
+	for c := range row {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// formatKey formats a primary key for error messages, e.g. "id=abc" or "slot_id=s1, date=2024-08-05".
+func formatKey(key map[string]interface{}) string {
+	var parts []string
+	for _, k := range sortedColumns(key) {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, key[k]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,320 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vickgenda-cli/internal/models"
+)
+
+func clearJournal(t *testing.T) {
+	t.Helper()
+	for _, table := range []string{"journal_operations", "journal_changes"} {
+		if _, err := db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("Failed to clear %s table: %v", table, err)
+		}
+	}
+}
+
+// recordStep runs step and records its changes as an operation named command.
+func recordStep(t *testing.T, command string, step func()) JournalOperation {
+	t.Helper()
+	mark, err := JournalMark()
+	if err != nil {
+		t.Fatalf("JournalMark failed: %v", err)
+	}
+	step()
+	op, err := RecordOperation(command, mark)
+	if err != nil {
+		t.Fatalf("RecordOperation(%s) failed: %v", command, err)
+	}
+	return op
+}
+
+func TestJournalUndoRedo(t *testing.T) {
+	clearJournal(t)
+	due := time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC)
+	task := models.Task{Description: "Fechar notas do 2º bimestre", DueDate: due, Priority: 1, Status: models.TaskStatusPending, Tags: []string{"notas"}}
+
+	create := recordStep(t, "tarefa criar", func() {
+		id, err := CreateTask(task)
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		task.ID = id
+		if err := AddTaskLink(models.TaskLink{TaskID: id, EntityType: models.TaskLinkClass, EntityID: "7B"}); err != nil {
+			t.Fatalf("AddTaskLink failed: %v", err)
+		}
+	})
+	if create.ID == 0 || len(create.Changes) != 2 || create.Changes[0].Kind() != "insert" || create.Changes[0].Table != "tasks" {
+		t.Fatalf("Unexpected operation: %+v", create)
+	}
+	if create.Changes[0].Key["id"] != task.ID {
+		t.Errorf("Expected the task ID as key, got %+v", create.Changes[0].Key)
+	}
+	if op := recordStep(t, "tarefa listar", func() {}); op.ID != 0 {
+		t.Errorf("An operation without changes should not be recorded, got %+v", op)
+	}
+
+	original, err := GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	remove := recordStep(t, "tarefa remover", func() {
+		if err := DeleteTask(task.ID); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+	})
+	kinds := map[string]string{}
+	for _, c := range remove.Changes {
+		kinds[c.Table] = c.Kind()
+	}
+	if kinds["tasks"] != "delete" || kinds["task_links"] != "delete" {
+		t.Errorf("Expected the task and its link to be deleted, got %+v", kinds)
+	}
+
+	ops, err := ListOperations(0)
+	if err != nil || len(ops) != 2 || ops[0].ID != remove.ID || ops[1].Command != "tarefa criar" {
+		t.Fatalf("Unexpected operations: %+v (%v)", ops, err)
+	}
+
+	undone, err := UndoOperation()
+	if err != nil || undone.ID != remove.ID || !undone.Undone() {
+		t.Fatalf("UndoOperation failed: %+v (%v)", undone, err)
+	}
+	restored, err := GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Expected the deleted task to be restored: %v", err)
+	}
+	if restored.Description != original.Description || !restored.DueDate.Equal(original.DueDate) ||
+		!restored.CreatedAt.Equal(original.CreatedAt) || restored.Priority != 1 || strings.Join(restored.Tags, ",") != "notas" {
+		t.Errorf("Restored task differs: %+v, expected %+v", restored, original)
+	}
+	if links, _ := ListTaskLinks(task.ID, "", ""); len(links) != 1 {
+		t.Errorf("Expected the link to be restored, got %+v", links)
+	}
+
+	if _, err := UndoOperation(); err != nil {
+		t.Fatalf("Second UndoOperation failed: %v", err)
+	}
+	if _, err := GetTask(task.ID); err == nil {
+		t.Errorf("Undoing the creation should remove the task")
+	}
+	if _, err := UndoOperation(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, got %v", err)
+	}
+	if op := recordStep(t, "desfazer", func() {}); op.ID != 0 {
+		t.Errorf("Undo must not be journaled, got %+v", op)
+	}
+
+	redone, err := RedoOperation()
+	if err != nil || redone.ID != create.ID || redone.Undone() {
+		t.Fatalf("RedoOperation failed: %+v (%v)", redone, err)
+	}
+	if _, err := GetTask(task.ID); err != nil {
+		t.Errorf("Redoing the creation should bring the task back: %v", err)
+	}
+
+	// A new operation discards the undone ones.
+	recordStep(t, "tarefa editar", func() {
+		original.Priority = 2
+		if err := UpdateTask(original); err != nil {
+			t.Fatalf("UpdateTask failed: %v", err)
+		}
+	})
+	if _, err := RedoOperation(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected ErrNothingToRedo after a new operation, got %v", err)
+	}
+	if ops, _ := ListOperations(0); len(ops) != 2 || ops[0].Command != "tarefa editar" || ops[0].Changes[0].Kind() != "update" {
+		t.Errorf("Unexpected operations after discarding the undone one: %+v", ops)
+	}
+
+	// A change made outside the journal blocks the undo and leaves the data as it is.
+	if _, err := db.Exec("UPDATE tasks SET priority = 3 WHERE id = ?", task.ID); err != nil {
+		t.Fatalf("Failed to update the task: %v", err)
+	}
+	if _, err := UndoOperation(); !errors.Is(err, ErrJournalConflict) {
+		t.Errorf("Expected ErrJournalConflict, got %v", err)
+	}
+	if current, _ := GetTask(task.ID); current.Priority != 3 {
+		t.Errorf("A conflicting undo should not change the task, got priority %d", current.Priority)
+	}
+}
+
+func TestJournalSessions(t *testing.T) {
+	clearJournal(t)
+	// other stands for a second process, such as the daemon, with its own connection to the database.
+	other, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open the second connection: %v", err)
+	}
+	defer other.Close()
+	other.SetMaxOpenConns(1)
+	if err := installJournal(other); err != nil {
+		t.Fatalf("installJournal failed: %v", err)
+	}
+
+	id, err := CreateTask(models.Task{Description: "Preparar aula", Priority: 2, Status: models.TaskStatusPending})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	userMark, err := JournalMark()
+	if err != nil {
+		t.Fatalf("JournalMark failed: %v", err)
+	}
+	daemonMark, err := markOperation(other)
+	if err != nil {
+		t.Fatalf("markOperation failed: %v", err)
+	}
+	if _, err := db.Exec("UPDATE tasks SET priority = 1 WHERE id = ?", id); err != nil {
+		t.Fatalf("Failed to update the task: %v", err)
+	}
+	if _, err := other.Exec("INSERT INTO tasks (id, description, priority, status, created_at) VALUES ('rotina-1', 'Chamada', 2, 'Pendente', CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatalf("Failed to insert the routine task: %v", err)
+	}
+
+	// The daemon records first and must not take the user's edit, nor discard it.
+	daemonOp, err := recordOperation(other, "daemon: rotina executar-pendentes", daemonMark)
+	if err != nil || len(daemonOp.Changes) != 1 || daemonOp.Changes[0].Kind() != "insert" || daemonOp.Changes[0].Key["id"] != "rotina-1" {
+		t.Fatalf("Unexpected daemon operation: %+v (%v)", daemonOp, err)
+	}
+	userOp, err := RecordOperation("tarefa editar", userMark)
+	if err != nil || len(userOp.Changes) != 1 || userOp.Changes[0].Kind() != "update" || userOp.Changes[0].Key["id"] != id {
+		t.Fatalf("Unexpected user operation: %+v (%v)", userOp, err)
+	}
+
+	if _, err := UndoOperation(); err != nil {
+		t.Fatalf("UndoOperation failed: %v", err)
+	}
+	if task, _ := GetTask(id); task.Priority != 2 {
+		t.Errorf("Expected the edit to be undone, got priority %d", task.Priority)
+	}
+	if _, err := GetTask("rotina-1"); err != nil {
+		t.Errorf("Undoing the user's edit must keep the routine task: %v", err)
+	}
+
+	// Changes made outside a session are not journaled.
+	if _, err := db.Exec("UPDATE tasks SET priority = 3 WHERE id = ?", id); err != nil {
+		t.Fatalf("Failed to update the task: %v", err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM journal_changes WHERE operation_id IS NULL").Scan(&n); err != nil || n != 0 {
+		t.Errorf("Expected no change outside a session, got %d (%v)", n, err)
+	}
+}
+
+func TestJournalDiscardsUnrecordedChanges(t *testing.T) {
+	clearJournal(t)
+	// A command that fails before RecordOperation leaves its changes without an operation.
+	if _, err := JournalMark(); err != nil {
+		t.Fatalf("JournalMark failed: %v", err)
+	}
+	if _, err := CreateTask(models.Task{Description: "Comando interrompido", Priority: 3, Status: models.TaskStatusPending}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	unattached := func() int {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM journal_changes WHERE operation_id IS NULL").Scan(&n); err != nil {
+			t.Fatalf("Failed to count changes: %v", err)
+		}
+		return n
+	}
+	if unattached() != 1 {
+		t.Fatalf("Expected the interrupted change to be pending")
+	}
+
+	op := recordStep(t, "tarefa criar", func() {
+		if _, err := CreateTask(models.Task{Description: "Próximo comando", Priority: 3, Status: models.TaskStatusPending}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	})
+	if len(op.Changes) != 1 || op.Changes[0].After["description"] != "Próximo comando" {
+		t.Errorf("Unexpected operation: %+v", op)
+	}
+	if n := unattached(); n != 0 {
+		t.Errorf("Expected the interrupted change to be discarded, %d left", n)
+	}
+
+	// An operation without changes discards them as well.
+	if _, err := JournalMark(); err != nil {
+		t.Fatalf("JournalMark failed: %v", err)
+	}
+	if _, err := CreateTask(models.Task{Description: "Outro comando interrompido", Priority: 3, Status: models.TaskStatusPending}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	recordStep(t, "tarefa listar", func() {})
+	if n := unattached(); n != 0 {
+		t.Errorf("Expected the interrupted change to be discarded by an empty operation, %d left", n)
+	}
+}
+
+func TestJournalTriggersFollowSchema(t *testing.T) {
+	conn := openTempDB(t)
+	conn.SetMaxOpenConns(1)
+	if err := Migrate(conn); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	triggers := func() map[string]string {
+		rows, err := conn.Query("SELECT name, sql FROM sqlite_temp_master WHERE type = 'trigger' AND name LIKE 'journal_%'")
+		if err != nil {
+			t.Fatalf("Failed to list triggers: %v", err)
+		}
+		defer rows.Close()
+		out := map[string]string{}
+		for rows.Next() {
+			var name, stmt string
+			if err := rows.Scan(&name, &stmt); err != nil {
+				t.Fatalf("Failed to scan trigger: %v", err)
+			}
+			out[name] = stmt
+		}
+		return out
+	}
+
+	before := triggers()
+	var persistent int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger'").Scan(&persistent); err != nil || persistent != 0 {
+		t.Errorf("Expected no trigger in the main schema, got %d (%v)", persistent, err)
+	}
+	for _, name := range []string{"journal_tasks_delete", "journal_grades_update", "journal_settings_insert", "journal_timetable_occurrences_delete"} {
+		if before[name] == "" {
+			t.Errorf("Expected trigger %s", name)
+		}
+	}
+	for name := range before {
+		if strings.HasPrefix(name, "journal_schema_migrations") || strings.HasPrefix(name, "journal_journal_") || strings.HasPrefix(name, "journal_scheduler_locks") {
+			t.Errorf("Unexpected trigger %s", name)
+		}
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if _, err := tx.Exec("ALTER TABLE tasks ADD COLUMN color TEXT"); err != nil {
+		t.Fatalf("ALTER TABLE failed: %v", err)
+	}
+	if err := syncJournalTriggers(tx); err != nil {
+		t.Fatalf("syncJournalTriggers failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	after := triggers()
+	if len(after) != len(before) || !strings.Contains(after["journal_tasks_insert"], `'color', NEW."color"`) {
+		t.Errorf("Expected the task triggers to include the new column, got %s", after["journal_tasks_insert"])
+	}
+	if after["journal_events_insert"] != before["journal_events_insert"] {
+		t.Errorf("Triggers of unchanged tables should be kept")
+	}
+
+	if _, err := MigrateDown(conn, len(migrations)); err != nil {
+		t.Fatalf("MigrateDown failed: %v", err)
+	}
+	if left := triggers(); len(left) != 0 {
+		t.Errorf("Expected no journal triggers after reverting every migration, got %d", len(left))
+	}
+}
@@ -0,0 +1,854 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Migration describes a single, numbered change to the database schema.
+// Migrations are applied in ascending Version order and recorded in the
+// schema_migrations table, so each one runs exactly once per database.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// MigrationState reports whether a registered migration has been applied to a database.
+type MigrationState struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// migrations is the ordered list of every schema change known to this binary.
+// New migrations must be appended with the next version number; released
+// migrations must never be edited, since user databases may already have run them.
+var migrations = []Migration{
+	{Version: 1, Name: "create_base_tables", Up: migrateCreateBaseTablesUp, Down: migrateCreateBaseTablesDown},
+	{Version: 2, Name: "reconcile_store_tables", Up: migrateReconcileStoreTablesUp, Down: migrateNoop},
+	{Version: 3, Name: "add_event_recurrence", Up: migrateAddEventRecurrenceUp, Down: migrateAddEventRecurrenceDown},
+	{Version: 4, Name: "add_event_ical_uid", Up: migrateAddEventICalUIDUp, Down: migrateAddEventICalUIDDown},
+	{Version: 5, Name: "create_timetable", Up: migrateCreateTimetableUp, Down: migrateCreateTimetableDown},
+	{Version: 6, Name: "create_non_school_days", Up: migrateCreateNonSchoolDaysUp, Down: migrateCreateNonSchoolDaysDown},
+	{Version: 7, Name: "create_scheduler_locks", Up: migrateCreateSchedulerLocksUp, Down: migrateCreateSchedulerLocksDown},
+	{Version: 8, Name: "create_routine_runs", Up: migrateCreateRoutineRunsUp, Down: migrateCreateRoutineRunsDown},
+	{Version: 9, Name: "add_routine_bundles", Up: migrateAddRoutineBundlesUp, Down: migrateAddRoutineBundlesDown},
+	{Version: 10, Name: "add_task_parent", Up: migrateAddTaskParentUp, Down: migrateAddTaskParentDown},
+	{Version: 11, Name: "create_task_dependencies", Up: migrateCreateTaskDependenciesUp, Down: migrateCreateTaskDependenciesDown},
+	{Version: 12, Name: "create_task_time_entries", Up: migrateCreateTaskTimeEntriesUp, Down: migrateCreateTaskTimeEntriesDown},
+	{Version: 13, Name: "create_projects", Up: migrateCreateProjectsUp, Down: migrateCreateProjectsDown},
+	{Version: 14, Name: "create_task_links", Up: migrateCreateTaskLinksUp, Down: migrateCreateTaskLinksDown},
+	{Version: 15, Name: "create_settings", Up: migrateCreateSettingsUp, Down: migrateCreateSettingsDown},
+	{Version: 16, Name: "create_journal", Up: migrateCreateJournalUp, Down: migrateCreateJournalDown},
+}
+
+// Migrations returns a copy of the registered migrations in version order.
+func Migrations() []Migration {
+	out := make([]Migration, len(migrations))
+	copy(out, migrations)
+	return out
+}
+
+// LatestSchemaVersion returns the highest migration version known to this binary.
+func LatestSchemaVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Migrate applies every pending migration to conn.
+// It is safe to call repeatedly; already applied migrations are skipped.
+func Migrate(conn *sql.DB) error {
+	_, err := MigrateUp(conn, 0)
+	return err
+}
+
+// MigrateUp applies up to steps pending migrations, in ascending order.
+// A steps value <= 0 applies all pending migrations.
+// It returns the migrations that were applied.
+func MigrateUp(conn *sql.DB, steps int) ([]Migration, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("database is not initialized")
+	}
+	applied, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := runMigration(conn, m, true); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts up to steps applied migrations, starting from the most recent one.
+// A steps value <= 0 reverts a single migration.
+// It returns the migrations that were reverted.
+func MigrateDown(conn *sql.DB, steps int) ([]Migration, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("database is not initialized")
+	}
+	if steps <= 0 {
+		steps = 1
+	}
+	applied, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) cannot be reverted", m.Version, m.Name)
+		}
+		if err := runMigration(conn, m, false); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrationStatus lists every registered migration together with its applied state in conn.
+func MigrationStatus(conn *sql.DB) ([]MigrationState, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("database is not initialized")
+	}
+	applied, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt, ok := applied[m.Version]
+		states = append(states, MigrationState{
+			Version:   m.Version,
+			Name:      m.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return states, nil
+}
+
+// SchemaVersion returns the highest migration version applied to conn, or 0 for an empty database.
+func SchemaVersion(conn *sql.DB) (int, error) {
+	applied, err := appliedVersions(conn)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// ensureMigrationsTable creates the bookkeeping table used to track applied migrations.
+func ensureMigrationsTable(conn *sql.DB) error {
+	_, err := conn.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	);`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions returns the applied migration versions mapped to their application time.
+func appliedVersions(conn *sql.DB) (map[int]time.Time, error) {
+	if err := ensureMigrationsTable(conn); err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema_migrations rows: %w", err)
+	}
+	return applied, nil
+}
+
+// runMigration executes one direction of m and updates schema_migrations in the same transaction,
+// so a failing migration leaves both the schema and the bookkeeping untouched.
+// Data changed by a migration is not journaled, and the journal triggers of the connection are
+// brought up to date with the new schema before committing.
+func runMigration(conn *sql.DB, m Migration, up bool) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if err := setJournalPaused(tx, true); err != nil {
+		return err
+	}
+	// The triggers of this connection are recreated below; dropping them lets the migration
+	// alter the columns they list.
+	if err := dropJournalTriggers(tx); err != nil {
+		return err
+	}
+
+	if up {
+		if err := m.Up(tx); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now()); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+		}
+	} else {
+		if err := m.Down(tx); err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+			return fmt.Errorf("failed to unrecord migration %d: %w", m.Version, err)
+		}
+	}
+
+	if err := syncJournalTriggers(tx); err != nil {
+		return fmt.Errorf("failed to update journal triggers for migration %d: %w", m.Version, err)
+	}
+	if err := setJournalPaused(tx, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+	return nil
+}
+
+// execAll runs each statement in order, stopping at the first failure.
+func execAll(tx *sql.Tx, statements ...string) error {
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnExists reports whether table has a column with the given name.
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table_info for %s: %w", table, err)
+		}
+		if strings.EqualFold(name, column) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// addColumnIfMissing adds column to table unless it already exists.
+// definition is the column type and constraints, e.g. "TEXT" or "TIMESTAMP".
+func addColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	exists, err := columnExists(tx, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// migrateNoop is used as the Down step of migrations whose changes are kept when reverting,
+// typically because they only add columns that earlier versions already define.
+func migrateNoop(tx *sql.Tx) error {
+	return nil
+}
+
+// --- Version 1: base tables ---
+
+// migrateCreateBaseTablesUp creates the tables that InitDB historically created on startup.
+// IF NOT EXISTS keeps it compatible with databases created before migrations existed.
+func migrateCreateBaseTablesUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS questions (
+			id TEXT PRIMARY KEY,
+			subject TEXT NOT NULL,
+			topic TEXT NOT NULL,
+			difficulty TEXT NOT NULL,
+			question_text TEXT NOT NULL,
+			answer_options TEXT,
+			correct_answers TEXT NOT NULL,
+			question_type TEXT NOT NULL,
+			source TEXT,
+			tags TEXT,
+			created_at TIMESTAMP NOT NULL,
+			last_used_at TIMESTAMP,
+			author TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS tasks (
+			id TEXT PRIMARY KEY,
+			description TEXT NOT NULL,
+			due_date TIMESTAMP,
+			priority INTEGER,
+			status TEXT,
+			tags TEXT, -- Store as JSON array
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS events (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			description TEXT,
+			start_time TIMESTAMP NOT NULL,
+			end_time TIMESTAMP NOT NULL,
+			location TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS routines (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			frequency TEXT,
+			task_description TEXT,
+			task_priority INTEGER,
+			task_tags TEXT, -- Store as JSON array
+			next_run_time TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS terms (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			academic_year TEXT,
+			start_date TIMESTAMP,
+			end_date TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS students (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			class_id TEXT,
+			email TEXT,
+			date_of_birth TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS lessons (
+			id TEXT PRIMARY KEY,
+			subject TEXT,
+			topic TEXT,
+			date TIMESTAMP,
+			class_id TEXT,
+			plan TEXT,
+			observations TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS grades (
+			id TEXT PRIMARY KEY,
+			student_id TEXT,
+			term_id TEXT,
+			subject TEXT,
+			description TEXT,
+			value REAL,
+			weight REAL,
+			date TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP,
+			FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+			FOREIGN KEY (term_id) REFERENCES terms(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS classes (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			level TEXT,
+			academic_year TEXT,
+			term_ids TEXT, -- Store as JSON array
+			subject_ids TEXT, -- Store as JSON array
+			student_ids TEXT, -- Store as JSON array
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS subjects (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			teacher_ids TEXT, -- Store as JSON array
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+	)
+}
+
+func migrateCreateBaseTablesDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP TABLE IF EXISTS subjects",
+		"DROP TABLE IF EXISTS classes",
+		"DROP TABLE IF EXISTS grades",
+		"DROP TABLE IF EXISTS lessons",
+		"DROP TABLE IF EXISTS students",
+		"DROP TABLE IF EXISTS terms",
+		"DROP TABLE IF EXISTS routines",
+		"DROP TABLE IF EXISTS events",
+		"DROP TABLE IF EXISTS tasks",
+		"DROP TABLE IF EXISTS questions",
+	)
+}
+
+// --- Version 2: reconcile tables created by the store Init() methods ---
+
+// migrateReconcileStoreTablesUp upgrades tables that were created by the old
+// SQLiteTermStore, SQLiteAulaStore, SQLiteGradeStore and SQLiteStudentStore Init()
+// methods, which used narrower shapes than version 1. Missing columns are added
+// and backfilled so that every database ends up with the same layout.
+func migrateReconcileStoreTablesUp(tx *sql.Tx) error {
+	columns := []struct {
+		table, column, definition string
+	}{
+		{"terms", "academic_year", "TEXT"},
+		{"terms", "created_at", "TIMESTAMP"},
+		{"terms", "updated_at", "TIMESTAMP"},
+		{"students", "class_id", "TEXT"},
+		{"students", "email", "TEXT"},
+		{"students", "date_of_birth", "TIMESTAMP"},
+		{"students", "created_at", "TIMESTAMP"},
+		{"students", "updated_at", "TIMESTAMP"},
+		{"lessons", "created_at", "TIMESTAMP"},
+		{"lessons", "updated_at", "TIMESTAMP"},
+		{"grades", "created_at", "TIMESTAMP"},
+		{"grades", "updated_at", "TIMESTAMP"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(tx, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// The old term store kept the academic year in an INTEGER "year" column.
+	hasYear, err := columnExists(tx, "terms", "year")
+	if err != nil {
+		return err
+	}
+	if hasYear {
+		if _, err := tx.Exec("UPDATE terms SET academic_year = CAST(year AS TEXT) WHERE academic_year IS NULL AND year IS NOT NULL"); err != nil {
+			return fmt.Errorf("failed to backfill terms.academic_year: %w", err)
+		}
+	}
+
+	for _, table := range []string{"terms", "students", "lessons", "grades"} {
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL", table)); err != nil {
+			return fmt.Errorf("failed to backfill %s.created_at: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// --- Version 3: recurring events ---
+
+// migrateAddEventRecurrenceUp adds the RFC 5545 recurrence columns to events.
+// exception_dates holds a JSON array of occurrence start times (EXDATE);
+// series_id and original_start_time identify an occurrence detached from its series.
+func migrateAddEventRecurrenceUp(tx *sql.Tx) error {
+	for _, column := range []string{"recurrence_rule", "exception_dates", "series_id"} {
+		if err := addColumnIfMissing(tx, "events", column, "TEXT"); err != nil {
+			return err
+		}
+	}
+	if err := addColumnIfMissing(tx, "events", "original_start_time", "TIMESTAMP"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_events_series_id ON events (series_id)"); err != nil {
+		return fmt.Errorf("failed to create idx_events_series_id: %w", err)
+	}
+	return nil
+}
+
+func migrateAddEventRecurrenceDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP INDEX IF EXISTS idx_events_series_id",
+		"ALTER TABLE events DROP COLUMN original_start_time",
+		"ALTER TABLE events DROP COLUMN series_id",
+		"ALTER TABLE events DROP COLUMN exception_dates",
+		"ALTER TABLE events DROP COLUMN recurrence_rule",
+	)
+}
+
+// --- Version 4: iCalendar UIDs ---
+
+// migrateAddEventICalUIDUp stores the UID of events imported from .ics files,
+// so re-importing a calendar updates the same rows instead of duplicating them.
+func migrateAddEventICalUIDUp(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "events", "ical_uid", "TEXT"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_events_ical_uid ON events (ical_uid) WHERE ical_uid IS NOT NULL AND ical_uid != ''"); err != nil {
+		return fmt.Errorf("failed to create idx_events_ical_uid: %w", err)
+	}
+	return nil
+}
+
+func migrateAddEventICalUIDDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP INDEX IF EXISTS idx_events_ical_uid",
+		"ALTER TABLE events DROP COLUMN ical_uid",
+	)
+}
+
+// --- Version 5: timetable ---
+
+// migrateCreateTimetableUp creates the weekly timetable slots of a term and the table that links
+// each generated occurrence (one per slot and school day) to its lesson and agenda event.
+func migrateCreateTimetableUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS timetable_slots (
+			id TEXT PRIMARY KEY,
+			term_id TEXT NOT NULL,
+			class_id TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			weekday INTEGER NOT NULL,
+			start_time TEXT NOT NULL,
+			end_time TEXT NOT NULL,
+			location TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_timetable_slots_term_id ON timetable_slots (term_id)",
+		`CREATE TABLE IF NOT EXISTS timetable_occurrences (
+			slot_id TEXT NOT NULL,
+			date TEXT NOT NULL,
+			start_time TIMESTAMP NOT NULL,
+			lesson_id TEXT,
+			event_id TEXT,
+			PRIMARY KEY (slot_id, date)
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_timetable_occurrences_start_time ON timetable_occurrences (start_time)",
+	)
+}
+
+func migrateCreateTimetableDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP TABLE IF EXISTS timetable_occurrences",
+		"DROP TABLE IF EXISTS timetable_slots",
+	)
+}
+
+// --- Version 6: school calendar ---
+
+// migrateCreateNonSchoolDaysUp creates the non-school days (holidays, recess, planning days) of the school calendar.
+// Dates are stored as "YYYY-MM-DD" text so range checks do not depend on the time zone; end_date is inclusive.
+func migrateCreateNonSchoolDaysUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS non_school_days (
+			id TEXT PRIMARY KEY,
+			academic_year TEXT NOT NULL,
+			name TEXT NOT NULL,
+			kind TEXT,
+			start_date TEXT NOT NULL,
+			end_date TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_non_school_days_dates ON non_school_days (start_date, end_date)",
+	)
+}
+
+func migrateCreateNonSchoolDaysDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS non_school_days")
+}
+
+// --- Version 7: scheduler locks ---
+
+// migrateCreateSchedulerLocksUp creates the named locks that keep two scheduler runs from overlapping.
+// Times are Unix milliseconds so expiry checks are plain integer comparisons, independent of the time zone.
+func migrateCreateSchedulerLocksUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS scheduler_locks (
+			name TEXT PRIMARY KEY,
+			owner TEXT NOT NULL,
+			acquired_at INTEGER NOT NULL,
+			expires_at INTEGER NOT NULL
+		);`,
+	)
+}
+
+func migrateCreateSchedulerLocksDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS scheduler_locks")
+}
+
+// --- Version 8: routine runs ---
+
+// migrateCreateRoutineRunsUp creates the history of routine runs. occurrence_date is the logical
+// occurrence as "YYYY-MM-DD" text, so lookups for a date do not depend on the time zone;
+// task_ids is a JSON array with the IDs of the generated tasks.
+func migrateCreateRoutineRunsUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS routine_runs (
+			id TEXT PRIMARY KEY,
+			routine_id TEXT NOT NULL,
+			occurrence_date TEXT NOT NULL,
+			task_ids TEXT,
+			forced INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_routine_runs_routine_date ON routine_runs (routine_id, occurrence_date)",
+	)
+}
+
+func migrateCreateRoutineRunsDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS routine_runs")
+}
+
+// --- Version 9: routine bundles ---
+
+// migrateAddRoutineBundlesUp lets a routine generate extra tasks and events along with its main task.
+// task_templates and event_templates hold JSON arrays of templates; routine_runs.event_ids is a JSON
+// array with the IDs of the events a run created.
+func migrateAddRoutineBundlesUp(tx *sql.Tx) error {
+	for _, c := range []struct{ table, column string }{
+		{"routines", "task_templates"},
+		{"routines", "event_templates"},
+		{"routine_runs", "event_ids"},
+	} {
+		if err := addColumnIfMissing(tx, c.table, c.column, "TEXT"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateAddRoutineBundlesDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"ALTER TABLE routine_runs DROP COLUMN event_ids",
+		"ALTER TABLE routines DROP COLUMN event_templates",
+		"ALTER TABLE routines DROP COLUMN task_templates",
+	)
+}
+
+// --- Version 10: subtasks ---
+
+// migrateAddTaskParentUp adds parent_id to tasks, turning them into a tree: a task with a parent_id
+// is a subtask (checklist item) of that task.
+func migrateAddTaskParentUp(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "tasks", "parent_id", "TEXT"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id)"); err != nil {
+		return fmt.Errorf("failed to create idx_tasks_parent_id: %w", err)
+	}
+	return nil
+}
+
+func migrateAddTaskParentDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP INDEX IF EXISTS idx_tasks_parent_id",
+		"ALTER TABLE tasks DROP COLUMN parent_id",
+	)
+}
+
+// --- Version 11: task dependencies ---
+
+// migrateCreateTaskDependenciesUp creates the dependency links between tasks: task_id can only be done
+// after depends_on_id is completed. The index on depends_on_id finds the tasks waiting on a given task.
+func migrateCreateTaskDependenciesUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id TEXT NOT NULL,
+			depends_on_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (task_id, depends_on_id)
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on_id ON task_dependencies (depends_on_id)",
+	)
+}
+
+func migrateCreateTaskDependenciesDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS task_dependencies")
+}
+
+// --- Version 12: task time entries ---
+
+// migrateCreateTaskTimeEntriesUp creates the work intervals logged on tasks. An open interval (NULL end_time)
+// is the running timer; the index on end_time finds it without scanning every entry.
+func migrateCreateTaskTimeEntriesUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS task_time_entries (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			start_time TIMESTAMP NOT NULL,
+			end_time TIMESTAMP,
+			manual BOOLEAN NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_task_time_entries_task_id ON task_time_entries (task_id)",
+		"CREATE INDEX IF NOT EXISTS idx_task_time_entries_end_time ON task_time_entries (end_time)",
+	)
+}
+
+func migrateCreateTaskTimeEntriesDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS task_time_entries")
+}
+
+// --- Version 13: projects ---
+
+// migrateCreateProjectsUp creates the projects (contexts such as "Feira de Ciências" or "Escola A") and adds
+// project_id to tasks, events and routines, each of which may belong to at most one project.
+// Project names are unique regardless of case, since the CLI accepts a name wherever it accepts a project ID.
+func migrateCreateProjectsUp(tx *sql.Tx) error {
+	if err := execAll(tx,
+		`CREATE TABLE IF NOT EXISTS projects (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			due_date TIMESTAMP,
+			status TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		);`,
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_name ON projects (name COLLATE NOCASE)",
+	); err != nil {
+		return err
+	}
+	for _, table := range []string{"tasks", "events", "routines"} {
+		if err := addColumnIfMissing(tx, table, "project_id", "TEXT"); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_project_id ON %s (project_id)", table, table)); err != nil {
+			return fmt.Errorf("failed to create idx_%s_project_id: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func migrateCreateProjectsDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP INDEX IF EXISTS idx_routines_project_id",
+		"ALTER TABLE routines DROP COLUMN project_id",
+		"DROP INDEX IF EXISTS idx_events_project_id",
+		"ALTER TABLE events DROP COLUMN project_id",
+		"DROP INDEX IF EXISTS idx_tasks_project_id",
+		"ALTER TABLE tasks DROP COLUMN project_id",
+		"DROP TABLE IF EXISTS projects",
+	)
+}
+
+// --- Version 14: task links ---
+
+// migrateCreateTaskLinksUp creates the typed references from tasks to other entities (lessons, tests, classes,
+// students and events). The index on (entity_type, entity_id) finds the tasks linked to a given entity.
+func migrateCreateTaskLinksUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS task_links (
+			task_id TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (task_id, entity_type, entity_id)
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_task_links_entity ON task_links (entity_type, entity_id)",
+	)
+}
+
+func migrateCreateTaskLinksDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS task_links")
+}
+
+// --- Version 15: settings ---
+
+// migrateCreateSettingsUp creates a key-value table for user preferences, such as the task urgency weights.
+func migrateCreateSettingsUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		);`,
+	)
+}
+
+func migrateCreateSettingsDown(tx *sql.Tx) error {
+	return execAll(tx, "DROP TABLE IF EXISTS settings")
+}
+
+// --- Version 16: operation journal ---
+
+// migrateCreateJournalUp creates the operation journal tables. The triggers on the journaled tables
+// are TEMP triggers of each connection, created by syncJournalTriggers, which OpenDB and runMigration call.
+func migrateCreateJournalUp(tx *sql.Tx) error {
+	return execAll(tx,
+		`CREATE TABLE IF NOT EXISTS journal_operations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			command TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			undone_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS journal_changes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			operation_id INTEGER,
+			session TEXT,
+			table_name TEXT NOT NULL,
+			row_key TEXT NOT NULL,
+			before_row TEXT,
+			after_row TEXT
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_journal_changes_operation_id ON journal_changes (operation_id)",
+		"CREATE INDEX IF NOT EXISTS idx_journal_changes_session ON journal_changes (session)",
+		// A row in journal_pause, inserted and deleted inside a transaction, stops the triggers.
+		"CREATE TABLE IF NOT EXISTS journal_pause (paused INTEGER NOT NULL);",
+	)
+}
+
+func migrateCreateJournalDown(tx *sql.Tx) error {
+	return execAll(tx,
+		"DROP TABLE IF EXISTS journal_pause",
+		"DROP TABLE IF EXISTS journal_changes",
+		"DROP TABLE IF EXISTS journal_operations",
+	)
+}
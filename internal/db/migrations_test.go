@@ -0,0 +1,142 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTempDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatalf("failed to open temp database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func tableExists(t *testing.T, conn *sql.DB, table string) bool {
+	t.Helper()
+	var name string
+	err := conn.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name)
+	if err == sql.ErrNoRows {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("failed to check table %s: %v", table, err)
+	}
+	return true
+}
+
+func TestMigrateUpDownAndStatus(t *testing.T) {
+	conn := openTempDB(t)
+
+	if err := Migrate(conn); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	version, err := SchemaVersion(conn)
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("expected schema version %d, got %d", LatestSchemaVersion(), version)
+	}
+	for _, table := range []string{"questions", "tasks", "events", "routines", "terms", "students", "lessons", "grades", "classes", "subjects"} {
+		if !tableExists(t, conn, table) {
+			t.Errorf("expected table %s to exist after migrating", table)
+		}
+	}
+
+	// Running again must be a no-op.
+	applied, err := MigrateUp(conn, 0)
+	if err != nil {
+		t.Fatalf("second MigrateUp failed: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no migrations on second run, got %d", len(applied))
+	}
+
+	reverted, err := MigrateDown(conn, len(migrations))
+	if err != nil {
+		t.Fatalf("MigrateDown failed: %v", err)
+	}
+	if len(reverted) != len(migrations) {
+		t.Errorf("expected %d reverted migrations, got %d", len(migrations), len(reverted))
+	}
+	if tableExists(t, conn, "tasks") {
+		t.Errorf("expected tasks table to be dropped after reverting all migrations")
+	}
+
+	states, err := MigrationStatus(conn)
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	for _, s := range states {
+		if s.Applied {
+			t.Errorf("migration %d should not be applied after reverting", s.Version)
+		}
+	}
+
+	applied, err = MigrateUp(conn, 1)
+	if err != nil {
+		t.Fatalf("MigrateUp(1) failed: %v", err)
+	}
+	if len(applied) != 1 || applied[0].Version != 1 {
+		t.Fatalf("expected only migration 1 to be applied, got %+v", applied)
+	}
+	states, err = MigrationStatus(conn)
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	if !states[0].Applied || states[0].AppliedAt.IsZero() {
+		t.Errorf("expected migration 1 to be applied with a timestamp, got %+v", states[0])
+	}
+	if len(states) > 1 && states[1].Applied {
+		t.Errorf("expected migration 2 to be pending, got %+v", states[1])
+	}
+}
+
+func TestMigrateReconcilesLegacyStoreTables(t *testing.T) {
+	conn := openTempDB(t)
+
+	// Shapes created by the store Init() methods before migrations existed.
+	legacy := []string{
+		"CREATE TABLE terms (id TEXT PRIMARY KEY, name TEXT, start_date DATETIME, end_date DATETIME, year INTEGER)",
+		"CREATE TABLE students (id TEXT PRIMARY KEY, name TEXT)",
+		"CREATE TABLE lessons (id TEXT PRIMARY KEY, subject TEXT, topic TEXT, date DATETIME, class_id TEXT, plan TEXT, observations TEXT)",
+		"INSERT INTO terms (id, name, year) VALUES ('t1', '1º Bimestre', 2024)",
+		"INSERT INTO students (id, name) VALUES ('s1', 'Ana')",
+	}
+	for _, stmt := range legacy {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("failed to create legacy schema (%s): %v", stmt, err)
+		}
+	}
+
+	if err := Migrate(conn); err != nil {
+		t.Fatalf("Migrate failed on legacy database: %v", err)
+	}
+
+	var academicYear string
+	var createdAt sql.NullTime
+	if err := conn.QueryRow("SELECT academic_year, created_at FROM terms WHERE id = 't1'").Scan(&academicYear, &createdAt); err != nil {
+		t.Fatalf("failed to read migrated term: %v", err)
+	}
+	if academicYear != "2024" {
+		t.Errorf("expected academic_year backfilled to '2024', got %q", academicYear)
+	}
+	if !createdAt.Valid {
+		t.Errorf("expected created_at to be backfilled for legacy term")
+	}
+
+	var email sql.NullString
+	if err := conn.QueryRow("SELECT email FROM students WHERE id = 's1'").Scan(&email); err != nil {
+		t.Fatalf("expected students.email column after migration: %v", err)
+	}
+	if _, err := conn.Exec("UPDATE lessons SET updated_at = CURRENT_TIMESTAMP"); err != nil {
+		t.Errorf("expected lessons.updated_at column after migration: %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package db
+
+import "log"
+
+// InitTestDB initializes a shared in-memory database for the tests of one package and stops the
+// test binary if it cannot be opened. Call it from TestMain with the package name, so that each
+// test package gets its own database ("file:<name>_test?mode=memory&cache=shared").
+func InitTestDB(name string) {
+	if err := InitDB("file:" + name + "_test?mode=memory&cache=shared"); err != nil {
+		log.Fatalf("failed to initialize the in-memory test database: %v", err)
+	}
+}
@@ -81,4 +81,47 @@ type Subject struct {
 	UpdatedAt   time.Time `json:"updated_at"`                 // Timestamp da última atualização da disciplina
 }
 
+// TimetableSlot representa um horário fixo da grade horária semanal de um período (ex: "seg 08:00-08:50 7B Matemática").
+type TimetableSlot struct {
+	ID        string       `json:"id"`                 // Identificador único do horário
+	TermID    string       `json:"term_id"`            // ID do período (Term) em que o horário vale
+	ClassID   string       `json:"class_id"`           // Turma (ex: "7B")
+	Subject   string       `json:"subject"`            // Disciplina (ex: "Matemática")
+	Weekday   time.Weekday `json:"weekday"`            // Dia da semana do horário
+	StartTime string       `json:"start_time"`         // Horário de início no formato "HH:MM"
+	EndTime   string       `json:"end_time"`           // Horário de término no formato "HH:MM"
+	Location  string       `json:"location,omitempty"` // Sala ou local (opcional)
+	CreatedAt time.Time    `json:"created_at"`         // Timestamp da criação do horário
+	UpdatedAt time.Time    `json:"updated_at"`         // Timestamp da última atualização do horário
+}
+
+// TimetableOccurrence liga a ocorrência de um horário da grade em um dia letivo à aula (Lesson)
+// e ao evento de agenda gerados para ela.
+type TimetableOccurrence struct {
+	SlotID    string    `json:"slot_id"`    // ID do horário (TimetableSlot)
+	Date      string    `json:"date"`       // Dia da ocorrência no formato "YYYY-MM-DD"
+	StartTime time.Time `json:"start_time"` // Início da aula gerada
+	LessonID  string    `json:"lesson_id"`  // ID da aula gerada
+	EventID   string    `json:"event_id"`   // ID do evento de agenda gerado
+}
+
+// Tipos de NonSchoolDay.
+const (
+	NonSchoolDayHoliday  = "feriado"      // NonSchoolDayHoliday indica um feriado.
+	NonSchoolDayRecess   = "recesso"      // NonSchoolDayRecess indica recesso ou férias escolares.
+	NonSchoolDayPlanning = "planejamento" // NonSchoolDayPlanning indica um dia de planejamento ou formação, sem aulas.
+)
+
+// NonSchoolDay representa um dia ou período sem aulas do calendário escolar de um ano letivo.
+type NonSchoolDay struct {
+	ID           string    `json:"id"`            // Identificador único
+	AcademicYear string    `json:"academic_year"` // Ano letivo (ex: "2025")
+	Name         string    `json:"name"`          // Nome (ex: "Carnaval", "Recesso de julho")
+	Kind         string    `json:"kind"`          // Tipo: NonSchoolDayHoliday, NonSchoolDayRecess ou NonSchoolDayPlanning
+	StartDate    time.Time `json:"start_date"`    // Primeiro dia sem aulas
+	EndDate      time.Time `json:"end_date"`      // Último dia sem aulas (inclusivo; igual a StartDate para um único dia)
+	CreatedAt    time.Time `json:"created_at"`    // Timestamp da criação do registro
+	UpdatedAt    time.Time `json:"updated_at"`    // Timestamp da última atualização do registro
+}
+
 // Outras structs relacionadas à gestão acadêmica podem ser adicionadas aqui.
@@ -13,6 +13,9 @@ type Task struct {
 	Tags        []string  `json:"tags,omitempty"`           // Etiquetas ou categorias associadas à tarefa para facilitar a filtragem e organização.
 	CreatedAt   time.Time `json:"created_at"`               // Timestamp da criação da tarefa.
 	UpdatedAt   time.Time `json:"updated_at"`               // Timestamp da última atualização da tarefa.
+
+	ParentID  string `json:"parent_id,omitempty"`  // ID da tarefa pai, para subtarefas (itens de checklist); vazio para tarefas de primeiro nível.
+	ProjectID string `json:"project_id,omitempty"` // ID do projeto (Project) ao qual a tarefa pertence; vazio se nenhum.
 }
 
 // TaskStatus constants
@@ -20,6 +23,82 @@ const (
 	TaskStatusPending    = "Pendente"     // TaskStatusPending indica que a tarefa está pendente.
 	TaskStatusInProgress = "Em Andamento" // TaskStatusInProgress indica que a tarefa está em andamento.
 	TaskStatusCompleted  = "Concluída"    // TaskStatusCompleted indica que a tarefa foi concluída.
+
+	// TaskStatusBlocked é um status derivado, nunca gravado: uma tarefa não concluída com pré-requisitos
+	// (TaskDependency) ainda não concluídos é exibida como "Bloqueada".
+	TaskStatusBlocked = "Bloqueada"
+)
+
+// TaskDependency indica que a tarefa TaskID só pode ser feita depois que DependsOnID for concluída.
+type TaskDependency struct {
+	TaskID      string    `json:"task_id"`       // ID da tarefa que depende de outra.
+	DependsOnID string    `json:"depends_on_id"` // ID da tarefa pré-requisito.
+	CreatedAt   time.Time `json:"created_at"`    // Timestamp da criação da dependência.
+}
+
+// Tipos de entidade a que uma tarefa pode ser vinculada (TaskLink.EntityType).
+const (
+	TaskLinkLesson  = "aula"   // TaskLinkLesson vincula a tarefa a uma aula (Lesson).
+	TaskLinkTest    = "prova"  // TaskLinkTest vincula a tarefa a uma prova (Test).
+	TaskLinkClass   = "turma"  // TaskLinkClass vincula a tarefa a uma turma (Class).
+	TaskLinkStudent = "aluno"  // TaskLinkStudent vincula a tarefa a um aluno (Student).
+	TaskLinkEvent   = "evento" // TaskLinkEvent vincula a tarefa a um evento da agenda (Event).
+)
+
+// TaskLink vincula a tarefa TaskID a uma entidade do sistema, como em "corrigir a prova prova123"
+// ou "ligar para os pais do aluno X". Uma tarefa pode ter vários vínculos.
+type TaskLink struct {
+	TaskID     string    `json:"task_id"`     // ID da tarefa.
+	EntityType string    `json:"entity_type"` // Tipo da entidade: TaskLinkLesson, TaskLinkTest, TaskLinkClass, TaskLinkStudent ou TaskLinkEvent.
+	EntityID   string    `json:"entity_id"`   // ID da entidade.
+	CreatedAt  time.Time `json:"created_at"`  // Timestamp da criação do vínculo.
+}
+
+// TaskTimeEntry é um intervalo de trabalho em uma tarefa, medido pelo cronômetro ("tarefa iniciar"/"tarefa pausar")
+// ou registrado manualmente ("tarefa registrar"). Só pode haver um intervalo em aberto (cronômetro rodando) por vez.
+type TaskTimeEntry struct {
+	ID        string    `json:"id"`                 // Identificador único do intervalo.
+	TaskID    string    `json:"task_id"`            // ID da tarefa em que o tempo foi gasto.
+	StartTime time.Time `json:"start_time"`         // Início do intervalo.
+	EndTime   time.Time `json:"end_time,omitempty"` // Fim do intervalo; zero enquanto o cronômetro está rodando.
+	Manual    bool      `json:"manual"`             // Indica se o intervalo foi registrado manualmente, e não pelo cronômetro.
+	CreatedAt time.Time `json:"created_at"`         // Timestamp do registro.
+}
+
+// Running informa se o intervalo está em aberto, ou seja, se o cronômetro está rodando.
+func (e TaskTimeEntry) Running() bool {
+	return e.EndTime.IsZero()
+}
+
+// Duration devolve a duração do intervalo; para um intervalo em aberto, o tempo decorrido até now.
+func (e TaskTimeEntry) Duration(now time.Time) time.Duration {
+	end := e.EndTime
+	if e.Running() {
+		end = now
+	}
+	if end.Before(e.StartTime) {
+		return 0
+	}
+	return end.Sub(e.StartTime)
+}
+
+// Project agrupa tarefas, eventos e rotinas de um mesmo projeto ou contexto (ex: "Feira de Ciências", "Escola A").
+// O progresso de um projeto é derivado das suas tarefas.
+type Project struct {
+	ID          string    `json:"id"`                    // Identificador único do projeto.
+	Name        string    `json:"name"`                  // Nome do projeto, único (sem diferenciar maiúsculas); aceito no lugar do ID na CLI.
+	Description string    `json:"description,omitempty"` // Descrição do projeto (opcional).
+	DueDate     time.Time `json:"due_date,omitempty"`    // Prazo do projeto. Pode ser zero se não houver prazo.
+	Status      string    `json:"status"`                // Status do projeto (ex: "Ativo", "Concluído", "Arquivado").
+	CreatedAt   time.Time `json:"created_at"`            // Timestamp da criação do projeto.
+	UpdatedAt   time.Time `json:"updated_at"`            // Timestamp da última atualização do projeto.
+}
+
+// ProjectStatus constants
+const (
+	ProjectStatusActive    = "Ativo"     // ProjectStatusActive indica um projeto em andamento.
+	ProjectStatusCompleted = "Concluído" // ProjectStatusCompleted indica um projeto concluído.
+	ProjectStatusArchived  = "Arquivado" // ProjectStatusArchived indica um projeto arquivado, fora das listagens padrão.
 )
 
 // Event representa um evento ou compromisso na agenda.
@@ -33,6 +112,23 @@ type Event struct {
 	Location    string    `json:"location,omitempty"`         // Local onde o evento ocorrerá (opcional).
 	CreatedAt   time.Time `json:"created_at"`                 // Timestamp da criação do evento.
 	UpdatedAt   time.Time `json:"updated_at"`                 // Timestamp da última atualização do evento.
+
+	// Recorrência (RFC 5545). Um evento com RecurrenceRule é o mestre de uma série;
+	// StartTime/EndTime descrevem a primeira ocorrência (DTSTART) e as demais são calculadas sob demanda.
+	RecurrenceRule    string      `json:"recurrence_rule,omitempty"`     // Regra RRULE (ex: "FREQ=WEEKLY;BYDAY=MO,WE"). Vazia para eventos únicos.
+	ExceptionDates    []time.Time `json:"exception_dates,omitempty"`     // Inícios de ocorrências excluídas da série (EXDATE).
+	SeriesID          string      `json:"series_id,omitempty"`           // Para uma ocorrência editada isoladamente: ID do evento mestre da série.
+	OriginalStartTime time.Time   `json:"original_start_time,omitempty"` // Para uma ocorrência editada isoladamente: início original na série (RECURRENCE-ID).
+
+	ICalUID   string `json:"ical_uid,omitempty"`   // UID do VEVENT de origem, para eventos importados de arquivos .ics.
+	ProjectID string `json:"project_id,omitempty"` // ID do projeto (Project) ao qual o evento pertence; vazio se nenhum.
+}
+
+// IntervalsOverlap informa se os intervalos [startA, endA) e [startB, endB) se sobrepõem,
+// ou seja, se startA < endB e startB < endA. Intervalos apenas encostados (um termina quando o outro começa)
+// não se sobrepõem.
+func IntervalsOverlap(startA, endA, startB, endB time.Time) bool {
+	return startA.Before(endB) && startB.Before(endA)
 }
 
 // Routine representa um modelo para a criação de tarefas recorrentes ou em massa.
@@ -48,4 +144,41 @@ type Routine struct {
 	NextRunTime     time.Time `json:"next_run_time,omitempty"`          // Data e hora da próxima execução da rotina.
 	CreatedAt       time.Time `json:"created_at"`                       // Timestamp da criação do modelo de rotina.
 	UpdatedAt       time.Time `json:"updated_at"`                       // Timestamp da última atualização do modelo de rotina.
+
+	// Pacote: tarefas adicionais e eventos gerados junto com a tarefa principal, na mesma execução.
+	TaskTemplates  []RoutineTaskTemplate  `json:"task_templates,omitempty"`  // Tarefas adicionais, na ordem em que são geradas.
+	EventTemplates []RoutineEventTemplate `json:"event_templates,omitempty"` // Eventos gerados com as tarefas.
+
+	ProjectID string `json:"project_id,omitempty"` // ID do projeto (Project) da rotina; as tarefas e eventos gerados pertencem a ele.
+}
+
+// RoutineTaskTemplate é uma tarefa adicional do pacote de um modelo de rotina.
+// A descrição e as tags são templates, como Routine.TaskDescription e Routine.TaskTags.
+type RoutineTaskTemplate struct {
+	Description string   `json:"description"`          // Modelo para a descrição da tarefa.
+	DueOffset   string   `json:"due_offset,omitempty"` // Prazo relativo à data base (ex: "+2d", "+1s", "+3u"); vazio = sem prazo.
+	Priority    int      `json:"priority,omitempty"`   // Prioridade da tarefa; 0 usa a TaskPriority do modelo.
+	Tags        []string `json:"tags,omitempty"`       // Etiquetas da tarefa, somadas às TaskTags do modelo.
+}
+
+// RoutineEventTemplate é um evento do pacote de um modelo de rotina.
+// O título, o local e a descrição são templates, como Routine.TaskDescription.
+type RoutineEventTemplate struct {
+	Title       string `json:"title"`                 // Modelo para o título do evento.
+	StartOffset string `json:"start_offset"`          // Início relativo à data base, com horário (ex: "+7d 14:00").
+	Duration    string `json:"duration"`              // Duração do evento (ex: "2h", "1h30m").
+	Location    string `json:"location,omitempty"`    // Modelo para o local do evento (opcional).
+	Description string `json:"description,omitempty"` // Modelo para a descrição do evento (opcional).
+}
+
+// RoutineRun registra uma execução de um modelo de rotina: a data lógica da ocorrência e as tarefas geradas.
+// Permite saber o que cada rotina produziu e evitar gerar duas vezes as tarefas da mesma ocorrência.
+type RoutineRun struct {
+	ID             string    `json:"id"`                  // Identificador único da execução.
+	RoutineID      string    `json:"routine_id"`          // ID do modelo de rotina executado.
+	OccurrenceDate string    `json:"occurrence_date"`     // Data lógica da ocorrência ("YYYY-MM-DD"), antes do adiamento por dia não letivo.
+	TaskIDs        []string  `json:"task_ids,omitempty"`  // IDs das tarefas geradas.
+	EventIDs       []string  `json:"event_ids,omitempty"` // IDs dos eventos gerados.
+	Forced         bool      `json:"forced"`              // Indica se a execução repetiu uma ocorrência já executada (--forcar).
+	CreatedAt      time.Time `json:"created_at"`          // Timestamp da execução.
 }
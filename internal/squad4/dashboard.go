@@ -1,6 +1,7 @@
 package squad4
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -28,7 +29,6 @@ var DashboardCmd = &cobra.Command{
 func displayDashboard() {
 	userName := "Prof. Exemplo" // Static for now
 	today := time.Now().Format("02/01/2006")
-	focusQuote := "\"Concentre-se em uma tarefa de cada vez.\"" // Static for now
 
 	// --- Fetch Real Events ---
 	var eventStrings []string
@@ -65,6 +65,13 @@ func displayDashboard() {
 		if len(realTasks) == 0 {
 			taskStrings = append(taskStrings, "Nenhuma tarefa pendente. Bom trabalho!")
 		} else {
+			// Progress of parent tasks counts every subtask, including the completed ones.
+			progress := map[string]tarefa.Progresso{}
+			if allTasks, err := tarefa.ListarTarefas("", 0, "", "", "", ""); err != nil {
+				log.Printf("Error fetching subtasks for dashboard: %v", err)
+			} else {
+				progress = tarefa.CalcularProgresso(allTasks)
+			}
 			for _, task := range realTasks {
 				// Format: [ ] Descricao (Prazo: DD/MM/YYYY)
 				dueDateStr := ""
@@ -81,12 +88,43 @@ func displayDashboard() {
 				}
 				// If task.Status is models.TaskStatusPending, it will remain "[ ]"
 
-				taskStrings = append(taskStrings, fmt.Sprintf("%s %s%s",
-					statusMarker, task.Description, dueDateStr))
+				progressStr := ""
+				if p, ok := progress[task.ID]; ok {
+					progressStr = fmt.Sprintf(" (%s)", p)
+				}
+
+				taskStrings = append(taskStrings, fmt.Sprintf("%s %s%s%s",
+					statusMarker, task.Description, progressStr, dueDateStr))
 			}
 		}
 	}
 
+	// --- Focus: the most urgent task that is ready to be worked on ---
+	focusStr := "Nenhuma tarefa pronta para ser feita. Aproveite o dia!"
+	if next, err := tarefa.ProximasTarefas(1); err != nil {
+		log.Printf("Error fetching focus task for dashboard: %v", err)
+		focusStr = "Erro ao carregar a tarefa mais urgente."
+	} else if len(next) == 1 {
+		dueDateStr := ""
+		if !next[0].Tarefa.DueDate.IsZero() {
+			dueDateStr = fmt.Sprintf(" (Prazo: %s)", next[0].Tarefa.DueDate.Format("02/01/2006"))
+		}
+		focusStr = fmt.Sprintf("%s%s - urgência %s", next[0].Tarefa.Description, dueDateStr, next[0].Urgencia)
+	}
+
+	// --- Running Timer ---
+	timerStr := ""
+	if running, err := tarefa.CronometroAtivo(); err == nil {
+		elapsed := running.Registro.Duration(time.Now())
+		timerStr = fmt.Sprintf("▶ %s - %s (desde %s)", running.Tarefa.Description, tarefa.FormatarDuracao(elapsed),
+			running.Registro.StartTime.Local().Format("15:04"))
+		if total, err := tarefa.TempoTarefa(running.Tarefa.ID); err == nil && total-elapsed >= time.Minute { // Earlier intervals on the same task.
+			timerStr += fmt.Sprintf(", total na tarefa: %s", tarefa.FormatarDuracao(total))
+		}
+	} else if !errors.Is(err, tarefa.ErrNenhumCronometro) {
+		log.Printf("Error fetching running timer for dashboard: %v", err)
+	}
+
 	// --- Display Logic ---
 	fmt.Println("==================================================")
 	fmt.Println("                PAINEL PRINCIPAL")
@@ -104,6 +142,13 @@ func displayDashboard() {
 	}
 	fmt.Println()
 
+	if timerStr != "" {
+		fmt.Println("CRONÔMETRO:")
+		fmt.Println("--------------------------------------------------")
+		fmt.Println(timerStr)
+		fmt.Println()
+	}
+
 	fmt.Println("TAREFAS PENDENTES:")
 	fmt.Println("--------------------------------------------------")
 	if len(taskStrings) == 0 {
@@ -117,7 +162,7 @@ func displayDashboard() {
 
 	fmt.Println("FOCO DO DIA:")
 	fmt.Println("--------------------------------------------------")
-	fmt.Println(focusQuote)
+	fmt.Println(focusStr)
 	fmt.Println()
 
 	fmt.Println("--------------------------------------------------")
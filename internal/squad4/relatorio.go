@@ -7,6 +7,7 @@ import (
 	// "strings" // Stays removed
 
 	"vickgenda-cli/internal/commands/agenda"
+	"vickgenda-cli/internal/commands/projeto"
 	"vickgenda-cli/internal/commands/rotina"
 	"vickgenda-cli/internal/commands/tarefa"
 	"vickgenda-cli/internal/models"
@@ -24,10 +25,23 @@ var RelatorioCmd = &cobra.Command{
 var relatorioProdutividadeCmd = &cobra.Command{
 	Use:   "produtividade [periodo]",
 	Short: "Gera um relatório de produtividade.",
-	Long: `Mostra um relatório sobre tarefas concluídas, tempo gasto em eventos, etc.
+	Long: `Mostra um relatório sobre tarefas concluídas, tempo registrado nas tarefas (por tag e por tarefa),
+tempo gasto em eventos, o progresso dos projetos ativos, etc.
 O argumento 'periodo' (ex: "mes_atual", "geral") é opcional e pode influenciar os dados exibidos.
-Atualmente, o período para eventos é fixo como "mês atual".`,
+Atualmente, o período para eventos é fixo como "mês atual".
+Com --projeto, considera apenas as tarefas, eventos e rotinas do projeto informado (nome ou ID).`,
 	Run: func(cmd *cobra.Command, args []string) {
+		projetoRef, _ := cmd.Flags().GetString("projeto")
+		var projetoFiltro models.Project // Vazio (sem ID) quando --projeto não é informado.
+		if projetoRef != "" {
+			p, err := projeto.BuscarProjeto(projetoRef)
+			if err != nil {
+				fmt.Printf("Erro: %v\n", err)
+				return
+			}
+			projetoFiltro = p
+		}
+
 		periodoArg := "geral" // Default period
 		if len(args) > 0 {
 			periodoArg = args[0]
@@ -42,6 +56,9 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 		// Clarify the actual period being reported for different sections
 		fmt.Printf("Período de Análise (Eventos): Mês Atual\n")
 		fmt.Printf("Período de Análise (Tarefas/Rotinas): Geral\n")
+		if projetoFiltro.ID != "" {
+			fmt.Printf("Projeto: %s\n", projetoFiltro.Name)
+		}
 		if periodoArg != "geral" {
 			fmt.Printf("(Argumento de período fornecido: %s - filtragem detalhada por período ainda em desenvolvimento)\n", periodoArg)
 		}
@@ -52,7 +69,7 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 		numConcluidas := 0
 		numPendentes := 0
 
-		allTasks, errAll := tarefa.ListarTarefas("", 0, "", "", "", "")
+		allTasks, errAll := tarefa.ListarTarefasFiltro(tarefa.FiltroTarefas{Projeto: projetoFiltro.ID})
 		if errAll != nil {
 			log.Printf("Erro ao buscar todas as tarefas para relatório: %v", errAll)
 			fmt.Println("Tarefas: Erro ao carregar dados de tarefas criadas.")
@@ -60,7 +77,7 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 			numCriadas = len(allTasks)
 		}
 
-		completedTasks, errCompleted := tarefa.ListarTarefas(models.TaskStatusCompleted, 0, "", "", "", "")
+		completedTasks, errCompleted := tarefa.ListarTarefasFiltro(tarefa.FiltroTarefas{Status: models.TaskStatusCompleted, Projeto: projetoFiltro.ID})
 		if errCompleted != nil {
 			log.Printf("Erro ao buscar tarefas concluídas para relatório: %v", errCompleted)
 			fmt.Println("Tarefas: Erro ao carregar dados de tarefas concluídas.")
@@ -68,7 +85,7 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 			numConcluidas = len(completedTasks)
 		}
 
-		pendingTasks, errPending := tarefa.ListarTarefas(models.TaskStatusPending, 0, "", "", "", "")
+		pendingTasks, errPending := tarefa.ListarTarefasFiltro(tarefa.FiltroTarefas{Status: models.TaskStatusPending, Projeto: projetoFiltro.ID})
 		if errPending != nil {
 			log.Printf("Erro ao buscar tarefas pendentes para relatório: %v", errPending)
 			fmt.Println("Tarefas: Erro ao carregar dados de tarefas pendentes.")
@@ -82,6 +99,35 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 		fmt.Printf("  - Pendentes: %d\n", numPendentes)
 		fmt.Println("--------------------------------------------------")
 
+		// --- Time Tracking Data ---
+		fmt.Println("\nTEMPO REGISTRADO:")
+		resumoTempo, errTempo := tarefa.CarregarResumoTempo(projetoFiltro.ID)
+		if errTempo != nil {
+			log.Printf("Erro ao buscar o tempo registrado para relatório: %v", errTempo)
+			fmt.Println("  - Erro ao carregar o tempo registrado nas tarefas.")
+		} else if resumoTempo.Total == 0 {
+			fmt.Println("  - Nenhum tempo registrado. Use 'tarefa iniciar', 'tarefa pausar' ou 'tarefa registrar'.")
+		} else {
+			fmt.Printf("  - Total: %s\n", tarefa.FormatarDuracao(resumoTempo.Total))
+			if resumoTempo.ConcluidasComTempo > 0 {
+				fmt.Printf("  - Tempo médio por tarefa concluída: %s (%d tarefa(s))\n",
+					tarefa.FormatarDuracao(resumoTempo.MediaConclusao), resumoTempo.ConcluidasComTempo)
+			}
+			fmt.Println("  - Por tag:")
+			for _, t := range resumoTempo.PorTag {
+				fmt.Printf("    * %s: %s\n", t.Nome, tarefa.FormatarDuracao(t.Tempo))
+			}
+			fmt.Println("  - Por tarefa:")
+			for i, t := range resumoTempo.PorTarefa {
+				if i == maxTarefasTempoRelatorio {
+					fmt.Printf("    * ... e mais %d tarefa(s)\n", len(resumoTempo.PorTarefa)-i)
+					break
+				}
+				fmt.Printf("    * %s: %s\n", t.Nome, tarefa.FormatarDuracao(t.Tempo))
+			}
+		}
+		fmt.Println("--------------------------------------------------")
+
 		// --- Fetch Agenda Data ---
 		totalTempoEventos := time.Duration(0)
 		eventosMes, errEvents := agenda.ListarEventos("mes", "", "", "inicio", "asc")
@@ -91,6 +137,9 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 			fmt.Println("  - Tempo total em eventos (mês atual): Erro ao carregar dados.")
 		} else {
 			for _, evento := range eventosMes {
+				if projetoFiltro.ID != "" && evento.ProjectID != projetoFiltro.ID {
+					continue
+				}
 				if !evento.StartTime.IsZero() && !evento.EndTime.IsZero() && evento.EndTime.After(evento.StartTime) {
 					totalTempoEventos += evento.EndTime.Sub(evento.StartTime)
 				}
@@ -101,22 +150,101 @@ Atualmente, o período para eventos é fixo como "mês atual".`,
 		fmt.Println("--------------------------------------------------")
 
 		// --- Fetch Rotina Data ---
-		numModelosRotina := 0
-		modelos, errRotinas := rotina.ListarModelosRotina("", "") // No specific sort needed for count
+		estatisticas, errRotinas := rotina.EstatisticasRotinas()
+		if errRotinas == nil && projetoFiltro.ID != "" {
+			doProjeto := estatisticas[:0]
+			for _, e := range estatisticas {
+				if e.Modelo.ProjectID == projetoFiltro.ID {
+					doProjeto = append(doProjeto, e)
+				}
+			}
+			estatisticas = doProjeto
+		}
+		fmt.Println("\nROTINAS:")
 		if errRotinas != nil {
-			log.Printf("Erro ao buscar modelos de rotina para relatório: %v", errRotinas)
-			fmt.Println("\nROTINAS:")
+			log.Printf("Erro ao buscar estatísticas de rotina para relatório: %v", errRotinas)
 			fmt.Println("  - Modelos de rotina definidos: Erro ao carregar dados.")
 		} else {
-			numModelosRotina = len(modelos)
-			fmt.Println("\nROTINAS:")
-			fmt.Printf("  - Modelos de rotina definidos: %d\n", numModelosRotina)
+			totalExecucoes, totalGeradas, totalConcluidas := 0, 0, 0
+			for _, e := range estatisticas {
+				totalExecucoes += e.Execucoes
+				totalGeradas += e.TarefasGeradas
+				totalConcluidas += e.TarefasConcluidas
+			}
+			fmt.Printf("  - Modelos de rotina definidos: %d\n", len(estatisticas))
+			fmt.Printf("  - Execuções registradas: %d\n", totalExecucoes)
+			fmt.Printf("  - Tarefas geradas: %d (%d concluídas%s)\n", totalGeradas, totalConcluidas, formatarTaxaConclusao(totalConcluidas, totalGeradas))
+			for _, e := range estatisticas {
+				if e.Execucoes == 0 {
+					fmt.Printf("    * %s: nenhuma execução\n", e.Modelo.Name)
+					continue
+				}
+				ultima := e.UltimaOcorrencia
+				if d, err := time.Parse("2006-01-02", e.UltimaOcorrencia); err == nil {
+					ultima = d.Format("02/01/2006")
+				}
+				linha := fmt.Sprintf("    * %s: %d execução(ões), última em %s; %d tarefa(s), %d concluída(s)%s",
+					e.Modelo.Name, e.Execucoes, ultima, e.TarefasGeradas, e.TarefasConcluidas, formatarTaxaConclusao(e.TarefasConcluidas, e.TarefasGeradas))
+				if e.TarefasRemovidas > 0 {
+					linha += fmt.Sprintf("; %d removida(s)", e.TarefasRemovidas)
+				}
+				if e.EventosGerados > 0 {
+					linha += fmt.Sprintf("; %d evento(s)", e.EventosGerados)
+				}
+				fmt.Println(linha)
+			}
+		}
+
+		// --- Project Progress (only in the general report) ---
+		if projetoFiltro.ID == "" {
+			fmt.Println("--------------------------------------------------")
+			fmt.Println("\nPROJETOS ATIVOS:")
+			progressos, errProjetos := projeto.CarregarProgressos(models.ProjectStatusActive)
+			if errProjetos != nil {
+				log.Printf("Erro ao buscar o progresso dos projetos para relatório: %v", errProjetos)
+				fmt.Println("  - Erro ao carregar os projetos.")
+			} else if len(progressos) == 0 {
+				fmt.Println("  - Nenhum projeto ativo. Use 'projeto criar' para agrupar tarefas.")
+			} else {
+				for _, p := range progressos {
+					fmt.Printf("  - %s\n", formatarProgressoProjeto(p, time.Now()))
+				}
+			}
 		}
-		fmt.Println("  (Nota: A frequência de execução e o número de tarefas geradas por rotina não são rastreados atualmente.)")
 		fmt.Println("==================================================")
 	},
 }
 
+// formatarProgressoProjeto resume o progresso de um projeto em uma linha:
+// "Nome: 3/10 (30%); 1 atrasada(s); 2h15m registradas; prazo 30/06/2024 (vencido)".
+func formatarProgressoProjeto(p projeto.Progresso, agora time.Time) string {
+	linha := fmt.Sprintf("%s: %s", p.Projeto.Name, p.String())
+	if p.Atrasadas > 0 {
+		linha += fmt.Sprintf("; %d atrasada(s)", p.Atrasadas)
+	}
+	if p.Tempo > 0 {
+		linha += fmt.Sprintf("; %s registradas", tarefa.FormatarDuracao(p.Tempo))
+	}
+	if !p.Projeto.DueDate.IsZero() {
+		linha += "; prazo " + p.Projeto.DueDate.Format("02/01/2006")
+		if p.PrazoVencido(agora) {
+			linha += " (vencido)"
+		}
+	}
+	return linha
+}
+
+// maxTarefasTempoRelatorio limita as tarefas listadas na seção de tempo registrado do relatório de produtividade.
+const maxTarefasTempoRelatorio = 10
+
+// formatarTaxaConclusao formata a taxa de conclusão como ", NN%", ou vazio se não houver tarefas.
+func formatarTaxaConclusao(concluidas, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %.0f%%", float64(concluidas)*100/float64(total))
+}
+
 var relatorioAcademicoCmd = &cobra.Command{
 	Use:   "academico [turma <nome_turma>|disciplina <nome_disciplina>] [bimestre <num>]",
 	Short: "Gera um relatório de desempenho acadêmico.",
@@ -227,6 +355,7 @@ func init() {
 	// Ensure db package is imported if not already by other commands in this file
 	// _ "vickgenda-cli/internal/db" // if ListQuestions is used and db connection needs to be available
 
+	relatorioProdutividadeCmd.Flags().String("projeto", "", "Considera apenas as tarefas, eventos e rotinas do projeto (nome ou ID)")
 	RelatorioCmd.AddCommand(relatorioProdutividadeCmd)
 	RelatorioCmd.AddCommand(relatorioAcademicoCmd)
 	RelatorioCmd.AddCommand(relatorioUsoConteudoCmd)
@@ -7,6 +7,7 @@ import (
 	"time" // Required for date formatting if not already present
 
 	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/datas"
 	"vickgenda-cli/internal/models"
 
 	"github.com/spf13/cobra"
@@ -19,25 +20,37 @@ var RelembrarCmd = &cobra.Command{
 }
 
 var relembrarAdicionarCmd = &cobra.Command{
-	Use:   "adicionar \"<lembrete>\" <data_YYYY-MM-DD> [hora_HH:MM]",
+	Use:   "adicionar \"<lembrete>\" <data> [hora_HH:MM]",
 	Short: "Adiciona um novo lembrete.",
 	Long: `Adiciona um novo lembrete (tarefa) com uma descrição, data e, opcionalmente, uma hora.
 A descrição do lembrete deve estar entre aspas.
-Formato da data: YYYY-MM-DD (ex: 2024-07-23) ou deixe em branco para nenhum prazo.
-Formato da hora (opcional): HH:MM. Se fornecida, será anexada à descrição.`,
+Data: ` + datas.Exemplos + ` (ex: "amanhã 14h"), ou deixe em branco para nenhum prazo.
+Formato da hora (opcional): HH:MM. Se fornecida, ou se a data incluir um horário, será anexada à descrição.`,
 	Example: `relembrar adicionar "Comprar canetas vermelhas" 2024-07-21 10:00
-relembrar adicionar "Buscar provas na gráfica" 2024-07-23`,
+relembrar adicionar "Buscar provas na gráfica" "amanhã 14h"
+relembrar adicionar "Reunião de pais" "próxima sexta"`,
 	Args: cobra.MinimumNArgs(1), // lembrete é obrigatório, data é opcional mas precisa de "" se hora for usada
 	Run: func(cmd *cobra.Command, args []string) {
 		descricaoLembrete := args[0]
 		data := ""
 		if len(args) > 1 {
-			data = args[1] // Expected "YYYY-MM-DD" or empty
+			data = args[1] // Expressão do pacote datas ou vazio
 		}
 		hora := ""
 		if len(args) > 2 {
 			hora = args[2]
 		}
+		if data != "" {
+			r, err := datas.DataHora(data)
+			if err != nil {
+				fmt.Printf("Erro: %v\n", err)
+				return
+			}
+			if r.TemHorario && hora == "" {
+				hora = r.Momento.Format("15:04")
+			}
+			data = r.Momento.Format("2006-01-02")
+		}
 
 		finalDescription := descricaoLembrete
 		if hora != "" {
@@ -48,28 +61,7 @@ relembrar adicionar "Buscar provas na gráfica" 2024-07-23`,
 		priority := 2
 		tags := []string{"lembrete"}
 
-		// Call tarefa.CriarTarefa
-		// CriarTarefa(description string, dueDateStr string, priority int, tagsStr string) (models.Task, error)
-		// The tags argument should be a comma-separated string.
-		_, err := tarefa.CriarTarefa(finalDescription, data, priority, strings.Join(tags, ","))
-		if err != nil {
-			log.Printf("Erro ao criar lembrete (tarefa): %v", err)
-			fmt.Println("Falha ao adicionar o lembrete. Verifique os logs para mais detalhes.")
-			return
-		}
-		// Assuming CriarTarefa now returns models.Task and error, and ID is part of models.Task
-		// If we need the ID, and CriarTarefa is changed to return models.Task, then:
-		// task, err := tarefa.CriarTarefa(finalDescription, data, priority, strings.Join(tags, ","))
-		// if err != nil { ... }
-		// fmt.Printf("Lembrete (ID: %s) adicionado com sucesso: '%s'\n", task.ID, finalDescription)
-		// For now, let's assume we don't need the ID directly in the success message or the returned type is still (string, error)
-		// but the build error indicates it expects a string for tags.
-		// The original error was `cannot use tags (variable of type []string) as string value in argument to tarefa.CriarTarefa`
-		// This implies the function signature for CriarTarefa expects a string for the tags argument.
-		// The return type of CriarTarefa (string, error) vs (models.Task, error) is a separate issue.
-		// Let's stick to fixing the tags argument type first based on the error.
-		// The previous build error did not complain about the return type `id`, so let's assume it's still (string, error)
-		// Correcting based on actual signature: CriarTarefa returns (models.Task, error)
+		// CriarTarefa persiste a tarefa no banco; as tags são passadas como string separada por vírgulas.
 		task, err := tarefa.CriarTarefa(finalDescription, data, priority, strings.Join(tags, ","))
 		if err != nil {
 			log.Printf("Erro ao criar lembrete (tarefa): %v", err)
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
@@ -36,26 +38,11 @@ func NewSQLiteAulaStore(db *sql.DB) AulaStore {
 	return &SQLiteAulaStore{DB: db}
 }
 
+// Init ensures the lessons table exists by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
 func (s *SQLiteAulaStore) Init() error {
-	stmt, err := s.DB.Prepare(`
-		CREATE TABLE IF NOT EXISTS lessons (
-			id TEXT PRIMARY KEY,
-			subject TEXT,
-			topic TEXT,
-			date DATETIME,
-			class_id TEXT,
-			plan TEXT,
-			observations TEXT
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare create lessons table statement: %w", err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec()
-	if err != nil {
-		return fmt.Errorf("failed to execute create lessons table statement: %w", err)
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate lessons table: %w", err)
 	}
 	return nil
 }
@@ -64,18 +51,26 @@ func (s *SQLiteAulaStore) SaveLesson(lesson models.Lesson) (models.Lesson, error
 	if lesson.ID == "" {
 		lesson.ID = uuid.NewString()
 	}
+	now := time.Now()
+	if lesson.CreatedAt.IsZero() {
+		lesson.CreatedAt = now
+	}
+	lesson.UpdatedAt = now
 
 	stmt, err := s.DB.Prepare(`
-		INSERT OR REPLACE INTO lessons
-		(id, subject, topic, date, class_id, plan, observations)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO lessons
+		(id, subject, topic, date, class_id, plan, observations, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET subject = excluded.subject, topic = excluded.topic, date = excluded.date,
+			class_id = excluded.class_id, plan = excluded.plan, observations = excluded.observations,
+			updated_at = excluded.updated_at
 	`)
 	if err != nil {
 		return models.Lesson{}, fmt.Errorf("failed to prepare save lesson statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(lesson.ID, lesson.Subject, lesson.Topic, lesson.Date, lesson.ClassID, lesson.Plan, lesson.Observations)
+	_, err = stmt.Exec(lesson.ID, lesson.Subject, lesson.Topic, lesson.Date, lesson.ClassID, lesson.Plan, lesson.Observations, lesson.CreatedAt, lesson.UpdatedAt)
 	if err != nil {
 		return models.Lesson{}, fmt.Errorf("failed to execute save lesson statement for lesson ID %s: %w", lesson.ID, err)
 	}
@@ -119,12 +114,13 @@ func (s *SQLiteAulaStore) ListLessons(disciplina, turma, periodo, mes, ano strin
 		if len(parts) == 2 {
 			startDateStr := parts[0]
 			endDateStr := parts[1]
-			// Assuming format "dd-mm-yyyy"
-			layout := "02-01-2006"
-			startDate, err := time.Parse(layout, startDateStr)
+			// Each side is a datas expression ("dd-mm-yyyy", "hoje", "fim do mês"), kept as a UTC date.
+			startDate, err := datas.Data(startDateStr)
 			if err == nil {
-				endDate, err := time.Parse(layout, endDateStr)
+				startDate = datas.DataUTC(startDate)
+				endDate, err := datas.Data(endDateStr)
 				if err == nil {
+					endDate = datas.DataUTC(endDate)
 					// Ensure endDate includes the whole day
 					endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 					queryFilters = append(queryFilters, "date BETWEEN ? AND ?")
@@ -194,13 +190,13 @@ func (s *SQLiteAulaStore) UpdateLessonPlan(id, novoPlano, novasObservacoes strin
 		return models.Lesson{}, fmt.Errorf("cannot update lesson plan, lesson with ID '%s' not found: %w", id, err)
 	}
 
-	stmt, err := s.DB.Prepare("UPDATE lessons SET plan = ?, observations = ? WHERE id = ?")
+	stmt, err := s.DB.Prepare("UPDATE lessons SET plan = ?, observations = ?, updated_at = ? WHERE id = ?")
 	if err != nil {
 		return models.Lesson{}, fmt.Errorf("failed to prepare update lesson plan statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(novoPlano, novasObservacoes, id)
+	_, err = stmt.Exec(novoPlano, novasObservacoes, time.Now(), id)
 	if err != nil {
 		return models.Lesson{}, fmt.Errorf("failed to execute update lesson plan for ID %s: %w", id, err)
 	}
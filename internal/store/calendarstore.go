@@ -0,0 +1,160 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// SchoolCalendarStore defines the interface for school calendar persistence: the non-school days of each academic year.
+type SchoolCalendarStore interface {
+	Init() error
+	SaveNonSchoolDay(day models.NonSchoolDay) (models.NonSchoolDay, error)
+	GetNonSchoolDayByID(id string) (models.NonSchoolDay, error)
+	ListNonSchoolDays(academicYear string) ([]models.NonSchoolDay, error)
+	ListNonSchoolDaysBetween(from, to time.Time) ([]models.NonSchoolDay, error)
+	DeleteNonSchoolDay(id string) error
+}
+
+// SQLiteSchoolCalendarStore implements SchoolCalendarStore on the non_school_days table.
+type SQLiteSchoolCalendarStore struct {
+	DB *sql.DB
+}
+
+func NewSQLiteSchoolCalendarStore(db *sql.DB) SchoolCalendarStore {
+	return &SQLiteSchoolCalendarStore{DB: db}
+}
+
+// Init ensures the non_school_days table exists by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
+func (s *SQLiteSchoolCalendarStore) Init() error {
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate non_school_days table: %w", err)
+	}
+	return nil
+}
+
+// calendarDateLayout is the text format of start_date and end_date.
+const calendarDateLayout = "2006-01-02"
+
+const nonSchoolDayColumns = "id, academic_year, name, kind, start_date, end_date, created_at, updated_at"
+
+func scanNonSchoolDay(row interface{ Scan(...interface{}) error }) (models.NonSchoolDay, error) {
+	var day models.NonSchoolDay
+	var kind sql.NullString
+	var start, end string
+	var updatedAt sql.NullTime
+	if err := row.Scan(&day.ID, &day.AcademicYear, &day.Name, &kind, &start, &end, &day.CreatedAt, &updatedAt); err != nil {
+		return models.NonSchoolDay{}, err
+	}
+	var err error
+	if day.StartDate, err = time.ParseInLocation(calendarDateLayout, start, time.Local); err != nil {
+		return models.NonSchoolDay{}, fmt.Errorf("invalid start_date %q: %w", start, err)
+	}
+	if day.EndDate, err = time.ParseInLocation(calendarDateLayout, end, time.Local); err != nil {
+		return models.NonSchoolDay{}, fmt.Errorf("invalid end_date %q: %w", end, err)
+	}
+	day.Kind = kind.String
+	day.UpdatedAt = updatedAt.Time
+	return day, nil
+}
+
+// SaveNonSchoolDay inserts or updates a non-school day. Only the calendar date of StartDate and EndDate is stored;
+// an empty AcademicYear defaults to the year of StartDate and a zero EndDate to StartDate.
+func (s *SQLiteSchoolCalendarStore) SaveNonSchoolDay(day models.NonSchoolDay) (models.NonSchoolDay, error) {
+	if day.ID == "" {
+		day.ID = uuid.NewString()
+	}
+	if day.EndDate.IsZero() {
+		day.EndDate = day.StartDate
+	}
+	if day.EndDate.Before(day.StartDate) {
+		return models.NonSchoolDay{}, fmt.Errorf("non-school day '%s' ends before it starts", day.Name)
+	}
+	if day.AcademicYear == "" {
+		day.AcademicYear = fmt.Sprint(day.StartDate.Year())
+	}
+	now := time.Now()
+	if day.CreatedAt.IsZero() {
+		day.CreatedAt = now
+	}
+	day.UpdatedAt = now
+
+	_, err := s.DB.Exec(`INSERT INTO non_school_days (`+nonSchoolDayColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET academic_year = excluded.academic_year, name = excluded.name, kind = excluded.kind,
+			start_date = excluded.start_date, end_date = excluded.end_date, updated_at = excluded.updated_at`,
+		day.ID, day.AcademicYear, day.Name, day.Kind, day.StartDate.Format(calendarDateLayout), day.EndDate.Format(calendarDateLayout),
+		day.CreatedAt, day.UpdatedAt)
+	if err != nil {
+		return models.NonSchoolDay{}, fmt.Errorf("failed to save non-school day ID %s: %w", day.ID, err)
+	}
+	return day, nil
+}
+
+func (s *SQLiteSchoolCalendarStore) GetNonSchoolDayByID(id string) (models.NonSchoolDay, error) {
+	day, err := scanNonSchoolDay(s.DB.QueryRow("SELECT "+nonSchoolDayColumns+" FROM non_school_days WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.NonSchoolDay{}, fmt.Errorf("non-school day with ID '%s' not found: %w", id, err)
+		}
+		return models.NonSchoolDay{}, fmt.Errorf("failed to get non-school day by ID '%s': %w", id, err)
+	}
+	return day, nil
+}
+
+func (s *SQLiteSchoolCalendarStore) queryNonSchoolDays(where string, args ...interface{}) ([]models.NonSchoolDay, error) {
+	query := "SELECT " + nonSchoolDayColumns + " FROM non_school_days"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := s.DB.Query(query+" ORDER BY start_date ASC, name ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query non-school days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []models.NonSchoolDay
+	for rows.Next() {
+		day, err := scanNonSchoolDay(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan non-school day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of non-school days: %w", err)
+	}
+	return days, nil
+}
+
+// ListNonSchoolDays returns the non-school days of an academic year ordered by start date.
+// An empty academicYear returns every year.
+func (s *SQLiteSchoolCalendarStore) ListNonSchoolDays(academicYear string) ([]models.NonSchoolDay, error) {
+	if academicYear == "" {
+		return s.queryNonSchoolDays("")
+	}
+	return s.queryNonSchoolDays("academic_year = ?", academicYear)
+}
+
+// ListNonSchoolDaysBetween returns the non-school days that include at least one calendar day from from to to (inclusive).
+func (s *SQLiteSchoolCalendarStore) ListNonSchoolDaysBetween(from, to time.Time) ([]models.NonSchoolDay, error) {
+	return s.queryNonSchoolDays("start_date <= ? AND end_date >= ?", to.Format(calendarDateLayout), from.Format(calendarDateLayout))
+}
+
+func (s *SQLiteSchoolCalendarStore) DeleteNonSchoolDay(id string) error {
+	res, err := s.DB.Exec("DELETE FROM non_school_days WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete non-school day ID %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected after deleting non-school day ID %s: %w", id, err)
+	} else if n == 0 {
+		return fmt.Errorf("no non-school day found with ID '%s' to delete: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
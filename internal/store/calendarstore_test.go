@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Driver for sqlite3
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// setupCalendarDB initializes an in-memory SQLite database and a SchoolCalendarStore for testing.
+func setupCalendarDB(t *testing.T) (store.SchoolCalendarStore, func()) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	calendarStore := store.NewSQLiteSchoolCalendarStore(db)
+	if err := calendarStore.Init(); err != nil {
+		db.Close()
+		t.Fatalf("Failed to initialize school calendar store: %v", err)
+	}
+	return calendarStore, func() { db.Close() }
+}
+
+func TestSchoolCalendarStore(t *testing.T) {
+	calendarStore, teardown := setupCalendarDB(t)
+	defer teardown()
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+	days := []models.NonSchoolDay{
+		{Name: "Recesso de julho", Kind: models.NonSchoolDayRecess, StartDate: date(2024, 7, 15), EndDate: date(2024, 7, 26)},
+		{Name: "Carnaval", Kind: models.NonSchoolDayHoliday, StartDate: date(2024, 2, 12), EndDate: date(2024, 2, 13)},
+		{Name: "Tiradentes", Kind: models.NonSchoolDayHoliday, StartDate: date(2024, 4, 21)},
+		{Name: "Confraternização", Kind: models.NonSchoolDayHoliday, StartDate: date(2025, 1, 1), AcademicYear: "2024"},
+	}
+	for i, day := range days {
+		saved, err := calendarStore.SaveNonSchoolDay(day)
+		if err != nil {
+			t.Fatalf("SaveNonSchoolDay failed: %v", err)
+		}
+		days[i] = saved
+	}
+
+	got, err := calendarStore.GetNonSchoolDayByID(days[2].ID)
+	if err != nil {
+		t.Fatalf("GetNonSchoolDayByID failed: %v", err)
+	}
+	if got.AcademicYear != "2024" || !got.StartDate.Equal(date(2024, 4, 21)) || !got.EndDate.Equal(got.StartDate) {
+		t.Errorf("GetNonSchoolDayByID returned unexpected day: %+v", got)
+	}
+
+	listed, err := calendarStore.ListNonSchoolDays("2024")
+	if err != nil {
+		t.Fatalf("ListNonSchoolDays failed: %v", err)
+	}
+	if len(listed) != 4 || listed[0].Name != "Carnaval" || listed[3].Name != "Confraternização" {
+		t.Errorf("ListNonSchoolDays returned unexpected days: %+v", listed)
+	}
+
+	// Ranges are inclusive on both ends.
+	between, err := calendarStore.ListNonSchoolDaysBetween(date(2024, 2, 13), date(2024, 7, 15))
+	if err != nil {
+		t.Fatalf("ListNonSchoolDaysBetween failed: %v", err)
+	}
+	if len(between) != 3 {
+		t.Errorf("Expected 3 non-school days between 2024-02-13 and 2024-07-15, got %+v", between)
+	}
+
+	if _, err := calendarStore.SaveNonSchoolDay(models.NonSchoolDay{Name: "Inválido", StartDate: date(2024, 5, 2), EndDate: date(2024, 5, 1)}); err == nil {
+		t.Error("Expected error saving a day that ends before it starts")
+	}
+
+	if err := calendarStore.DeleteNonSchoolDay(days[0].ID); err != nil {
+		t.Fatalf("DeleteNonSchoolDay failed: %v", err)
+	}
+	if _, err := calendarStore.GetNonSchoolDayByID(days[0].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if err := calendarStore.DeleteNonSchoolDay(days[0].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting a missing day, got %v", err)
+	}
+}
@@ -3,9 +3,10 @@ package store
 import (
 	"database/sql"
 	"fmt"
-	// "time" // Removed as it's unused
+	"time"
 
 	"github.com/google/uuid"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
@@ -28,36 +29,11 @@ func NewSQLiteGradeStore(db *sql.DB) GradeStore {
 	return &SQLiteGradeStore{DB: db}
 }
 
+// Init ensures the grades table exists by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
 func (s *SQLiteGradeStore) Init() error {
-	// Enable foreign key support if not enabled by default.
-	// For SQLite, this is often done per connection, but some drivers might handle it.
-	// _, err := s.DB.Exec("PRAGMA foreign_keys = ON;")
-	// if err != nil {
-	// 	return fmt.Errorf("failed to enable foreign keys: %w", err)
-	// }
-
-	stmt, err := s.DB.Prepare(`
-		CREATE TABLE IF NOT EXISTS grades (
-			id TEXT PRIMARY KEY,
-			student_id TEXT,
-			term_id TEXT,
-			subject TEXT,
-			description TEXT,
-			value REAL,
-			weight REAL,
-			date DATETIME,
-			FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
-			FOREIGN KEY (term_id) REFERENCES terms(id) ON DELETE CASCADE
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare create grades table statement: %w", err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec()
-	if err != nil {
-		return fmt.Errorf("failed to execute create grades table statement: %w", err)
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate grades table: %w", err)
 	}
 	return nil
 }
@@ -66,18 +42,26 @@ func (s *SQLiteGradeStore) SaveGrade(grade models.Grade) (models.Grade, error) {
 	if grade.ID == "" {
 		grade.ID = uuid.NewString()
 	}
+	now := time.Now()
+	if grade.CreatedAt.IsZero() {
+		grade.CreatedAt = now
+	}
+	grade.UpdatedAt = now
 
 	stmt, err := s.DB.Prepare(`
-		INSERT OR REPLACE INTO grades
-		(id, student_id, term_id, subject, description, value, weight, date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO grades
+		(id, student_id, term_id, subject, description, value, weight, date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET student_id = excluded.student_id, term_id = excluded.term_id,
+			subject = excluded.subject, description = excluded.description, value = excluded.value,
+			weight = excluded.weight, date = excluded.date, updated_at = excluded.updated_at
 	`)
 	if err != nil {
 		return models.Grade{}, fmt.Errorf("failed to prepare save grade statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(grade.ID, grade.StudentID, grade.TermID, grade.Subject, grade.Description, grade.Value, grade.Weight, grade.Date)
+	_, err = stmt.Exec(grade.ID, grade.StudentID, grade.TermID, grade.Subject, grade.Description, grade.Value, grade.Weight, grade.Date, grade.CreatedAt, grade.UpdatedAt)
 	if err != nil {
 		return models.Grade{}, fmt.Errorf("failed to execute save grade statement for grade ID %s: %w", grade.ID, err)
 	}
@@ -3,8 +3,10 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
@@ -26,22 +28,11 @@ func NewSQLiteStudentStore(db *sql.DB) StudentStore {
 	return &SQLiteStudentStore{DB: db}
 }
 
-// Init creates the 'students' table if it doesn't exist.
+// Init ensures the students table exists by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
 func (s *SQLiteStudentStore) Init() error {
-	stmt, err := s.DB.Prepare(`
-		CREATE TABLE IF NOT EXISTS students (
-			id TEXT PRIMARY KEY,
-			name TEXT
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare create students table statement: %w", err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec()
-	if err != nil {
-		return fmt.Errorf("failed to execute create students table statement: %w", err)
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate students table: %w", err)
 	}
 	return nil
 }
@@ -51,14 +42,20 @@ func (s *SQLiteStudentStore) SaveStudent(student models.Student) (models.Student
 	if student.ID == "" {
 		student.ID = uuid.NewString()
 	}
+	now := time.Now()
+	if student.CreatedAt.IsZero() {
+		student.CreatedAt = now
+	}
+	student.UpdatedAt = now
 
-	stmt, err := s.DB.Prepare("INSERT OR REPLACE INTO students (id, name) VALUES (?, ?)")
+	stmt, err := s.DB.Prepare(`INSERT INTO students (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET name = excluded.name, updated_at = excluded.updated_at`)
 	if err != nil {
 		return models.Student{}, fmt.Errorf("failed to prepare save student statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(student.ID, student.Name)
+	_, err = stmt.Exec(student.ID, student.Name, student.CreatedAt, student.UpdatedAt)
 	if err != nil {
 		return models.Student{}, fmt.Errorf("failed to execute save student statement for student ID %s: %w", student.ID, err)
 	}
@@ -3,9 +3,11 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"vickgenda-cli/internal/db"
 	"vickgenda-cli/internal/models"
 )
 
@@ -28,24 +30,11 @@ func NewSQLiteTermStore(db *sql.DB) TermStore {
 	return &SQLiteTermStore{DB: db}
 }
 
+// Init ensures the terms table exists by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
 func (s *SQLiteTermStore) Init() error {
-	stmt, err := s.DB.Prepare(`
-		CREATE TABLE IF NOT EXISTS terms (
-			id TEXT PRIMARY KEY,
-			name TEXT,
-			start_date DATETIME,
-			end_date DATETIME,
-			year INTEGER
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare create table statement: %w", err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec()
-	if err != nil {
-		return fmt.Errorf("failed to execute create table statement: %w", err)
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate terms table: %w", err)
 	}
 	return nil
 }
@@ -54,10 +43,12 @@ func (s *SQLiteTermStore) SaveTerm(term models.Term) (models.Term, error) {
 	if term.ID == "" {
 		term.ID = uuid.NewString()
 	}
-	year := term.StartDate.Year()
+	if term.AcademicYear == "" {
+		term.AcademicYear = strconv.Itoa(term.StartDate.Year())
+	}
 
 	// Overlap Validation
-	rows, err := s.DB.Query("SELECT id, start_date, end_date FROM terms WHERE year = ? AND id != ?", year, term.ID)
+	rows, err := s.DB.Query("SELECT id, name, start_date, end_date FROM terms WHERE academic_year = ? AND id != ?", term.AcademicYear, term.ID)
 	if err != nil {
 		return models.Term{}, fmt.Errorf("failed to query existing terms for overlap check: %w", err)
 	}
@@ -65,35 +56,43 @@ func (s *SQLiteTermStore) SaveTerm(term models.Term) (models.Term, error) {
 
 	for rows.Next() {
 		var existingID string
+		var existingName sql.NullString
 		var existingStartDate, existingEndDate time.Time
-		if err := rows.Scan(&existingID, &existingStartDate, &existingEndDate); err != nil {
+		if err := rows.Scan(&existingID, &existingName, &existingStartDate, &existingEndDate); err != nil {
 			return models.Term{}, fmt.Errorf("failed to scan existing term for overlap check: %w", err)
 		}
 
 		// Check for overlap: (new.Start <= existing.End) AND (new.End >= existing.Start)
 		if (term.StartDate.Equal(existingEndDate) || term.StartDate.Before(existingEndDate)) &&
 			(term.EndDate.Equal(existingStartDate) || term.EndDate.After(existingStartDate)) {
-			// Retrieving the name of the overlapping term for a more informative error message
-			var existingName string
-			err := s.DB.QueryRow("SELECT name FROM terms WHERE id = ?", existingID).Scan(&existingName)
-			if err != nil {
-				// If we can't get the name, use the ID in the error
+			if !existingName.Valid || existingName.String == "" {
+				// Without a name, use the ID in the error
 				return models.Term{}, fmt.Errorf("term dates overlap with existing term ID '%s'", existingID)
 			}
-			return models.Term{}, fmt.Errorf("term '%s' overlaps with existing term '%s'", term.Name, existingName)
+			return models.Term{}, fmt.Errorf("term '%s' overlaps with existing term '%s'", term.Name, existingName.String)
 		}
 	}
 	if err = rows.Err(); err != nil {
 		return models.Term{}, fmt.Errorf("error during iteration of existing terms for overlap check: %w", err)
 	}
+	rows.Close()
+
+	now := time.Now()
+	if term.CreatedAt.IsZero() {
+		term.CreatedAt = now
+	}
+	term.UpdatedAt = now
 
-	stmt, err := s.DB.Prepare("INSERT OR REPLACE INTO terms (id, name, start_date, end_date, year) VALUES (?, ?, ?, ?, ?)")
+	stmt, err := s.DB.Prepare(`INSERT INTO terms (id, name, academic_year, start_date, end_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET name = excluded.name, academic_year = excluded.academic_year,
+			start_date = excluded.start_date, end_date = excluded.end_date, updated_at = excluded.updated_at`)
 	if err != nil {
 		return models.Term{}, fmt.Errorf("failed to prepare save term statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(term.ID, term.Name, term.StartDate, term.EndDate, year)
+	_, err = stmt.Exec(term.ID, term.Name, term.AcademicYear, term.StartDate, term.EndDate, term.CreatedAt, term.UpdatedAt)
 	if err != nil {
 		return models.Term{}, fmt.Errorf("failed to execute save term statement: %w", err)
 	}
@@ -103,18 +102,20 @@ func (s *SQLiteTermStore) SaveTerm(term models.Term) (models.Term, error) {
 
 func (s *SQLiteTermStore) GetTermByID(id string) (models.Term, error) {
 	var term models.Term
-	err := s.DB.QueryRow("SELECT id, name, start_date, end_date FROM terms WHERE id = ?", id).Scan(&term.ID, &term.Name, &term.StartDate, &term.EndDate)
+	var academicYear sql.NullString
+	err := s.DB.QueryRow("SELECT id, name, academic_year, start_date, end_date FROM terms WHERE id = ?", id).Scan(&term.ID, &term.Name, &academicYear, &term.StartDate, &term.EndDate)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.Term{}, fmt.Errorf("term with ID '%s' not found: %w", id, err)
 		}
 		return models.Term{}, fmt.Errorf("failed to get term by ID '%s': %w", id, err)
 	}
+	term.AcademicYear = academicYear.String
 	return term, nil
 }
 
 func (s *SQLiteTermStore) ListTermsByYear(year int) ([]models.Term, error) {
-	rows, err := s.DB.Query("SELECT id, name, start_date, end_date FROM terms WHERE year = ? ORDER BY start_date ASC", year)
+	rows, err := s.DB.Query("SELECT id, name, academic_year, start_date, end_date FROM terms WHERE academic_year = ? ORDER BY start_date ASC", strconv.Itoa(year))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query terms by year %d: %w", year, err)
 	}
@@ -123,9 +124,11 @@ func (s *SQLiteTermStore) ListTermsByYear(year int) ([]models.Term, error) {
 	var terms []models.Term
 	for rows.Next() {
 		var term models.Term
-		if err := rows.Scan(&term.ID, &term.Name, &term.StartDate, &term.EndDate); err != nil {
+		var academicYear sql.NullString
+		if err := rows.Scan(&term.ID, &term.Name, &academicYear, &term.StartDate, &term.EndDate); err != nil {
 			return nil, fmt.Errorf("failed to scan term during ListTermsByYear for year %d: %w", year, err)
 		}
+		term.AcademicYear = academicYear.String
 		terms = append(terms, term)
 	}
 
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// TimetableStore defines the interface for timetable (grade horária) persistence:
+// the weekly slots of a term and the occurrences generated from them.
+type TimetableStore interface {
+	Init() error
+	SaveSlot(slot models.TimetableSlot) (models.TimetableSlot, error)
+	GetSlotByID(id string) (models.TimetableSlot, error)
+	ListSlots(termID, classID string) ([]models.TimetableSlot, error)
+	DeleteSlot(id string) error
+	SaveOccurrence(occurrence models.TimetableOccurrence) error
+	ListOccurrences(slotID string) ([]models.TimetableOccurrence, error)
+	ListOccurrencesBetween(from, to time.Time) ([]models.TimetableOccurrence, error)
+	DeleteOccurrence(slotID, date string) error
+}
+
+// SQLiteTimetableStore implements TimetableStore on the timetable_slots and timetable_occurrences tables.
+type SQLiteTimetableStore struct {
+	DB *sql.DB
+}
+
+func NewSQLiteTimetableStore(db *sql.DB) TimetableStore {
+	return &SQLiteTimetableStore{DB: db}
+}
+
+// Init ensures the timetable tables exist by applying the shared schema migrations.
+// The table layout is owned by internal/db; see db.Migrate.
+func (s *SQLiteTimetableStore) Init() error {
+	if err := db.Migrate(s.DB); err != nil {
+		return fmt.Errorf("failed to migrate timetable tables: %w", err)
+	}
+	return nil
+}
+
+const timetableSlotColumns = "id, term_id, class_id, subject, weekday, start_time, end_time, location, created_at, updated_at"
+
+func scanTimetableSlot(row interface{ Scan(...interface{}) error }) (models.TimetableSlot, error) {
+	var slot models.TimetableSlot
+	var weekday int
+	var location sql.NullString
+	var updatedAt sql.NullTime
+	if err := row.Scan(&slot.ID, &slot.TermID, &slot.ClassID, &slot.Subject, &weekday, &slot.StartTime, &slot.EndTime,
+		&location, &slot.CreatedAt, &updatedAt); err != nil {
+		return models.TimetableSlot{}, err
+	}
+	slot.Weekday = time.Weekday(weekday)
+	slot.Location = location.String
+	slot.UpdatedAt = updatedAt.Time
+	return slot, nil
+}
+
+func (s *SQLiteTimetableStore) SaveSlot(slot models.TimetableSlot) (models.TimetableSlot, error) {
+	if slot.ID == "" {
+		slot.ID = uuid.NewString()
+	}
+	now := time.Now()
+	if slot.CreatedAt.IsZero() {
+		slot.CreatedAt = now
+	}
+	slot.UpdatedAt = now
+
+	_, err := s.DB.Exec(`INSERT INTO timetable_slots (`+timetableSlotColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET term_id = excluded.term_id, class_id = excluded.class_id, subject = excluded.subject,
+			weekday = excluded.weekday, start_time = excluded.start_time, end_time = excluded.end_time,
+			location = excluded.location, updated_at = excluded.updated_at`,
+		slot.ID, slot.TermID, slot.ClassID, slot.Subject, int(slot.Weekday), slot.StartTime, slot.EndTime,
+		slot.Location, slot.CreatedAt, slot.UpdatedAt)
+	if err != nil {
+		return models.TimetableSlot{}, fmt.Errorf("failed to save timetable slot ID %s: %w", slot.ID, err)
+	}
+	return slot, nil
+}
+
+func (s *SQLiteTimetableStore) GetSlotByID(id string) (models.TimetableSlot, error) {
+	slot, err := scanTimetableSlot(s.DB.QueryRow("SELECT "+timetableSlotColumns+" FROM timetable_slots WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.TimetableSlot{}, fmt.Errorf("timetable slot with ID '%s' not found: %w", id, err)
+		}
+		return models.TimetableSlot{}, fmt.Errorf("failed to get timetable slot by ID '%s': %w", id, err)
+	}
+	return slot, nil
+}
+
+// ListSlots returns the slots of a term ordered by weekday (Monday first) and start time.
+// Empty termID or classID match every term or class.
+func (s *SQLiteTimetableStore) ListSlots(termID, classID string) ([]models.TimetableSlot, error) {
+	var filters []string
+	var args []interface{}
+	if termID != "" {
+		filters = append(filters, "term_id = ?")
+		args = append(args, termID)
+	}
+	if classID != "" {
+		filters = append(filters, "LOWER(class_id) = LOWER(?)")
+		args = append(args, classID)
+	}
+	query := "SELECT " + timetableSlotColumns + " FROM timetable_slots"
+	if len(filters) > 0 {
+		query += " WHERE " + strings.Join(filters, " AND ")
+	}
+	// Sunday is stored as 0; (weekday + 6) % 7 puts it after Saturday.
+	query += " ORDER BY (weekday + 6) % 7 ASC, start_time ASC, class_id ASC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timetable slots: %w", err)
+	}
+	defer rows.Close()
+
+	var slots []models.TimetableSlot
+	for rows.Next() {
+		slot, err := scanTimetableSlot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan timetable slot: %w", err)
+		}
+		slots = append(slots, slot)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of timetable slots: %w", err)
+	}
+	return slots, nil
+}
+
+// DeleteSlot removes a slot and its occurrence records. Generated lessons and events are not touched.
+func (s *SQLiteTimetableStore) DeleteSlot(id string) error {
+	res, err := s.DB.Exec("DELETE FROM timetable_slots WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete timetable slot ID %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected after deleting timetable slot ID %s: %w", id, err)
+	} else if n == 0 {
+		return fmt.Errorf("no timetable slot found with ID '%s' to delete", id)
+	}
+	if _, err := s.DB.Exec("DELETE FROM timetable_occurrences WHERE slot_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete occurrences of timetable slot ID %s: %w", id, err)
+	}
+	return nil
+}
+
+// SaveOccurrence inserts or replaces the occurrence of a slot on occurrence.Date.
+func (s *SQLiteTimetableStore) SaveOccurrence(occurrence models.TimetableOccurrence) error {
+	_, err := s.DB.Exec(`INSERT INTO timetable_occurrences (slot_id, date, start_time, lesson_id, event_id)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(slot_id, date) DO UPDATE SET start_time = excluded.start_time,
+			lesson_id = excluded.lesson_id, event_id = excluded.event_id`,
+		occurrence.SlotID, occurrence.Date, occurrence.StartTime, occurrence.LessonID, occurrence.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to save occurrence %s of timetable slot ID %s: %w", occurrence.Date, occurrence.SlotID, err)
+	}
+	return nil
+}
+
+func (s *SQLiteTimetableStore) queryOccurrences(where string, args ...interface{}) ([]models.TimetableOccurrence, error) {
+	rows, err := s.DB.Query("SELECT slot_id, date, start_time, lesson_id, event_id FROM timetable_occurrences WHERE "+where+" ORDER BY start_time ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timetable occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	var occurrences []models.TimetableOccurrence
+	for rows.Next() {
+		var o models.TimetableOccurrence
+		var lessonID, eventID sql.NullString
+		if err := rows.Scan(&o.SlotID, &o.Date, &o.StartTime, &lessonID, &eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan timetable occurrence: %w", err)
+		}
+		o.LessonID = lessonID.String
+		o.EventID = eventID.String
+		occurrences = append(occurrences, o)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iteration of timetable occurrences: %w", err)
+	}
+	return occurrences, nil
+}
+
+// ListOccurrences returns the occurrences generated for a slot, ordered by start time.
+func (s *SQLiteTimetableStore) ListOccurrences(slotID string) ([]models.TimetableOccurrence, error) {
+	return s.queryOccurrences("slot_id = ?", slotID)
+}
+
+// ListOccurrencesBetween returns the occurrences of every slot starting in [from, to).
+func (s *SQLiteTimetableStore) ListOccurrencesBetween(from, to time.Time) ([]models.TimetableOccurrence, error) {
+	return s.queryOccurrences("start_time >= ? AND start_time < ?", from, to)
+}
+
+func (s *SQLiteTimetableStore) DeleteOccurrence(slotID, date string) error {
+	if _, err := s.DB.Exec("DELETE FROM timetable_occurrences WHERE slot_id = ? AND date = ?", slotID, date); err != nil {
+		return fmt.Errorf("failed to delete occurrence %s of timetable slot ID %s: %w", date, slotID, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package store_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Driver for sqlite3
+	"vickgenda-cli/internal/models"
+	"vickgenda-cli/internal/store"
+)
+
+// setupTimetableDB initializes an in-memory SQLite database and a TimetableStore for testing.
+func setupTimetableDB(t *testing.T) (store.TimetableStore, func()) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	timetableStore := store.NewSQLiteTimetableStore(db)
+	if err := timetableStore.Init(); err != nil {
+		db.Close()
+		t.Fatalf("Failed to initialize timetable store: %v", err)
+	}
+	return timetableStore, func() { db.Close() }
+}
+
+func TestTimetableStore_Slots(t *testing.T) {
+	timetableStore, teardown := setupTimetableDB(t)
+	defer teardown()
+
+	slots := []models.TimetableSlot{
+		{TermID: "term-1", ClassID: "7B", Subject: "Matemática", Weekday: time.Sunday, StartTime: "08:00", EndTime: "08:50"},
+		{TermID: "term-1", ClassID: "8A", Subject: "História", Weekday: time.Monday, StartTime: "10:00", EndTime: "10:50", Location: "Sala 2"},
+		{TermID: "term-1", ClassID: "7B", Subject: "Ciências", Weekday: time.Monday, StartTime: "08:00", EndTime: "08:50"},
+		{TermID: "term-2", ClassID: "7B", Subject: "Matemática", Weekday: time.Monday, StartTime: "08:00", EndTime: "08:50"},
+	}
+	for i, slot := range slots {
+		saved, err := timetableStore.SaveSlot(slot)
+		if err != nil {
+			t.Fatalf("SaveSlot failed: %v", err)
+		}
+		slots[i] = saved
+	}
+
+	got, err := timetableStore.GetSlotByID(slots[1].ID)
+	if err != nil {
+		t.Fatalf("GetSlotByID failed: %v", err)
+	}
+	if got.Subject != "História" || got.Weekday != time.Monday || got.Location != "Sala 2" {
+		t.Errorf("GetSlotByID returned unexpected slot: %+v", got)
+	}
+
+	listed, err := timetableStore.ListSlots("term-1", "")
+	if err != nil {
+		t.Fatalf("ListSlots failed: %v", err)
+	}
+	// Monday first, Sunday last, then by start time.
+	var subjects []string
+	for _, s := range listed {
+		subjects = append(subjects, s.Subject)
+	}
+	if len(subjects) != 3 || subjects[0] != "Ciências" || subjects[1] != "História" || subjects[2] != "Matemática" {
+		t.Errorf("ListSlots returned unexpected order: %v", subjects)
+	}
+	if byClass, _ := timetableStore.ListSlots("term-1", "7b"); len(byClass) != 2 {
+		t.Errorf("Expected 2 slots for class 7B, got %d", len(byClass))
+	}
+
+	if err := timetableStore.DeleteSlot(slots[0].ID); err != nil {
+		t.Fatalf("DeleteSlot failed: %v", err)
+	}
+	if _, err := timetableStore.GetSlotByID(slots[0].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if err := timetableStore.DeleteSlot(slots[0].ID); err == nil {
+		t.Error("Expected error deleting a missing slot")
+	}
+}
+
+func TestTimetableStore_Occurrences(t *testing.T) {
+	timetableStore, teardown := setupTimetableDB(t)
+	defer teardown()
+
+	slot, err := timetableStore.SaveSlot(models.TimetableSlot{TermID: "term-1", ClassID: "7B", Subject: "Matemática", Weekday: time.Monday, StartTime: "08:00", EndTime: "08:50"})
+	if err != nil {
+		t.Fatalf("SaveSlot failed: %v", err)
+	}
+	for _, day := range []int{5, 12, 19} {
+		start := time.Date(2024, 8, day, 8, 0, 0, 0, time.Local)
+		occurrence := models.TimetableOccurrence{SlotID: slot.ID, Date: start.Format("2006-01-02"), StartTime: start, LessonID: "lesson", EventID: "event"}
+		if err := timetableStore.SaveOccurrence(occurrence); err != nil {
+			t.Fatalf("SaveOccurrence failed: %v", err)
+		}
+	}
+	// Saving the same date again replaces the occurrence.
+	moved := time.Date(2024, 8, 12, 9, 0, 0, 0, time.Local)
+	if err := timetableStore.SaveOccurrence(models.TimetableOccurrence{SlotID: slot.ID, Date: "2024-08-12", StartTime: moved, LessonID: "lesson-2", EventID: "event-2"}); err != nil {
+		t.Fatalf("SaveOccurrence failed: %v", err)
+	}
+
+	occurrences, err := timetableStore.ListOccurrences(slot.ID)
+	if err != nil {
+		t.Fatalf("ListOccurrences failed: %v", err)
+	}
+	if len(occurrences) != 3 || occurrences[1].LessonID != "lesson-2" || !occurrences[1].StartTime.Equal(moved) {
+		t.Errorf("ListOccurrences returned unexpected occurrences: %+v", occurrences)
+	}
+
+	between, err := timetableStore.ListOccurrencesBetween(time.Date(2024, 8, 10, 0, 0, 0, 0, time.Local), time.Date(2024, 8, 19, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("ListOccurrencesBetween failed: %v", err)
+	}
+	if len(between) != 1 || between[0].Date != "2024-08-12" {
+		t.Errorf("ListOccurrencesBetween returned unexpected occurrences: %+v", between)
+	}
+
+	if err := timetableStore.DeleteOccurrence(slot.ID, "2024-08-05"); err != nil {
+		t.Fatalf("DeleteOccurrence failed: %v", err)
+	}
+	if err := timetableStore.DeleteSlot(slot.ID); err != nil {
+		t.Fatalf("DeleteSlot failed: %v", err)
+	}
+	if remaining, _ := timetableStore.ListOccurrences(slot.ID); len(remaining) != 0 {
+		t.Errorf("Expected occurrences to be deleted with the slot, got %+v", remaining)
+	}
+}
@@ -0,0 +1,396 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TipoItemCalendario distingue os itens exibidos nas visões de calendário.
+// Cada tipo tem uma letra marcadora e, em terminais, uma cor própria.
+type TipoItemCalendario int
+
+const (
+	ItemEvento    TipoItemCalendario = iota // Evento da agenda.
+	ItemAula                                // Aula registrada.
+	ItemTarefa                              // Prazo de uma tarefa.
+	ItemProva                               // Prova agendada.
+	ItemNaoLetivo                           // Feriado, recesso ou outro dia sem aulas.
+)
+
+// ItemCalendario é um bloco exibido no calendário.
+// Itens pontuais, como o prazo de uma tarefa, têm Fim igual a Inicio; à meia-noite, valem para o dia inteiro.
+type ItemCalendario struct {
+	Tipo   TipoItemCalendario
+	Titulo string
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// OpcoesCalendario configura a renderização das visões de semana e mês.
+// Campos zerados assumem os valores padrão indicados.
+type OpcoesCalendario struct {
+	ASCII       bool      // Sem cores e com bordas ASCII, para saídas que não são um terminal.
+	HoraInicio  int       // Primeira hora da grade semanal (padrão 7).
+	HoraFim     int       // Hora em que a grade semanal termina (padrão 19).
+	LarguraDia  int       // Largura de cada coluna de dia (padrão 18).
+	ItensPorDia int       // Itens listados por dia na visão mensal antes de "+N" (padrão 3).
+	Hoje        time.Time // Dia destacado no cabeçalho (opcional).
+}
+
+// minutosPorLinha é a resolução da grade semanal.
+const minutosPorLinha = 30
+
+var (
+	diasAbreviados = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+	marcadoresCalendario = map[TipoItemCalendario]string{ItemEvento: "E", ItemAula: "A", ItemTarefa: "T", ItemProva: "P", ItemNaoLetivo: "N"}
+	nomesCalendario      = map[TipoItemCalendario]string{ItemEvento: "evento", ItemAula: "aula", ItemTarefa: "tarefa", ItemProva: "prova", ItemNaoLetivo: "não letivo"}
+	coresCalendario      = map[TipoItemCalendario]lipgloss.Color{
+		ItemEvento:    lipgloss.Color("63"),  // Azul
+		ItemAula:      lipgloss.Color("35"),  // Verde
+		ItemTarefa:    lipgloss.Color("214"), // Laranja
+		ItemProva:     lipgloss.Color("160"), // Vermelho
+		ItemNaoLetivo: lipgloss.Color("245"), // Cinza
+	}
+	estiloCabecalhoCalendario = lipgloss.NewStyle().Bold(true)
+	estiloHojeCalendario      = lipgloss.NewStyle().Bold(true).Underline(true)
+	estiloForaDoMes           = lipgloss.NewStyle().Faint(true)
+)
+
+func (o OpcoesCalendario) comPadroes() OpcoesCalendario {
+	if o.HoraInicio <= 0 && o.HoraFim <= 0 {
+		o.HoraInicio, o.HoraFim = 7, 19
+	}
+	if o.HoraFim <= o.HoraInicio || o.HoraFim > 24 {
+		o.HoraFim = 24
+	}
+	if o.LarguraDia <= 0 {
+		o.LarguraDia = 18
+	}
+	if o.ItensPorDia <= 0 {
+		o.ItensPorDia = 3
+	}
+	return o
+}
+
+// RenderSemana desenha a grade semanal de 7 dias a partir de inicio: horas nas linhas e dias nas colunas,
+// em intervalos de 30 minutos. Itens sobrepostos no mesmo dia aparecem lado a lado.
+// Itens de dia inteiro, prazos sem horário e itens fora da faixa de horas vão para a linha "dia".
+func RenderSemana(inicio time.Time, itens []ItemCalendario, opcoes OpcoesCalendario) string {
+	opcoes = opcoes.comPadroes()
+	primeiroDia := time.Date(inicio.Year(), inicio.Month(), inicio.Day(), 0, 0, 0, 0, inicio.Location())
+	linhas := (opcoes.HoraFim - opcoes.HoraInicio) * 60 / minutosPorLinha
+
+	cabecalhos := []string{""}
+	colunas := make([][]string, 7)
+	var linhaDia []string
+	temLinhaDia := false
+	for d := 0; d < 7; d++ {
+		dia := primeiroDia.AddDate(0, 0, d)
+		cabecalhos = append(cabecalhos, cabecalhoDoDia(dia, opcoes))
+		blocos, topo := distribuirDia(dia, itens, opcoes)
+		colunas[d] = desenharColunaDoDia(blocos, linhas, opcoes)
+
+		var textos []string
+		for _, item := range topo {
+			textos = append(textos, ajustarLargura(rotuloDoItem(item, true, opcoes), opcoes.LarguraDia, opcoes))
+		}
+		if len(textos) > 0 {
+			temLinhaDia = true
+		}
+		linhaDia = append(linhaDia, strings.Join(textos, "\n"))
+	}
+
+	var linhasTabela [][]string
+	if temLinhaDia {
+		linhasTabela = append(linhasTabela, append([]string{"dia"}, linhaDia...))
+	}
+	for r := 0; r < linhas; r++ {
+		rotulo := ""
+		if minutos := opcoes.HoraInicio*60 + r*minutosPorLinha; minutos%60 == 0 {
+			rotulo = fmt.Sprintf("%02d:00", minutos/60)
+		}
+		linha := []string{rotulo}
+		for d := 0; d < 7; d++ {
+			linha = append(linha, colunas[d][r])
+		}
+		linhasTabela = append(linhasTabela, linha)
+	}
+
+	return novaTabelaCalendario(cabecalhos, linhasTabela, false, opcoes) + "\n" + legendaCalendario(opcoes)
+}
+
+// RenderMes desenha o mês de referência (qualquer dia do mês) em semanas de segunda a domingo,
+// listando em cada dia até ItensPorDia itens em ordem de horário.
+func RenderMes(referencia time.Time, itens []ItemCalendario, opcoes OpcoesCalendario) string {
+	opcoes = opcoes.comPadroes()
+	primeiro := time.Date(referencia.Year(), referencia.Month(), 1, 0, 0, 0, 0, referencia.Location())
+	inicioGrade := primeiro.AddDate(0, 0, -((int(primeiro.Weekday()) + 6) % 7)) // Segunda-feira anterior ou igual.
+
+	cabecalhos := []string{"seg", "ter", "qua", "qui", "sex", "sáb", "dom"}
+	var linhasTabela [][]string
+	for semana := inicioGrade; semana.Before(primeiro.AddDate(0, 1, 0)); semana = semana.AddDate(0, 0, 7) {
+		var linha []string
+		for d := 0; d < 7; d++ {
+			dia := semana.AddDate(0, 0, d)
+			linha = append(linha, celulaDoMes(dia, dia.Month() == primeiro.Month(), itens, opcoes))
+		}
+		linhasTabela = append(linhasTabela, linha)
+	}
+
+	titulo := fmt.Sprintf("%s de %d", nomeDoMes(primeiro.Month()), primeiro.Year())
+	if !opcoes.ASCII {
+		titulo = estiloCabecalhoCalendario.Render(titulo)
+	}
+	return titulo + "\n" + novaTabelaCalendario(cabecalhos, linhasTabela, true, opcoes) + "\n" + legendaCalendario(opcoes)
+}
+
+// blocoCalendario é um item posicionado na grade semanal, nas linhas [inicio, fim) da faixa indicada.
+type blocoCalendario struct {
+	item        ItemCalendario
+	inicio, fim int
+	faixa       int
+}
+
+// distribuirDia separa os itens de um dia entre blocos da grade e itens da linha "dia",
+// atribuindo a cada bloco a primeira faixa livre para que sobreposições fiquem lado a lado.
+func distribuirDia(dia time.Time, itens []ItemCalendario, opcoes OpcoesCalendario) ([]blocoCalendario, []ItemCalendario) {
+	fimDoDia := dia.AddDate(0, 0, 1)
+	inicioGrade := dia.Add(time.Duration(opcoes.HoraInicio) * time.Hour)
+	fimGrade := dia.Add(time.Duration(opcoes.HoraFim) * time.Hour)
+	passo := minutosPorLinha * time.Minute
+
+	var blocos []blocoCalendario
+	var topo []ItemCalendario
+	for _, item := range itens {
+		pontual := !item.Fim.After(item.Inicio)
+		if pontual {
+			if item.Inicio.Before(dia) || !item.Inicio.Before(fimDoDia) {
+				continue
+			}
+		} else if !item.Inicio.Before(fimDoDia) || !item.Fim.After(dia) {
+			continue
+		}
+
+		inicio, fim := item.Inicio, item.Fim
+		if pontual {
+			fim = inicio.Add(passo)
+		}
+		diaInteiro := (pontual && inicio.Equal(dia)) || (!inicio.After(dia) && !fim.Before(fimDoDia))
+		if diaInteiro || !fim.After(inicioGrade) || !inicio.Before(fimGrade) {
+			topo = append(topo, item)
+			continue
+		}
+		if inicio.Before(inicioGrade) {
+			inicio = inicioGrade
+		}
+		if fim.After(fimGrade) {
+			fim = fimGrade
+		}
+		primeira := int(inicio.Sub(inicioGrade) / passo)
+		ultima := int((fim.Sub(inicioGrade) + passo - 1) / passo)
+		if ultima <= primeira {
+			ultima = primeira + 1
+		}
+		blocos = append(blocos, blocoCalendario{item: item, inicio: primeira, fim: ultima})
+	}
+
+	sort.SliceStable(blocos, func(i, j int) bool {
+		if blocos[i].inicio != blocos[j].inicio {
+			return blocos[i].inicio < blocos[j].inicio
+		}
+		return blocos[i].fim > blocos[j].fim
+	})
+	var fimDasFaixas []int
+	for i := range blocos {
+		faixa := len(fimDasFaixas)
+		for f, fim := range fimDasFaixas {
+			if fim <= blocos[i].inicio {
+				faixa = f
+				break
+			}
+		}
+		if faixa == len(fimDasFaixas) {
+			fimDasFaixas = append(fimDasFaixas, 0)
+		}
+		fimDasFaixas[faixa] = blocos[i].fim
+		blocos[i].faixa = faixa
+	}
+
+	sort.SliceStable(topo, func(i, j int) bool { return topo[i].Inicio.Before(topo[j].Inicio) })
+	return blocos, topo
+}
+
+// desenharColunaDoDia monta o texto de cada linha da grade de um dia, dividindo a largura entre as faixas.
+func desenharColunaDoDia(blocos []blocoCalendario, linhas int, opcoes OpcoesCalendario) []string {
+	faixas := 1
+	for _, b := range blocos {
+		if b.faixa+1 > faixas {
+			faixas = b.faixa + 1
+		}
+	}
+	larguraFaixa := (opcoes.LarguraDia - (faixas - 1)) / faixas
+	if larguraFaixa < 1 {
+		larguraFaixa = 1
+	}
+
+	coluna := make([]string, linhas)
+	for r := 0; r < linhas; r++ {
+		partes := make([]string, faixas)
+		for f := range partes {
+			partes[f] = strings.Repeat(" ", larguraFaixa)
+		}
+		for _, b := range blocos {
+			if r < b.inicio || r >= b.fim {
+				continue
+			}
+			texto := "|"
+			if !opcoes.ASCII {
+				texto = ""
+			}
+			if r == b.inicio {
+				texto = marcadoresCalendario[b.item.Tipo] + " " + b.item.Titulo
+			}
+			texto = ajustarLargura(texto, larguraFaixa, opcoes)
+			if !opcoes.ASCII {
+				texto = lipgloss.NewStyle().Background(coresCalendario[b.item.Tipo]).Foreground(lipgloss.Color("231")).Render(texto)
+			}
+			partes[b.faixa] = texto
+		}
+		coluna[r] = ajustarLargura(strings.Join(partes, " "), opcoes.LarguraDia, opcoes)
+	}
+	return coluna
+}
+
+// celulaDoMes monta a célula de um dia na visão mensal: o número do dia e os primeiros itens.
+func celulaDoMes(dia time.Time, doMes bool, itens []ItemCalendario, opcoes OpcoesCalendario) string {
+	numero := fmt.Sprintf("%2d", dia.Day())
+	if !doMes {
+		if opcoes.ASCII {
+			return ajustarLargura("", opcoes.LarguraDia, opcoes)
+		}
+		return ajustarLargura(estiloForaDoMes.Render(numero), opcoes.LarguraDia, opcoes)
+	}
+	if mesmoDia(dia, opcoes.Hoje) {
+		if opcoes.ASCII {
+			numero += " *"
+		} else {
+			numero = estiloHojeCalendario.Render(numero)
+		}
+	}
+
+	fimDoDia := dia.AddDate(0, 0, 1)
+	var doDia []ItemCalendario
+	for _, item := range itens {
+		if item.Fim.After(item.Inicio) {
+			if item.Inicio.Before(fimDoDia) && item.Fim.After(dia) {
+				doDia = append(doDia, item)
+			}
+		} else if !item.Inicio.Before(dia) && item.Inicio.Before(fimDoDia) {
+			doDia = append(doDia, item)
+		}
+	}
+	sort.SliceStable(doDia, func(i, j int) bool { return doDia[i].Inicio.Before(doDia[j].Inicio) })
+
+	linhas := []string{ajustarLargura(numero, opcoes.LarguraDia, opcoes)}
+	for i, item := range doDia {
+		if i == opcoes.ItensPorDia {
+			linhas = append(linhas, ajustarLargura(fmt.Sprintf("+%d mais", len(doDia)-opcoes.ItensPorDia), opcoes.LarguraDia, opcoes))
+			break
+		}
+		// Itens que começaram em dias anteriores aparecem como de dia inteiro.
+		comHorario := !item.Inicio.Before(dia)
+		linhas = append(linhas, ajustarLargura(rotuloDoItem(item, comHorario, opcoes), opcoes.LarguraDia, opcoes))
+	}
+	return strings.Join(linhas, "\n")
+}
+
+// rotuloDoItem formata "HH:MM M Título" (sem horário à meia-noite ou quando comHorario é falso).
+func rotuloDoItem(item ItemCalendario, comHorario bool, opcoes OpcoesCalendario) string {
+	marcador := marcadoresCalendario[item.Tipo]
+	if !opcoes.ASCII {
+		marcador = lipgloss.NewStyle().Bold(true).Foreground(coresCalendario[item.Tipo]).Render(marcador)
+	}
+	rotulo := marcador + " " + item.Titulo
+	if comHorario && (item.Inicio.Hour() != 0 || item.Inicio.Minute() != 0) {
+		rotulo = item.Inicio.Format("15:04") + " " + rotulo
+	}
+	return rotulo
+}
+
+// ajustarLargura corta ou completa s com espaços para ocupar exatamente largura colunas, ignorando códigos ANSI.
+func ajustarLargura(s string, largura int, opcoes OpcoesCalendario) string {
+	if lipgloss.Width(s) > largura {
+		final := "…"
+		if opcoes.ASCII {
+			final = "~"
+		}
+		s = ansi.Truncate(s, largura, final)
+	}
+	return s + strings.Repeat(" ", largura-lipgloss.Width(s))
+}
+
+func cabecalhoDoDia(dia time.Time, opcoes OpcoesCalendario) string {
+	texto := diasAbreviados[dia.Weekday()] + " " + dia.Format("02/01")
+	if mesmoDia(dia, opcoes.Hoje) {
+		if opcoes.ASCII {
+			return texto + " *"
+		}
+		return estiloHojeCalendario.Render(texto)
+	}
+	return texto
+}
+
+func mesmoDia(a, b time.Time) bool {
+	if b.IsZero() {
+		return false
+	}
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// novaTabelaCalendario monta a grade com lipgloss/table, com bordas ASCII quando opcoes.ASCII é verdadeiro.
+func novaTabelaCalendario(cabecalhos []string, linhas [][]string, bordaEntreLinhas bool, opcoes OpcoesCalendario) string {
+	borda := lipgloss.NormalBorder()
+	if opcoes.ASCII {
+		borda = lipgloss.ASCIIBorder()
+	}
+	t := table.New().
+		Border(borda).
+		BorderRow(bordaEntreLinhas).
+		Headers(cabecalhos...).
+		Rows(linhas...).
+		StyleFunc(func(linha, coluna int) lipgloss.Style {
+			estilo := lipgloss.NewStyle().Padding(0, 1)
+			if linha == table.HeaderRow && !opcoes.ASCII {
+				estilo = estilo.Inherit(estiloCabecalhoCalendario)
+			}
+			return estilo
+		})
+	return t.String()
+}
+
+func legendaCalendario(opcoes OpcoesCalendario) string {
+	var partes []string
+	for _, tipo := range []TipoItemCalendario{ItemEvento, ItemAula, ItemTarefa, ItemProva, ItemNaoLetivo} {
+		marcador := marcadoresCalendario[tipo]
+		if !opcoes.ASCII {
+			marcador = lipgloss.NewStyle().Bold(true).Foreground(coresCalendario[tipo]).Render(marcador)
+		}
+		partes = append(partes, marcador+" "+nomesCalendario[tipo])
+	}
+	return "Legenda: " + strings.Join(partes, "  ")
+}
+
+func nomeDoMes(mes time.Month) string {
+	nomes := [...]string{"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho", "Julho",
+		"Agosto", "Setembro", "Outubro", "Novembro", "Dezembro"}
+	return nomes[mes-1]
+}
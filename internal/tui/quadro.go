@@ -0,0 +1,615 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/datas"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// ColunasQuadro são os status exibidos como colunas do quadro, da esquerda para a direita.
+// As tarefas bloqueadas ficam na coluna do status gravado, marcadas como "Bloqueada".
+var ColunasQuadro = []string{models.TaskStatusPending, models.TaskStatusInProgress, models.TaskStatusCompleted}
+
+// campoEdicao indica o que está sendo digitado na linha de edição do quadro.
+type campoEdicao int
+
+const (
+	editandoNada campoEdicao = iota
+	editandoDescricao
+	editandoPrazo
+	editandoTags
+	editandoFiltroTag
+)
+
+var rotulosEdicao = map[campoEdicao]string{
+	editandoDescricao: "Descrição",
+	editandoPrazo:     "Prazo",
+	editandoTags:      "Tags (separadas por vírgula)",
+	editandoFiltroTag: "Filtrar pela tag",
+}
+
+// SituacaoPrazo classifica o prazo de uma tarefa em relação ao dia atual, para colorir os cartões.
+type SituacaoPrazo int
+
+const (
+	SemPrazo      SituacaoPrazo = iota // Tarefa sem prazo ou já concluída.
+	PrazoFuturo                        // Prazo daqui a mais de dois dias.
+	PrazoProximo                       // Prazo amanhã ou depois de amanhã.
+	PrazoHoje                          // Prazo hoje.
+	PrazoAtrasado                      // Prazo anterior a hoje.
+)
+
+var (
+	coresPrazo = map[SituacaoPrazo]lipgloss.Color{
+		PrazoProximo:  lipgloss.Color("220"), // Amarelo
+		PrazoHoje:     lipgloss.Color("214"), // Laranja
+		PrazoAtrasado: lipgloss.Color("160"), // Vermelho
+	}
+	estiloColunaQuadro      = lipgloss.NewStyle().Bold(true)
+	estiloColunaAtivaQuadro = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("212"))
+	estiloCartao            = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).PaddingLeft(1).PaddingRight(1)
+	estiloCartaoSelecionado = estiloCartao.BorderForeground(lipgloss.Color("212"))
+	estiloConcluida         = lipgloss.NewStyle().Faint(true)
+	estiloBloqueada         = lipgloss.NewStyle().Foreground(lipgloss.Color("160")).Bold(true)
+	estiloAjudaQuadro       = lipgloss.NewStyle().Faint(true)
+)
+
+// ClassificarPrazo devolve a situação do prazo de t em relação a agora. Só o dia civil é comparado.
+func ClassificarPrazo(t models.Task, agora time.Time) SituacaoPrazo {
+	if t.DueDate.IsZero() || t.Status == models.TaskStatusCompleted {
+		return SemPrazo
+	}
+	dias := int(datas.DataUTC(t.DueDate).Sub(datas.DataUTC(agora)).Hours() / 24)
+	switch {
+	case dias < 0:
+		return PrazoAtrasado
+	case dias == 0:
+		return PrazoHoje
+	case dias <= 2:
+		return PrazoProximo
+	}
+	return PrazoFuturo
+}
+
+// quadroCarregadoMsg traz as tarefas recarregadas do banco, a mensagem da operação que as alterou
+// e o ID da tarefa a manter selecionada.
+type quadroCarregadoMsg struct {
+	tarefas    []models.Task
+	bloqueios  tarefa.Bloqueios
+	mensagem   string
+	selecionar string
+}
+
+// quadroErroMsg informa que uma operação do quadro falhou; as tarefas exibidas não mudam.
+type quadroErroMsg struct{ err error }
+
+// QuadroModel é o quadro kanban das tarefas ('tarefa quadro'): uma coluna por status, com os cartões
+// ordenados pelo prazo. As alterações feitas no quadro são gravadas pela API do pacote tarefa.
+type QuadroModel struct {
+	statusBar StatusBarModel
+
+	tarefas   []models.Task
+	bloqueios tarefa.Bloqueios
+	progresso map[string]tarefa.Progresso
+	colunas   [][]models.Task // Tarefas visíveis (após os filtros) de cada coluna.
+
+	coluna  int   // Coluna selecionada.
+	cursor  []int // Cartão selecionado em cada coluna.
+	rolagem []int // Primeiro cartão exibido em cada coluna.
+
+	FiltroTag        string // Exibe apenas as tarefas com esta tag (sem diferenciar maiúsculas); vazio desativa.
+	FiltroPrioridade int    // Exibe apenas as tarefas com esta prioridade; 0 desativa.
+
+	edicao  campoEdicao
+	entrada []rune
+
+	largura, altura int
+	carregado       bool
+}
+
+// NovoQuadro cria o quadro com os filtros iniciais informados. As tarefas são carregadas em Init.
+func NovoQuadro(filtroTag string, filtroPrioridade int) QuadroModel {
+	m := QuadroModel{
+		statusBar:        NewStatusBarModel(),
+		FiltroTag:        strings.TrimSpace(filtroTag),
+		FiltroPrioridade: filtroPrioridade,
+		colunas:          make([][]models.Task, len(ColunasQuadro)),
+		cursor:           make([]int, len(ColunasQuadro)),
+		rolagem:          make([]int, len(ColunasQuadro)),
+	}
+	m.atualizarContexto()
+	return m
+}
+
+// Init carrega as tarefas.
+func (m QuadroModel) Init() tea.Cmd {
+	return carregarQuadro("", "")
+}
+
+// carregarQuadro lê todas as tarefas e os seus bloqueios.
+func carregarQuadro(mensagem, selecionar string) tea.Cmd {
+	return func() tea.Msg {
+		tarefas, err := tarefa.ListarTarefas("", 0, "", "", "", "")
+		if err != nil {
+			return quadroErroMsg{err}
+		}
+		bloqueios, err := tarefa.CarregarBloqueios()
+		if err != nil {
+			return quadroErroMsg{err}
+		}
+		return quadroCarregadoMsg{tarefas: tarefas, bloqueios: bloqueios, mensagem: mensagem, selecionar: selecionar}
+	}
+}
+
+// gravacao serializa as operações do quadro, que rodam em goroutines, para que cada uma
+// seja registrada no histórico com as suas próprias alterações.
+var gravacao sync.Mutex
+
+// salvar executa a operação op e recarrega o quadro, mantendo a tarefa id selecionada.
+// op devolve a mensagem exibida na barra de status. Cada operação é registrada no histórico
+// separadamente, para que 'vickgenda desfazer' reverta só a última alteração feita no quadro.
+func salvar(id string, op func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		gravacao.Lock()
+		marca, errMarca := db.JournalMark()
+		mensagem, err := op()
+		if errMarca == nil {
+			comando := "tarefa quadro"
+			if mensagem != "" {
+				comando += ": " + strings.TrimSuffix(mensagem, ".")
+			}
+			if _, errOp := db.RecordOperation(comando, marca); errOp != nil && err == nil {
+				mensagem += " (Aviso: a alteração não foi registrada no histórico.)"
+			}
+		}
+		gravacao.Unlock()
+		if err != nil {
+			return quadroErroMsg{err}
+		}
+		return carregarQuadro(mensagem, id)()
+	}
+}
+
+// Update trata as teclas e os resultados das operações.
+func (m QuadroModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.largura, m.altura = msg.Width, msg.Height
+	case quadroCarregadoMsg:
+		m.tarefas, m.bloqueios, m.carregado = msg.tarefas, msg.bloqueios, true
+		m.progresso = tarefa.CalcularProgresso(msg.tarefas)
+		m.statusBar.Message = msg.mensagem
+		m.distribuir(msg.selecionar)
+	case quadroErroMsg:
+		m.statusBar.Message = "Erro: " + msg.err.Error()
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.edicao != editandoNada {
+			return m.teclaEdicao(msg)
+		}
+		return m.teclaQuadro(msg)
+	}
+	return m, nil
+}
+
+// teclaQuadro trata as teclas da navegação do quadro.
+func (m QuadroModel) teclaQuadro(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.statusBar.Message = ""
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "left", "h":
+		if m.coluna > 0 {
+			m.coluna--
+		}
+	case "right", "l":
+		if m.coluna < len(ColunasQuadro)-1 {
+			m.coluna++
+		}
+	case "up", "k":
+		if m.cursor[m.coluna] > 0 {
+			m.cursor[m.coluna]--
+		}
+	case "down", "j":
+		if m.cursor[m.coluna] < len(m.colunas[m.coluna])-1 {
+			m.cursor[m.coluna]++
+		}
+	case "shift+left", "<", "H":
+		return m, m.mover(-1)
+	case "shift+right", ">", "L":
+		return m, m.mover(1)
+	case "1", "2", "3":
+		t, ok := m.selecionada()
+		if !ok {
+			break
+		}
+		prioridade := int(msg.Runes[0] - '0')
+		return m, salvar(t.ID, func() (string, error) {
+			if _, err := tarefa.EditarTarefa(t.ID, "", "", prioridade, "", ""); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Prioridade de '%s' alterada para %d.", t.Description, prioridade), nil
+		})
+	case "e":
+		if t, ok := m.selecionada(); ok {
+			m.editar(editandoDescricao, t.Description)
+		}
+	case "d":
+		if t, ok := m.selecionada(); ok {
+			prazo := ""
+			if !t.DueDate.IsZero() {
+				prazo = t.DueDate.Format("02/01/2006")
+			}
+			m.editar(editandoPrazo, prazo)
+		}
+	case "t":
+		if t, ok := m.selecionada(); ok {
+			m.editar(editandoTags, strings.Join(t.Tags, ", "))
+		}
+	case "/":
+		m.editar(editandoFiltroTag, m.FiltroTag)
+	case "f":
+		m.FiltroPrioridade = (m.FiltroPrioridade + 1) % 4
+		m.distribuir(m.idSelecionado())
+	case "esc":
+		m.FiltroTag, m.FiltroPrioridade = "", 0
+		m.distribuir(m.idSelecionado())
+	case "r":
+		return m, carregarQuadro("Quadro recarregado.", m.idSelecionado())
+	}
+	m.atualizarContexto()
+	return m, nil
+}
+
+// teclaEdicao trata as teclas da linha de edição: enter confirma, esc cancela.
+func (m QuadroModel) teclaEdicao(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.edicao, m.entrada = editandoNada, nil
+	case tea.KeyEnter:
+		cmd := m.confirmarEdicao()
+		m.edicao, m.entrada = editandoNada, nil
+		m.atualizarContexto()
+		return m, cmd
+	case tea.KeyBackspace:
+		if len(m.entrada) > 0 {
+			m.entrada = m.entrada[:len(m.entrada)-1]
+		}
+	case tea.KeyCtrlU:
+		m.entrada = nil
+	case tea.KeyRunes, tea.KeySpace:
+		m.entrada = append(m.entrada, msg.Runes...)
+	}
+	m.atualizarContexto()
+	return m, nil
+}
+
+// editar abre a linha de edição do campo, preenchida com o valor atual.
+func (m *QuadroModel) editar(campo campoEdicao, valor string) {
+	m.edicao, m.entrada = campo, []rune(valor)
+}
+
+// confirmarEdicao aplica o texto digitado ao campo em edição. Um valor igual ao atual não altera a tarefa.
+func (m *QuadroModel) confirmarEdicao() tea.Cmd {
+	valor := strings.TrimSpace(string(m.entrada))
+	if m.edicao == editandoFiltroTag {
+		m.FiltroTag = valor
+		m.distribuir(m.idSelecionado())
+		return nil
+	}
+	t, ok := m.selecionada()
+	if !ok {
+		return nil
+	}
+	switch m.edicao {
+	case editandoDescricao:
+		if valor == "" || valor == t.Description {
+			return nil
+		}
+		return salvar(t.ID, func() (string, error) {
+			_, err := tarefa.EditarTarefa(t.ID, valor, "", 0, "", "")
+			return "Descrição alterada.", err
+		})
+	case editandoPrazo:
+		if valor == "" {
+			if t.DueDate.IsZero() {
+				return nil
+			}
+			return salvar(t.ID, func() (string, error) {
+				_, err := tarefa.EditarTarefa(t.ID, "", " ", 0, "", "") // Um espaço remove o prazo.
+				return "Prazo removido.", err
+			})
+		}
+		if !t.DueDate.IsZero() && valor == t.DueDate.Format("02/01/2006") {
+			return nil
+		}
+		return salvar(t.ID, func() (string, error) {
+			editada, err := tarefa.EditarTarefa(t.ID, "", valor, 0, "", "")
+			if err != nil {
+				return "", err
+			}
+			return "Prazo alterado para " + editada.DueDate.Format("02/01/2006") + ".", nil
+		})
+	case editandoTags:
+		if valor == strings.Join(t.Tags, ", ") {
+			return nil
+		}
+		tags := valor
+		if tags == "" {
+			tags = " " // EditarTarefa mantém as tags com uma string vazia; um espaço as remove.
+		}
+		return salvar(t.ID, func() (string, error) {
+			_, err := tarefa.EditarTarefa(t.ID, "", "", 0, "", tags)
+			return "Tags alteradas.", err
+		})
+	}
+	return nil
+}
+
+// mover devolve a operação que move a tarefa selecionada para a coluna ao lado (direcao -1 ou 1).
+// Concluir usa tarefa.ConcluirTarefaComAvisos, que também para o cronômetro da tarefa.
+func (m QuadroModel) mover(direcao int) tea.Cmd {
+	t, ok := m.selecionada()
+	destino := m.coluna + direcao
+	if !ok || destino < 0 || destino >= len(ColunasQuadro) {
+		return nil
+	}
+	status := ColunasQuadro[destino]
+	return salvar(t.ID, func() (string, error) {
+		if status != models.TaskStatusCompleted {
+			if _, err := tarefa.EditarTarefa(t.ID, "", "", 0, status, ""); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("'%s' movida para %s.", t.Description, status), nil
+		}
+		conclusao, err := tarefa.ConcluirTarefaComAvisos(t.ID)
+		if err != nil {
+			return "", err
+		}
+		mensagem := fmt.Sprintf("'%s' concluída.", t.Description)
+		if n := len(conclusao.SubtarefasAbertas); n > 0 {
+			mensagem += fmt.Sprintf(" %d subtarefa(s) continuam abertas.", n)
+		}
+		if len(conclusao.Desbloqueadas) > 0 {
+			var descricoes []string
+			for _, d := range conclusao.Desbloqueadas {
+				descricoes = append(descricoes, d.Description)
+			}
+			mensagem += " Desbloqueada(s): " + strings.Join(descricoes, ", ") + "."
+		}
+		return mensagem, nil
+	})
+}
+
+// selecionada devolve a tarefa selecionada, se a coluna atual tiver alguma.
+func (m QuadroModel) selecionada() (models.Task, bool) {
+	cartoes := m.colunas[m.coluna]
+	if len(cartoes) == 0 {
+		return models.Task{}, false
+	}
+	return cartoes[m.cursor[m.coluna]], true
+}
+
+func (m QuadroModel) idSelecionado() string {
+	t, _ := m.selecionada()
+	return t.ID
+}
+
+// distribuir separa as tarefas que passam pelos filtros nas colunas, ordenadas pelo prazo (as sem prazo
+// por último) e pela prioridade. Se a tarefa id estiver visível, ela passa a ser a selecionada.
+func (m *QuadroModel) distribuir(id string) {
+	indice := make(map[string]int, len(ColunasQuadro))
+	for i, status := range ColunasQuadro {
+		indice[status] = i
+		m.colunas[i] = nil
+	}
+	for _, t := range m.tarefas {
+		i, ok := indice[t.Status]
+		if !ok || !m.passaFiltros(t) {
+			continue
+		}
+		m.colunas[i] = append(m.colunas[i], t)
+	}
+	for i, cartoes := range m.colunas {
+		sort.SliceStable(cartoes, func(a, b int) bool {
+			pa, pb := cartoes[a].DueDate, cartoes[b].DueDate
+			if pa.IsZero() != pb.IsZero() {
+				return !pa.IsZero()
+			}
+			if !pa.Equal(pb) {
+				return pa.Before(pb)
+			}
+			return cartoes[a].Priority < cartoes[b].Priority
+		})
+		if m.cursor[i] >= len(cartoes) {
+			m.cursor[i] = len(cartoes) - 1
+		}
+		if m.cursor[i] < 0 {
+			m.cursor[i] = 0
+		}
+		for j, t := range cartoes {
+			if t.ID == id && id != "" {
+				m.coluna, m.cursor[i] = i, j
+			}
+		}
+	}
+	m.atualizarContexto()
+}
+
+// passaFiltros informa se a tarefa atende aos filtros de tag e de prioridade.
+func (m QuadroModel) passaFiltros(t models.Task) bool {
+	if m.FiltroPrioridade > 0 && t.Priority != m.FiltroPrioridade {
+		return false
+	}
+	if m.FiltroTag == "" {
+		return true
+	}
+	for _, tag := range t.Tags {
+		if strings.EqualFold(tag, m.FiltroTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// atualizarContexto mostra na barra de status o modo atual e os filtros ativos.
+func (m *QuadroModel) atualizarContexto() {
+	if m.edicao != editandoNada {
+		m.statusBar.CurrentContext = "Editando: " + strings.ToLower(rotulosEdicao[m.edicao])
+		return
+	}
+	var filtros []string
+	if m.FiltroTag != "" {
+		filtros = append(filtros, "tag "+m.FiltroTag)
+	}
+	if m.FiltroPrioridade > 0 {
+		filtros = append(filtros, fmt.Sprintf("prioridade %d", m.FiltroPrioridade))
+	}
+	m.statusBar.CurrentContext = "Quadro de tarefas"
+	if len(filtros) > 0 {
+		m.statusBar.CurrentContext += " (" + strings.Join(filtros, ", ") + ")"
+	}
+}
+
+// View desenha as colunas, a linha de ajuda (ou de edição) e a barra de status.
+func (m QuadroModel) View() string {
+	if !m.carregado {
+		return "Carregando tarefas...\n\n" + m.statusBar.View()
+	}
+	largura := m.largura
+	if largura <= 0 {
+		largura = 120
+	}
+	larguraColuna := (largura - len(ColunasQuadro) + 1) / len(ColunasQuadro)
+	if larguraColuna < 24 {
+		larguraColuna = 24
+	}
+	// Linhas disponíveis para os cartões: descontam o cabeçalho, os avisos de rolagem ("↑ mais N"),
+	// a linha em branco, a linha de ajuda e a barra de status.
+	alturaCartoes := 0
+	if m.altura > 0 {
+		alturaCartoes = m.altura - 6
+	}
+
+	colunas := make([]string, len(ColunasQuadro))
+	for i, status := range ColunasQuadro {
+		colunas[i] = m.viewColuna(i, status, larguraColuna, alturaCartoes)
+	}
+	quadro := lipgloss.JoinHorizontal(lipgloss.Top, intercalar(colunas, " ")...)
+
+	rodape := estiloAjudaQuadro.Render(ansi.Truncate("←→ coluna  ↑↓ cartão  <> mover  e descrição  d prazo  t tags  1-3 prioridade  / tag  f prioridade  esc limpa filtros  r recarrega  q sai", largura, "…"))
+	if m.edicao != editandoNada {
+		rodape = rotulosEdicao[m.edicao] + ": " + string(m.entrada) + "█  " + estiloAjudaQuadro.Render("(enter confirma, esc cancela)")
+	}
+	return quadro + "\n\n" + rodape + "\n" + m.statusBar.View()
+}
+
+// viewColuna desenha o cabeçalho e os cartões de uma coluna que cabem em altura linhas (0 = todos),
+// rolando para manter o cartão selecionado visível.
+func (m *QuadroModel) viewColuna(i int, status string, largura, altura int) string {
+	cabecalho := fmt.Sprintf("%s (%d)", status, len(m.colunas[i]))
+	estilo := estiloColunaQuadro
+	if i == m.coluna {
+		estilo = estiloColunaAtivaQuadro
+	}
+	linhas := []string{estilo.Width(largura).Render(cabecalho)}
+	cartoes := make([]string, len(m.colunas[i]))
+	for j, t := range m.colunas[i] {
+		cartoes[j] = m.viewCartao(t, i == m.coluna && j == m.cursor[i], largura)
+	}
+	if len(cartoes) == 0 {
+		linhas = append(linhas, estiloAjudaQuadro.Render("Nenhuma tarefa."))
+		return lipgloss.NewStyle().Width(largura).Render(strings.Join(linhas, "\n"))
+	}
+
+	inicio, fim := 0, len(cartoes)
+	if altura > 0 {
+		if m.rolagem[i] > m.cursor[i] {
+			m.rolagem[i] = m.cursor[i]
+		}
+		for {
+			inicio, fim = m.rolagem[i], m.rolagem[i]
+			usado := 0
+			for fim < len(cartoes) && usado+lipgloss.Height(cartoes[fim]) <= altura {
+				usado += lipgloss.Height(cartoes[fim])
+				fim++
+			}
+			if m.cursor[i] < fim || m.rolagem[i] >= m.cursor[i] {
+				break
+			}
+			m.rolagem[i]++
+		}
+		if fim == inicio {
+			fim = inicio + 1
+		}
+	}
+	if inicio > 0 {
+		linhas = append(linhas, estiloAjudaQuadro.Render(fmt.Sprintf("↑ mais %d", inicio)))
+	}
+	linhas = append(linhas, cartoes[inicio:fim]...)
+	if fim < len(cartoes) {
+		linhas = append(linhas, estiloAjudaQuadro.Render(fmt.Sprintf("↓ mais %d", len(cartoes)-fim)))
+	}
+	return lipgloss.NewStyle().Width(largura).Render(strings.Join(linhas, "\n"))
+}
+
+// viewCartao desenha o cartão de uma tarefa: prioridade e descrição, prazo colorido pela situação,
+// progresso das subtarefas, tags e a marca de bloqueio.
+func (m QuadroModel) viewCartao(t models.Task, selecionado bool, largura int) string {
+	estilo := estiloCartao
+	if selecionado {
+		estilo = estiloCartaoSelecionado
+	}
+	texto := largura - estilo.GetHorizontalFrameSize()
+
+	linhas := []string{ansi.Truncate(fmt.Sprintf("P%d %s", t.Priority, t.Description), texto, "…")}
+	prazo := "sem prazo"
+	if !t.DueDate.IsZero() {
+		prazo = "prazo " + t.DueDate.Format("02/01/2006")
+	}
+	detalhes := prazo
+	if cor, ok := coresPrazo[ClassificarPrazo(t, datas.Agora())]; ok {
+		detalhes = lipgloss.NewStyle().Foreground(cor).Render(prazo)
+	}
+	if p, ok := m.progresso[t.ID]; ok {
+		detalhes += "  " + p.String()
+	}
+	linhas = append(linhas, detalhes)
+	if len(t.Tags) > 0 {
+		linhas = append(linhas, ansi.Truncate("#"+strings.Join(t.Tags, " #"), texto, "…"))
+	}
+	if m.bloqueios.Bloqueada(t.ID) && t.Status != models.TaskStatusCompleted {
+		linhas = append(linhas, estiloBloqueada.Render(models.TaskStatusBlocked))
+	}
+
+	conteudo := strings.Join(linhas, "\n")
+	if t.Status == models.TaskStatusCompleted {
+		conteudo = estiloConcluida.Render(conteudo)
+	}
+	return estilo.Width(largura - estilo.GetHorizontalBorderSize()).Render(conteudo)
+}
+
+// intercalar devolve itens com sep entre cada par.
+func intercalar(itens []string, sep string) []string {
+	var resultado []string
+	for i, item := range itens {
+		if i > 0 {
+			resultado = append(resultado, sep)
+		}
+		resultado = append(resultado, item)
+	}
+	return resultado
+}
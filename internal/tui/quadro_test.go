@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"vickgenda-cli/internal/commands/tarefa"
+	"vickgenda-cli/internal/db"
+	"vickgenda-cli/internal/models"
+)
+
+// TestMain inicializa um banco SQLite em memória compartilhado para os testes do quadro.
+func TestMain(m *testing.M) {
+	db.InitTestDB("tui")
+	code := m.Run()
+	db.GetDB().Close()
+	os.Exit(code)
+}
+
+// enviar entrega as mensagens ao quadro, executando em seguida os comandos devolvidos (carregar, salvar).
+func enviar(m QuadroModel, msgs ...tea.Msg) QuadroModel {
+	for _, msg := range msgs {
+		novo, cmd := m.Update(msg)
+		m = novo.(QuadroModel)
+		for cmd != nil {
+			novo, cmd = m.Update(cmd())
+			m = novo.(QuadroModel)
+		}
+	}
+	return m
+}
+
+func tecla(s string) tea.Msg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func digitar(s string) []tea.Msg {
+	msgs := []tea.Msg{tea.KeyMsg{Type: tea.KeyCtrlU}}
+	for _, r := range s {
+		msgs = append(msgs, tecla(string(r)))
+	}
+	return append(msgs, tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestClassificarPrazo(t *testing.T) {
+	agora := time.Date(2024, 8, 14, 22, 0, 0, 0, time.Local)
+	dia := func(d int) models.Task {
+		return models.Task{Status: models.TaskStatusPending, DueDate: time.Date(2024, 8, d, 0, 0, 0, 0, time.Local)}
+	}
+	casos := []struct {
+		tarefa   models.Task
+		esperado SituacaoPrazo
+	}{
+		{models.Task{Status: models.TaskStatusPending}, SemPrazo},
+		{dia(13), PrazoAtrasado},
+		{dia(14), PrazoHoje},
+		{dia(16), PrazoProximo},
+		{dia(17), PrazoFuturo},
+		{models.Task{Status: models.TaskStatusCompleted, DueDate: dia(13).DueDate}, SemPrazo},
+	}
+	for _, c := range casos {
+		if obtido := ClassificarPrazo(c.tarefa, agora); obtido != c.esperado {
+			t.Errorf("ClassificarPrazo(%v, %s): esperado %d, obtido %d", c.tarefa.DueDate, c.tarefa.Status, c.esperado, obtido)
+		}
+	}
+}
+
+func TestQuadro(t *testing.T) {
+	tarefa.LimparTarefasStore()
+	corrigir, _ := tarefa.CriarTarefa("Corrigir provas", "2024-08-10", 1, "provas")
+	lancar, _ := tarefa.CriarTarefa("Lançar notas", "2024-08-12", 2, "")
+	planejar, _ := tarefa.CriarTarefa("Planejar aula", "", 3, "")
+	if err := tarefa.AdicionarDependencia(lancar.ID, corrigir.ID); err != nil {
+		t.Fatalf("AdicionarDependencia falhou: %v", err)
+	}
+	if _, err := tarefa.EditarTarefa(planejar.ID, "", "", 0, models.TaskStatusInProgress, ""); err != nil {
+		t.Fatalf("EditarTarefa falhou: %v", err)
+	}
+
+	m := NovoQuadro("", 0)
+	m = enviar(m, m.Init()(), tea.WindowSizeMsg{Width: 120, Height: 40})
+	if len(m.colunas[0]) != 2 || len(m.colunas[1]) != 1 || len(m.colunas[2]) != 0 {
+		t.Fatalf("Distribuição inesperada: %d/%d/%d", len(m.colunas[0]), len(m.colunas[1]), len(m.colunas[2]))
+	}
+	if m.colunas[0][0].ID != corrigir.ID {
+		t.Errorf("Esperada a tarefa de prazo mais próximo no topo, obtida '%s'", m.colunas[0][0].Description)
+	}
+	if view := m.View(); !strings.Contains(view, "Pendente (2)") || !strings.Contains(view, "Bloqueada") {
+		t.Errorf("View sem o cabeçalho da coluna ou a marca de bloqueio:\n%s", view)
+	}
+
+	t.Run("Mover cartões", func(t *testing.T) {
+		m = enviar(m, tecla(">"))
+		if atual, _ := tarefa.GetTarefaByID(corrigir.ID); atual.Status != models.TaskStatusInProgress {
+			t.Fatalf("Esperado status '%s', obtido '%s'", models.TaskStatusInProgress, atual.Status)
+		}
+		if m.coluna != 1 || m.idSelecionado() != corrigir.ID {
+			t.Errorf("O cartão movido deveria continuar selecionado, na coluna 1")
+		}
+		m = enviar(m, tecla(">"))
+		if atual, _ := tarefa.GetTarefaByID(corrigir.ID); atual.Status != models.TaskStatusCompleted {
+			t.Fatalf("Esperado status '%s', obtido '%s'", models.TaskStatusCompleted, atual.Status)
+		}
+		if !strings.Contains(m.statusBar.Message, "Desbloqueada(s): Lançar notas") {
+			t.Errorf("Mensagem inesperada ao concluir: %q", m.statusBar.Message)
+		}
+		m = enviar(m, tecla(">"))
+		if m.coluna != 2 {
+			t.Errorf("Mover além da última coluna não deveria fazer nada")
+		}
+		m = enviar(m, tecla("<"))
+		if atual, _ := tarefa.GetTarefaByID(corrigir.ID); atual.Status != models.TaskStatusInProgress {
+			t.Errorf("Reabrir: esperado status '%s', obtido '%s'", models.TaskStatusInProgress, atual.Status)
+		}
+		// Cada movimento é uma operação do histórico.
+		ops, err := db.ListOperations(3)
+		if err != nil || len(ops) != 3 || ops[0].Command != "tarefa quadro: 'Corrigir provas' movida para "+models.TaskStatusInProgress ||
+			!strings.HasPrefix(ops[1].Command, "tarefa quadro: 'Corrigir provas' concluída") {
+			t.Errorf("Histórico inesperado: %+v (%v)", ops, err)
+		}
+	})
+
+	t.Run("Editar no quadro", func(t *testing.T) {
+		m = enviar(m, tecla("e"))
+		if m.edicao != editandoDescricao || string(m.entrada) != "Corrigir provas" {
+			t.Fatalf("A edição deveria começar com a descrição atual, obtido %q", string(m.entrada))
+		}
+		m = enviar(m, digitar("Corrigir provas 7B")...)
+		m = enviar(m, tecla("1"))
+		m = enviar(m, tecla("d"))
+		m = enviar(m, digitar("2024-08-20")...)
+		m = enviar(m, tecla("t"))
+		m = enviar(m, digitar("provas, 7B")...)
+		atual, _ := tarefa.GetTarefaByID(corrigir.ID)
+		if atual.Description != "Corrigir provas 7B" || atual.Priority != 1 || atual.DueDate.Format("2006-01-02") != "2024-08-20" || len(atual.Tags) != 2 {
+			t.Errorf("Edição inesperada: %+v", atual)
+		}
+
+		m = enviar(m, tecla("d"))
+		m = enviar(m, digitar("data inválida")...)
+		if !strings.HasPrefix(m.statusBar.Message, "Erro:") {
+			t.Errorf("Esperado erro na barra de status, obtido %q", m.statusBar.Message)
+		}
+		// Apagar o prazo na linha de edição remove o prazo da tarefa.
+		m = enviar(m, tecla("d"))
+		m = enviar(m, digitar("")...)
+		if atual, _ := tarefa.GetTarefaByID(corrigir.ID); !atual.DueDate.IsZero() || m.statusBar.Message != "Prazo removido." {
+			t.Errorf("Esperado prazo removido, obtido %v (%q)", atual.DueDate, m.statusBar.Message)
+		}
+		m = enviar(m, tecla("e"), tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEsc})
+		if atual, _ := tarefa.GetTarefaByID(corrigir.ID); atual.Description != "Corrigir provas 7B" || m.edicao != editandoNada {
+			t.Errorf("Esc deveria cancelar a edição sem alterar a tarefa")
+		}
+	})
+
+	t.Run("Filtros", func(t *testing.T) {
+		m = enviar(m, tecla("/"))
+		m = enviar(m, digitar("PROVAS")...)
+		if len(m.colunas[0])+len(m.colunas[1])+len(m.colunas[2]) != 1 || !strings.Contains(m.statusBar.CurrentContext, "tag PROVAS") {
+			t.Errorf("Filtro por tag inesperado: %d/%d/%d (%s)", len(m.colunas[0]), len(m.colunas[1]), len(m.colunas[2]), m.statusBar.CurrentContext)
+		}
+		m = enviar(m, tea.KeyMsg{Type: tea.KeyEsc}, tecla("f"), tecla("f"))
+		if m.FiltroTag != "" || m.FiltroPrioridade != 2 || len(m.colunas[0]) != 1 || m.colunas[0][0].ID != lancar.ID {
+			t.Errorf("Filtro por prioridade inesperado: %+v", m.colunas)
+		}
+	})
+}
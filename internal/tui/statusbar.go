@@ -28,9 +28,9 @@ type StatusBarModel struct {
 	// CurrentContext provides information about the current view or mode of the application.
 	// E.g., "Navegação", "Editando Tarefa".
 	CurrentContext string
-	// TODO: Add a field for dynamic messages or short-lived alerts,
-	// e.g., "Salvando...", "Erro: Falha ao conectar". This would require
-	// handling messages or having methods to set/clear this message.
+	// Message is a short-lived message shown after the context, e.g., "Tarefa concluída."
+	// or "Erro: Falha ao conectar". The parent model sets and clears it; empty hides it.
+	Message string
 }
 
 // NewStatusBarModel creates and returns a new StatusBarModel initialized with default values.
@@ -82,6 +82,9 @@ func (m StatusBarModel) View() string {
 
 	// Combine the styled parts into a single string.
 	statusText := fmt.Sprintf("%s %s | %s", appName, version, context)
+	if m.Message != "" {
+		statusText += " | " + m.Message
+	}
 	// Apply the overall status bar style (background, foreground, padding) to the combined text.
 	return statusBarStyle.Render(statusText)
 }
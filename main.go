@@ -3,12 +3,14 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"vickgenda-cli/cmd"         // For cmd/root.go init() and cmd.AnotacaoHistoricoPorAcao
 	"vickgenda-cli/cmd/cli"    // For cli.SetupRootCmd, cli.Execute
 	"vickgenda-cli/internal/db" // For db.InitDB
 
 	// Import packages for side effects (to run their init() functions)
-	_ "vickgenda-cli/cmd"               // For cmd/root.go init()
 	_ "vickgenda-cli/cmd/bancoq"        // For cmd/bancoq init() which adds to cmd.BancoqCmd
 	_ "vickgenda-cli/cmd/prova"         // For cmd/prova init()
 	_ "vickgenda-cli/cmd/vickgenda"     // For cmd/vickgenda/auth.go and setup.go init()
@@ -24,7 +26,13 @@ import (
 
 func main() {
 	// Initialize the database early.
-	if err := db.InitDB(""); err != nil {
+	// "db" maintenance commands only open the connection, so that
+	// "db migrate down" is not immediately undone by the automatic migration.
+	initDB := db.InitDB
+	if len(os.Args) > 1 && os.Args[1] == "db" {
+		initDB = db.OpenDB
+	}
+	if err := initDB(""); err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao inicializar o banco de dados: %v\n", err)
 		os.Exit(1)
 	}
@@ -37,9 +45,43 @@ func main() {
 	// are added to cli.GetRootCmd() within their own init() functions,
 	// which are run due to the blank imports above.
 
+	// Every change the command makes to the database is journaled as one operation,
+	// which 'vickgenda desfazer' can revert. A failed command keeps the changes it made before failing.
+	// Long-running commands (the daemon, the task board) record one operation per action themselves.
+	journaled := true
+	if c, _, err := cli.GetRootCmd().Find(os.Args[1:]); err == nil {
+		_, perAction := c.Annotations[cmd.AnotacaoHistoricoPorAcao]
+		journaled = !perAction
+	}
+	var mark db.OperationMark
+	var err error
+	if journaled {
+		if mark, err = db.JournalMark(); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: o histórico de operações não está disponível: %v\n", err)
+		}
+	}
+
 	// Execute the root command from the cli package
-	if err := cli.Execute(); err != nil {
+	execErr := cli.Execute()
+	if journaled && err == nil {
+		if _, err := db.RecordOperation(commandLine(os.Args[1:]), mark); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: a operação não foi registrada no histórico: %v\n", err)
+		}
+	}
+	if execErr != nil {
 		// Cobra's Execute usually prints errors itself.
 		os.Exit(1)
 	}
 }
+
+// commandLine rebuilds the command line for the operation journal, quoting arguments with spaces.
+func commandLine(args []string) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a
+		if a == "" || strings.ContainsAny(a, " \t\"'") {
+			parts[i] = strconv.Quote(a)
+		}
+	}
+	return strings.Join(parts, " ")
+}